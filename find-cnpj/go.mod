@@ -6,6 +6,7 @@ require (
 	github.com/PuerkitoBio/goquery v1.11.0
 	github.com/chromedp/chromedp v0.14.2
 	github.com/joho/godotenv v1.5.1
+	github.com/lucasfdcampos/serp v0.0.0
 )
 
 require (
@@ -19,3 +20,5 @@ require (
 	golang.org/x/net v0.47.0 // indirect
 	golang.org/x/sys v0.38.0 // indirect
 )
+
+replace github.com/lucasfdcampos/serp => ../serp
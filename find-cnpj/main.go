@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/lucasfdcampos/find-cnpj/pkg/cnpj"
@@ -89,6 +90,9 @@ func main() {
 			}
 			fmt.Println()
 		}
+		if len(result.CNPJ.CNAEsSecundarios) > 0 {
+			fmt.Printf("   Secundários: %s\n", strings.Join(result.CNPJ.CNAEsSecundarios, ", "))
+		}
 	}
 
 	fmt.Printf("\n⏱️  Tempo total: %v\n", result.Duration)
@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+
+	"github.com/lucasfdcampos/serp/pkg/serp"
 )
 
 type ReceitaWSSearcher struct {
@@ -57,10 +59,14 @@ func (r *ReceitaWSSearcher) Search(ctx context.Context, query string) (*CNPJ, er
 		Nome               string `json:"nome"`
 		Fantasia           string `json:"fantasia"`
 		Telefone           string `json:"telefone"`
+		Email              string `json:"email"`
 		AtividadePrincipal []struct {
 			Code string `json:"code"`
 			Text string `json:"text"`
 		} `json:"atividade_principal"`
+		AtividadesSecundarias []struct {
+			Code string `json:"code"`
+		} `json:"atividades_secundarias"`
 		QSA []struct {
 			Nome string `json:"nome"`
 		} `json:"qsa"`
@@ -90,12 +96,20 @@ func (r *ReceitaWSSearcher) Search(ctx context.Context, query string) (*CNPJ, er
 	if result.Telefone != "" {
 		cnpjObj.Telefones = append(cnpjObj.Telefones, result.Telefone)
 	}
+	if result.Email != "" {
+		cnpjObj.Email = strings.ToLower(strings.TrimSpace(result.Email))
+	}
 
 	// Adiciona CNAE principal
 	if len(result.AtividadePrincipal) > 0 {
 		cnpjObj.CNAE = result.AtividadePrincipal[0].Code
 		cnpjObj.CNAEDesc = result.AtividadePrincipal[0].Text
 	}
+	for _, sec := range result.AtividadesSecundarias {
+		if code := cnaeDigits(sec.Code); code != "" {
+			cnpjObj.CNAEsSecundarios = append(cnpjObj.CNAEsSecundarios, code)
+		}
+	}
 
 	// Adiciona sócios
 	for _, qsa := range result.QSA {
@@ -217,38 +231,19 @@ func (d *DuckDuckGoSearcher) Name() string {
 }
 
 func (d *DuckDuckGoSearcher) Search(ctx context.Context, query string) (*CNPJ, error) {
-	searchURL := "https://html.duckduckgo.com/html/?q=" + url.QueryEscape(query)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao criar requisição: %w", err)
-	}
-
-	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36")
-
-	client := &http.Client{Timeout: 15 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao fazer requisição: %w", err)
-	}
-	defer resp.Body.Close()
+	return searchEngineCNPJ(ctx, serp.DuckDuckGo, query)
+}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+// searchEngineCNPJ procura um CNPJ nos resultados de um motor de busca
+func searchEngineCNPJ(ctx context.Context, engine serp.Engine, query string) (*CNPJ, error) {
+	page, err := serp.Search(ctx, engine, query)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao parsear HTML: %w", err)
+		return nil, err
 	}
-
-	var results []string
-	doc.Find(".result__snippet").Each(func(i int, s *goquery.Selection) {
-		results = append(results, s.Text())
-	})
-
-	fullText := strings.Join(results, " ")
-	if cnpj := ExtractCNPJ(fullText); cnpj != nil {
+	if cnpj := ExtractCNPJ(page.Text()); cnpj != nil {
 		return cnpj, nil
 	}
-
-	return nil, fmt.Errorf("CNPJ não encontrado no DuckDuckGo")
+	return nil, fmt.Errorf("CNPJ não encontrado no %s", engine.Name())
 }
 
 // EnrichFromReceitaWS tenta enriquecer dados usando ReceitaWS
@@ -280,18 +275,17 @@ func EnrichFromReceitaWS(ctx context.Context, cnpj *CNPJ) error {
 		cnpj.CNAE = enriched.CNAE
 		cnpj.CNAEDesc = enriched.CNAEDesc
 	}
+	if len(cnpj.CNAEsSecundarios) == 0 && len(enriched.CNAEsSecundarios) > 0 {
+		cnpj.CNAEsSecundarios = enriched.CNAEsSecundarios
+	}
+	if cnpj.Email == "" && enriched.Email != "" {
+		cnpj.Email = enriched.Email
+	}
 	return nil
 }
 
 // ─── SearXNG CNPJ Searcher ────────────────────────────────────────────────────
 
-// searxngCNPJInstances lists public SearXNG instances to try in order.
-var searxngCNPJInstances = []string{
-	"https://searx.be",
-	"https://search.bus-hit.me",
-	"https://paulgo.io",
-}
-
 // SearXNGSearcher searches for a CNPJ using SearXNG public instances.
 type SearXNGSearcher struct{}
 
@@ -299,43 +293,7 @@ func NewSearXNGSearcher() *SearXNGSearcher { return &SearXNGSearcher{} }
 func (s *SearXNGSearcher) Name() string    { return "SearXNG" }
 
 func (s *SearXNGSearcher) Search(ctx context.Context, query string) (*CNPJ, error) {
-	client := &http.Client{Timeout: 15 * time.Second}
-
-	for _, instance := range searxngCNPJInstances {
-		searchURL := fmt.Sprintf("%s/search?q=%s&language=pt-BR&format=html", instance, url.QueryEscape(query))
-
-		req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
-		if err != nil {
-			continue
-		}
-		req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 Chrome/120.0.0.0 Safari/537.36")
-		req.Header.Set("Accept-Language", "pt-BR,pt;q=0.9")
-
-		time.Sleep(800 * time.Millisecond)
-
-		resp, err := client.Do(req)
-		if err != nil {
-			continue
-		}
-
-		doc, err := goquery.NewDocumentFromReader(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			continue
-		}
-
-		var sb strings.Builder
-		doc.Find(".result-content, .result-text, .result__snippet, p").Each(func(_ int, sel *goquery.Selection) {
-			sb.WriteString(sel.Text())
-			sb.WriteString(" ")
-		})
-
-		if cnpj := ExtractCNPJ(sb.String()); cnpj != nil {
-			return cnpj, nil
-		}
-	}
-
-	return nil, fmt.Errorf("CNPJ não encontrado no SearXNG")
+	return searchEngineCNPJ(ctx, serp.SearXNG, query)
 }
 
 // ─── Mojeek CNPJ Searcher ─────────────────────────────────────────────────────
@@ -347,44 +305,7 @@ func NewMojeekSearcher() *MojeekSearcher { return &MojeekSearcher{} }
 func (m *MojeekSearcher) Name() string   { return "Mojeek" }
 
 func (m *MojeekSearcher) Search(ctx context.Context, query string) (*CNPJ, error) {
-	searchURL := "https://www.mojeek.com/search?q=" + url.QueryEscape(query)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("mojeek: request: %w", err)
-	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 Chrome/120.0.0.0 Safari/537.36")
-	req.Header.Set("Accept-Language", "pt-BR,pt;q=0.9")
-
-	time.Sleep(800 * time.Millisecond)
-
-	client := &http.Client{Timeout: 15 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("mojeek: do: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("mojeek: status %d", resp.StatusCode)
-	}
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("mojeek: parse: %w", err)
-	}
-
-	var sb strings.Builder
-	doc.Find(".result-text, .result__body, p").Each(func(_ int, sel *goquery.Selection) {
-		sb.WriteString(sel.Text())
-		sb.WriteString(" ")
-	})
-
-	if cnpj := ExtractCNPJ(sb.String()); cnpj != nil {
-		return cnpj, nil
-	}
-
-	return nil, fmt.Errorf("CNPJ não encontrado no Mojeek")
+	return searchEngineCNPJ(ctx, serp.Mojeek, query)
 }
 
 // ─── Swisscows CNPJ Searcher ──────────────────────────────────────────────────
@@ -396,42 +317,5 @@ func NewSwisscowsSearcher() *SwisscowsSearcher { return &SwisscowsSearcher{} }
 func (s *SwisscowsSearcher) Name() string      { return "Swisscows" }
 
 func (s *SwisscowsSearcher) Search(ctx context.Context, query string) (*CNPJ, error) {
-	searchURL := "https://swisscows.com/web?query=" + url.QueryEscape(query) + "&region=pt-BR"
-
-	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("swisscows: request: %w", err)
-	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 Chrome/120.0.0.0 Safari/537.36")
-	req.Header.Set("Accept-Language", "pt-BR,pt;q=0.9")
-
-	time.Sleep(1 * time.Second)
-
-	client := &http.Client{Timeout: 15 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("swisscows: do: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("swisscows: status %d", resp.StatusCode)
-	}
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("swisscows: parse: %w", err)
-	}
-
-	var sb strings.Builder
-	doc.Find(".web-results .item-body, .result-item, p").Each(func(_ int, sel *goquery.Selection) {
-		sb.WriteString(sel.Text())
-		sb.WriteString(" ")
-	})
-
-	if cnpj := ExtractCNPJ(sb.String()); cnpj != nil {
-		return cnpj, nil
-	}
-
-	return nil, fmt.Errorf("CNPJ não encontrado no Swisscows")
+	return searchEngineCNPJ(ctx, serp.Swisscows, query)
 }
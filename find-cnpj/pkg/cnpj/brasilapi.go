@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -45,17 +46,21 @@ func (b *BrasilAPISearcher) Search(ctx context.Context, query string) (*CNPJ, er
 	}
 
 	var result struct {
-		CNPJ         string `json:"cnpj"`
-		RazaoSocial  string `json:"razao_social"`
-		NomeFantasia string `json:"nome_fantasia"`
-		Situacao     string `json:"descricao_situacao_cadastral"`
-		DDD          string `json:"ddd_telefone_1"`
-		Telefone     string `json:"telefone_1"`
-		CNAEFiscal   int    `json:"cnae_fiscal"`
-		CNAEDesc     string `json:"cnae_fiscal_descricao"`
-		Municipio    string `json:"municipio"`
-		UF           string `json:"uf"`
-		QSA          []struct {
+		CNPJ             string `json:"cnpj"`
+		RazaoSocial      string `json:"razao_social"`
+		NomeFantasia     string `json:"nome_fantasia"`
+		Situacao         string `json:"descricao_situacao_cadastral"`
+		DDD              string `json:"ddd_telefone_1"`
+		Telefone         string `json:"telefone_1"`
+		CNAEFiscal       int    `json:"cnae_fiscal"`
+		CNAEDesc         string `json:"cnae_fiscal_descricao"`
+		CNAEsSecundarios []struct {
+			Codigo int `json:"codigo"`
+		} `json:"cnaes_secundarios"`
+		Municipio string `json:"municipio"`
+		UF        string `json:"uf"`
+		Email     string `json:"email"`
+		QSA       []struct {
 			Nome string `json:"nome_socio"`
 		} `json:"qsa"`
 	}
@@ -82,10 +87,16 @@ func (b *BrasilAPISearcher) Search(ctx context.Context, query string) (*CNPJ, er
 		cnpjObj.CNAE = fmt.Sprintf("%07d", result.CNAEFiscal) // Formata com 7 dígitos
 		cnpjObj.CNAEDesc = result.CNAEDesc
 	}
+	for _, sec := range result.CNAEsSecundarios {
+		if code := cnaeDigits(fmt.Sprint(sec.Codigo)); code != "" {
+			cnpjObj.CNAEsSecundarios = append(cnpjObj.CNAEsSecundarios, code)
+		}
+	}
 
 	// Adiciona município e UF
 	cnpjObj.Municipio = result.Municipio
 	cnpjObj.UF = result.UF
+	cnpjObj.Email = strings.ToLower(strings.TrimSpace(result.Email))
 
 	// Adiciona sócios
 	for _, socio := range result.QSA {
@@ -133,6 +144,12 @@ func EnrichCNPJData(ctx context.Context, cnpj *CNPJ) error {
 			cnpj.CNAE = enriched.CNAE
 			cnpj.CNAEDesc = enriched.CNAEDesc
 		}
+		if len(enriched.CNAEsSecundarios) > 0 {
+			cnpj.CNAEsSecundarios = enriched.CNAEsSecundarios
+		}
+		if enriched.Email != "" {
+			cnpj.Email = enriched.Email
+		}
 
 		// Se já temos dados completos, retorna
 		if isComplete() {
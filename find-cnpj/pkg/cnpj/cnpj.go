@@ -7,17 +7,19 @@ import (
 
 // CNPJ representa um CNPJ validado com informações adicionais
 type CNPJ struct {
-	Number       string   // Apenas números
-	Formatted    string   // Com formatação XX.XXX.XXX/XXXX-XX
-	RazaoSocial  string   // Razão Social da empresa
-	NomeFantasia string   // Nome Fantasia
-	Situacao     string   // Situação cadastral (ex: ATIVA, BAIXADA)
-	Socios       []string // Lista de sócios
-	Telefones    []string // Lista de telefones
-	CNAE         string   // CNAE principal (código da atividade econômica)
-	CNAEDesc     string   // Descrição do CNAE
-	Municipio    string   // Município do estabelecimento
-	UF           string   // Unidade Federativa (estado)
+	Number           string   // Apenas números
+	Formatted        string   // Com formatação XX.XXX.XXX/XXXX-XX
+	RazaoSocial      string   // Razão Social da empresa
+	NomeFantasia     string   // Nome Fantasia
+	Situacao         string   // Situação cadastral (ex: ATIVA, BAIXADA)
+	Socios           []string // Lista de sócios
+	Telefones        []string // Lista de telefones
+	CNAE             string   // CNAE principal (código da atividade econômica)
+	CNAEDesc         string   // Descrição do CNAE
+	CNAEsSecundarios []string // CNAEs secundários (7 dígitos, ex: 4789099)
+	Municipio        string   // Município do estabelecimento
+	UF               string   // Unidade Federativa (estado)
+	Email            string   // E-mail cadastrado na Receita (quando informado)
 }
 
 // ExtractCNPJ extrai o primeiro CNPJ válido de um texto
@@ -157,3 +159,16 @@ func NormalizarQuery(query string) string {
 
 	return query
 }
+
+// nonDigit casa tudo o que não é dígito num código CNAE.
+var nonDigit = regexp.MustCompile(`\D`)
+
+// cnaeDigits normaliza um código CNAE ("47.89-0-99", 4789099) para os 7
+// dígitos da subclasse. Retorna "" para o código vazio da Receita (0000000).
+func cnaeDigits(code string) string {
+	d := nonDigit.ReplaceAllString(code, "")
+	if len(d) == 0 || len(d) > 7 || strings.Trim(d, "0") == "" {
+		return ""
+	}
+	return strings.Repeat("0", 7-len(d)) + d
+}
@@ -3,12 +3,11 @@ package cnpj
 import (
 	"context"
 	"fmt"
-	"net/http"
 	"regexp"
 	"strings"
-	"time"
 
-	"github.com/PuerkitoBio/goquery"
+	"github.com/lucasfdcampos/find-cnpj/pkg/phone"
+	"github.com/lucasfdcampos/serp/pkg/serp"
 )
 
 // DuckDuckGoScraper busca dados de CNPJ via snippets de busca do DuckDuckGo
@@ -29,143 +28,120 @@ func EnrichFromDuckDuckGo(ctx context.Context, cnpj *CNPJ) error {
 	}
 
 	// Tenta buscar por CNPJ
-	socios, razaoSocial, telefones, cnaeCode, cnaeDesc := searchDuckDuckGo(ctx, cnpj.Number, "cnpj")
+	found, _ := searchSnippets(ctx, serp.DuckDuckGo, fmt.Sprintf("%s sócios administradores", cnpj.Number))
 
 	// Se não conseguiu muita coisa e tem razão social, busca por ela
-	if len(socios) == 0 && cnpj.RazaoSocial != "" {
-		sociosRS, razaoRS, telefonesRS, cnaeRS, cnaeDescRS := searchDuckDuckGo(ctx, cnpj.RazaoSocial, "razao-social")
+	if len(found.socios) == 0 && cnpj.RazaoSocial != "" {
+		byName, _ := searchSnippets(ctx, serp.DuckDuckGo, fmt.Sprintf("%s CNPJ sócios", cnpj.RazaoSocial))
+		found.merge(byName)
+	}
 
-		// Merge resultados
-		socios = append(socios, sociosRS...)
-		if razaoSocial == "" && razaoRS != "" {
-			razaoSocial = razaoRS
-		}
-		telefones = append(telefones, telefonesRS...)
-		if cnaeCode == "" && cnaeRS != "" {
-			cnaeCode = cnaeRS
-			cnaeDesc = cnaeDescRS
-		}
+	if !found.apply(cnpj) {
+		return fmt.Errorf("nenhum dado novo encontrado no DuckDuckGo")
 	}
+	return nil
+}
 
-	// Atualiza dados do CNPJ
-	updated := false
+// snippetData reúne o que foi extraído dos snippets de uma busca
+type snippetData struct {
+	razaoSocial string
+	socios      []string
+	telefones   []string
+	cnaeCode    string
+	cnaeDesc    string
+}
 
-	if razaoSocial != "" && cnpj.RazaoSocial == "" {
-		cnpj.RazaoSocial = razaoSocial
-		updated = true
+// searchSnippets busca no motor e extrai razão social, sócios, telefones e
+// CNAE de cada resultado (ou do texto da página, se nenhum foi reconhecido).
+// Nunca retorna nil, mesmo com erro.
+func searchSnippets(ctx context.Context, engine serp.Engine, query string) (*snippetData, error) {
+	d := &snippetData{}
+	page, err := serp.Search(ctx, engine, query)
+	if err != nil {
+		return d, err
 	}
 
-	if len(socios) > 0 {
-		// Remove duplicatas
-		sociosMap := make(map[string]bool)
-		for _, s := range cnpj.Socios {
-			sociosMap[strings.ToLower(s)] = true
-		}
-
-		for _, s := range socios {
-			if !sociosMap[strings.ToLower(s)] {
-				cnpj.Socios = append(cnpj.Socios, s)
-				updated = true
-			}
+	texts := []string{page.Raw}
+	if len(page.Results) > 0 {
+		texts = texts[:0]
+		for _, r := range page.Results {
+			texts = append(texts, r.Title+"\n"+r.Snippet)
 		}
 	}
 
-	if len(telefones) > 0 {
-		// Remove duplicatas
-		telefonesMap := make(map[string]bool)
-		for _, t := range cnpj.Telefones {
-			telefonesMap[t] = true
+	for _, text := range texts {
+		// Busca razão social se ainda não temos
+		if d.razaoSocial == "" {
+			d.razaoSocial = extractRazaoSocial(text)
 		}
 
-		for _, t := range telefones {
-			if !telefonesMap[t] {
-				cnpj.Telefones = append(cnpj.Telefones, t)
-				updated = true
-			}
+		// Busca CNAE se ainda não temos
+		if d.cnaeCode == "" {
+			d.cnaeCode, d.cnaeDesc = extractCNAEFromText(text)
 		}
-	}
 
-	if cnpj.CNAE == "" && cnaeCode != "" {
-		cnpj.CNAE = cnaeCode
-		cnpj.CNAEDesc = cnaeDesc
-		updated = true
-	}
-
-	if !updated {
-		return fmt.Errorf("nenhum dado novo encontrado no DuckDuckGo")
+		d.socios = append(d.socios, extractSocios(text)...)
+		d.telefones = append(d.telefones, extractTelefonesFromText(text)...)
 	}
 
-	return nil
+	// Remove duplicatas
+	d.socios = removeDuplicates(d.socios)
+	d.telefones = removeDuplicates(d.telefones)
+	return d, nil
 }
 
-// searchDuckDuckGo faz busca no DuckDuckGo e extrai informações
-func searchDuckDuckGo(ctx context.Context, query string, searchType string) (socios []string, razaoSocial string, telefones []string, cnaeCode string, cnaeDesc string) {
-	var searchQuery string
-
-	if searchType == "cnpj" {
-		searchQuery = fmt.Sprintf("%s sócios administradores", query)
-	} else {
-		searchQuery = fmt.Sprintf("%s CNPJ sócios", query)
+// merge completa d com o que ainda não tem
+func (d *snippetData) merge(o *snippetData) {
+	d.socios = removeDuplicates(append(d.socios, o.socios...))
+	d.telefones = removeDuplicates(append(d.telefones, o.telefones...))
+	if d.razaoSocial == "" {
+		d.razaoSocial = o.razaoSocial
 	}
-
-	url := fmt.Sprintf("https://duckduckgo.com/html/?q=%s", searchQuery)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return
+	if d.cnaeCode == "" && o.cnaeCode != "" {
+		d.cnaeCode, d.cnaeDesc = o.cnaeCode, o.cnaeDesc
 	}
+}
 
-	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36")
-	req.Header.Set("Accept-Language", "pt-BR,pt;q=0.9")
-
-	time.Sleep(1 * time.Second)
+// apply preenche o CNPJ com os dados novos; retorna false se nada mudou
+func (d *snippetData) apply(cnpj *CNPJ) bool {
+	updated := false
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return
+	if d.razaoSocial != "" && cnpj.RazaoSocial == "" {
+		cnpj.RazaoSocial = d.razaoSocial
+		updated = true
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return
+	sociosMap := make(map[string]bool)
+	for _, s := range cnpj.Socios {
+		sociosMap[strings.ToLower(s)] = true
 	}
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return
-	}
-
-	// Extrai de snippets de resultados
-	doc.Find(".result__snippet, .result-snippet, .result__body").Each(func(i int, s *goquery.Selection) {
-		text := s.Text()
-
-		// Busca razão social se ainda não temos
-		if razaoSocial == "" {
-			if rs := extractRazaoSocial(text); rs != "" {
-				razaoSocial = rs
-			}
+	for _, s := range d.socios {
+		if !sociosMap[strings.ToLower(s)] {
+			sociosMap[strings.ToLower(s)] = true
+			cnpj.Socios = append(cnpj.Socios, s)
+			updated = true
 		}
+	}
 
-		// Busca CNAE se ainda não temos
-		if cnaeCode == "" {
-			cnaeCode, cnaeDesc = extractCNAEFromText(text)
+	telefonesMap := make(map[string]bool)
+	for _, t := range cnpj.Telefones {
+		telefonesMap[t] = true
+	}
+	for _, t := range d.telefones {
+		if !telefonesMap[t] {
+			telefonesMap[t] = true
+			cnpj.Telefones = append(cnpj.Telefones, t)
+			updated = true
 		}
+	}
 
-		// Busca sócios
-		foundSocios := extractSocios(text)
-		socios = append(socios, foundSocios...)
-
-		// Busca telefones
-		foundTelefones := extractTelefonesFromText(text)
-		telefones = append(telefones, foundTelefones...)
-	})
-
-	// Remove duplicatas de sócios
-	socios = removeDuplicates(socios)
-	telefones = removeDuplicates(telefones)
+	if cnpj.CNAE == "" && d.cnaeCode != "" {
+		cnpj.CNAE = d.cnaeCode
+		cnpj.CNAEDesc = d.cnaeDesc
+		updated = true
+	}
 
-	return
+	return updated
 }
 
 // extractRazaoSocial tenta extrair razão social de um texto
@@ -360,23 +336,10 @@ func isValidName(name string) bool {
 	return true
 }
 
-// normalizeTelefone normaliza formato de telefone
+// normalizeTelefone normaliza formato de telefone: (XX) XXXX-XXXX ou
+// (XX) XXXXX-XXXX. Retorna "" se o número for inválido.
 func normalizeTelefone(tel string) string {
-	// Remove caracteres não numéricos
-	re := regexp.MustCompile(`\D`)
-	digits := re.ReplaceAllString(tel, "")
-
-	// Deve ter 10 ou 11 dígitos
-	if len(digits) < 10 || len(digits) > 11 {
-		return ""
-	}
-
-	// Formata: (XX) XXXX-XXXX ou (XX) XXXXX-XXXX
-	if len(digits) == 10 {
-		return fmt.Sprintf("(%s) %s-%s", digits[0:2], digits[2:6], digits[6:10])
-	} else {
-		return fmt.Sprintf("(%s) %s-%s", digits[0:2], digits[2:7], digits[7:11])
-	}
+	return phone.Normalize(tel)
 }
 
 // extractCNAEFromText tenta extrair código CNAE e descrição de um snippet de texto
@@ -441,95 +404,16 @@ func EnrichFromBing(ctx context.Context, cnpj *CNPJ) error {
 		return fmt.Errorf("CNPJ inválido")
 	}
 
-	searchQuery := fmt.Sprintf("%s sócios administradores", cnpj.Number)
-	url := fmt.Sprintf("https://www.bing.com/search?q=%s", searchQuery)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	found, err := searchSnippets(ctx, serp.Bing, fmt.Sprintf("%s sócios administradores", cnpj.Number))
 	if err != nil {
-		return fmt.Errorf("erro ao criar requisição: %w", err)
+		return err
 	}
+	// Aqui só completa sócios, telefones e CNAE
+	found.razaoSocial = ""
 
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
-	req.Header.Set("Accept-Language", "pt-BR,pt;q=0.9")
-
-	time.Sleep(1 * time.Second)
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("erro na requisição: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("status code: %d", resp.StatusCode)
-	}
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return fmt.Errorf("erro ao parsear HTML: %w", err)
-	}
-
-	updated := false
-	var socios []string
-	var telefones []string
-	var cnaeCode, cnaeDesc string
-
-	// Extrai de snippets
-	doc.Find(".b_caption, .b_snippet").Each(func(i int, s *goquery.Selection) {
-		text := s.Text()
-
-		if cnaeCode == "" {
-			cnaeCode, cnaeDesc = extractCNAEFromText(text)
-		}
-
-		foundSocios := extractSocios(text)
-		socios = append(socios, foundSocios...)
-
-		foundTelefones := extractTelefonesFromText(text)
-		telefones = append(telefones, foundTelefones...)
-	})
-
-	// Atualiza sócios
-	if len(socios) > 0 {
-		sociosMap := make(map[string]bool)
-		for _, s := range cnpj.Socios {
-			sociosMap[strings.ToLower(s)] = true
-		}
-
-		for _, s := range removeDuplicates(socios) {
-			if !sociosMap[strings.ToLower(s)] {
-				cnpj.Socios = append(cnpj.Socios, s)
-				updated = true
-			}
-		}
-	}
-
-	// Atualiza telefones
-	if len(telefones) > 0 {
-		telefonesMap := make(map[string]bool)
-		for _, t := range cnpj.Telefones {
-			telefonesMap[t] = true
-		}
-
-		for _, t := range removeDuplicates(telefones) {
-			if !telefonesMap[t] {
-				cnpj.Telefones = append(cnpj.Telefones, t)
-				updated = true
-			}
-		}
-	}
-
-	if cnpj.CNAE == "" && cnaeCode != "" {
-		cnpj.CNAE = cnaeCode
-		cnpj.CNAEDesc = cnaeDesc
-		updated = true
-	}
-
-	if !updated {
+	if !found.apply(cnpj) {
 		return fmt.Errorf("nenhum dado novo encontrado no Bing")
 	}
-
 	return nil
 }
 
@@ -550,112 +434,16 @@ func EnrichFromBrave(ctx context.Context, cnpj *CNPJ) error {
 		return fmt.Errorf("CNPJ inválido")
 	}
 
-	searchQuery := fmt.Sprintf("%s sócios administradores", cnpj.Number)
-	url := fmt.Sprintf("https://search.brave.com/search?q=%s", searchQuery)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return fmt.Errorf("erro ao criar requisição: %w", err)
-	}
-
-	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36")
-	req.Header.Set("Accept-Language", "pt-BR,pt;q=0.9")
-
-	time.Sleep(1 * time.Second)
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	found, err := searchSnippets(ctx, serp.Brave, fmt.Sprintf("%s sócios administradores", cnpj.Number))
 	if err != nil {
-		return fmt.Errorf("erro na requisição: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("status code: %d", resp.StatusCode)
-	}
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return fmt.Errorf("erro ao parsear HTML: %w", err)
-	}
-
-	updated := false
-	var socios []string
-	var telefones []string
-	var cnaeCode, cnaeDesc string
-
-	// Brave usa classes específicas para snippets
-	doc.Find(".snippet, .snippet-description, .snippet-content").Each(func(i int, s *goquery.Selection) {
-		text := s.Text()
-
-		if cnaeCode == "" {
-			cnaeCode, cnaeDesc = extractCNAEFromText(text)
-		}
-
-		foundSocios := extractSocios(text)
-		socios = append(socios, foundSocios...)
-
-		foundTelefones := extractTelefonesFromText(text)
-		telefones = append(telefones, foundTelefones...)
-	})
-
-	// Fallback: busca em divs gerais de resultado
-	if len(socios) == 0 && len(telefones) == 0 {
-		doc.Find(".result, .search-result").Each(func(i int, s *goquery.Selection) {
-			text := s.Text()
-
-			if cnaeCode == "" {
-				cnaeCode, cnaeDesc = extractCNAEFromText(text)
-			}
-
-			foundSocios := extractSocios(text)
-			socios = append(socios, foundSocios...)
-
-			foundTelefones := extractTelefonesFromText(text)
-			telefones = append(telefones, foundTelefones...)
-		})
-	}
-
-	// Atualiza sócios
-	if len(socios) > 0 {
-		sociosMap := make(map[string]bool)
-		for _, s := range cnpj.Socios {
-			sociosMap[strings.ToLower(s)] = true
-		}
-
-		for _, s := range removeDuplicates(socios) {
-			if !sociosMap[strings.ToLower(s)] {
-				cnpj.Socios = append(cnpj.Socios, s)
-				updated = true
-			}
-		}
-	}
-
-	// Atualiza telefones
-	if len(telefones) > 0 {
-		telefonesMap := make(map[string]bool)
-		for _, t := range cnpj.Telefones {
-			telefonesMap[t] = true
-		}
-
-		for _, t := range removeDuplicates(telefones) {
-			if !telefonesMap[t] {
-				cnpj.Telefones = append(cnpj.Telefones, t)
-				updated = true
-			}
-		}
-	}
-
-	if cnpj.CNAE == "" && cnaeCode != "" {
-		cnpj.CNAE = cnaeCode
-		cnpj.CNAEDesc = cnaeDesc
-		updated = true
+		return err
 	}
+	// Aqui só completa sócios, telefones e CNAE
+	found.razaoSocial = ""
 
-	if !updated {
+	if !found.apply(cnpj) {
 		return fmt.Errorf("nenhum dado novo encontrado no Brave")
 	}
-
 	return nil
 }
 
@@ -676,111 +464,15 @@ func EnrichFromYandex(ctx context.Context, cnpj *CNPJ) error {
 		return fmt.Errorf("CNPJ inválido")
 	}
 
-	searchQuery := fmt.Sprintf("%s sócios administradores Brasil", cnpj.Number)
-	url := fmt.Sprintf("https://yandex.com/search/?text=%s&lr=102", searchQuery) // lr=102 = Brasil
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return fmt.Errorf("erro ao criar requisição: %w", err)
-	}
-
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
-	req.Header.Set("Accept-Language", "pt-BR,pt;q=0.9,en;q=0.8")
-
-	time.Sleep(1 * time.Second)
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("erro na requisição: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("status code: %d", resp.StatusCode)
-	}
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	found, err := searchSnippets(ctx, serp.Yandex, fmt.Sprintf("%s sócios administradores Brasil", cnpj.Number))
 	if err != nil {
-		return fmt.Errorf("erro ao parsear HTML: %w", err)
+		return err
 	}
+	// Aqui só completa sócios, telefones e CNAE
+	found.razaoSocial = ""
 
-	updated := false
-	var socios []string
-	var telefones []string
-	var cnaeCode, cnaeDesc string
-
-	// Yandex usa classes específicas para snippets
-	doc.Find(".Organic-Text, .OrganicText, .ExtendedText").Each(func(i int, s *goquery.Selection) {
-		text := s.Text()
-
-		if cnaeCode == "" {
-			cnaeCode, cnaeDesc = extractCNAEFromText(text)
-		}
-
-		foundSocios := extractSocios(text)
-		socios = append(socios, foundSocios...)
-
-		foundTelefones := extractTelefonesFromText(text)
-		telefones = append(telefones, foundTelefones...)
-	})
-
-	// Fallback: busca em títulos e descrições gerais
-	if len(socios) == 0 && len(telefones) == 0 {
-		doc.Find(".Organic, .serp-item").Each(func(i int, s *goquery.Selection) {
-			text := s.Text()
-
-			if cnaeCode == "" {
-				cnaeCode, cnaeDesc = extractCNAEFromText(text)
-			}
-
-			foundSocios := extractSocios(text)
-			socios = append(socios, foundSocios...)
-
-			foundTelefones := extractTelefonesFromText(text)
-			telefones = append(telefones, foundTelefones...)
-		})
-	}
-
-	// Atualiza sócios
-	if len(socios) > 0 {
-		sociosMap := make(map[string]bool)
-		for _, s := range cnpj.Socios {
-			sociosMap[strings.ToLower(s)] = true
-		}
-
-		for _, s := range removeDuplicates(socios) {
-			if !sociosMap[strings.ToLower(s)] {
-				cnpj.Socios = append(cnpj.Socios, s)
-				updated = true
-			}
-		}
-	}
-
-	// Atualiza telefones
-	if len(telefones) > 0 {
-		telefonesMap := make(map[string]bool)
-		for _, t := range cnpj.Telefones {
-			telefonesMap[t] = true
-		}
-
-		for _, t := range removeDuplicates(telefones) {
-			if !telefonesMap[t] {
-				cnpj.Telefones = append(cnpj.Telefones, t)
-				updated = true
-			}
-		}
-	}
-
-	if cnpj.CNAE == "" && cnaeCode != "" {
-		cnpj.CNAE = cnaeCode
-		cnpj.CNAEDesc = cnaeDesc
-		updated = true
-	}
-
-	if !updated {
+	if !found.apply(cnpj) {
 		return fmt.Errorf("nenhum dado novo encontrado no Yandex")
 	}
-
 	return nil
 }
@@ -0,0 +1,254 @@
+// Package phone normaliza telefones brasileiros para E.164.
+//
+// Aceita os formatos encontrados nos scrapers: com ou sem DDI (+55), com
+// prefixo de tronco (0XX) ou código de operadora (0 15 43 ...), celulares
+// sem o nono dígito, 0800 e números de tarifa compartilhada (4004, 3003).
+package phone
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// LineType é o tipo da linha telefônica.
+type LineType string
+
+const (
+	Mobile     LineType = "mobile"      // celular (9XXXX-XXXX)
+	Landline   LineType = "landline"    // fixo (2XXX a 5XXX)
+	TollFree   LineType = "toll_free"   // 0800 (gratuito para quem liga)
+	SharedCost LineType = "shared_cost" // 4004, 3003... (número único nacional)
+)
+
+var (
+	ErrEmpty       = errors.New("telefone vazio")
+	ErrLength      = errors.New("quantidade de dígitos inválida")
+	ErrDDD         = errors.New("DDD inválido")
+	ErrSubscriber  = errors.New("número de assinante inválido")
+	ErrPlaceholder = errors.New("número de preenchimento (dígitos repetidos)")
+)
+
+// Number é um telefone brasileiro validado.
+type Number struct {
+	DDD        string // 2 dígitos; vazio para 0800 e tarifa compartilhada
+	Subscriber string // 8 ou 9 dígitos (0800: 7 dígitos após "0800")
+	Type       LineType
+	UF         string // estado do DDD; vazio para números não geográficos
+}
+
+// dddUF mapeia cada DDD válido para a UF (plano de numeração da Anatel).
+var dddUF = map[string]string{
+	"11": "SP", "12": "SP", "13": "SP", "14": "SP", "15": "SP", "16": "SP", "17": "SP", "18": "SP", "19": "SP",
+	"21": "RJ", "22": "RJ", "24": "RJ",
+	"27": "ES", "28": "ES",
+	"31": "MG", "32": "MG", "33": "MG", "34": "MG", "35": "MG", "37": "MG", "38": "MG",
+	"41": "PR", "42": "PR", "43": "PR", "44": "PR", "45": "PR", "46": "PR",
+	"47": "SC", "48": "SC", "49": "SC",
+	"51": "RS", "53": "RS", "54": "RS", "55": "RS",
+	"61": "DF", "62": "GO", "64": "GO",
+	"63": "TO",
+	"65": "MT", "66": "MT",
+	"67": "MS",
+	"68": "AC",
+	"69": "RO",
+	"71": "BA", "73": "BA", "74": "BA", "75": "BA", "77": "BA",
+	"79": "SE",
+	"81": "PE", "87": "PE",
+	"82": "AL",
+	"83": "PB",
+	"84": "RN",
+	"85": "CE", "88": "CE",
+	"86": "PI", "89": "PI",
+	"91": "PA", "93": "PA", "94": "PA",
+	"92": "AM", "97": "AM",
+	"95": "RR",
+	"96": "AP",
+	"98": "MA", "99": "MA",
+}
+
+// sharedCostPrefixes são os prefixos de números únicos nacionais (8 dígitos).
+var sharedCostPrefixes = []string{"3003", "4003", "4004", "4007", "4020", "4062", "4090"}
+
+// ValidDDD indica se o DDD existe no plano de numeração.
+func ValidDDD(ddd string) bool {
+	_, ok := dddUF[ddd]
+	return ok
+}
+
+// StateForDDD retorna a UF do DDD, ou "" se o DDD não existir.
+// O DDD 61 cobre o DF e parte de GO; retorna "DF".
+func StateForDDD(ddd string) string {
+	return dddUF[ddd]
+}
+
+// Parse valida e classifica um telefone em qualquer formatação.
+func Parse(raw string) (Number, error) {
+	digits := onlyDigits(raw)
+	if digits == "" {
+		return Number{}, ErrEmpty
+	}
+
+	// DDI (+55 800 também: sobra o 800 sem o zero, tratado abaixo)
+	if strings.HasPrefix(digits, "55") && (len(digits) == 12 || len(digits) == 13) {
+		digits = digits[2:]
+	}
+
+	// Não geográficos
+	if strings.HasPrefix(digits, "0800") && len(digits) == 11 {
+		return Number{Subscriber: digits[4:], Type: TollFree}, nil
+	}
+	if strings.HasPrefix(digits, "800") && len(digits) == 10 {
+		return Number{Subscriber: digits[3:], Type: TollFree}, nil
+	}
+	if len(digits) == 8 {
+		for _, p := range sharedCostPrefixes {
+			if strings.HasPrefix(digits, p) {
+				return Number{Subscriber: digits, Type: SharedCost}, nil
+			}
+		}
+	}
+
+	// Prefixo de tronco (0 + DDD) e código de operadora (0 + CSP + DDD)
+	if strings.HasPrefix(digits, "0") {
+		switch len(digits) {
+		case 11, 12:
+			digits = digits[1:]
+		case 13, 14:
+			digits = digits[3:]
+		}
+	}
+
+	if len(digits) != 10 && len(digits) != 11 {
+		return Number{}, ErrLength
+	}
+
+	ddd, sub := digits[:2], digits[2:]
+	uf, ok := dddUF[ddd]
+	if !ok {
+		return Number{}, ErrDDD
+	}
+
+	n := Number{DDD: ddd, UF: uf}
+	switch {
+	case len(sub) == 9 && sub[0] == '9':
+		n.Type = Mobile
+	case len(sub) == 8 && sub[0] >= '2' && sub[0] <= '5':
+		n.Type = Landline
+	case len(sub) == 8 && sub[0] >= '6':
+		// Celular anterior ao nono dígito (obrigatório em todo o país desde 2016)
+		sub = "9" + sub
+		n.Type = Mobile
+	default:
+		return Number{}, ErrSubscriber
+	}
+	if repeatedTail(sub) {
+		return Number{}, ErrPlaceholder
+	}
+	n.Subscriber = sub
+	return n, nil
+}
+
+// E164 retorna o número no formato internacional (+5543999998888).
+func (n Number) E164() string {
+	switch n.Type {
+	case "":
+		return ""
+	case TollFree:
+		return "+55800" + n.Subscriber
+	case SharedCost:
+		return "+55" + n.Subscriber
+	}
+	return "+55" + n.DDD + n.Subscriber
+}
+
+// Format retorna o número no formato nacional usado nos leads:
+// "(43) 99999-8888", "(43) 3333-4444", "0800 123 4567" ou "4004-1234".
+func (n Number) Format() string {
+	s := n.Subscriber
+	switch n.Type {
+	case TollFree:
+		return fmt.Sprintf("0800 %s %s", s[:3], s[3:])
+	case SharedCost:
+		return fmt.Sprintf("%s-%s", s[:4], s[4:])
+	}
+	split := len(s) - 4
+	return fmt.Sprintf("(%s) %s-%s", n.DDD, s[:split], s[split:])
+}
+
+// WhatsApp retorna o número no formato do wa.me (só dígitos, com DDI),
+// ou "" se a linha não for celular.
+func (n Number) WhatsApp() string {
+	if n.Type != Mobile {
+		return ""
+	}
+	return "55" + n.DDD + n.Subscriber
+}
+
+// WhatsAppLink retorna o link https://wa.me/... para celulares, ou "".
+func (n Number) WhatsAppLink() string {
+	if wa := n.WhatsApp(); wa != "" {
+		return "https://wa.me/" + wa
+	}
+	return ""
+}
+
+// MatchesState indica se o DDD é compatível com a UF informada. Números não
+// geográficos e UF vazia são sempre compatíveis. O DDD 61 aceita DF e GO.
+func (n Number) MatchesState(uf string) bool {
+	uf = strings.ToUpper(strings.TrimSpace(uf))
+	if uf == "" || n.UF == "" {
+		return true
+	}
+	if n.DDD == "61" && uf == "GO" {
+		return true
+	}
+	return n.UF == uf
+}
+
+// Normalize formata o telefone no padrão nacional, ou "" se for inválido.
+func Normalize(raw string) string {
+	n, err := Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return n.Format()
+}
+
+// E164 converte o telefone para E.164, ou "" se for inválido.
+func E164(raw string) string {
+	n, err := Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return n.E164()
+}
+
+// Key retorna a chave de deduplicação do telefone: E.164 quando válido,
+// senão os dígitos (mínimo 8). Formatações diferentes do mesmo número
+// ("+55 43 99999-8888", "(43) 9999-8888") geram a mesma chave.
+func Key(raw string) string {
+	if e := E164(raw); e != "" {
+		return e
+	}
+	if d := onlyDigits(raw); len(d) >= 8 {
+		return d
+	}
+	return ""
+}
+
+func onlyDigits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// repeatedTail detecta números de preenchimento como 9999-9999 ou 3333-0000.
+func repeatedTail(sub string) bool {
+	tail := sub[len(sub)-4:]
+	return strings.Count(tail, tail[:1]) == 4
+}
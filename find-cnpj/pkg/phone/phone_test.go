@@ -0,0 +1,104 @@
+package phone
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		raw    string
+		e164   string
+		format string
+		typ    LineType
+		uf     string
+	}{
+		// celulares
+		{"(43) 99812-3456", "+5543998123456", "(43) 99812-3456", Mobile, "PR"},
+		{"+55 43 99812-3456", "+5543998123456", "(43) 99812-3456", Mobile, "PR"},
+		{"5543998123456", "+5543998123456", "(43) 99812-3456", Mobile, "PR"},
+		{"043 99812-3456", "+5543998123456", "(43) 99812-3456", Mobile, "PR"},
+		{"0 15 43 99812-3456", "+5543998123456", "(43) 99812-3456", Mobile, "PR"},
+		{"(11) 8765-4321", "+5511987654321", "(11) 98765-4321", Mobile, "SP"}, // sem o nono dígito
+		{"55 (55) 99812-3456", "+5555998123456", "(55) 99812-3456", Mobile, "RS"},
+		// fixos
+		{"(43) 3325-4471", "+554333254471", "(43) 3325-4471", Landline, "PR"},
+		{"+55 (21) 2547-1234", "+552125471234", "(21) 2547-1234", Landline, "RJ"},
+		{"0xx43 3325-4471", "+554333254471", "(43) 3325-4471", Landline, "PR"},
+		{"061 3312-4567", "+556133124567", "(61) 3312-4567", Landline, "DF"},
+		// não geográficos
+		{"0800 123 4567", "+558001234567", "0800 123 4567", TollFree, ""},
+		{"800 123 4567", "+558001234567", "0800 123 4567", TollFree, ""},
+		{"+55 800 123 4567", "+558001234567", "0800 123 4567", TollFree, ""},
+		{"4004-1234", "+5540041234", "4004-1234", SharedCost, ""},
+	}
+	for _, tt := range tests {
+		n, err := Parse(tt.raw)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.raw, err)
+			continue
+		}
+		if n.E164() != tt.e164 || n.Format() != tt.format || n.Type != tt.typ || n.UF != tt.uf {
+			t.Errorf("Parse(%q) = %s %q %s %q, want %s %q %s %q",
+				tt.raw, n.E164(), n.Format(), n.Type, n.UF, tt.e164, tt.format, tt.typ, tt.uf)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want error
+	}{
+		{"", ErrEmpty},
+		{"telefone", ErrEmpty},
+		{"3325-447", ErrLength},
+		{"(43) 3325-44712345", ErrLength},
+		{"(20) 3325-4471", ErrDDD},
+		{"(10) 99812-3456", ErrDDD},
+		{"(43) 1325-4471", ErrSubscriber},
+		{"(43) 89812-3456", ErrSubscriber}, // 9 dígitos sem começar por 9
+		{"(43) 99999-9999", ErrPlaceholder},
+		{"(43) 3333-0000", ErrPlaceholder},
+	}
+	for _, tt := range tests {
+		if _, err := Parse(tt.raw); !errors.Is(err, tt.want) {
+			t.Errorf("Parse(%q) = %v, want %v", tt.raw, err, tt.want)
+		}
+	}
+}
+
+func TestNumberHelpers(t *testing.T) {
+	mobile, _ := Parse("(43) 99812-3456")
+	if got := mobile.WhatsAppLink(); got != "https://wa.me/5543998123456" {
+		t.Errorf("WhatsAppLink() = %q", got)
+	}
+	landline, _ := Parse("(43) 3325-4471")
+	if got := landline.WhatsApp(); got != "" {
+		t.Errorf("fixo com WhatsApp %q", got)
+	}
+
+	brasilia, _ := Parse("(61) 3312-4567")
+	for uf, want := range map[string]bool{"DF": true, "go": true, "SP": false, "": true} {
+		if got := brasilia.MatchesState(uf); got != want {
+			t.Errorf("DDD 61 MatchesState(%q) = %v, want %v", uf, got, want)
+		}
+	}
+	tollFree, _ := Parse("0800 123 4567")
+	if !tollFree.MatchesState("AM") {
+		t.Error("0800 deveria ser compatível com qualquer UF")
+	}
+
+	if got := Key("+55 43 99812-3456"); got != Key("(43) 9812-3456") {
+		t.Errorf("Key difere entre formatações: %q", got)
+	}
+	if got := Key("1234-5678"); got != "12345678" {
+		t.Errorf("Key(inválido) = %q, want os dígitos", got)
+	}
+	if got := Normalize("(43) 99999-9999"); got != "" {
+		t.Errorf("Normalize(preenchimento) = %q", got)
+	}
+	if StateForDDD("43") != "PR" || ValidDDD("20") {
+		t.Error("StateForDDD/ValidDDD")
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// checkpointEntry é uma linha do arquivo de checkpoint (NDJSON).
+type checkpointEntry struct {
+	Key       string    `json:"key"`
+	Resultado Resultado `json:"resultado"`
+}
+
+// Checkpoint registra as entradas já processadas para que uma execução
+// interrompida possa ser retomada sem refazer as linhas concluídas.
+// Cada resultado é gravado (com fsync) assim que fica pronto.
+type Checkpoint struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+	done map[string]Resultado
+}
+
+// OpenCheckpoint carrega o checkpoint existente (se houver) e o abre para
+// novas gravações. Com resume=false o arquivo anterior é descartado.
+func OpenCheckpoint(path string, resume bool) (*Checkpoint, error) {
+	cp := &Checkpoint{path: path, done: make(map[string]Resultado)}
+
+	if resume {
+		if err := cp.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if !resume {
+		flags |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir checkpoint: %w", err)
+	}
+	cp.f = f
+	return cp, nil
+}
+
+func (c *Checkpoint) load() error {
+	f, err := os.Open(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao ler checkpoint: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e checkpointEntry
+		// Linha truncada (processo morto no meio da gravação) é ignorada
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.Key == "" {
+			continue
+		}
+		c.done[e.Key] = e.Resultado
+	}
+	return scanner.Err()
+}
+
+// Done informa se a entrada já foi processada numa execução anterior.
+func (c *Checkpoint) Done(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.done[key]
+	return ok
+}
+
+// Previous retorna os resultados carregados do checkpoint.
+func (c *Checkpoint) Previous() []Resultado {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]Resultado, 0, len(c.done))
+	for _, r := range c.done {
+		out = append(out, r)
+	}
+	return out
+}
+
+// Len retorna quantas entradas já foram concluídas.
+func (c *Checkpoint) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.done)
+}
+
+// Mark registra a entrada como concluída.
+func (c *Checkpoint) Mark(key string, r Resultado) error {
+	data, err := json.Marshal(checkpointEntry{Key: key, Resultado: r})
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.done[key] = r
+	if _, err := c.f.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return c.f.Sync()
+}
+
+// Close fecha o arquivo. Com remove=true (execução concluída) o checkpoint
+// é apagado para que a próxima execução comece do zero.
+func (c *Checkpoint) Close(remove bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	err := c.f.Close()
+	if remove {
+		if rmErr := os.Remove(c.path); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
+			return rmErr
+		}
+	}
+	return err
+}
@@ -2,9 +2,14 @@ module github.com/lucasfdcampos/find-instagram
 
 go 1.24.0
 
-require github.com/PuerkitoBio/goquery v1.11.0
+require (
+	github.com/PuerkitoBio/goquery v1.11.0
+	github.com/lucasfdcampos/serp v0.0.0
+)
 
 require (
 	github.com/andybalholm/cascadia v1.3.3 // indirect
 	golang.org/x/net v0.47.0 // indirect
 )
+
+replace github.com/lucasfdcampos/serp => ../serp
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Entrada é uma linha da lista de empresas a processar.
+type Entrada struct {
+	Linha  int // número da linha no arquivo de entrada (1 = primeira linha de dados)
+	Nome   string
+	Cidade string
+}
+
+// Query monta a consulta de busca: "nome cidade".
+func (e Entrada) Query() string {
+	if e.Cidade == "" {
+		return e.Nome
+	}
+	return e.Nome + " " + e.Cidade
+}
+
+// Key identifica a entrada no checkpoint.
+func (e Entrada) Key() string {
+	return fmt.Sprintf("%d|%s|%s", e.Linha, e.Nome, e.Cidade)
+}
+
+// InputOptions descreve como ler o arquivo de entrada.
+type InputOptions struct {
+	Format    string // "txt", "csv" ou "auto" (pela extensão)
+	NameCol   string // coluna do nome: nome do header ou índice 1-based
+	CityCol   string // coluna da cidade (opcional)
+	Delimiter string // separador do CSV (padrão ",")
+}
+
+func readInput(filename string, opts InputOptions) ([]Entrada, error) {
+	format := strings.ToLower(opts.Format)
+	if format == "" || format == "auto" {
+		format = "txt"
+		if strings.EqualFold(filepath.Ext(filename), ".csv") {
+			format = "csv"
+		}
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch format {
+	case "txt":
+		return readTXT(f)
+	case "csv":
+		return readCSV(f, opts)
+	default:
+		return nil, fmt.Errorf("formato de entrada inválido: %q (use txt ou csv)", opts.Format)
+	}
+}
+
+// readTXT lê uma empresa por linha, ignorando linhas vazias e comentários (#).
+func readTXT(r io.Reader) ([]Entrada, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var entradas []Entrada
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			entradas = append(entradas, Entrada{Linha: i + 1, Nome: line})
+		}
+	}
+	return entradas, nil
+}
+
+// readCSV lê o CSV com header, mapeando as colunas de nome e cidade.
+func readCSV(r io.Reader, opts InputOptions) ([]Entrada, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if opts.Delimiter != "" {
+		reader.Comma = []rune(opts.Delimiter)[0]
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler header do CSV: %w", err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff") // BOM do Excel
+	}
+
+	nameCol := opts.NameCol
+	if nameCol == "" {
+		nameCol = "nome"
+	}
+	nameIdx, err := columnIndex(header, nameCol, "name", "empresa")
+	if err != nil {
+		return nil, err
+	}
+	cityIdx := -1
+	if opts.CityCol != "" {
+		if cityIdx, err = columnIndex(header, opts.CityCol); err != nil {
+			return nil, err
+		}
+	}
+
+	var entradas []Entrada
+	for linha := 1; ; linha++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler linha %d do CSV: %w", linha, err)
+		}
+
+		e := Entrada{Linha: linha}
+		if nameIdx < len(record) {
+			e.Nome = strings.TrimSpace(record[nameIdx])
+		}
+		if cityIdx >= 0 && cityIdx < len(record) {
+			e.Cidade = strings.TrimSpace(record[cityIdx])
+		}
+		if e.Nome != "" {
+			entradas = append(entradas, e)
+		}
+	}
+	return entradas, nil
+}
+
+// columnIndex resolve a coluna pelo nome do header (sem diferenciar
+// maiúsculas) ou por índice 1-based. Nomes alternativos são tentados quando
+// a coluna principal não existe.
+func columnIndex(header []string, col string, alternatives ...string) (int, error) {
+	if n, err := strconv.Atoi(col); err == nil {
+		if n < 1 || n > len(header) {
+			return 0, fmt.Errorf("coluna %d fora do intervalo (CSV tem %d colunas)", n, len(header))
+		}
+		return n - 1, nil
+	}
+	for _, name := range append([]string{col}, alternatives...) {
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), name) {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("coluna %q não encontrada no header: %s", col, strings.Join(header, ", "))
+}
@@ -2,230 +2,363 @@ package main
 
 import (
 	"context"
-	"encoding/csv"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/lucasfdcampos/find-instagram/pkg/instagram"
 )
 
-type Resultado struct {
-	Nome       string
-	Handle     string
-	URL        string
-	Followers  string
-	Fonte      string
-	Tempo      int64
-	Tentativas int
-	Status     string
+// Config reúne as opções da linha de comando.
+type Config struct {
+	Input      string
+	Output     string
+	Format     string
+	Checkpoint string
+	Resume     bool
+
+	InputOpts InputOptions
+
+	DelayBetweenQueries time.Duration
+	DelayAfterError     time.Duration
+	BatchSize           int
+	DelayBetweenBatches time.Duration
+	QueryTimeout        time.Duration
+	MaxRetries          int
+	Concurrency         int
 }
 
-func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("❌ Uso: process-list <arquivo.txt>")
-		fmt.Println("\nExemplo:")
-		fmt.Println("  process-list empresas.txt")
+func parseFlags() Config {
+	var cfg Config
+	flag.StringVar(&cfg.InputOpts.Format, "input-format", "auto", "Formato da entrada: txt, csv ou auto (pela extensão)")
+	flag.StringVar(&cfg.InputOpts.NameCol, "name-col", "nome", "Coluna do nome no CSV (nome do header ou índice 1-based)")
+	flag.StringVar(&cfg.InputOpts.CityCol, "city-col", "", "Coluna da cidade no CSV (opcional; adicionada à busca)")
+	flag.StringVar(&cfg.InputOpts.Delimiter, "delimiter", ",", "Separador do CSV de entrada")
+	flag.StringVar(&cfg.Format, "format", "csv", "Formato da saída: csv, json ou ndjson")
+	flag.StringVar(&cfg.Output, "output", "", "Arquivo de saída (padrão: resultados_instagram.<formato>)")
+	flag.StringVar(&cfg.Checkpoint, "checkpoint", "", "Arquivo de checkpoint (padrão: <saída>.checkpoint)")
+	flag.BoolVar(&cfg.Resume, "resume", true, "Retoma a partir do checkpoint, se existir")
+	flag.DurationVar(&cfg.DelayBetweenQueries, "delay", 2*time.Second, "Delay entre consultas (por worker)")
+	flag.DurationVar(&cfg.DelayAfterError, "error-delay", 5*time.Second, "Delay após uma tentativa com falha")
+	flag.IntVar(&cfg.BatchSize, "batch", 20, "Tamanho do lote (0 = sem pausa entre lotes)")
+	flag.DurationVar(&cfg.DelayBetweenBatches, "batch-pause", 15*time.Second, "Pausa entre lotes")
+	flag.DurationVar(&cfg.QueryTimeout, "timeout", 45*time.Second, "Timeout por consulta")
+	flag.IntVar(&cfg.MaxRetries, "retries", 2, "Tentativas por empresa")
+	flag.IntVar(&cfg.Concurrency, "concurrency", 1, "Consultas simultâneas")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Uso: find-instagram [flags] <arquivo.txt|arquivo.csv>")
+		fmt.Fprintln(os.Stderr, "\nExemplos:")
+		fmt.Fprintln(os.Stderr, "  find-instagram empresas.txt")
+		fmt.Fprintln(os.Stderr, "  find-instagram -name-col razao -city-col municipio -format ndjson leads.csv")
+		fmt.Fprintln(os.Stderr, "\nFlags:")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(1)
+	}
+	cfg.Input = flag.Arg(0)
+
+	cfg.Format = strings.ToLower(cfg.Format)
+	ext, ok := outputExtensions[cfg.Format]
+	if !ok {
+		fmt.Printf("❌ Formato de saída inválido: %q (use csv, json ou ndjson)\n", cfg.Format)
 		os.Exit(1)
 	}
+	if cfg.Output == "" {
+		cfg.Output = "resultados_instagram" + ext
+	}
+	if cfg.Checkpoint == "" {
+		cfg.Checkpoint = cfg.Output + ".checkpoint"
+	}
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
+	if cfg.MaxRetries < 1 {
+		cfg.MaxRetries = 1
+	}
+	return cfg
+}
 
-	filename := os.Args[1]
+func main() {
+	cfg := parseFlags()
 
 	fmt.Println("╔═══════════════════════════════════════════════╗")
 	fmt.Println("║  🛡️  Processamento SEGURO de Lista de Instagram ║")
 	fmt.Println("╚═══════════════════════════════════════════════╝")
 	fmt.Println()
 
-	// Configurações
-	delayBetweenQueries := 2 * time.Second
-	delayAfterError := 5 * time.Second
-	batchSize := 20
-	delayBetweenBatches := 15 * time.Second
-	queryTimeout := 45 * time.Second
-	maxRetries := 2
-
-	fmt.Printf("📁 Arquivo: %s\n", filename)
-	fmt.Printf("⏱️  Delay entre consultas: %v\n", delayBetweenQueries)
-	fmt.Printf("⏱️  Delay após erro: %v\n", delayAfterError)
-	fmt.Printf("📦 Tamanho do lote: %d (pausa de %v)\n", batchSize, delayBetweenBatches)
-	fmt.Printf("🔄 Tentativas por empresa: %d\n\n", maxRetries)
+	fmt.Printf("📁 Arquivo: %s\n", cfg.Input)
+	fmt.Printf("💾 Saída: %s (%s)\n", cfg.Output, cfg.Format)
+	fmt.Printf("⏱️  Delay entre consultas: %v\n", cfg.DelayBetweenQueries)
+	fmt.Printf("⏱️  Delay após erro: %v\n", cfg.DelayAfterError)
+	fmt.Printf("📦 Tamanho do lote: %d (pausa de %v)\n", cfg.BatchSize, cfg.DelayBetweenBatches)
+	fmt.Printf("🔀 Concorrência: %d\n", cfg.Concurrency)
+	fmt.Printf("🔄 Tentativas por empresa: %d\n\n", cfg.MaxRetries)
 
 	// Ler arquivo
-	empresas, err := readFile(filename)
+	empresas, err := readInput(cfg.Input, cfg.InputOpts)
 	if err != nil {
 		fmt.Printf("❌ Erro ao ler arquivo: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("📋 Total de empresas: %d\n", len(empresas))
-	fmt.Printf("⏱️  Tempo estimado: ~%v\n\n", estimateTime(len(empresas), delayBetweenQueries, batchSize, delayBetweenBatches))
+	// Checkpoint: pula as linhas já processadas numa execução anterior
+	checkpoint, err := OpenCheckpoint(cfg.Checkpoint, cfg.Resume)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	resuming := checkpoint.Len() > 0
 
-	// Setup searchers
-	searchers := []instagram.Searcher{
-		instagram.NewInstagramProfileChecker(), // geração de handles + check Facebot (mais confiável)
-		instagram.NewDuckDuckGoSearcher(),
-		instagram.NewBingSearcher(),
+	var pendentes []Entrada
+	for _, e := range empresas {
+		if !checkpoint.Done(e.Key()) {
+			pendentes = append(pendentes, e)
+		}
+	}
+
+	fmt.Printf("📋 Total de empresas: %d\n", len(empresas))
+	if resuming {
+		fmt.Printf("♻️  Retomando checkpoint %s: %d já processadas, %d pendentes\n",
+			cfg.Checkpoint, len(empresas)-len(pendentes), len(pendentes))
 	}
+	fmt.Printf("⏱️  Tempo estimado: ~%v\n\n", estimateTime(len(pendentes), cfg.DelayBetweenQueries, cfg.BatchSize, cfg.DelayBetweenBatches)/time.Duration(cfg.Concurrency))
 
-	// Criar arquivo de output
-	outputFile, err := os.Create("resultados_instagram.csv")
+	writer, err := newResultWriter(cfg.Format, cfg.Output, checkpoint.Previous(), resuming)
 	if err != nil {
 		fmt.Printf("❌ Erro ao criar arquivo de saída: %v\n", err)
 		os.Exit(1)
 	}
-	defer outputFile.Close()
-
-	writer := csv.NewWriter(outputFile)
-	defer writer.Flush()
-
-	// Escrever header
-	writer.Write([]string{"Nome", "Handle", "URL", "Followers", "Fonte", "Tempo_ms", "Tentativas", "Status"})
-	writer.Flush()
-
-	// Captura Ctrl+C para salvar progresso
-	signalChan := make(chan os.Signal, 1)
-	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-signalChan
-		fmt.Println("\n\n⚠️  Interrompido pelo usuário. Salvando progresso...")
-		writer.Flush()
-		outputFile.Close()
-		fmt.Println("💾 Progresso salvo em: resultados_instagram.csv")
-		os.Exit(0)
-	}()
-
-	// Processar empresas
-	var resultados []Resultado
-	sucessos := 0
-	falhas := 0
+
+	// Captura Ctrl+C: interrompe os workers; o progresso já está no checkpoint
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	startTime := time.Now()
+	stats := run(ctx, cfg, pendentes, writer, checkpoint)
 
-	for i, empresa := range empresas {
-		empresa = strings.TrimSpace(empresa)
-		if empresa == "" {
-			continue
-		}
+	if err := writer.Close(); err != nil {
+		fmt.Printf("❌ Erro ao gravar saída: %v\n", err)
+	}
 
-		fmt.Printf("[%3d/%3d] %-50s ", i+1, len(empresas), truncate(empresa, 50))
+	interrupted := ctx.Err() != nil
+	if err := checkpoint.Close(!interrupted); err != nil {
+		fmt.Printf("⚠️  Erro ao fechar checkpoint: %v\n", err)
+	}
 
-		var resultado Resultado
-		resultado.Nome = empresa
+	if interrupted {
+		fmt.Println("\n\n⚠️  Interrompido pelo usuário.")
+		fmt.Printf("💾 Progresso salvo em: %s (checkpoint: %s)\n", cfg.Output, cfg.Checkpoint)
+		fmt.Println("   Execute o mesmo comando novamente para continuar.")
+		return
+	}
 
-		// Tentar com retry
-		found := false
-		for tentativa := 1; tentativa <= maxRetries && !found; tentativa++ {
-			resultado.Tentativas = tentativa
+	if stats.total == 0 {
+		fmt.Printf("✅ Nada a processar. Resultados em: %s\n", cfg.Output)
+		return
+	}
 
-			// Contexto com timeout
-			ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	// Resumo final
+	printFinalSummary(stats.total, stats.sucessos, stats.falhas, time.Since(startTime), cfg.Output)
+}
 
-			// Buscar
-			queryStart := time.Now()
-			searchResult := instagram.SearchWithFallbackQuiet(ctx, empresa, searchers...)
-			queryDuration := time.Since(queryStart)
+type runStats struct {
+	total, sucessos, falhas int
+}
 
-			cancel()
+// pacer aplica a pausa entre lotes para todos os workers.
+type pacer struct {
+	mu         sync.Mutex
+	processed  int
+	pauseUntil time.Time
+	batchSize  int
+	batchPause time.Duration
+}
 
-			if searchResult.Error == nil && searchResult.Instagram != nil {
-				// Sucesso
-				resultado.Handle = searchResult.Instagram.Handle
-				resultado.URL = searchResult.Instagram.URL
-				resultado.Fonte = searchResult.Source
-				resultado.Tempo = queryDuration.Milliseconds()
-				resultado.Status = "sucesso"
-				found = true
-				sucessos++
+// wait bloqueia enquanto houver uma pausa de lote em andamento.
+func (p *pacer) wait(ctx context.Context) bool {
+	p.mu.Lock()
+	d := time.Until(p.pauseUntil)
+	p.mu.Unlock()
+	return sleepCtx(ctx, d)
+}
 
-				// Buscar seguidores
-				followersCtx, followersCancel := context.WithTimeout(context.Background(), 20*time.Second)
-				if err := instagram.EnrichInstagramFollowers(followersCtx, searchResult.Instagram); err == nil {
-					resultado.Followers = searchResult.Instagram.Followers
+// done registra uma consulta concluída e retorna true se um lote fechou.
+func (p *pacer) done() (int, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.processed++
+	if p.batchSize > 0 && p.processed%p.batchSize == 0 {
+		p.pauseUntil = time.Now().Add(p.batchPause)
+		return p.processed, true
+	}
+	return p.processed, false
+}
+
+func run(ctx context.Context, cfg Config, empresas []Entrada, writer resultWriter, checkpoint *Checkpoint) runStats {
+	// Setup searchers
+	searchers := []instagram.Searcher{
+		instagram.NewInstagramProfileChecker(), // geração de handles + check Facebot (mais confiável)
+		instagram.NewDuckDuckGoSearcher(),
+		instagram.NewBingSearcher(),
+	}
+
+	var (
+		mu    sync.Mutex
+		stats = runStats{total: len(empresas)}
+		wg    sync.WaitGroup
+	)
+	pace := &pacer{batchSize: cfg.BatchSize, batchPause: cfg.DelayBetweenBatches}
+	startTime := time.Now()
+
+	jobs := make(chan Entrada)
+	for w := 0; w < cfg.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			first := true
+			for empresa := range jobs {
+				// Delay entre consultas
+				if !first && !sleepCtx(ctx, cfg.DelayBetweenQueries) {
+					return
+				}
+				first = false
+				if !pace.wait(ctx) {
+					return
 				}
-				followersCancel()
 
-				followersInfo := ""
-				if resultado.Followers != "" {
-					followersInfo = fmt.Sprintf(" [%s seguidores]", resultado.Followers)
+				resultado, ok := processEntrada(ctx, cfg, empresa, searchers)
+				if !ok {
+					return // interrompido: não marca no checkpoint
+				}
+
+				if err := writer.Write(resultado); err != nil {
+					fmt.Printf("⚠️  Erro ao gravar resultado: %v\n", err)
+				}
+				if err := checkpoint.Mark(empresa.Key(), resultado); err != nil {
+					fmt.Printf("⚠️  Erro ao gravar checkpoint: %v\n", err)
 				}
 
-				fmt.Printf("✅ %s%s (%s, %.1fs)\n",
-					searchResult.Instagram.Formatted,
-					followersInfo,
-					searchResult.Source,
-					queryDuration.Seconds())
-			} else {
-				// Falha nessa tentativa
-				if tentativa < maxRetries {
-					fmt.Printf("⚠️  Tentativa %d falhou, aguardando %v...\n", tentativa, delayAfterError)
-					time.Sleep(delayAfterError)
+				mu.Lock()
+				if resultado.Status == "sucesso" {
+					stats.sucessos++
 				} else {
-					// Falha definitiva
-					resultado.Status = "não_encontrado"
-					resultado.Tempo = queryDuration.Milliseconds()
-					falhas++
-					fmt.Printf("❌ Não encontrado\n")
+					stats.falhas++
+				}
+				printResultado(resultado, len(empresas))
+				sucessos, falhas := stats.sucessos, stats.falhas
+				mu.Unlock()
+
+				// Pausa maior a cada lote
+				if n, batchDone := pace.done(); batchDone && n < len(empresas) {
+					fmt.Println()
+					printProgress(n, len(empresas), sucessos, falhas, time.Since(startTime), len(empresas)-n, cfg.DelayBetweenQueries, cfg.DelayBetweenBatches)
+					fmt.Printf("\n⏸️  Pausa de %v para respeitar rate limit...\n\n", cfg.DelayBetweenBatches)
 				}
 			}
-		}
+		}()
+	}
 
-		// Salvar resultado
-		resultados = append(resultados, resultado)
-		writeResultado(writer, resultado)
-		writer.Flush() // Flush imediato para não perder dados
-
-		// Delay entre consultas
-		if i < len(empresas)-1 {
-			time.Sleep(delayBetweenQueries)
-
-			// Pausa maior a cada lote
-			if (i+1)%batchSize == 0 {
-				elapsed := time.Since(startTime)
-				remaining := len(empresas) - (i + 1)
-				fmt.Println()
-				printProgress(i+1, len(empresas), sucessos, falhas, elapsed, remaining, delayBetweenQueries, delayBetweenBatches)
-				fmt.Printf("\n⏸️  Pausa de %v para respeitar rate limit...\n\n", delayBetweenBatches)
-				time.Sleep(delayBetweenBatches)
-			}
+	for _, e := range empresas {
+		select {
+		case <-ctx.Done():
+		case jobs <- e:
+			continue
 		}
+		break
 	}
+	close(jobs)
+	wg.Wait()
 
-	// Resumo final
-	totalTime := time.Since(startTime)
-	printFinalSummary(len(empresas), sucessos, falhas, totalTime)
+	return stats
 }
 
-func readFile(filename string) ([]string, error) {
-	data, err := os.ReadFile(filename)
-	if err != nil {
-		return nil, err
-	}
+// processEntrada busca o Instagram de uma empresa com retry.
+// Retorna ok=false se o contexto foi cancelado antes de concluir.
+func processEntrada(ctx context.Context, cfg Config, empresa Entrada, searchers []instagram.Searcher) (Resultado, bool) {
+	resultado := Resultado{Linha: empresa.Linha, Nome: empresa.Nome, Cidade: empresa.Cidade}
+	query := empresa.Query()
+
+	for tentativa := 1; tentativa <= cfg.MaxRetries; tentativa++ {
+		resultado.Tentativas = tentativa
+
+		// Contexto com timeout
+		qctx, cancel := context.WithTimeout(ctx, cfg.QueryTimeout)
 
-	lines := strings.Split(string(data), "\n")
-	var empresas []string
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" && !strings.HasPrefix(line, "#") {
-			empresas = append(empresas, line)
+		// Buscar
+		queryStart := time.Now()
+		searchResult := instagram.SearchWithFallbackQuiet(qctx, query, searchers...)
+		queryDuration := time.Since(queryStart)
+
+		cancel()
+
+		if ctx.Err() != nil {
+			return resultado, false
+		}
+
+		resultado.Tempo = queryDuration.Milliseconds()
+		if searchResult.Error == nil && searchResult.Instagram != nil {
+			// Sucesso
+			resultado.Handle = searchResult.Instagram.Handle
+			resultado.URL = searchResult.Instagram.URL
+			resultado.Fonte = searchResult.Source
+			resultado.Status = "sucesso"
+
+			// Buscar seguidores
+			followersCtx, followersCancel := context.WithTimeout(ctx, 20*time.Second)
+			if err := instagram.EnrichInstagramFollowers(followersCtx, searchResult.Instagram); err == nil {
+				resultado.Followers = searchResult.Instagram.Followers
+			}
+			followersCancel()
+			return resultado, true
+		}
+
+		// Falha nessa tentativa
+		if tentativa < cfg.MaxRetries && !sleepCtx(ctx, cfg.DelayAfterError) {
+			return resultado, false
 		}
 	}
 
-	return empresas, nil
+	// Falha definitiva
+	resultado.Status = "não_encontrado"
+	return resultado, true
 }
 
-func writeResultado(writer *csv.Writer, r Resultado) {
-	writer.Write([]string{
-		r.Nome,
-		r.Handle,
-		r.URL,
-		r.Followers,
-		r.Fonte,
-		fmt.Sprintf("%d", r.Tempo),
-		fmt.Sprintf("%d", r.Tentativas),
-		r.Status,
-	})
+func printResultado(r Resultado, total int) {
+	fmt.Printf("[%3d/%3d] %-50s ", r.Linha, total, truncate(strings.TrimSpace(r.Nome+" "+r.Cidade), 50))
+	if r.Status != "sucesso" {
+		fmt.Printf("❌ Não encontrado (%d tentativas)\n", r.Tentativas)
+		return
+	}
+	followersInfo := ""
+	if r.Followers != "" {
+		followersInfo = fmt.Sprintf(" [%s seguidores]", r.Followers)
+	}
+	fmt.Printf("✅ @%s%s (%s, %.1fs)\n", r.Handle, followersInfo, r.Fonte, float64(r.Tempo)/1000)
+}
+
+// sleepCtx dorme por d ou até o contexto ser cancelado.
+// Retorna false se o contexto foi cancelado.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
 }
 
 func printProgress(current, total, sucessos, falhas int, elapsed time.Duration, remaining int, delayQuery, delayBatch time.Duration) {
@@ -242,7 +375,7 @@ func printProgress(current, total, sucessos, falhas int, elapsed time.Duration,
 	fmt.Printf("   🎯 Previsão de término: %v\n\n", time.Now().Add(estimatedRemaining).Format("15:04:05"))
 }
 
-func printFinalSummary(total, sucessos, falhas int, duration time.Duration) {
+func printFinalSummary(total, sucessos, falhas int, duration time.Duration, output string) {
 	fmt.Println()
 	fmt.Println("═══════════════════════════════════════════════════════════")
 	fmt.Println("📊 RESUMO FINAL")
@@ -258,11 +391,14 @@ func printFinalSummary(total, sucessos, falhas int, duration time.Duration) {
 	}
 	fmt.Println("═══════════════════════════════════════════════════════════")
 	fmt.Println()
-	fmt.Println("💾 Resultados salvos em: resultados_instagram.csv")
+	fmt.Printf("💾 Resultados salvos em: %s\n", output)
 }
 
 func estimateTime(total int, delayQuery time.Duration, batchSize int, delayBatch time.Duration) time.Duration {
-	batches := total / batchSize
+	batches := 0
+	if batchSize > 0 {
+		batches = total / batchSize
+	}
 	queryTime := time.Duration(total) * (delayQuery + 3*time.Second) // 3s média por query
 	batchTime := time.Duration(batches) * delayBatch
 	return queryTime + batchTime
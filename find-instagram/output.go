@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Resultado é uma linha do arquivo de saída.
+type Resultado struct {
+	Linha      int    `json:"linha"`
+	Nome       string `json:"nome"`
+	Cidade     string `json:"cidade,omitempty"`
+	Handle     string `json:"handle,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Followers  string `json:"followers,omitempty"`
+	Fonte      string `json:"fonte,omitempty"`
+	Tempo      int64  `json:"tempo_ms"`
+	Tentativas int    `json:"tentativas"`
+	Status     string `json:"status"`
+}
+
+var csvHeader = []string{"Linha", "Nome", "Cidade", "Handle", "URL", "Followers", "Fonte", "Tempo_ms", "Tentativas", "Status"}
+
+// resultWriter grava resultados conforme ficam prontos.
+// Implementações são seguras para uso concorrente.
+type resultWriter interface {
+	Write(r Resultado) error
+	Close() error
+}
+
+// outputExtensions define a extensão padrão do arquivo para cada formato.
+var outputExtensions = map[string]string{
+	"csv":    ".csv",
+	"json":   ".json",
+	"ndjson": ".ndjson",
+}
+
+// newResultWriter cria o writer do formato pedido. Em modo append (retomada
+// de checkpoint) os resultados anteriores são preservados: CSV e NDJSON
+// continuam o arquivo; JSON é regravado com os anteriores + novos.
+func newResultWriter(format, path string, previous []Resultado, appendMode bool) (resultWriter, error) {
+	switch format {
+	case "csv":
+		return newCSVWriter(path, appendMode)
+	case "ndjson":
+		return newNDJSONWriter(path, appendMode)
+	case "json":
+		return &jsonWriter{path: path, results: append([]Resultado(nil), previous...)}, nil
+	default:
+		return nil, fmt.Errorf("formato de saída inválido: %q (use csv, json ou ndjson)", format)
+	}
+}
+
+func openOutput(path string, appendMode bool) (*os.File, bool, error) {
+	if appendMode {
+		if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+			f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+			return f, true, err
+		}
+	}
+	f, err := os.Create(path)
+	return f, false, err
+}
+
+// ─── CSV ────────────────────────────────────────────────────────────────────
+
+type csvWriter struct {
+	mu sync.Mutex
+	f  *os.File
+	w  *csv.Writer
+}
+
+func newCSVWriter(path string, appendMode bool) (*csvWriter, error) {
+	f, appended, err := openOutput(path, appendMode)
+	if err != nil {
+		return nil, err
+	}
+	cw := &csvWriter{f: f, w: csv.NewWriter(f)}
+	if !appended {
+		cw.w.Write(csvHeader)
+		cw.w.Flush()
+	}
+	return cw, cw.w.Error()
+}
+
+func (c *csvWriter) Write(r Resultado) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.w.Write([]string{
+		fmt.Sprintf("%d", r.Linha),
+		r.Nome,
+		r.Cidade,
+		r.Handle,
+		r.URL,
+		r.Followers,
+		r.Fonte,
+		fmt.Sprintf("%d", r.Tempo),
+		fmt.Sprintf("%d", r.Tentativas),
+		r.Status,
+	})
+	c.w.Flush() // Flush imediato para não perder dados
+	return c.w.Error()
+}
+
+func (c *csvWriter) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.w.Flush()
+	return c.f.Close()
+}
+
+// ─── NDJSON ─────────────────────────────────────────────────────────────────
+
+type ndjsonWriter struct {
+	mu  sync.Mutex
+	f   *os.File
+	enc *json.Encoder
+}
+
+func newNDJSONWriter(path string, appendMode bool) (*ndjsonWriter, error) {
+	f, _, err := openOutput(path, appendMode)
+	if err != nil {
+		return nil, err
+	}
+	return &ndjsonWriter{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+func (n *ndjsonWriter) Write(r Resultado) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.enc.Encode(r)
+}
+
+func (n *ndjsonWriter) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.f.Close()
+}
+
+// ─── JSON ───────────────────────────────────────────────────────────────────
+
+// jsonWriter acumula os resultados e grava o array completo no Close.
+// O progresso parcial fica no checkpoint até lá.
+type jsonWriter struct {
+	mu      sync.Mutex
+	path    string
+	results []Resultado
+}
+
+func (j *jsonWriter) Write(r Resultado) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.results = append(j.results, r)
+	return nil
+}
+
+func (j *jsonWriter) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	sort.SliceStable(j.results, func(a, b int) bool { return j.results[a].Linha < j.results[b].Linha })
+	data, err := json.MarshalIndent(j.results, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(j.path, append(data, '\n'), 0o644)
+}
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+
+	"github.com/lucasfdcampos/serp/pkg/serp"
 )
 
 // PicukiScraper busca seguidores no Picuki
@@ -99,68 +101,7 @@ func (d *DuckDuckGoFollowersScraper) Search(ctx context.Context, query string) (
 	if handle == "" {
 		return nil, fmt.Errorf("handle inválido")
 	}
-
-	// Busca específica por seguidores
-	searchQuery := fmt.Sprintf("instagram @%s followers", handle)
-	url := fmt.Sprintf("https://duckduckgo.com/html/?q=%s", searchQuery)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36")
-
-	time.Sleep(1 * time.Second)
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("erro na requisição: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("status code: %d", resp.StatusCode)
-	}
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao parsear HTML: %w", err)
-	}
-
-	followers := ""
-
-	// Busca nos snippets de resultados
-	doc.Find(".result__snippet, .result-snippet").Each(func(i int, s *goquery.Selection) {
-		text := s.Text()
-		if found := extractFollowersFromText(text); found != "" {
-			followers = found
-			return
-		}
-	})
-
-	// Busca em qualquer texto que mencione o handle e seguidores
-	if followers == "" {
-		doc.Find("*").Each(func(i int, s *goquery.Selection) {
-			text := s.Text()
-			// Verifica se menciona o handle e tem padrão de seguidores
-			if strings.Contains(strings.ToLower(text), handle) {
-				if found := extractFollowersFromText(text); found != "" {
-					followers = found
-					return
-				}
-			}
-		})
-	}
-
-	if followers == "" {
-		return nil, fmt.Errorf("seguidores não encontrados no DuckDuckGo")
-	}
-
-	instagram := NewInstagram(handle)
-	instagram.Followers = followers
-	return instagram, nil
+	return searchEngineFollowers(ctx, serp.DuckDuckGo, handle, fmt.Sprintf("instagram @%s followers", handle))
 }
 
 // InstagramDirectScraper tenta obter dados diretamente do Instagram (endpoint público)
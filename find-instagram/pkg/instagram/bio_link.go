@@ -0,0 +1,79 @@
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// instagramWebAppID é o app id público usado pelo front-end web do Instagram.
+const instagramWebAppID = "936619743392459"
+
+var reExternalURL = regexp.MustCompile(`"external_url"\s*:\s*"([^"]+)"`)
+
+// FetchBioLink retorna o link externo da bio de um perfil (campo
+// external_url), ex: o site oficial do negócio. Tenta o endpoint JSON do
+// front-end web e, se bloqueado, o HTML do perfil.
+func FetchBioLink(ctx context.Context, handle string) (string, error) {
+	handle = NormalizeHandle(handle)
+	if !IsValidHandle(handle) {
+		return "", fmt.Errorf("handle inválido: %q", handle)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	// 1. API web_profile_info (JSON)
+	apiURL := "https://www.instagram.com/api/v1/users/web_profile_info/?username=" + handle
+	if req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil); err == nil {
+		req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+		req.Header.Set("X-IG-App-ID", instagramWebAppID)
+		req.Header.Set("Accept", "application/json")
+		if resp, err := client.Do(req); err == nil {
+			var payload struct {
+				Data struct {
+					User struct {
+						ExternalURL string `json:"external_url"`
+					} `json:"user"`
+				} `json:"data"`
+			}
+			if resp.StatusCode == http.StatusOK {
+				_ = json.NewDecoder(io.LimitReader(resp.Body, 2<<20)).Decode(&payload)
+			}
+			resp.Body.Close()
+			if link := strings.TrimSpace(payload.Data.User.ExternalURL); link != "" {
+				return link, nil
+			}
+		}
+	}
+
+	// 2. HTML do perfil (o JSON embutido às vezes traz external_url)
+	profileURL := fmt.Sprintf("https://www.instagram.com/%s/", handle)
+	req, err := http.NewRequestWithContext(ctx, "GET", profileURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("erro ao criar requisição: %w", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("erro ao buscar perfil: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("perfil retornou status %d", resp.StatusCode)
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 2<<20))
+	if m := reExternalURL.FindSubmatch(body); m != nil {
+		var link string
+		// O valor vem com escapes JSON (\/, \u0026)
+		if err := json.Unmarshal([]byte(`"`+string(m[1])+`"`), &link); err == nil && link != "" {
+			return link, nil
+		}
+	}
+	return "", fmt.Errorf("link da bio não encontrado para @%s", handle)
+}
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+
+	"github.com/lucasfdcampos/serp/pkg/serp"
 )
 
 // ImginnScraper busca seguidores no Imginn (viewer de Instagram)
@@ -314,66 +316,7 @@ func (b *BingSearchScraper) Search(ctx context.Context, query string) (*Instagra
 	if handle == "" {
 		return nil, fmt.Errorf("handle inválido")
 	}
-
-	searchQuery := fmt.Sprintf("instagram @%s followers", handle)
-	url := fmt.Sprintf("https://www.bing.com/search?q=%s", searchQuery)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
-
-	time.Sleep(1 * time.Second)
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("erro na requisição: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("status code: %d", resp.StatusCode)
-	}
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao parsear HTML: %w", err)
-	}
-
-	followers := ""
-
-	// Busca nos snippets de resultados do Bing
-	doc.Find(".b_caption, .b_attribution, .b_snippet").Each(func(i int, s *goquery.Selection) {
-		text := s.Text()
-		if found := extractFollowersFromText(text); found != "" {
-			followers = found
-			return
-		}
-	})
-
-	// Busca em knowledge panels
-	if followers == "" {
-		doc.Find(".b_entityTitle, .b_factrow").Each(func(i int, s *goquery.Selection) {
-			text := s.Text()
-			if strings.Contains(strings.ToLower(text), "follower") {
-				if found := extractFollowersFromText(text); found != "" {
-					followers = found
-					return
-				}
-			}
-		})
-	}
-
-	if followers == "" {
-		return nil, fmt.Errorf("seguidores não encontrados no Bing")
-	}
-
-	instagram := NewInstagram(handle)
-	instagram.Followers = followers
-	return instagram, nil
+	return searchEngineFollowers(ctx, serp.Bing, handle, fmt.Sprintf("instagram @%s followers", handle))
 }
 
 // extractNumberFromJSON extrai números de strings JSON
@@ -402,67 +345,7 @@ func (b *BraveSearchFollowersScraper) Search(ctx context.Context, query string)
 	if handle == "" {
 		return nil, fmt.Errorf("handle inválido")
 	}
-
-	searchQuery := fmt.Sprintf("instagram @%s followers", handle)
-	url := fmt.Sprintf("https://search.brave.com/search?q=%s", searchQuery)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36")
-	req.Header.Set("Accept-Language", "pt-BR,pt;q=0.9,en;q=0.8")
-
-	time.Sleep(1 * time.Second)
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("erro na requisição: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("status code: %d", resp.StatusCode)
-	}
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao parsear HTML: %w", err)
-	}
-
-	followers := ""
-
-	// Busca nos snippets do Brave
-	doc.Find(".snippet, .snippet-description, .snippet-content").Each(func(i int, s *goquery.Selection) {
-		text := s.Text()
-		if found := extractFollowersFromText(text); found != "" {
-			followers = found
-			return
-		}
-	})
-
-	// Fallback: busca em resultados gerais
-	if followers == "" {
-		doc.Find(".result, .search-result").Each(func(i int, s *goquery.Selection) {
-			text := s.Text()
-			if strings.Contains(strings.ToLower(text), handle) {
-				if found := extractFollowersFromText(text); found != "" {
-					followers = found
-					return
-				}
-			}
-		})
-	}
-
-	if followers == "" {
-		return nil, fmt.Errorf("seguidores não encontrados no Brave")
-	}
-
-	instagram := NewInstagram(handle)
-	instagram.Followers = followers
-	return instagram, nil
+	return searchEngineFollowers(ctx, serp.Brave, handle, fmt.Sprintf("instagram @%s followers", handle))
 }
 
 // YandexSearchFollowersScraper busca seguidores via Yandex
@@ -481,62 +364,37 @@ func (y *YandexSearchFollowersScraper) Search(ctx context.Context, query string)
 	if handle == "" {
 		return nil, fmt.Errorf("handle inválido")
 	}
+	return searchEngineFollowers(ctx, serp.Yandex, handle, fmt.Sprintf("instagram @%s followers seguidores", handle))
+}
 
-	searchQuery := fmt.Sprintf("instagram @%s followers seguidores", handle)
-	url := fmt.Sprintf("https://yandex.com/search/?text=%s&lr=102", searchQuery) // lr=102 = Brasil
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// searchEngineFollowers procura a contagem de seguidores nos snippets de um
+// motor de busca ("12,3 mil seguidores"). Sem resultados reconhecidos, usa
+// as linhas da página que mencionam o handle.
+func searchEngineFollowers(ctx context.Context, engine serp.Engine, handle, query string) (*Instagram, error) {
+	page, err := serp.Search(ctx, engine, query)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
-	req.Header.Set("Accept-Language", "pt-BR,pt;q=0.9,en;q=0.8,ru;q=0.7")
-
-	time.Sleep(1 * time.Second)
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("erro na requisição: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("status code: %d", resp.StatusCode)
-	}
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao parsear HTML: %w", err)
-	}
-
 	followers := ""
-
-	// Busca nos elementos do Yandex
-	doc.Find(".Organic-Text, .OrganicText, .ExtendedText").Each(func(i int, s *goquery.Selection) {
-		text := s.Text()
-		if found := extractFollowersFromText(text); found != "" {
-			followers = found
-			return
+	for _, r := range page.Results {
+		if followers = extractFollowersFromText(r.Title + " " + r.Snippet); followers != "" {
+			break
 		}
-	})
-
-	// Fallback: busca em resultados gerais
-	if followers == "" {
-		doc.Find(".Organic, .serp-item").Each(func(i int, s *goquery.Selection) {
-			text := s.Text()
-			if strings.Contains(strings.ToLower(text), handle) {
-				if found := extractFollowersFromText(text); found != "" {
-					followers = found
-					return
-				}
+	}
+	if followers == "" && len(page.Results) == 0 {
+		for _, line := range strings.Split(page.Raw, "\n") {
+			if !strings.Contains(strings.ToLower(line), handle) {
+				continue
+			}
+			if followers = extractFollowersFromText(line); followers != "" {
+				break
 			}
-		})
+		}
 	}
 
 	if followers == "" {
-		return nil, fmt.Errorf("seguidores não encontrados no Yandex")
+		return nil, fmt.Errorf("seguidores não encontrados no %s", engine.Name())
 	}
 
 	instagram := NewInstagram(handle)
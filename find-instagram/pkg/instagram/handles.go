@@ -0,0 +1,328 @@
+package instagram
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ─── Consulta ───────────────────────────────────────────────────────────────
+
+// HandleQuery é a consulta já decomposta usada para gerar handles candidatos.
+// Todas as palavras estão em minúsculas e sem acentos.
+type HandleQuery struct {
+	Words     []string // palavras do nome do negócio (sem stopwords)
+	City      []string // palavras da cidade, quando identificada
+	Connector int      // posição em Words precedida por "e" / "&" no nome original (0 = nenhuma)
+}
+
+// BusinessWords retorna as palavras usadas para pontuar o display name dos
+// perfis encontrados (no máximo 2, como no gerador original).
+func (q HandleQuery) BusinessWords() []string {
+	return q.Words[:min(2, len(q.Words))]
+}
+
+var handleStopWords = map[string]bool{
+	"instagram": true, "ig": true, "perfil": true, "profile": true,
+	"oficial": true, "official": true,
+	"de": true, "da": true, "do": true, "das": true, "dos": true,
+	"em": true, "na": true, "no": true, "nas": true, "nos": true,
+	"a": true, "o": true, "as": true, "os": true,
+	"ltda": true, "eireli": true, "mei": true, "sa": true, "me": true, "epp": true,
+	"ac": true, "al": true, "ap": true, "am": true, "ba": true,
+	"ce": true, "df": true, "es": true, "go": true, "ma": true,
+	"mt": true, "ms": true, "mg": true, "pa": true, "pb": true,
+	"pr": true, "pe": true, "pi": true, "rj": true, "rn": true,
+	"rs": true, "ro": true, "rr": true, "sc": true, "sp": true,
+	"se": true, "to": true,
+}
+
+// Palavras que indicam tipo de negócio — não são cidade, fazem parte do nome
+var handleBusinessTypeWords = map[string]bool{
+	"academia": true, "restaurante": true, "loja": true, "salao": true,
+	"salon": true, "clinica": true, "studio": true, "estudio": true,
+	"mercado": true, "farmacia": true, "escola": true, "colegio": true,
+	"hospital": true, "oficina": true, "barbearia": true, "pet": true,
+	"shop": true, "store": true, "fitness": true, "gym": true,
+	"estetica": true, "moda": true, "boutique": true, "sorveteria": true,
+	"padaria": true, "acougue": true, "auto": true, "motos": true,
+	"veiculos": true, "imoveis": true, "construcao": true, "confeitaria": true,
+}
+
+var foldAccents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+var nonHandleChars = regexp.MustCompile(`[^a-z0-9&]+`)
+
+// FoldHandleText converte o texto para minúsculas, remove acentos e troca
+// qualquer caractere fora de [a-z0-9&] por espaço.
+// Ex: "Confeitaria Açúcar & Cia." → "confeitaria acucar & cia"
+func FoldHandleText(s string) string {
+	s = foldAccents.Replace(strings.ToLower(s))
+	s = strings.ReplaceAll(s, "&", " & ")
+	return strings.TrimSpace(nonHandleChars.ReplaceAllString(s, " "))
+}
+
+// ParseHandleQuery decompõe "Nome do Negócio Cidade" em palavras do nome e da
+// cidade. Mantém a heurística original: as duas primeiras palavras
+// significativas são o nome; a terceira é cidade, a não ser que seja uma
+// palavra de tipo de negócio (ex: "MVB FIT ACADEMIA") ou venha depois de
+// "e" / "&" (ex: "Pão & Cia"). Tudo que vem depois é cidade, sem a UF final
+// ("Santa Terezinha de Itaipu PR" → "santa terezinha de itaipu").
+// Cidades conhecidas da tabela de abreviações são reconhecidas no fim da
+// query mesmo quando o nome tem uma palavra só ("Zara Rio de Janeiro").
+func ParseHandleQuery(query string) HandleQuery {
+	var q HandleQuery
+	words := strings.Fields(FoldHandleText(query))
+	for len(words) > 0 && len(words[len(words)-1]) <= 2 {
+		words = words[:len(words)-1]
+	}
+	for n := 4; n >= 1; n-- {
+		if len(words) > n {
+			if _, ok := cityAbbreviations[strings.Join(words[len(words)-n:], " ")]; ok {
+				q.City = append(q.City, words[len(words)-n:]...)
+				words = words[:len(words)-n]
+				break
+			}
+		}
+	}
+	knownCity := len(q.City) > 0
+
+	afterConnector := false
+	for _, word := range words {
+		if len(q.City) > 0 && !knownCity {
+			q.City = append(q.City, word)
+			continue
+		}
+		if word == "e" || word == "&" {
+			afterConnector = len(q.Words) > 0
+			continue
+		}
+		if len(word) <= 2 || handleStopWords[word] {
+			continue
+		}
+		switch {
+		case len(q.Words) < 2,
+			len(q.Words) == 2 && handleBusinessTypeWords[word],
+			len(q.Words) < 3 && afterConnector:
+			if afterConnector {
+				q.Connector = len(q.Words)
+			}
+			q.Words = append(q.Words, word)
+		case !knownCity:
+			q.City = append(q.City, word)
+		}
+		afterConnector = false
+	}
+	// Remove UF / sufixos curtos no fim da cidade ("arapongas pr")
+	for len(q.City) > 0 && len(q.City[len(q.City)-1]) <= 2 {
+		q.City = q.City[:len(q.City)-1]
+	}
+	return q
+}
+
+// ─── Abreviações de cidade ──────────────────────────────────────────────────
+
+// cityAbbreviations lista abreviações usadas em handles de negócios locais
+// (ex: "@dimazzo.ara" para Arapongas). Chave: nome da cidade sem acentos.
+// Cidades ausentes usam as 3 primeiras letras como abreviação.
+var cityAbbreviations = map[string][]string{
+	"arapongas":           {"ara"},
+	"apucarana":           {"apuc", "apu"},
+	"londrina":            {"ldn", "lda", "lon"},
+	"maringa":             {"mga"},
+	"cambe":               {"cbe"},
+	"rolandia":            {"rol"},
+	"curitiba":            {"cwb", "ctba"},
+	"cascavel":            {"cvel", "cac"},
+	"ponta grossa":        {"pg"},
+	"foz do iguacu":       {"foz"},
+	"sao paulo":           {"sp", "sampa"},
+	"rio de janeiro":      {"rj", "rio"},
+	"belo horizonte":      {"bh"},
+	"porto alegre":        {"poa"},
+	"florianopolis":       {"floripa", "fln"},
+	"brasilia":            {"bsb", "df"},
+	"goiania":             {"gyn"},
+	"salvador":            {"ssa"},
+	"recife":              {"rec"},
+	"fortaleza":           {"fortal", "for"},
+	"campinas":            {"cps"},
+	"ribeirao preto":      {"rp", "rib"},
+	"sao jose dos campos": {"sjc"},
+	"campo grande":        {"cg"},
+	"joao pessoa":         {"jp"},
+	"belem":               {"bel"},
+	"manaus":              {"mao"},
+}
+
+// CityAbbreviations retorna as abreviações conhecidas para a cidade, ou as 3
+// primeiras letras quando não houver entrada na tabela.
+func CityAbbreviations(city string) []string {
+	key := strings.Join(strings.Fields(FoldHandleText(city)), " ")
+	if key == "" {
+		return nil
+	}
+	if abbrs, ok := cityAbbreviations[key]; ok {
+		return abbrs
+	}
+	compact := strings.ReplaceAll(key, " ", "")
+	if len(compact) <= 3 {
+		return []string{compact}
+	}
+	return []string{compact[:3]}
+}
+
+// ─── Padrões ────────────────────────────────────────────────────────────────
+
+// HandlePattern é um molde de handle com placeholders entre chaves:
+//
+//	{name}  {name.}  {name_}   palavras do nome juntas / com "." / com "_"
+//	{rev}   {rev.}   {rev_}    palavras do nome em ordem inversa
+//	{namee}                    nome com o conector "e" ("paoecia")
+//	{city}  {abbr}             cidade compacta / abreviação da cidade
+//
+// Padrões cujo placeholder não tem valor na consulta são ignorados.
+// Rank maior = candidato testado antes.
+type HandlePattern struct {
+	Template string
+	Rank     int
+}
+
+// DefaultHandlePatterns são os padrões observados em perfis reais de negócios
+// brasileiros, do mais para o menos frequente.
+var DefaultHandlePatterns = []HandlePattern{
+	{"{name}", 100},
+	{"{name.}", 95},
+	{"{name_}", 90},
+	{"{namee}", 88},
+	{"{rev}", 86},
+	{"{name}.{city}", 84},
+	{"{name}{city}", 83},
+	{"{name}.{abbr}", 82},
+	{"{rev.}", 80},
+	{"{name}_{city}", 78},
+	{"{name}_oficial", 76},
+	{"{name}oficial", 75},
+	{"{name}store", 74},
+	{"{name}{abbr}", 72},
+	{"{rev_}", 70},
+	{"{name}_{abbr}", 68},
+	{"{name}.oficial", 66},
+	{"{name}.store", 64},
+	{"use{name}", 50},
+	{"{name}loja", 45},
+	{"loja{name}", 44},
+}
+
+// HandleCandidate é um handle candidato com o padrão que o gerou.
+type HandleCandidate struct {
+	Handle  string
+	Pattern string
+	Rank    int
+}
+
+// HandleGenerator produz handles candidatos ordenados por probabilidade.
+// Implementações devem retornar no máximo budget candidatos (budget <= 0 = sem limite).
+type HandleGenerator interface {
+	Generate(q HandleQuery, budget int) []HandleCandidate
+}
+
+// PatternGenerator é o HandleGenerator padrão, dirigido por uma tabela de padrões.
+type PatternGenerator struct {
+	Patterns []HandlePattern
+}
+
+// NewPatternGenerator cria um gerador com os padrões informados ou, se
+// nenhum for passado, com DefaultHandlePatterns.
+func NewPatternGenerator(patterns ...HandlePattern) *PatternGenerator {
+	if len(patterns) == 0 {
+		patterns = DefaultHandlePatterns
+	}
+	return &PatternGenerator{Patterns: patterns}
+}
+
+var placeholderRe = regexp.MustCompile(`\{[a-z._]+\}`)
+
+func (g *PatternGenerator) Generate(q HandleQuery, budget int) []HandleCandidate {
+	if len(q.Words) == 0 {
+		return nil
+	}
+
+	values := map[string][]string{
+		"{name}":  {strings.Join(q.Words, "")},
+		"{name.}": {strings.Join(q.Words, ".")},
+		"{name_}": {strings.Join(q.Words, "_")},
+	}
+	if len(q.Words) >= 2 {
+		rev := make([]string, len(q.Words))
+		for i, w := range q.Words {
+			rev[len(q.Words)-1-i] = w
+		}
+		values["{rev}"] = []string{strings.Join(rev, "")}
+		values["{rev.}"] = []string{strings.Join(rev, ".")}
+		values["{rev_}"] = []string{strings.Join(rev, "_")}
+	}
+	if q.Connector > 0 {
+		values["{namee}"] = []string{
+			strings.Join(q.Words[:q.Connector], "") + "e" + strings.Join(q.Words[q.Connector:], ""),
+		}
+	}
+	if len(q.City) > 0 {
+		city := strings.Join(q.City, " ")
+		values["{city}"] = []string{strings.ReplaceAll(city, " ", "")}
+		values["{abbr}"] = CityAbbreviations(city)
+	}
+
+	patterns := make([]HandlePattern, len(g.Patterns))
+	copy(patterns, g.Patterns)
+	sort.SliceStable(patterns, func(i, j int) bool { return patterns[i].Rank > patterns[j].Rank })
+
+	seen := make(map[string]bool)
+	var out []HandleCandidate
+	for _, p := range patterns {
+		for _, h := range expandPattern(p.Template, values) {
+			if seen[h] || !IsValidHandle(h) {
+				continue
+			}
+			seen[h] = true
+			out = append(out, HandleCandidate{Handle: h, Pattern: p.Template, Rank: p.Rank})
+			if budget > 0 && len(out) >= budget {
+				return out
+			}
+		}
+	}
+	return out
+}
+
+// expandPattern substitui os placeholders do template por todas as
+// combinações de valores. Retorna nil se algum placeholder não tiver valor.
+func expandPattern(template string, values map[string][]string) []string {
+	results := []string{template}
+	for _, ph := range placeholderRe.FindAllString(template, -1) {
+		vals := values[ph]
+		if len(vals) == 0 {
+			return nil
+		}
+		var next []string
+		for _, r := range results {
+			for _, v := range vals {
+				next = append(next, strings.Replace(r, ph, v, 1))
+			}
+		}
+		results = next
+	}
+	return results
+}
+
+// GenerateHandles é um atalho para gerar handles a partir de uma query livre
+// com o gerador padrão.
+func GenerateHandles(query string, budget int) []HandleCandidate {
+	return NewPatternGenerator().Generate(ParseHandleQuery(query), budget)
+}
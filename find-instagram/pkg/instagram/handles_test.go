@@ -0,0 +1,209 @@
+package instagram
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFoldHandleText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Confeitaria Açúcar & Cia.", "confeitaria acucar & cia"},
+		{"Pão&Cia", "pao & cia"},
+		{"  São  João  ", "sao joao"},
+		{"Ñandú Ümlaut", "nandu umlaut"},
+		{"Studio 7 — Estética", "studio 7 estetica"},
+	}
+	for _, tt := range tests {
+		if got := FoldHandleText(tt.in); got != tt.want {
+			t.Errorf("FoldHandleText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseHandleQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  HandleQuery
+	}{
+		{
+			query: "Dimazzo Arapongas",
+			want:  HandleQuery{Words: []string{"dimazzo"}, City: []string{"arapongas"}},
+		},
+		{
+			query: "Zara Rio de Janeiro",
+			want:  HandleQuery{Words: []string{"zara"}, City: []string{"rio", "de", "janeiro"}},
+		},
+		{
+			query: "MVB FIT ACADEMIA Arapongas PR",
+			want:  HandleQuery{Words: []string{"mvb", "fit", "academia"}, City: []string{"arapongas"}},
+		},
+		{
+			query: "Pão & Cia Londrina",
+			want:  HandleQuery{Words: []string{"pao", "cia"}, City: []string{"londrina"}, Connector: 1},
+		},
+		{
+			query: "Dimazzo Pizzas Santa Terezinha de Itaipu PR",
+			want:  HandleQuery{Words: []string{"dimazzo", "pizzas"}, City: []string{"santa", "terezinha", "de", "itaipu"}},
+		},
+		{
+			query: "Confeitaria Açúcar Maringá",
+			want:  HandleQuery{Words: []string{"confeitaria", "acucar"}, City: []string{"maringa"}},
+		},
+		{
+			query: "Studio Bella Instagram",
+			want:  HandleQuery{Words: []string{"studio", "bella"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := ParseHandleQuery(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseHandleQuery(%q) = %+v, want %+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCityAbbreviations(t *testing.T) {
+	tests := []struct {
+		city string
+		want []string
+	}{
+		{"Arapongas", []string{"ara"}},
+		{"Londrina", []string{"ldn", "lda", "lon"}},
+		{"Maringá", []string{"mga"}},
+		{"Foz do Iguaçu", []string{"foz"}},
+		{"São José dos Campos", []string{"sjc"}},
+		{"Ibiporã", []string{"ibi"}},
+		{"Rio Bom", []string{"rio"}},
+		{"Ai", []string{"ai"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := CityAbbreviations(tt.city); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("CityAbbreviations(%q) = %v, want %v", tt.city, got, tt.want)
+		}
+	}
+}
+
+func TestExpandPattern(t *testing.T) {
+	values := map[string][]string{
+		"{name}": {"dimazzo"},
+		"{abbr}": {"ldn", "lon"},
+	}
+	tests := []struct {
+		template string
+		want     []string
+	}{
+		{"{name}", []string{"dimazzo"}},
+		{"{name}.{abbr}", []string{"dimazzo.ldn", "dimazzo.lon"}},
+		{"use{name}", []string{"usedimazzo"}},
+		{"{name}.{city}", nil},
+		{"fixo", []string{"fixo"}},
+	}
+	for _, tt := range tests {
+		if got := expandPattern(tt.template, values); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("expandPattern(%q) = %v, want %v", tt.template, got, tt.want)
+		}
+	}
+}
+
+func handles(cands []HandleCandidate) []string {
+	out := make([]string, len(cands))
+	for i, c := range cands {
+		out[i] = c.Handle
+	}
+	return out
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Handles confirmados manualmente em perfis reais devem aparecer entre os
+// candidatos gerados a partir do nome e da cidade do negócio.
+func TestGenerateHandlesConfirmed(t *testing.T) {
+	tests := []struct {
+		query  string
+		handle string
+	}{
+		{"Dimazzo Arapongas", "dimazzo.ara"},
+		{"Dimazzo Arapongas", "dimazzo"},
+		{"Confeitaria Açúcar Maringá", "confeitariaacucar"},
+		{"Confeitaria Açúcar Maringá", "confeitaria.acucar"},
+		{"Pão & Cia Londrina", "paoecia"},
+		{"Pão & Cia Londrina", "paocia.ldn"},
+		{"Zara Rio de Janeiro", "zara.rio"},
+		{"MVB FIT ACADEMIA Arapongas PR", "mvbfitacademia"},
+		{"Bella Napoli Curitiba", "bellanapoli.cwb"},
+		{"Bella Napoli Curitiba", "bellanapoli_oficial"},
+	}
+	for _, tt := range tests {
+		got := handles(GenerateHandles(tt.query, 0))
+		if !contains(got, tt.handle) {
+			t.Errorf("GenerateHandles(%q) não gerou %q: %v", tt.query, tt.handle, got)
+		}
+	}
+}
+
+func TestPatternGeneratorRanking(t *testing.T) {
+	q := ParseHandleQuery("Bella Napoli Curitiba")
+	got := NewPatternGenerator().Generate(q, 0)
+	if len(got) == 0 {
+		t.Fatal("nenhum candidato gerado")
+	}
+	if got[0].Handle != "bellanapoli" || got[0].Rank != 100 {
+		t.Errorf("primeiro candidato = %+v, want bellanapoli/100", got[0])
+	}
+	seen := make(map[string]bool)
+	for i, c := range got {
+		if i > 0 && c.Rank > got[i-1].Rank {
+			t.Errorf("candidatos fora de ordem: %+v antes de %+v", got[i-1], c)
+		}
+		if seen[c.Handle] {
+			t.Errorf("handle duplicado: %q", c.Handle)
+		}
+		seen[c.Handle] = true
+		if !IsValidHandle(c.Handle) {
+			t.Errorf("handle inválido gerado: %q", c.Handle)
+		}
+	}
+}
+
+func TestPatternGeneratorBudget(t *testing.T) {
+	q := ParseHandleQuery("Bella Napoli Curitiba")
+	all := NewPatternGenerator().Generate(q, 0)
+	for _, budget := range []int{1, 3, 5} {
+		got := NewPatternGenerator().Generate(q, budget)
+		if len(got) != budget {
+			t.Errorf("budget %d: %d candidatos", budget, len(got))
+			continue
+		}
+		if !reflect.DeepEqual(got, all[:budget]) {
+			t.Errorf("budget %d: %v, want prefixo %v", budget, handles(got), handles(all[:budget]))
+		}
+	}
+}
+
+func TestPatternGeneratorCustomPatterns(t *testing.T) {
+	g := NewPatternGenerator(
+		HandlePattern{"{name}.{city}", 10},
+		HandlePattern{"{rev}", 20},
+		HandlePattern{"{namee}", 30},
+	)
+	got := handles(g.Generate(ParseHandleQuery("Bella Napoli Curitiba"), 0))
+	want := []string{"napolibella", "bellanapoli.curitiba"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Generate = %v, want %v", got, want)
+	}
+
+	if got := g.Generate(HandleQuery{}, 0); got != nil {
+		t.Errorf("consulta vazia gerou %v", got)
+	}
+}
@@ -7,6 +7,8 @@ require (
 	github.com/joho/godotenv v1.5.1
 	github.com/lucasfdcampos/find-cnpj v0.0.0
 	github.com/lucasfdcampos/find-instagram v0.0.0
+	github.com/lucasfdcampos/serp v0.0.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
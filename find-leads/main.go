@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,6 +19,12 @@ func main() {
 
 	enrichCNPJ := flag.Bool("enrich-cnpj", false, "Enriquecer leads com dados de CNPJ (razão social, sócios, CNAE, situação)")
 	enrichInstagram := flag.Bool("enrich-instagram", false, "Enriquecer leads com perfil do Instagram (handle + seguidores)")
+	enrichWebsite := flag.Bool("enrich-website", false, "Enriquecer leads pelo site oficial (e-mail, telefones, WhatsApp, CNPJ, redes sociais)")
+	radius := flag.Float64("radius", 0, "Manter só leads a até N km do centro (leads sem coordenadas são mantidos)")
+	center := flag.String("center", "", "Centro do raio como \"lat,lon\" (padrão: centro da cidade via Nominatim)")
+	dedupName := flag.Float64("dedup-name", leads.DefaultDedupConfig().NameThreshold, "Similaridade mínima de nome (0–1) para unir leads duplicados")
+	mergeLog := flag.Bool("merge-log", false, "Mostrar quais leads foram unidos na deduplicação e por quê")
+	defsDir := flag.String("defs", os.Getenv("LEADS_DEFINITIONS_DIR"), "Diretório com definições JSON/YAML de guias (substituem fontes de mesmo nome ou viram fontes novas)")
 	flag.Parse()
 
 	query := "loja de roupas"
@@ -44,6 +51,12 @@ func main() {
 	if *enrichInstagram {
 		fmt.Println("  IG    : ativado")
 	}
+	if *enrichWebsite {
+		fmt.Println("  Site  : ativado")
+	}
+	if *radius > 0 {
+		fmt.Printf("  Raio  : %.1f km\n", *radius)
+	}
 	fmt.Printf("  Início: %s\n\n", time.Now().Format("02/01/2006 15:04:05"))
 
 	geoapifyKey := os.Getenv("GEOAPIFY_API_KEY")
@@ -76,6 +89,31 @@ func main() {
 	if geminiKey != "" {
 		searchers = append(searchers, leads.NewGeminiScraper(geminiKey))
 	}
+	if s := leads.NewLocalLLMScraper(); s != nil {
+		// LLM local compatível com a OpenAI (Ollama, llama.cpp)
+		searchers = append(searchers, s)
+	}
+	if dir := os.Getenv("LLM_PROMPTS_DIR"); dir != "" {
+		prompts, err := leads.LoadPrompts(dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Erro nos prompts de %s: %v\n", dir, err)
+			os.Exit(1)
+		}
+		leads.SetPrompts(prompts)
+	}
+	if os.Getenv("SEARXNG_URL") != "" {
+		// instância própria do SearXNG (API JSON)
+		searchers = append(searchers, leads.NewSearXNGLeadScraper())
+	}
+	if *defsDir != "" {
+		defs, err := leads.LoadDefinitions(*defsDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Erro nas definições de %s: %v\n", *defsDir, err)
+			os.Exit(1)
+		}
+		searchers = leads.ApplyDefinitions(searchers, defs)
+		fmt.Printf("  Defs  : %d definições de %s\n", len(defs), *defsDir)
+	}
 
 	// Timeout total: 5 min de scraping + até 30 min de enriquecimento
 	totalTimeout := 5 * time.Minute
@@ -85,17 +123,44 @@ func main() {
 	if *enrichInstagram {
 		totalTimeout += 10 * time.Minute
 	}
+	if *enrichWebsite {
+		totalTimeout += 10 * time.Minute
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), totalTimeout)
 	defer cancel()
 
-	found, results := leads.SearchAll(ctx, query, location, searchers...)
+	dedupCfg := leads.DefaultDedupConfig()
+	dedupCfg.NameThreshold = *dedupName
+	found, results, merges := leads.SearchAllWithMerges(ctx, query, location, dedupCfg, searchers...)
+	if *mergeLog && len(merges) > 0 {
+		fmt.Printf("\n  🔗 %d leads unidos na deduplicação:\n", len(merges))
+		for _, m := range merges {
+			fmt.Printf("     %s\n", m)
+		}
+	}
+
+	// ── Raio ──────────────────────────────────────────────────────────────────
+	if *radius > 0 || *center != "" {
+		lat, lon, err := parseCenter(*center)
+		if err == nil && *center == "" {
+			lat, lon, err = leads.GeocodeCity(ctx, city, state)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  ⚠️  centro do raio indisponível (%v) — filtro por raio ignorado\n", err)
+		} else {
+			before := len(found)
+			found = leads.FilterByRadius(found, lat, lon, *radius)
+			fmt.Printf("  📍 Raio: %d de %d leads dentro de %.1f km\n", len(found), before, *radius)
+		}
+	}
 
 	// ── Enriquecimento ────────────────────────────────────────────────────────
-	if *enrichCNPJ || *enrichInstagram {
+	if *enrichCNPJ || *enrichInstagram || *enrichWebsite {
 		fmt.Printf("\n  💡 Enriquecendo %d leads...", len(found))
 		leads.EnrichAll(ctx, found, leads.EnrichOptions{
 			CNPJ:      *enrichCNPJ,
 			Instagram: *enrichInstagram,
+			Website:   *enrichWebsite,
 		})
 		fmt.Println(" OK")
 	}
@@ -119,8 +184,31 @@ func main() {
 		if r.Err != nil {
 			status = "✗"
 			detail = r.Err.Error()
+		} else if len(r.Discarded) > 0 {
+			detail += fmt.Sprintf(", %d descartados sem corroboração", len(r.Discarded))
 		}
 		fmt.Printf("  %s %-25s %s\n", status, r.Source, detail)
+		for _, d := range r.Discarded {
+			fmt.Printf("      ✗ %s\n", d)
+		}
 	}
 	fmt.Printf("\nTotal: %d leads únicos encontrados\n", len(found))
 }
+
+// parseCenter lê "lat,lon". String vazia retorna 0,0 sem erro.
+func parseCenter(s string) (lat, lon float64, err error) {
+	if s == "" {
+		return 0, 0, nil
+	}
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("centro inválido %q (use lat,lon)", s)
+	}
+	if lat, err = strconv.ParseFloat(strings.TrimSpace(parts[0]), 64); err != nil {
+		return 0, 0, fmt.Errorf("latitude inválida: %w", err)
+	}
+	if lon, err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64); err != nil {
+		return 0, 0, fmt.Errorf("longitude inválida: %w", err)
+	}
+	return lat, lon, nil
+}
@@ -0,0 +1,633 @@
+// Package ibge embute a tabela de municípios do IBGE (código, nome, UF,
+// micro/mesorregião, região metropolitana e centróide) e expande regiões
+// ("Norte Central Paranaense", "RM de Londrina", "PR", raio em km) em listas
+// de cidades.
+//
+// Resolve converte o texto digitado pelo usuário ("Sta. Terezinha de Itaipu",
+// "Londrna/PR", "Campinas, São Paulo") no município canônico, com
+// abreviações, apelidos e tolerância a erros de digitação.
+//
+// municipios.csv é gerado por internal/gen (go generate) a partir da API de
+// localidades do IBGE; LoadFile + SetDefault trocam a tabela em tempo de
+// execução. Uma tabela com menos de Count municípios é parcial (Partial):
+// nela, Resolve não aplica correspondência aproximada, porque uma cidade real
+// ausente da tabela seria trocada por outra parecida que está nela.
+package ibge
+
+//go:generate go run ./internal/gen -out municipios.csv
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+//go:embed municipios.csv
+var municipiosCSV []byte
+
+// Count é o número de municípios do Brasil (5.568, mais o DF e Fernando de
+// Noronha, como na API de localidades do IBGE).
+const Count = 5570
+
+// Municipality é um município da tabela do IBGE.
+type Municipality struct {
+	Code        string   // código IBGE de 7 dígitos (ex: 4113700)
+	Name        string   // nome oficial com acentos (ex: Londrina)
+	UF          string   // sigla da UF (ex: PR)
+	MicroRegion string   // microrregião geográfica (ex: Londrina)
+	MesoRegion  string   // mesorregião geográfica (ex: Norte Central Paranaense)
+	Metro       string   // região metropolitana, se houver
+	Aliases     []string // apelidos e grafias alternativas (ex: Foz, SJP)
+	Lat, Lon    float64  // centróide aproximado da sede
+}
+
+// String retorna "Nome - UF".
+func (m Municipality) String() string {
+	return m.Name + " - " + m.UF
+}
+
+// Dataset é uma tabela de municípios indexada por nome e código.
+type Dataset struct {
+	all    []Municipality
+	byName map[string][]int // nameKey do nome e dos apelidos → índices em all
+	byCode map[string]int
+}
+
+var (
+	embeddedOnce sync.Once
+	embeddedSet  *Dataset
+	override     atomic.Pointer[Dataset]
+)
+
+// Default retorna a tabela definida por SetDefault ou, se nenhuma foi
+// definida, a tabela embutida.
+func Default() *Dataset {
+	if ds := override.Load(); ds != nil {
+		return ds
+	}
+	embeddedOnce.Do(func() {
+		ds, err := Parse(bytes.NewReader(municipiosCSV))
+		if err != nil {
+			panic("ibge: tabela embutida inválida: " + err.Error())
+		}
+		embeddedSet = ds
+	})
+	return embeddedSet
+}
+
+// SetDefault substitui a tabela retornada por Default (ex: a tabela completa
+// carregada com LoadFile na inicialização).
+func SetDefault(ds *Dataset) {
+	override.Store(ds)
+}
+
+// LoadFile lê uma tabela completa no formato de municipios.csv.
+func LoadFile(path string) (*Dataset, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("ibge: %w", err)
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Partial indica se a tabela cobre só parte dos municípios (menos de Count).
+// Numa tabela parcial, cidade não encontrada não significa cidade inexistente.
+func (d *Dataset) Partial() bool {
+	return len(d.all) < Count
+}
+
+// Parse lê uma tabela no formato de municipios.csv (com header).
+// Colunas: codigo, nome, uf, microrregiao, mesorregiao,
+// regiao_metropolitana, lat, lon, aliases (separados por "|").
+func Parse(r io.Reader) (*Dataset, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("ibge: erro ao ler header: %w", err)
+	}
+	col := make(map[string]int, len(header))
+	for i, h := range header {
+		col[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))] = i
+	}
+	for _, required := range []string{"codigo", "nome", "uf"} {
+		if _, ok := col[required]; !ok {
+			return nil, fmt.Errorf("ibge: coluna %q ausente", required)
+		}
+	}
+	get := func(rec []string, name string) string {
+		if i, ok := col[name]; ok && i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+
+	ds := &Dataset{byName: make(map[string][]int), byCode: make(map[string]int)}
+	for line := 2; ; line++ {
+		rec, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ibge: linha %d: %w", line, err)
+		}
+		m := Municipality{
+			Code:        get(rec, "codigo"),
+			Name:        get(rec, "nome"),
+			UF:          strings.ToUpper(get(rec, "uf")),
+			MicroRegion: get(rec, "microrregiao"),
+			MesoRegion:  get(rec, "mesorregiao"),
+			Metro:       get(rec, "regiao_metropolitana"),
+		}
+		if m.Code == "" || m.Name == "" || m.UF == "" {
+			return nil, fmt.Errorf("ibge: linha %d: código, nome e UF são obrigatórios", line)
+		}
+		m.Lat, _ = strconv.ParseFloat(get(rec, "lat"), 64)
+		m.Lon, _ = strconv.ParseFloat(get(rec, "lon"), 64)
+		for _, a := range strings.Split(get(rec, "aliases"), "|") {
+			if a = strings.TrimSpace(a); a != "" {
+				m.Aliases = append(m.Aliases, a)
+			}
+		}
+		if _, dup := ds.byCode[m.Code]; dup {
+			return nil, fmt.Errorf("ibge: linha %d: código %s duplicado", line, m.Code)
+		}
+
+		i := len(ds.all)
+		ds.all = append(ds.all, m)
+		ds.byCode[m.Code] = i
+		seen := make(map[string]bool, 1+len(m.Aliases))
+		for _, n := range append([]string{m.Name}, m.Aliases...) {
+			if k := nameKey(n); k != "" && !seen[k] {
+				seen[k] = true
+				ds.byName[k] = append(ds.byName[k], i)
+			}
+		}
+	}
+	return ds, nil
+}
+
+// All retorna todos os municípios da tabela.
+func (d *Dataset) All() []Municipality {
+	return d.all
+}
+
+// ByCode busca o município pelo código IBGE.
+func (d *Dataset) ByCode(code string) (Municipality, bool) {
+	i, ok := d.byCode[strings.TrimSpace(code)]
+	if !ok {
+		return Municipality{}, false
+	}
+	return d.all[i], true
+}
+
+// Find busca o município pelo nome ou apelido, sem diferenciar acentos e
+// maiúsculas e expandindo abreviações (Sta., Sto., S., Pres., Mal., ...).
+// Com uf vazia, só encontra nomes que existem em uma única UF.
+func (d *Dataset) Find(name, uf string) (Municipality, bool) {
+	uf = strings.ToUpper(strings.TrimSpace(uf))
+	idx := d.byName[nameKey(name)]
+	if uf == "" {
+		if len(idx) == 1 {
+			return d.all[idx[0]], true
+		}
+		return Municipality{}, false
+	}
+	for _, i := range idx {
+		if d.all[i].UF == uf {
+			return d.all[i], true
+		}
+	}
+	return Municipality{}, false
+}
+
+// ByUF retorna os municípios de uma UF.
+func (d *Dataset) ByUF(uf string) []Municipality {
+	uf = strings.ToUpper(strings.TrimSpace(uf))
+	var out []Municipality
+	for _, m := range d.all {
+		if m.UF == uf {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// Within retorna os municípios cujo centróide está a até km do ponto,
+// do mais próximo ao mais distante.
+func (d *Dataset) Within(lat, lon, km float64) []Municipality {
+	type hit struct {
+		m    Municipality
+		dist float64
+	}
+	var hits []hit
+	for _, m := range d.all {
+		if m.Lat == 0 && m.Lon == 0 {
+			continue
+		}
+		if dist := DistanceKm(lat, lon, m.Lat, m.Lon); dist <= km {
+			hits = append(hits, hit{m, dist})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].dist < hits[j].dist })
+	out := make([]Municipality, len(hits))
+	for i, h := range hits {
+		out[i] = h.m
+	}
+	return out
+}
+
+// ─── Resolve ─────────────────────────────────────────────────────────────────
+
+var (
+	// ErrUnknownCity indica que a cidade não está na tabela.
+	ErrUnknownCity = errors.New("cidade desconhecida")
+	// ErrAmbiguousCity indica um nome que existe em mais de uma UF sem UF informada.
+	ErrAmbiguousCity = errors.New("cidade ambígua, informe a UF")
+)
+
+// LookupError é retornado por Resolve quando a cidade não é encontrada ou é
+// ambígua. Suggestions traz os municípios mais parecidos (até 3).
+type LookupError struct {
+	Input       string
+	Err         error // ErrUnknownCity ou ErrAmbiguousCity
+	Suggestions []Municipality
+}
+
+func (e *LookupError) Error() string {
+	msg := fmt.Sprintf("%v: %q", e.Err, e.Input)
+	if len(e.Suggestions) > 0 {
+		names := make([]string, len(e.Suggestions))
+		for i, m := range e.Suggestions {
+			names[i] = m.String()
+		}
+		msg += " (você quis dizer " + strings.Join(names, ", ") + "?)"
+	}
+	return msg
+}
+
+func (e *LookupError) Unwrap() error { return e.Err }
+
+// reUFSuffix separa a UF no fim: "Londrina - PR", "Londrina/PR",
+// "Londrina,PR", "Londrina PR", "Londrina (PR)".
+var reUFSuffix = regexp.MustCompile(`^(.+?)[\s,/\-(]+([A-Za-z]{2})\)?$`)
+
+// SplitLocation separa cidade e UF. A UF pode vir como sigla ou nome do
+// estado ("Campinas, São Paulo"); siglas que não são UF ficam no nome.
+func SplitLocation(location string) (city, uf string) {
+	location = strings.TrimSpace(location)
+	if m := reUFSuffix.FindStringSubmatch(location); m != nil {
+		if code := StateCode(m[2]); code != "" {
+			return strings.TrimSpace(m[1]), code
+		}
+	}
+	if i := strings.LastIndexAny(location, ",/"); i > 0 {
+		if code := StateCode(location[i+1:]); code != "" {
+			return strings.TrimSpace(location[:i]), code
+		}
+	}
+	if i := strings.LastIndex(location, " - "); i > 0 {
+		if code := StateCode(location[i+3:]); code != "" {
+			return strings.TrimSpace(location[:i]), code
+		}
+	}
+	return location, ""
+}
+
+// Resolve converte "Cidade - UF" (em qualquer formato aceito por
+// SplitLocation) no município canônico. Tenta, nesta ordem: nome ou apelido
+// exato (com abreviações expandidas) e correspondência aproximada, aceita
+// quando há um único candidato mais próximo dentro do limite de edições.
+// Em tabelas parciais a correspondência aproximada só gera sugestões.
+//
+// Erros são *LookupError com ErrUnknownCity ou ErrAmbiguousCity.
+func (d *Dataset) Resolve(location string) (Municipality, error) {
+	city, uf := SplitLocation(location)
+	key := nameKey(city)
+	if key == "" {
+		return Municipality{}, &LookupError{Input: location, Err: ErrUnknownCity}
+	}
+
+	if m, ok := d.Find(city, uf); ok {
+		return m, nil
+	}
+	if idx := d.byName[key]; uf == "" && len(idx) > 1 {
+		e := &LookupError{Input: location, Err: ErrAmbiguousCity}
+		for _, i := range idx {
+			e.Suggestions = append(e.Suggestions, d.all[i])
+		}
+		return Municipality{}, e
+	}
+
+	// Numa tabela parcial, "Itati" (real, mas ausente) viraria "Irati": os
+	// candidatos aproximados só são aceitos com a tabela completa.
+	cands := d.fuzzy(key, uf)
+	if !d.Partial() && (len(cands) == 1 || (len(cands) > 1 && cands[0].dist < cands[1].dist)) {
+		return cands[0].m, nil
+	}
+	e := &LookupError{Input: location, Err: ErrUnknownCity}
+	for i := 0; i < len(cands) && i < 3; i++ {
+		e.Suggestions = append(e.Suggestions, cands[i].m)
+	}
+	return Municipality{}, e
+}
+
+type candidate struct {
+	m    Municipality
+	dist int
+}
+
+// fuzzy retorna os municípios (da UF, se informada) cujo nome ou apelido está
+// a até maxEdits(key) edições de key, do mais próximo ao mais distante.
+func (d *Dataset) fuzzy(key, uf string) []candidate {
+	limit := maxEdits(key)
+	if limit == 0 {
+		return nil
+	}
+	best := make(map[int]int)
+	for k, idx := range d.byName {
+		dist := editDistance(key, k)
+		if dist > limit {
+			continue
+		}
+		for _, i := range idx {
+			if uf != "" && d.all[i].UF != uf {
+				continue
+			}
+			if prev, ok := best[i]; !ok || dist < prev {
+				best[i] = dist
+			}
+		}
+	}
+	out := make([]candidate, 0, len(best))
+	for i, dist := range best {
+		out = append(out, candidate{d.all[i], dist})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].dist != out[j].dist {
+			return out[i].dist < out[j].dist
+		}
+		return out[i].m.Code < out[j].m.Code
+	})
+	return out
+}
+
+// maxEdits é o número de erros de digitação tolerados: nenhum em nomes
+// curtos (siglas, "Rio"), um até 8 letras e dois acima disso.
+func maxEdits(key string) int {
+	n := len([]rune(key))
+	switch {
+	case n <= 4:
+		return 0
+	case n <= 8:
+		return 1
+	}
+	return 2
+}
+
+// editDistance é a distância de Damerau-Levenshtein (variante OSA): inserção,
+// remoção, troca e transposição de letras vizinhas custam 1.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
+
+// ErrUnknownRegion indica que a região não corresponde a UF, mesorregião,
+// microrregião nem região metropolitana conhecidas.
+var ErrUnknownRegion = errors.New("região desconhecida")
+
+// regionPrefixes são removidos antes de comparar nomes de regiões.
+var regionPrefixes = []string{
+	"regiao metropolitana de ", "regiao metropolitana do ", "regiao metropolitana ",
+	"rm de ", "rm do ", "rm ",
+	"microrregiao de ", "microrregiao do ", "microrregiao ",
+	"mesorregiao do ", "mesorregiao de ", "mesorregiao ",
+}
+
+// regionAlias aponta um nome popular para regiões oficiais do IBGE.
+type regionAlias struct {
+	kind  string // "mesorregiao" ou "microrregiao"
+	names []string
+}
+
+// regionAliases mapeia nomes populares de regiões (normalizados) para
+// meso/microrregiões do IBGE.
+var regionAliases = map[string]regionAlias{
+	"norte do parana":    {"mesorregiao", []string{"Norte Central Paranaense", "Norte Pioneiro Paranaense", "Noroeste Paranaense"}},
+	"norte pioneiro":     {"mesorregiao", []string{"Norte Pioneiro Paranaense"}},
+	"norte velho":        {"mesorregiao", []string{"Norte Pioneiro Paranaense"}},
+	"oeste do parana":    {"mesorregiao", []string{"Oeste Paranaense"}},
+	"sudoeste do parana": {"mesorregiao", []string{"Sudoeste Paranaense"}},
+	"noroeste do parana": {"mesorregiao", []string{"Noroeste Paranaense"}},
+	"campos gerais":      {"mesorregiao", []string{"Centro Oriental Paranaense"}},
+	"litoral do parana":  {"microrregiao", []string{"Paranaguá"}},
+	"litoral paranaense": {"microrregiao", []string{"Paranaguá"}},
+}
+
+// Region expande o nome de uma região em municípios. Aceita, nesta ordem:
+// sigla ou nome de UF, apelidos ("Norte do Paraná"), mesorregião,
+// microrregião e região metropolitana. Retorna também o tipo encontrado
+// ("uf", "mesorregiao", "microrregiao" ou "metropolitana").
+func (d *Dataset) Region(name string) ([]Municipality, string, error) {
+	key := Normalize(name)
+	if key == "" {
+		return nil, "", ErrUnknownRegion
+	}
+
+	if uf := StateCode(name); uf != "" {
+		if ms := d.ByUF(uf); len(ms) > 0 {
+			return ms, "uf", nil
+		}
+	}
+
+	if alias, ok := regionAliases[key]; ok {
+		field := mesoRegion
+		if alias.kind == "microrregiao" {
+			field = microRegion
+		}
+		var out []Municipality
+		for _, name := range alias.names {
+			out = append(out, d.filter(field, Normalize(name))...)
+		}
+		if len(out) > 0 {
+			return out, alias.kind, nil
+		}
+	}
+
+	stripped := key
+	for _, p := range regionPrefixes {
+		if strings.HasPrefix(stripped, p) {
+			stripped = strings.TrimPrefix(stripped, p)
+			break
+		}
+	}
+	// Prefixos explícitos direcionam a busca
+	switch {
+	case strings.HasPrefix(key, "microrregiao"):
+		if out := d.filter(microRegion, stripped); len(out) > 0 {
+			return out, "microrregiao", nil
+		}
+	case strings.HasPrefix(key, "regiao metropolitana"), strings.HasPrefix(key, "rm "):
+		if out := d.filter(metroKey, stripped); len(out) > 0 {
+			return out, "metropolitana", nil
+		}
+	}
+
+	if out := d.filter(mesoRegion, stripped); len(out) > 0 {
+		return out, "mesorregiao", nil
+	}
+	if out := d.filter(microRegion, stripped); len(out) > 0 {
+		return out, "microrregiao", nil
+	}
+	if out := d.filter(metroKey, stripped); len(out) > 0 {
+		return out, "metropolitana", nil
+	}
+	return nil, "", fmt.Errorf("%w: %q", ErrUnknownRegion, name)
+}
+
+func microRegion(m Municipality) string { return m.MicroRegion }
+func mesoRegion(m Municipality) string  { return m.MesoRegion }
+
+// metroKey retorna o nome da região metropolitana sem o prefixo, para
+// comparar "RM de Londrina" com "Região Metropolitana de Londrina".
+func metroKey(m Municipality) string {
+	k := Normalize(m.Metro)
+	for _, p := range regionPrefixes {
+		if strings.HasPrefix(k, p) {
+			return strings.TrimPrefix(k, p)
+		}
+	}
+	return k
+}
+
+func (d *Dataset) filter(field func(Municipality) string, want string) []Municipality {
+	var out []Municipality
+	for _, m := range d.all {
+		v := field(m)
+		if v != "" && Normalize(v) == want {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// ─── UFs ─────────────────────────────────────────────────────────────────────
+
+var stateNames = map[string]string{
+	"AC": "Acre", "AL": "Alagoas", "AP": "Amapá", "AM": "Amazonas",
+	"BA": "Bahia", "CE": "Ceará", "DF": "Distrito Federal", "ES": "Espírito Santo",
+	"GO": "Goiás", "MA": "Maranhão", "MT": "Mato Grosso", "MS": "Mato Grosso do Sul",
+	"MG": "Minas Gerais", "PA": "Pará", "PB": "Paraíba", "PR": "Paraná",
+	"PE": "Pernambuco", "PI": "Piauí", "RJ": "Rio de Janeiro", "RN": "Rio Grande do Norte",
+	"RS": "Rio Grande do Sul", "RO": "Rondônia", "RR": "Roraima", "SC": "Santa Catarina",
+	"SP": "São Paulo", "SE": "Sergipe", "TO": "Tocantins",
+}
+
+// StateCode retorna a sigla da UF a partir da sigla ou do nome do estado
+// ("pr", "Paraná", "estado do Paraná"), ou "" se não for uma UF.
+func StateCode(s string) string {
+	s = strings.TrimSpace(s)
+	if up := strings.ToUpper(s); len(up) == 2 {
+		if _, ok := stateNames[up]; ok {
+			return up
+		}
+		return ""
+	}
+	key := strings.TrimPrefix(Normalize(s), "estado do ")
+	key = strings.TrimPrefix(key, "estado de ")
+	for uf, name := range stateNames {
+		if Normalize(name) == key {
+			return uf
+		}
+	}
+	return ""
+}
+
+// StateName retorna o nome da UF (ex: "PR" → "Paraná").
+func StateName(uf string) string {
+	return stateNames[strings.ToUpper(strings.TrimSpace(uf))]
+}
+
+// ─── Helpers ─────────────────────────────────────────────────────────────────
+
+var foldAccents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// Normalize deixa o nome em minúsculas, sem acentos, com hífens e
+// apóstrofos trocados por espaço e espaços colapsados.
+func Normalize(s string) string {
+	s = foldAccents.Replace(strings.ToLower(strings.TrimSpace(s)))
+	s = strings.NewReplacer("-", " ", "'", " ", "’", " ", ".", " ").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// abbreviations expande abreviações comuns em nomes de municípios.
+var abbreviations = map[string]string{
+	"sta": "santa", "sto": "santo", "s": "sao",
+	"n": "nossa", "sra": "senhora", "nsa": "nossa",
+	"pres": "presidente", "gov": "governador", "mal": "marechal",
+	"cel": "coronel", "dr": "doutor", "gen": "general", "gal": "general",
+	"prof": "professor", "eng": "engenheiro",
+}
+
+// nameKey é a chave de busca de nomes: Normalize + abreviações expandidas.
+func nameKey(s string) string {
+	words := strings.Fields(Normalize(s))
+	for i, w := range words {
+		if full, ok := abbreviations[w]; ok {
+			words[i] = full
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+const earthRadiusKm = 6371.0
+
+// DistanceKm retorna a distância em linha reta (Haversine) entre dois pontos.
+func DistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(d float64) float64 { return d * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
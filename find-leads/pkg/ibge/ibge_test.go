@@ -0,0 +1,64 @@
+package ibge
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestResolvePartialSkipsFuzzy(t *testing.T) {
+	ds := Default()
+	if !ds.Partial() {
+		t.Fatal("tabela embutida deveria ser parcial")
+	}
+
+	// Itati (RS) não está na tabela embutida; não pode virar Irati (PR).
+	_, err := ds.Resolve("Itati")
+	if !errors.Is(err, ErrUnknownCity) {
+		t.Fatalf("Resolve(Itati) err = %v, want ErrUnknownCity", err)
+	}
+	var le *LookupError
+	if !errors.As(err, &le) || len(le.Suggestions) == 0 || le.Suggestions[0].Name != "Irati" {
+		t.Errorf("Resolve(Itati) deveria sugerir Irati: %v", err)
+	}
+
+	// Correspondências exatas e abreviações continuam funcionando.
+	for _, in := range []string{"Londrina - PR", "londrina/pr", "Sta. Terezinha de Itaipu"} {
+		if _, err := ds.Resolve(in); err != nil {
+			t.Errorf("Resolve(%q) = %v", in, err)
+		}
+	}
+}
+
+// completeTable devolve a tabela embutida completada com municípios fictícios
+// até Count linhas, como uma tabela gerada por internal/gen.
+func completeTable(t *testing.T) *Dataset {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.Write(bytes.TrimRight(municipiosCSV, "\n"))
+	buf.WriteByte('\n')
+	for i := len(Default().All()); i < Count; i++ {
+		fmt.Fprintf(&buf, "%07d,Xq%04d,AC,,,,,,\n", 9000000+i, i)
+	}
+	ds, err := Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ds
+}
+
+func TestResolveFullFuzzy(t *testing.T) {
+	ds := completeTable(t)
+	if ds.Partial() {
+		t.Fatalf("tabela com %d municípios não deveria ser parcial", len(ds.All()))
+	}
+	m, err := ds.Resolve("Londrna/PR")
+	if err != nil || m.Name != "Londrina" {
+		t.Errorf("Resolve(Londrna/PR) = %v, %v; want Londrina", m, err)
+	}
+	// Com a tabela completa, cidade fora dela é inexistente.
+	if _, err := ds.Resolve("Cidade Inventada - PR"); !errors.Is(err, ErrUnknownCity) {
+		t.Errorf("Resolve(cidade inexistente) err = %v, want ErrUnknownCity", err)
+	}
+}
@@ -0,0 +1,239 @@
+// Command gen regenera municipios.csv com todos os municípios do IBGE.
+//
+//	go generate ./pkg/ibge
+//
+// Código, nome, UF, micro e mesorregião vêm da API de localidades do IBGE;
+// os centróides, da tabela de municípios com latitude e longitude em -coords
+// (colunas codigo_ibge, latitude, longitude). A região metropolitana e os
+// apelidos da tabela atual são mantidos; -metro acrescenta regiões
+// metropolitanas de um CSV com as colunas codigo e regiao_metropolitana.
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	localidadesURL = "https://servicodados.ibge.gov.br/api/v1/localidades/municipios"
+	coordsURL      = "https://raw.githubusercontent.com/kelvins/municipios-brasileiros/main/csv/municipios.csv"
+)
+
+// header é o formato lido por ibge.Parse.
+var header = []string{"codigo", "nome", "uf", "microrregiao", "mesorregiao", "regiao_metropolitana", "lat", "lon", "aliases"}
+
+func main() {
+	out := flag.String("out", "municipios.csv", "arquivo gerado (a tabela atual é lida dele antes)")
+	localidades := flag.String("localidades", localidadesURL, "URL ou arquivo com o JSON de /localidades/municipios")
+	coords := flag.String("coords", coordsURL, "URL ou arquivo CSV com codigo_ibge, latitude e longitude")
+	metro := flag.String("metro", "", "CSV opcional com codigo e regiao_metropolitana")
+	flag.Parse()
+
+	current, err := readCurrent(*out)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var munis []localidade
+	if err := fetchJSON(*localidades, &munis); err != nil {
+		log.Fatal(err)
+	}
+	latLon, err := readTable(*coords, "codigo_ibge", "latitude", "longitude")
+	if err != nil {
+		log.Fatal(err)
+	}
+	metros := map[string][]string{}
+	if *metro != "" {
+		if metros, err = readTable(*metro, "codigo", "regiao_metropolitana"); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	rows, err := build(munis, latLon, metros, current)
+	if err != nil {
+		log.Fatal(err)
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		log.Fatal(err)
+	}
+	w := csv.NewWriter(f)
+	_ = w.Write(header)
+	_ = w.WriteAll(rows)
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%s: %d municípios", *out, len(rows))
+}
+
+// localidade é um item de /api/v1/localidades/municipios. Municípios criados
+// depois da extinção das micro/mesorregiões (2017) vêm sem microrregiao; a
+// UF sai então da região imediata.
+type localidade struct {
+	ID           json.Number `json:"id"`
+	Nome         string      `json:"nome"`
+	Microrregiao *struct {
+		Nome        string `json:"nome"`
+		Mesorregiao struct {
+			Nome string `json:"nome"`
+			UF   uf     `json:"UF"`
+		} `json:"mesorregiao"`
+	} `json:"microrregiao"`
+	RegiaoImediata *struct {
+		RegiaoIntermediaria struct {
+			UF uf `json:"UF"`
+		} `json:"regiao-intermediaria"`
+	} `json:"regiao-imediata"`
+}
+
+type uf struct {
+	Sigla string `json:"sigla"`
+}
+
+// build monta as linhas de municipios.csv, ordenadas por código. current
+// (código → linha da tabela atual) fornece região metropolitana e apelidos.
+func build(munis []localidade, latLon, metros, current map[string][]string) ([][]string, error) {
+	rows := make([][]string, 0, len(munis))
+	for _, m := range munis {
+		code := m.ID.String()
+		row := []string{code, m.Nome, "", "", "", "", "", "", ""}
+		switch {
+		case m.Microrregiao != nil:
+			row[2] = m.Microrregiao.Mesorregiao.UF.Sigla
+			row[3] = m.Microrregiao.Nome
+			row[4] = m.Microrregiao.Mesorregiao.Nome
+		case m.RegiaoImediata != nil:
+			row[2] = m.RegiaoImediata.RegiaoIntermediaria.UF.Sigla
+		}
+		if len(code) != 7 || row[1] == "" || row[2] == "" {
+			return nil, fmt.Errorf("município sem código, nome ou UF: %+v", m)
+		}
+		ll, ok := latLon[code]
+		if !ok {
+			return nil, fmt.Errorf("%s (%s): sem coordenadas", code, m.Nome)
+		}
+		row[6], row[7] = roundCoord(ll[0]), roundCoord(ll[1])
+		if cur, ok := current[code]; ok {
+			row[5], row[8] = cur[5], cur[8]
+		}
+		if rm, ok := metros[code]; ok && rm[0] != "" {
+			row[5] = rm[0]
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+	return rows, nil
+}
+
+func roundCoord(s string) string {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return ""
+	}
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}
+
+// readCurrent lê a tabela atual (código → linha com as colunas de header).
+func readCurrent(path string) (map[string][]string, error) {
+	cols := header[1:]
+	t, err := readTable(path, header[0], cols...)
+	if os.IsNotExist(err) {
+		return map[string][]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for code, rest := range t {
+		t[code] = append([]string{code}, rest...)
+	}
+	return t, nil
+}
+
+// readTable lê um CSV (URL ou arquivo) e indexa as colunas cols pela coluna
+// key. O separador (vírgula ou ponto e vírgula) é detectado no header.
+func readTable(src, key string, cols ...string) (map[string][]string, error) {
+	rc, err := open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", src, err)
+	}
+
+	r := csv.NewReader(strings.NewReader(string(data)))
+	first, _, _ := strings.Cut(string(data), "\n")
+	if strings.Count(first, ";") > strings.Count(first, ",") {
+		r.Comma = ';'
+	}
+	r.FieldsPerRecord = -1
+	recs, err := r.ReadAll()
+	if err != nil || len(recs) == 0 {
+		return nil, fmt.Errorf("%s: CSV inválido: %v", src, err)
+	}
+	idx := make(map[string]int)
+	for i, h := range recs[0] {
+		idx[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))] = i
+	}
+	for _, c := range append([]string{key}, cols...) {
+		if _, ok := idx[c]; !ok {
+			return nil, fmt.Errorf("%s: coluna %q ausente", src, c)
+		}
+	}
+
+	out := make(map[string][]string, len(recs)-1)
+	for _, rec := range recs[1:] {
+		get := func(c string) string {
+			if i := idx[c]; i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+		vals := make([]string, len(cols))
+		for i, c := range cols {
+			vals[i] = get(c)
+		}
+		out[get(key)] = vals
+	}
+	return out, nil
+}
+
+func fetchJSON(src string, v any) error {
+	rc, err := open(src)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := json.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("%s: %w", src, err)
+	}
+	return nil
+}
+
+// open abre um arquivo local ou baixa uma URL http(s).
+func open(src string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		return os.Open(src)
+	}
+	client := &http.Client{Timeout: 2 * time.Minute}
+	resp, err := client.Get(src)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: status %d", src, resp.StatusCode)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Formato de /api/v1/localidades/municipios (recortado).
+const localidadesJSON = `[
+	{"id": 4113700, "nome": "Londrina",
+	 "microrregiao": {"id": 41009, "nome": "Londrina",
+	   "mesorregiao": {"id": 4103, "nome": "Norte Central Paranaense",
+	     "UF": {"id": 41, "sigla": "PR", "nome": "Paraná"}}},
+	 "regiao-imediata": {"id": 410005, "nome": "Londrina",
+	   "regiao-intermediaria": {"id": 4102, "nome": "Londrina",
+	     "UF": {"id": 41, "sigla": "PR", "nome": "Paraná"}}}},
+	{"id": 5101837, "nome": "Boa Esperança do Norte",
+	 "microrregiao": null,
+	 "regiao-imediata": {"id": 510008, "nome": "Sorriso",
+	   "regiao-intermediaria": {"id": 5102, "nome": "Sinop",
+	     "UF": {"id": 51, "sigla": "MT", "nome": "Mato Grosso"}}}},
+	{"id": 4101507, "nome": "Arapongas",
+	 "microrregiao": {"id": 41010, "nome": "Apucarana",
+	   "mesorregiao": {"id": 4103, "nome": "Norte Central Paranaense",
+	     "UF": {"id": 41, "sigla": "PR", "nome": "Paraná"}}}}
+]`
+
+const coordsCSV = "codigo_ibge,nome,latitude,longitude,capital,codigo_uf\n" +
+	"4113700,Londrina,-23.304,-51.1691,0,41\n" +
+	"5101837,Boa Esperança do Norte,-13.0036119,-55.5812522,0,51\n" +
+	"4101507,Arapongas,-23.4153,-51.4259,0,41\n"
+
+const currentCSV = "codigo,nome,uf,microrregiao,mesorregiao,regiao_metropolitana,lat,lon,aliases\n" +
+	"4113700,Londrina,PR,Londrina,Norte Central Paranaense,Região Metropolitana de Londrina,-23.3045,-51.1696,Lda\n"
+
+func TestBuild(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	var munis []localidade
+	if err := json.Unmarshal([]byte(localidadesJSON), &munis); err != nil {
+		t.Fatal(err)
+	}
+	latLon, err := readTable(write("coords.csv", coordsCSV), "codigo_ibge", "latitude", "longitude")
+	if err != nil {
+		t.Fatal(err)
+	}
+	metros, err := readTable(write("metro.csv", "codigo;regiao_metropolitana\n4101507;Região Metropolitana de Londrina\n"), "codigo", "regiao_metropolitana")
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := readCurrent(write("municipios.csv", currentCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := build(munis, latLon, metros, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"4101507", "Arapongas", "PR", "Apucarana", "Norte Central Paranaense", "Região Metropolitana de Londrina", "-23.4153", "-51.4259", ""},
+		{"4113700", "Londrina", "PR", "Londrina", "Norte Central Paranaense", "Região Metropolitana de Londrina", "-23.3040", "-51.1691", "Lda"},
+		{"5101837", "Boa Esperança do Norte", "MT", "", "", "", "-13.0036", "-55.5813", ""},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("build:\n got  %q\n want %q", rows, want)
+	}
+
+	if _, err := build(munis, map[string][]string{}, metros, current); err == nil {
+		t.Error("build sem coordenadas deveria falhar")
+	}
+	if cur, err := readCurrent(filepath.Join(dir, "nao-existe.csv")); err != nil || len(cur) != 0 {
+		t.Errorf("readCurrent(arquivo ausente) = %v, %v", cur, err)
+	}
+}
@@ -0,0 +1,292 @@
+package leads
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/lucasfdcampos/serp/pkg/serp"
+)
+
+// ─── Extração de leads por LLM ───────────────────────────────────────────────
+
+// AIScraper extrai leads com um LLM: junta snippets de busca sobre a
+// categoria na cidade e pede ao modelo os estabelecimentos citados (ou, sem
+// texto suficiente, os que ele conhece), com saída restrita a leadsSchema.
+type AIScraper struct {
+	Client LLMClient
+	// MaxContext limita, em caracteres, o texto de busca enviado no prompt.
+	MaxContext int
+	// MaxTokens limita a resposta do modelo.
+	MaxTokens int
+	// Prompts tem os templates extract_leads.tmpl e list_leads.tmpl
+	// (padrão: os embutidos; ver LoadPrompts).
+	Prompts *template.Template
+
+	name   string
+	source string
+}
+
+// NewAIScraper cria o scraper com os limites de LLM_CONTEXT_CHARS (padrão
+// 5000) e LLM_MAX_TOKENS (padrão 2048). source é o valor de Lead.Source.
+func NewAIScraper(client LLMClient, source string) *AIScraper {
+	aiSources.Store(source, true)
+	return &AIScraper{
+		Client:     client,
+		MaxContext: envInt("LLM_CONTEXT_CHARS", 5000),
+		MaxTokens:  envInt("LLM_MAX_TOKENS", 2048),
+		name:       source,
+		source:     source,
+	}
+}
+
+func (a *AIScraper) Name() string { return a.name }
+
+// promptData são os campos disponíveis nos templates de prompt.
+type promptData struct {
+	Query, City, State string
+	Text               string // snippets de busca (só em extract_leads)
+}
+
+func (a *AIScraper) Search(ctx context.Context, query, location string) ([]*Lead, error) {
+	city, state := ParseLocation(location)
+
+	// Tenta coletar texto de contexto da web
+	rawText, _ := collectRawSearchText(ctx, query, city, state)
+
+	data := promptData{Query: query, City: city, State: state}
+	tmpl := "list_leads.tmpl" // usa conhecimento interno sem contexto web
+	if len(rawText) >= 100 {
+		tmpl = "extract_leads.tmpl"
+		data.Text = truncateText(rawText, a.MaxContext)
+	}
+	prompt, err := renderPrompt(a.Prompts, tmpl, data)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := a.Client.Complete(ctx, LLMRequest{
+		Prompt:      prompt,
+		Schema:      leadsSchema,
+		SchemaName:  "leads",
+		MaxTokens:   a.MaxTokens,
+		Temperature: 0.1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	found, err := parseAILeads(content, city, state, a.source)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range found {
+		if data.Text != "" && nameInText(l.Name, data.Text) {
+			l.addChecks(checkNameInText)
+		}
+	}
+	return found, nil
+}
+
+// leadsSchema é a saída pedida ao modelo: {"leads": [{name, phone, ...}]}.
+var leadsSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"leads": map[string]any{
+			"type": "array",
+			"items": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name":    map[string]any{"type": "string"},
+					"phone":   map[string]any{"type": "string"},
+					"address": map[string]any{"type": "string"},
+					"website": map[string]any{"type": "string"},
+					"email":   map[string]any{"type": "string"},
+				},
+				"required":             []string{"name", "phone", "address", "website", "email"},
+				"additionalProperties": false,
+			},
+		},
+	},
+	"required":             []string{"leads"},
+	"additionalProperties": false,
+}
+
+// ─── Prompts ─────────────────────────────────────────────────────────────────
+
+//go:embed prompts/*.tmpl
+var embeddedPrompts embed.FS
+
+var defaultPrompts = template.Must(template.ParseFS(embeddedPrompts, "prompts/*.tmpl"))
+
+// LoadPrompts lê os *.tmpl de dir por cima dos templates embutidos: um
+// arquivo com o mesmo nome (ex: extract_leads.tmpl) substitui o embutido.
+func LoadPrompts(dir string) (*template.Template, error) {
+	t, err := defaultPrompts.Clone()
+	if err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := t.New(filepath.Base(p)).Parse(string(data)); err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(p), err)
+		}
+	}
+	return t, nil
+}
+
+var promptsOverride *template.Template
+
+// SetPrompts troca os templates usados pelos AIScraper sem Prompts próprio.
+func SetPrompts(t *template.Template) { promptsOverride = t }
+
+func renderPrompt(t *template.Template, name string, data any) (string, error) {
+	if t == nil {
+		t = promptsOverride
+	}
+	if t == nil {
+		t = defaultPrompts
+	}
+	var sb strings.Builder
+	if err := t.ExecuteTemplate(&sb, name, data); err != nil {
+		return "", fmt.Errorf("prompt %s: %w", name, err)
+	}
+	return sb.String(), nil
+}
+
+// ─── Contexto e resposta ─────────────────────────────────────────────────────
+
+// collectRawSearchText junta títulos e snippets da primeira busca que
+// renda texto suficiente, na ordem Bing (mais estável), DDG Lite e Yandex.
+func collectRawSearchText(ctx context.Context, query, city, state string) (string, error) {
+	q := fmt.Sprintf(`%s "%s" "%s" telefone`, expandedQuery(query), city, state)
+	for _, engine := range []serp.Engine{serp.Bing, serp.DuckDuckGo, serp.Yandex} {
+		page, err := serp.Search(ctx, engine, q)
+		if err != nil {
+			continue
+		}
+		var sb strings.Builder
+		for _, line := range strings.Split(page.Text(), "\n") {
+			if t := strings.TrimSpace(line); len(t) > 10 {
+				sb.WriteString(t)
+				sb.WriteString("\n")
+			}
+		}
+		if sb.Len() > 100 {
+			return sb.String(), nil
+		}
+	}
+	return "", fmt.Errorf("todos os motores de busca falharam ou estão bloqueados")
+}
+
+// truncateText corta s em até max bytes sem partir um caractere UTF-8.
+func truncateText(s string, max int) string {
+	if max <= 0 || len(s) <= max {
+		return s
+	}
+	for max > 0 && !isRuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
+
+func isRuneStart(b byte) bool { return b&0xC0 != 0x80 }
+
+// aiLead é um item da resposta do modelo.
+type aiLead struct {
+	Name    string `json:"name"`
+	Phone   string `json:"phone"`
+	Address string `json:"address"`
+	Website string `json:"website"`
+	Email   string `json:"email"`
+}
+
+// decodeAILeads lê {"leads": [...]} ou, de modelos sem structured output,
+// um array solto (inclusive dentro de bloco markdown).
+func decodeAILeads(content string) ([]aiLead, error) {
+	content = strings.TrimSpace(content)
+	for _, pfx := range []string{"```json", "```"} {
+		content = strings.TrimPrefix(content, pfx)
+	}
+	content = strings.TrimSuffix(content, "```")
+	content = strings.TrimSpace(content)
+
+	var wrapped struct {
+		Leads []aiLead `json:"leads"`
+	}
+	if strings.HasPrefix(content, "{") {
+		if err := json.Unmarshal([]byte(content), &wrapped); err == nil {
+			return wrapped.Leads, nil
+		}
+	}
+
+	start := strings.Index(content, "[")
+	end := strings.LastIndex(content, "]")
+	if start == -1 || end == -1 || end <= start {
+		return nil, fmt.Errorf("resposta AI nao contem JSON valido")
+	}
+	var extracted []aiLead
+	if err := json.Unmarshal([]byte(content[start:end+1]), &extracted); err != nil {
+		return nil, fmt.Errorf("erro ao parsear JSON da AI: %w", err)
+	}
+	return extracted, nil
+}
+
+func parseAILeads(content, city, state, source string) ([]*Lead, error) {
+	extracted, err := decodeAILeads(content)
+	if err != nil {
+		return nil, err
+	}
+	// Lojas exclusivamente online — não têm endereço físico local
+	onlineOnly := []string{"zattini", "dafiti", "shoptime", "netshoes", "kanui", "centauro.com",
+		"lojas americanas", "submarino", "extra.com", "shopify", "amazon"}
+	var found []*Lead
+	for _, e := range extracted {
+		if e.Name == "" {
+			continue
+		}
+		// Filtra lojas online
+		nameLower := strings.ToLower(e.Name)
+		isOnline := false
+		for _, ol := range onlineOnly {
+			if strings.Contains(nameLower, ol) {
+				isOnline = true
+				break
+			}
+		}
+		if isOnline {
+			continue
+		}
+		phone := normalizePhone(e.Phone)
+		found = append(found, &Lead{Name: e.Name, Phone: phone,
+			Address: e.Address, Website: e.Website, Email: e.Email,
+			City: city, State: state, Source: source})
+	}
+	return found, nil
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func envInt(key string, fallback int) int {
+	if n, err := strconv.Atoi(os.Getenv(key)); err == nil && n > 0 {
+		return n
+	}
+	return fallback
+}
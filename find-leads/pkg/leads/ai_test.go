@@ -0,0 +1,152 @@
+package leads
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lucasfdcampos/serp/pkg/serp"
+)
+
+const aiSearchText = "Padaria Trigo de Ouro - Londrina. Pães artesanais na Av. Higienópolis, 1200, telefone (43) 3325-4471. " +
+	"Panificadora Central, Rua Sergipe 500, Centro, pedidos (43) 99812-3456."
+
+// stubSearch faz as buscas de contexto do AIScraper (serp.Default) irem para
+// uma instância local do SearXNG que responde com text, sem acessar a rede.
+// text vazio simula motores sem resultados.
+func stubSearch(t *testing.T, text string) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		type result struct {
+			URL     string `json:"url"`
+			Title   string `json:"title"`
+			Content string `json:"content"`
+		}
+		var body struct {
+			Results []result `json:"results"`
+		}
+		if text != "" {
+			body.Results = []result{{URL: "https://www.padariatrigo.com.br/", Title: "Padarias em Londrina", Content: text}}
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	c := serp.Default()
+	prev := c.SearXNG
+	c.SearXNG = serp.SearXNGConfig{URLs: []string{srv.URL}, Pages: 1, RouteAll: true}
+	t.Cleanup(func() {
+		c.SearXNG = prev
+		srv.Close()
+	})
+}
+
+func TestAIScraperSearch(t *testing.T) {
+	stubSearch(t, aiSearchText)
+
+	tests := []struct {
+		name     string
+		response string
+		want     []string // nomes dos leads
+		wantErr  bool
+	}{
+		{
+			name: "saída no schema",
+			response: `{"leads": [
+				{"name": "Padaria Trigo de Ouro", "phone": "(43) 3325-4471", "address": "Av. Higienópolis, 1200", "website": "", "email": ""},
+				{"name": "Panificadora Central", "phone": "43998123456", "address": "Rua Sergipe 500", "website": "", "email": ""},
+				{"name": "", "phone": "", "address": "", "website": "", "email": ""},
+				{"name": "Amazon Padaria", "phone": "", "address": "", "website": "", "email": ""}
+			]}`,
+			want: []string{"Padaria Trigo de Ouro", "Panificadora Central"},
+		},
+		{
+			name:     "bloco markdown",
+			response: "```json\n{\"leads\": [{\"name\": \"Padaria Trigo de Ouro\", \"phone\": \"\", \"address\": \"\", \"website\": \"\", \"email\": \"\"}]}\n```",
+			want:     []string{"Padaria Trigo de Ouro"},
+		},
+		{
+			name:     "array solto com texto em volta",
+			response: "Aqui estão os estabelecimentos:\n[{\"name\": \"Panificadora Central\", \"phone\": \"(43) 99812-3456\"}]\nEspero ter ajudado!",
+			want:     []string{"Panificadora Central"},
+		},
+		{
+			name:     "resultado vazio",
+			response: `{"leads": []}`,
+			want:     nil,
+		},
+		{
+			name:     "JSON truncado",
+			response: `{"leads": [{"name": "Padaria Trigo de Ouro", "phone": "(43) 3333`,
+			wantErr:  true,
+		},
+		{
+			name:     "sem JSON",
+			response: "Não encontrei estabelecimentos.",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := &FakeLLM{Responses: []string{tt.response}}
+			a := NewAIScraper(llm, "AI Fake")
+
+			got, err := a.Search(context.Background(), "padaria", "Londrina - PR")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if len(llm.Requests) != 1 {
+				t.Fatalf("%d chamadas ao LLM, want 1", len(llm.Requests))
+			}
+			req := llm.Requests[0]
+			if req.SchemaName != "leads" || req.Schema == nil {
+				t.Errorf("requisição sem schema: %+v", req)
+			}
+			if !strings.Contains(req.Prompt, "Panificadora Central") {
+				t.Error("prompt sem o texto da busca (extract_leads.tmpl)")
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var names []string
+			for _, l := range got {
+				names = append(names, l.Name)
+				if l.Source != "AI Fake" || l.City != "Londrina" || l.State != "PR" {
+					t.Errorf("lead %q: Source=%q City=%q State=%q", l.Name, l.Source, l.City, l.State)
+				}
+				if !strings.Contains(strings.Join(l.Checks, ","), checkNameInText) {
+					t.Errorf("lead %q sem a evidência %q: %v", l.Name, checkNameInText, l.Checks)
+				}
+			}
+			if strings.Join(names, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("leads = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestAIScraperSearchWithoutContext(t *testing.T) {
+	stubSearch(t, "")
+
+	llm := &FakeLLM{Responses: []string{`{"leads": [{"name": "Padaria Trigo de Ouro", "phone": "(43) 3325-4471", "address": "", "website": "", "email": ""}]}`}}
+	a := NewAIScraper(llm, "AI Fake")
+
+	got, err := a.Search(context.Background(), "padaria", "Londrina - PR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Phone != "(43) 3325-4471" {
+		t.Fatalf("leads = %+v", got)
+	}
+	// Sem texto de busca o prompt é o list_leads.tmpl e o nome não tem
+	// evidência no texto.
+	if strings.Contains(llm.Requests[0].Prompt, "Texto:") {
+		t.Error("prompt com texto de busca vazio deveria usar list_leads.tmpl")
+	}
+	if len(got[0].Checks) != 0 {
+		t.Errorf("Checks = %v, want nenhum", got[0].Checks)
+	}
+}
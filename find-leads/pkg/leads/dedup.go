@@ -0,0 +1,579 @@
+package leads
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	phonepkg "github.com/lucasfdcampos/find-cnpj/pkg/phone"
+)
+
+// ─── Configuração ────────────────────────────────────────────────────────────
+
+// DedupConfig define os limiares da resolução de entidades em Deduplicate.
+// Similaridades vão de 0 a 1 (Jaro-Winkler nos nomes e nas ruas).
+type DedupConfig struct {
+	// NameThreshold: nomes a partir desta similaridade são o mesmo negócio,
+	// desde que endereço e coordenadas não indiquem lugares diferentes.
+	NameThreshold float64
+	// NameAddressThreshold: similaridade de nome suficiente quando o endereço
+	// também bate (AddressThreshold) ou as coordenadas estão a até NearKm.
+	NameAddressThreshold float64
+	// LinkedNameThreshold: similaridade de nome suficiente quando os leads
+	// compartilham o domínio do site ou o handle do Instagram.
+	LinkedNameThreshold float64
+	// AddressThreshold: endereços a partir desta similaridade são o mesmo lugar.
+	AddressThreshold float64
+	// AddressMismatch: endereços abaixo desta similaridade são lugares
+	// diferentes (ex: filiais) e impedem o merge por nome.
+	AddressMismatch float64
+	// NearKm: coordenadas a até esta distância são o mesmo lugar.
+	NearKm float64
+	// FarKm: coordenadas além desta distância impedem o merge por nome.
+	FarKm float64
+}
+
+// DefaultDedupConfig retorna os limiares usados por Deduplicate.
+func DefaultDedupConfig() DedupConfig {
+	return DedupConfig{
+		NameThreshold:        0.92,
+		NameAddressThreshold: 0.80,
+		LinkedNameThreshold:  0.60,
+		AddressThreshold:     0.85,
+		AddressMismatch:      0.50,
+		NearKm:               0.10,
+		FarKm:                1.0,
+	}
+}
+
+// MergeRecord explica um merge feito por Deduplicate.
+type MergeRecord struct {
+	Kept         string   // nome do lead mantido (antes do merge)
+	KeptSource   string   // fonte do lead mantido (antes do merge)
+	Merged       string   // nome do lead absorvido
+	MergedSource string   // fonte do lead absorvido
+	Score        float64  // similaridade dos nomes (0–1)
+	Reasons      []string // ex: ["telefone +5543999998888", "nome 0.94"]
+}
+
+// String retorna `"Loja X Ltda" (solutudo) ← "Loja X" (overpass): nome igual`.
+func (m MergeRecord) String() string {
+	return fmt.Sprintf("%q (%s) ← %q (%s): %s",
+		m.Kept, m.KeptSource, m.Merged, m.MergedSource, strings.Join(m.Reasons, ", "))
+}
+
+// ─── Deduplicate ─────────────────────────────────────────────────────────────
+
+// Deduplicate remove leads duplicados, combinando os dados de cada grupo
+// campo a campo. Ver DeduplicateWithLog.
+func Deduplicate(leadsList []*Lead) []*Lead {
+	out, _ := DeduplicateWithLog(leadsList, DefaultDedupConfig())
+	return out
+}
+
+// DeduplicateWithLog agrupa leads que representam o mesmo negócio e retorna
+// os leads resultantes e o registro de cada merge.
+//
+// Candidatos são encontrados por chaves de bloqueio (telefone, CNPJ, domínio
+// do site, handle do Instagram, palavras distintivas do nome) e comparados por:
+//
+//   - CNPJ ou telefone em comum → mesmo negócio (CNPJs diferentes nunca)
+//   - site ou Instagram em comum → mesmo negócio se os nomes forem parecidos
+//   - nome (sem sufixos societários) com similaridade ≥ NameThreshold
+//   - nome com similaridade ≥ NameAddressThreshold, ou contido no outro
+//     ("Dimazzo" ⊂ "Dimazzo Menswear"), e mesmo endereço/local
+//   - nome contido no outro, mesma cidade e mesma categoria (os dois leads
+//     precisam ter ambas)
+//
+// Merges por nome e site são vetados quando endereço ou coordenadas
+// indicam lugares diferentes (filiais de uma mesma rede).
+//
+// O primeiro lead de cada grupo é mantido (mesmo ponteiro) e recebe os dados
+// dos demais pela política de merge campo a campo (ver mergeLead e
+// SourceTrust).
+func DeduplicateWithLog(leadsList []*Lead, cfg DedupConfig) ([]*Lead, []MergeRecord) {
+	var (
+		result  []*Lead
+		feats   []dedupFeatures
+		blocks  = make(map[string][]int) // chave de bloqueio → índices em result
+		merges  []MergeRecord
+		indexed = func(i int) {
+			for _, k := range feats[i].blockKeys() {
+				blocks[k] = appendUnique(blocks[k], i)
+			}
+		}
+	)
+
+	for _, lead := range leadsList {
+		if lead.Name == "" {
+			continue
+		}
+		f := newDedupFeatures(lead)
+
+		best, bestMatch := -1, dedupMatch{}
+		seen := make(map[int]bool)
+		for _, k := range f.blockKeys() {
+			for _, i := range blocks[k] {
+				if seen[i] {
+					continue
+				}
+				seen[i] = true
+				m := matchLeads(feats[i], f, cfg)
+				if m.ok && (best < 0 || m.better(bestMatch)) {
+					best, bestMatch = i, m
+				}
+			}
+		}
+
+		if best < 0 {
+			result = append(result, lead)
+			feats = append(feats, f)
+			indexed(len(result) - 1)
+			continue
+		}
+
+		existing := result[best]
+		merges = append(merges, MergeRecord{
+			Kept:         existing.Name,
+			KeptSource:   existing.Source,
+			Merged:       lead.Name,
+			MergedSource: lead.Source,
+			Score:        math.Round(bestMatch.nameScore*100) / 100,
+			Reasons:      bestMatch.reasons,
+		})
+		mergeLead(existing, lead)
+		feats[best] = newDedupFeatures(existing)
+		indexed(best)
+	}
+
+	for _, l := range result {
+		l.normalizeSources()
+		l.normalizePhones()
+	}
+	return result, merges
+}
+
+func appendUnique(s []int, v int) []int {
+	for _, x := range s {
+		if x == v {
+			return s
+		}
+	}
+	return append(s, v)
+}
+
+// ─── Features ────────────────────────────────────────────────────────────────
+
+// dedupFeatures são os dados normalizados de um lead usados na comparação.
+type dedupFeatures struct {
+	name     string   // nome sem sufixos societários
+	tokens   []string // palavras de name
+	distinct []string // palavras distintivas (não genéricas, ≥ 3 letras)
+	phones   []string // chaves de telefone (ver phonepkg.Key)
+	cnpj     string   // só dígitos
+	domain   string   // host do site sem "www."
+	handle   string   // handle do Instagram sem "@"
+	street   string   // logradouro normalizado
+	city     string   // cidade normalizada
+	category string   // categoria normalizada
+	number   string   // número do endereço
+	lat, lon float64
+}
+
+func newDedupFeatures(l *Lead) dedupFeatures {
+	f := dedupFeatures{lat: l.Lat, lon: l.Lon}
+	f.tokens = coreNameTokens(l.Name)
+	f.name = strings.Join(f.tokens, " ")
+	for _, t := range f.tokens {
+		if len(t) >= 3 && !genericNameWords[t] {
+			f.distinct = append(f.distinct, t)
+		}
+	}
+	for _, p := range append([]string{l.Phone, l.Phone2}, l.Phones...) {
+		if k := phonepkg.Key(p); len(k) >= 8 && !slices.Contains(f.phones, k) {
+			f.phones = append(f.phones, k)
+		}
+	}
+	if d := onlyDigits(l.CNPJ); len(d) == 14 {
+		f.cnpj = d
+	}
+	f.domain = siteDomain(l.Website)
+	f.handle = instagramHandle(l.Instagram)
+	f.street, f.number = parseAddress(l.Address)
+	f.city = strings.TrimSpace(normalizeString(l.City))
+	f.category = strings.TrimSpace(normalizeString(l.Category))
+	return f
+}
+
+// blockKeys retorna as chaves de bloqueio: só leads que compartilham ao menos
+// uma chave são comparados.
+func (f dedupFeatures) blockKeys() []string {
+	var keys []string
+	for _, p := range f.phones {
+		keys = append(keys, "tel:"+p)
+	}
+	if f.cnpj != "" {
+		keys = append(keys, "cnpj:"+f.cnpj)
+	}
+	if f.domain != "" {
+		keys = append(keys, "site:"+f.domain)
+	}
+	if f.handle != "" {
+		keys = append(keys, "ig:"+f.handle)
+	}
+	if f.name != "" {
+		keys = append(keys, "nome:"+f.name)
+	}
+	for _, t := range f.distinct {
+		keys = append(keys, "palavra:"+t)
+	}
+	return keys
+}
+
+func (f dedupFeatures) hasCoords() bool {
+	return f.lat != 0 || f.lon != 0
+}
+
+// legalSuffixes são sufixos societários removidos do fim do nome.
+var legalSuffixes = map[string]bool{
+	"ltda": true, "limitada": true, "me": true, "epp": true, "eireli": true,
+	"mei": true, "sa": true, "cia": true, "ss": true, "slu": true,
+}
+
+// genericNameWords são palavras comuns em nomes de negócio que não
+// identificam um estabelecimento sozinhas.
+var genericNameWords = map[string]bool{
+	"de": true, "da": true, "do": true, "das": true, "dos": true, "e": true,
+	"the": true, "and": true, "loja": true, "lojas": true, "comercio": true,
+	"comercial": true, "servicos": true, "restaurante": true, "bar": true,
+	"lanchonete": true, "padaria": true, "mercado": true, "supermercado": true,
+	"mercearia": true, "farmacia": true, "drogaria": true, "auto": true,
+	"center": true, "centro": true, "casa": true, "studio": true, "estudio": true,
+	"clinica": true, "consultorio": true, "oficina": true, "salao": true,
+	"academia": true, "pet": true, "shop": true, "store": true, "distribuidora": true,
+	"industria": true, "moda": true, "modas": true, "magazine": true, "atacado": true,
+	"varejo": true, "materiais": true, "construcao": true, "pecas": true, "escola": true, "colegio": true, "hotel": true, "pousada": true,
+	"grupo": true, "empresa": true, "brasil": true, "filial": true, "matriz": true,
+}
+
+// coreNameTokens normaliza o nome e remove sufixos societários do fim
+// ("Loja X Ltda - ME" → [loja x]).
+func coreNameTokens(name string) []string {
+	tokens := strings.Fields(normalizeString(name))
+	for len(tokens) > 1 {
+		last := tokens[len(tokens)-1]
+		switch {
+		case legalSuffixes[last]:
+			tokens = tokens[:len(tokens)-1]
+		case len(tokens) > 2 && last == "a" && tokens[len(tokens)-2] == "s": // "S/A", "S.A."
+			tokens = tokens[:len(tokens)-2]
+		case len(tokens) > 2 && last == "cia" && tokens[len(tokens)-2] == "e":
+			tokens = tokens[:len(tokens)-2]
+		default:
+			return tokens
+		}
+	}
+	return tokens
+}
+
+func onlyDigits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// siteDomain retorna o host do site próprio (sem "www."), ou "" para redes
+// sociais, diretórios e afins.
+func siteDomain(raw string) string {
+	if _, host, ok := normalizeSiteURL(raw); ok && isOfficialSiteHost(host) {
+		return strings.TrimPrefix(host, "www.")
+	}
+	return ""
+}
+
+// instagramHandle extrai o handle de "@loja", "loja" ou da URL do perfil.
+func instagramHandle(raw string) string {
+	raw = strings.TrimSpace(strings.ToLower(raw))
+	if strings.Contains(raw, "instagram.com") {
+		if !strings.Contains(raw, "://") {
+			raw = "https://" + raw
+		}
+		u, err := url.Parse(raw)
+		if err != nil {
+			return ""
+		}
+		raw = strings.Split(strings.Trim(u.Path, "/"), "/")[0]
+	}
+	return strings.TrimPrefix(raw, "@")
+}
+
+// streetAbbreviations expande abreviações de logradouro.
+var streetAbbreviations = map[string]string{
+	"r": "rua", "av": "avenida", "avda": "avenida", "al": "alameda",
+	"rod": "rodovia", "tv": "travessa", "trav": "travessa", "pc": "praca",
+	"pca": "praca", "est": "estrada", "estr": "estrada", "lgo": "largo",
+	"dr": "doutor", "prof": "professor", "pres": "presidente", "gov": "governador",
+	"cel": "coronel", "mal": "marechal", "sen": "senador", "dep": "deputado",
+	"sta": "santa", "sto": "santo",
+}
+
+var reCEP = regexp.MustCompile(`\d{5}-?\d{3}`)
+
+// parseAddress separa logradouro e número: "R. Sergipe, 1200 - Centro" →
+// ("rua sergipe", "1200"). O que vem depois do número (bairro, cidade) é
+// descartado.
+func parseAddress(addr string) (street, number string) {
+	addr = reCEP.ReplaceAllString(addr, " ")
+	var words []string
+	for _, t := range strings.Fields(normalizeString(addr)) {
+		if t == "n" || t == "no" || t == "numero" {
+			continue
+		}
+		if onlyDigits(t) == t && len(t) <= 5 {
+			if len(words) > 0 {
+				number = strings.TrimLeft(t, "0")
+				break
+			}
+			continue
+		}
+		if full, ok := streetAbbreviations[t]; ok {
+			t = full
+		}
+		if t == "de" || t == "da" || t == "do" || t == "das" || t == "dos" {
+			continue
+		}
+		words = append(words, t)
+	}
+	return strings.Join(words, " "), number
+}
+
+// ─── Comparação ──────────────────────────────────────────────────────────────
+
+// dedupMatch é o resultado da comparação de dois leads.
+type dedupMatch struct {
+	ok        bool
+	strong    bool // CNPJ ou telefone em comum
+	nameScore float64
+	reasons   []string
+}
+
+func (m dedupMatch) better(o dedupMatch) bool {
+	if m.strong != o.strong {
+		return m.strong
+	}
+	return m.nameScore > o.nameScore
+}
+
+// matchLeads decide se b é o mesmo negócio que a.
+func matchLeads(a, b dedupFeatures, cfg DedupConfig) dedupMatch {
+	if a.cnpj != "" && b.cnpj != "" && a.cnpj != b.cnpj {
+		return dedupMatch{}
+	}
+
+	m := dedupMatch{}
+	nameScore, nameReason, contained := nameSimilarity(a, b)
+	m.nameScore = nameScore
+
+	if a.cnpj != "" && a.cnpj == b.cnpj {
+		m.ok, m.strong = true, true
+		m.reasons = append(m.reasons, "CNPJ "+a.cnpj)
+	}
+	if p := sharedPhone(a.phones, b.phones); p != "" {
+		m.ok, m.strong = true, true
+		m.reasons = append(m.reasons, "telefone "+p)
+	}
+	if m.strong {
+		if nameReason != "" {
+			m.reasons = append(m.reasons, nameReason)
+		}
+		return m
+	}
+
+	// A partir daqui o merge depende do nome: endereço ou coordenadas
+	// divergentes indicam outro estabelecimento (ex: filial).
+	addrScore, addrOK := addressSimilarity(a, b)
+	var dist float64
+	coordsOK := a.hasCoords() && b.hasCoords()
+	if coordsOK {
+		dist = DistanceKm(a.lat, a.lon, b.lat, b.lon)
+	}
+	if (addrOK && addrScore < cfg.AddressMismatch) || (coordsOK && dist > cfg.FarKm) {
+		return dedupMatch{}
+	}
+
+	var linked string
+	switch {
+	case a.domain != "" && a.domain == b.domain:
+		linked = "site " + a.domain
+	case a.handle != "" && a.handle == b.handle:
+		linked = "instagram @" + a.handle
+	}
+	if linked != "" && nameScore >= cfg.LinkedNameThreshold {
+		m.ok = true
+		m.reasons = append(m.reasons, linked, nameReason)
+		return m
+	}
+
+	// Contenção sozinha não basta ("Bella" ⊂ "Pizzaria Bella Napoli"): exige
+	// mesma cidade e categoria, ou endereço ou coordenadas, abaixo.
+	if nameScore >= cfg.NameThreshold && !contained {
+		m.ok = true
+		m.reasons = append(m.reasons, nameReason)
+		return m
+	}
+	if contained && a.city != "" && a.city == b.city && a.category != "" && a.category == b.category {
+		m.ok = true
+		m.reasons = append(m.reasons, nameReason, "cidade e categoria iguais")
+		return m
+	}
+
+	if nameScore >= cfg.NameAddressThreshold {
+		switch {
+		case addrOK && addrScore >= cfg.AddressThreshold:
+			m.ok = true
+			m.reasons = append(m.reasons, nameReason, fmt.Sprintf("endereço %.2f", addrScore))
+		case coordsOK && dist <= cfg.NearKm:
+			m.ok = true
+			m.reasons = append(m.reasons, nameReason, fmt.Sprintf("distância %.0f m", dist*1000))
+		}
+	}
+	return m
+}
+
+func sharedPhone(a, b []string) string {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return x
+			}
+		}
+	}
+	return ""
+}
+
+// nameSimilarity compara os nomes sem sufixos societários: igualdade,
+// Jaro-Winkler (na ordem original e com as palavras ordenadas) e contenção
+// de um nome no outro quando a parte comum tem palavra distintiva.
+// contained indica que o score veio da contenção, não da grafia.
+func nameSimilarity(a, b dedupFeatures) (score float64, reason string, contained bool) {
+	if a.name == "" || b.name == "" {
+		return 0, "", false
+	}
+	if a.name == b.name {
+		return 1, "nome igual", false
+	}
+	if sortedWords(a.tokens) == sortedWords(b.tokens) {
+		return 1, "mesmas palavras no nome", false
+	}
+
+	score = JaroWinkler(a.name, b.name)
+	if s := JaroWinkler(sortedWords(a.tokens), sortedWords(b.tokens)); s > score {
+		score = s
+	}
+
+	short, long := a, b
+	if len(short.tokens) > len(long.tokens) {
+		short, long = long, short
+	}
+	if len(short.distinct) > 0 && containsAll(long.tokens, short.tokens) {
+		const containedScore = 0.95
+		if containedScore > score {
+			return containedScore, fmt.Sprintf("nome %q contido em %q", short.name, long.name), true
+		}
+	}
+	return score, fmt.Sprintf("nome %.2f", score), false
+}
+
+func sortedWords(tokens []string) string {
+	s := append([]string(nil), tokens...)
+	sort.Strings(s)
+	return strings.Join(s, " ")
+}
+
+func containsAll(haystack, needles []string) bool {
+	set := make(map[string]bool, len(haystack))
+	for _, t := range haystack {
+		set[t] = true
+	}
+	for _, t := range needles {
+		if !set[t] {
+			return false
+		}
+	}
+	return true
+}
+
+// addressSimilarity compara logradouro e número. ok=false quando um dos
+// leads não tem endereço reconhecível.
+func addressSimilarity(a, b dedupFeatures) (score float64, ok bool) {
+	if a.street == "" || b.street == "" {
+		return 0, false
+	}
+	if a.number != "" && b.number != "" && a.number != b.number {
+		return 0, true
+	}
+	return JaroWinkler(a.street, b.street), true
+}
+
+// JaroWinkler retorna a similaridade de Jaro-Winkler entre a e b (0 a 1).
+func JaroWinkler(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 || len(rb) == 0 {
+		if len(ra) == len(rb) {
+			return 1
+		}
+		return 0
+	}
+
+	window := max(len(ra), len(rb))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+	matchA := make([]bool, len(ra))
+	matchB := make([]bool, len(rb))
+	matches := 0
+	for i := range ra {
+		lo, hi := max(0, i-window), min(len(rb), i+window+1)
+		for j := lo; j < hi; j++ {
+			if !matchB[j] && ra[i] == rb[j] {
+				matchA[i], matchB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions, j := 0, 0
+	for i := range ra {
+		if !matchA[i] {
+			continue
+		}
+		for !matchB[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for i := 0; i < min(4, len(ra), len(rb)) && ra[i] == rb[i]; i++ {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
@@ -0,0 +1,92 @@
+package leads
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeduplicateContainedName(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  Lead
+		merge bool
+	}{
+		{
+			name:  "contenção sem evidência",
+			a:     Lead{Name: "Pizzaria Bella Napoli", Source: "solutudo"},
+			b:     Lead{Name: "Bella", Source: "overpass"},
+			merge: false,
+		},
+		{
+			name:  "contenção com mesmo endereço",
+			a:     Lead{Name: "Dimazzo Menswear", Address: "Rua Sergipe, 1200 - Centro", Source: "solutudo"},
+			b:     Lead{Name: "Dimazzo", Address: "R. Sergipe, 1200", Source: "overpass"},
+			merge: true,
+		},
+		{
+			name:  "contenção com coordenadas próximas",
+			a:     Lead{Name: "Dimazzo Menswear", Lat: -23.3100, Lon: -51.1600, Source: "solutudo"},
+			b:     Lead{Name: "Dimazzo", Lat: -23.3102, Lon: -51.1601, Source: "overpass"},
+			merge: true,
+		},
+		{
+			name:  "contenção com mesma cidade e categoria",
+			a:     Lead{Name: "Dimazzo Menswear", City: "Londrina", Category: "Loja de Roupas", Source: "solutudo"},
+			b:     Lead{Name: "Dimazzo", City: "londrina", Category: "loja de roupas", Source: "guiamais"},
+			merge: true,
+		},
+		{
+			name:  "contenção em outra cidade",
+			a:     Lead{Name: "Dimazzo Menswear", City: "Londrina", Category: "Loja de Roupas", Source: "solutudo"},
+			b:     Lead{Name: "Dimazzo", City: "Cambé", Category: "Loja de Roupas", Source: "guiamais"},
+			merge: false,
+		},
+		{
+			name:  "contenção com outra categoria",
+			a:     Lead{Name: "Pizzaria Bella Napoli", City: "Londrina", Category: "Pizzaria", Source: "solutudo"},
+			b:     Lead{Name: "Bella", City: "Londrina", Category: "Salão de Beleza", Source: "guiamais"},
+			merge: false,
+		},
+		{
+			name:  "contenção sem categoria",
+			a:     Lead{Name: "Dimazzo Menswear", City: "Londrina", Source: "solutudo"},
+			b:     Lead{Name: "Dimazzo", City: "Londrina", Source: "overpass"},
+			merge: false,
+		},
+		{
+			name:  "nome quase igual",
+			a:     Lead{Name: "Dimazzo Menswear Ltda", Source: "solutudo"},
+			b:     Lead{Name: "Dimazzo Menswer", Source: "overpass"},
+			merge: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := tt.a, tt.b
+			out, merges := DeduplicateWithLog([]*Lead{&a, &b}, DefaultDedupConfig())
+			if got := len(out) == 1; got != tt.merge {
+				t.Errorf("merge = %v, want %v (merges: %v)", got, tt.merge, merges)
+			}
+		})
+	}
+}
+
+func TestDeduplicateBlocksOnPhonesSlice(t *testing.T) {
+	// O telefone em comum está só em Phones (terceiro número, após um merge).
+	a := &Lead{
+		Name:   "Padaria Trigo de Ouro",
+		Phone:  "(43) 3333-1111",
+		Phone2: "(43) 3333-2222",
+		Phones: []string{"(43) 3333-1111", "(43) 3333-2222", "(43) 99999-8888"},
+		Source: "solutudo",
+	}
+	b := &Lead{Name: "Panificadora Central", Phone: "43 99999-8888", Source: "overpass"}
+
+	out, merges := DeduplicateWithLog([]*Lead{a, b}, DefaultDedupConfig())
+	if len(out) != 1 || len(merges) != 1 {
+		t.Fatalf("esperava 1 lead e 1 merge, got %d leads, merges %v", len(out), merges)
+	}
+	if r := merges[0].Reasons; len(r) == 0 || !strings.HasPrefix(r[0], "telefone ") {
+		t.Errorf("merge deveria ser pelo telefone: %v", merges[0])
+	}
+}
@@ -0,0 +1,615 @@
+package leads
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"gopkg.in/yaml.v3"
+)
+
+// ─── Definições declarativas de guias ────────────────────────────────────────
+
+// Definition descreve um guia/diretório de empresas em JSON ou YAML, sem código Go:
+// URLs, paginação, como extrair cada campo e limites de requisição. É lida
+// por ParseDefinition/LoadDefinitions e executada por DefinitionScraper.
+//
+// As URLs são templates (text/template) com as variáveis de definitionVars:
+//
+//	https://www.guiamais.com.br/{{.CitySlug}}-{{lower .UF}}/{{.QuerySlug}}
+//	https://www.apontador.com.br/local/busca/?q={{urlquery .Query}}&where={{urlquery .City}}
+type Definition struct {
+	Name       string                `json:"name"`       // nome da fonte (Lead.Source); substitui o scraper embutido de mesmo nome
+	URLs       []string              `json:"urls"`       // primeira página; tentadas em ordem até uma trazer leads
+	Pagination DefinitionPagination  `json:"pagination"` // páginas seguintes (opcional)
+	Mode       string                `json:"mode"`       // "html" (padrão: seletores CSS) ou "jsonld"
+	Items      string                `json:"items"`      // html: seletor CSS de cada resultado
+	SchemaOrg  bool                  `json:"schema_org"` // html: também lê os negócios em schema.org da página (ver ExtractSchemaOrg)
+	Types      []string              `json:"types"`      // jsonld: @type aceitos (padrão: tipos de negócio, ver isSchemaBusiness)
+	Fields     map[string]*FieldRule `json:"fields"`     // campo do Lead → regra de extração (jsonld: opcional, ver ExtractSchemaOrg)
+	Headers    map[string]string     `json:"headers"`    // cabeçalhos extras (User-Agent, Accept-Language...)
+	RateLimit  DefinitionRateLimit   `json:"rate_limit"`
+	MinName    int                   `json:"min_name"`   // tamanho mínimo do nome (padrão: 3)
+	SkipNames  []string              `json:"skip_names"` // nomes de UI a descartar (comparação sem acento/caixa)
+
+	urls      []*template.Template
+	pageURL   *template.Template
+	skipNames map[string]bool
+}
+
+// DefinitionPagination gera as páginas 2..MaxPages a partir de URL ({{.Page}}).
+// A paginação para na primeira página sem leads novos.
+type DefinitionPagination struct {
+	URL      string `json:"url"`
+	MaxPages int    `json:"max_pages"` // inclui a primeira página
+}
+
+// DefinitionRateLimit controla o ritmo das requisições à fonte.
+type DefinitionRateLimit struct {
+	DelayMs    int `json:"delay_ms"`    // espera antes de cada requisição
+	TimeoutSec int `json:"timeout_sec"` // timeout por requisição (padrão: 15)
+	Retries    int `json:"retries"`     // tentativas extras em 429/503 (padrão: 3)
+}
+
+// FieldRule extrai um campo do resultado.
+//
+// No modo html, Selectors são tentados em ordem (relativos ao item) até um
+// trazer valor; sem seletores, usa o texto do item inteiro. Attr lê um
+// atributo em vez do texto. No modo jsonld, JSONLD é o caminho no objeto
+// (ex: "address.streetAddress"). Regex, se houver, é aplicada ao valor e usa
+// o primeiro grupo (ou o trecho todo). Para "phone", todos os trechos que
+// casam com Regex viram telefones do lead.
+type FieldRule struct {
+	Selectors []string `json:"selectors"`
+	Attr      string   `json:"attr"`
+	JSONLD    string   `json:"jsonld"`
+	Regex     string   `json:"regex"`
+
+	re *regexp.Regexp
+}
+
+// definitionVars são as variáveis disponíveis nos templates de URL.
+type definitionVars struct {
+	Query     string // termo como digitado
+	QuerySlug string // QuerySlug(query)
+	City      string
+	CitySlug  string // CitySlug(city)
+	UF        string // sigla em maiúsculas
+	Location  string // localização como recebida
+	Page      int
+}
+
+var definitionFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	// plus troca "-" por "+" (slugs usados em query string)
+	"plus": func(s string) string { return strings.ReplaceAll(s, "-", "+") },
+}
+
+// definitionFields são os campos do Lead aceitos em Definition.Fields.
+var definitionFields = map[string]func(*Lead) *string{
+	"name":          func(l *Lead) *string { return &l.Name },
+	"address":       func(l *Lead) *string { return &l.Address },
+	"category":      func(l *Lead) *string { return &l.Category },
+	"website":       func(l *Lead) *string { return &l.Website },
+	"email":         func(l *Lead) *string { return &l.Email },
+	"cnpj":          func(l *Lead) *string { return &l.CNPJ },
+	"rating":        func(l *Lead) *string { return &l.Rating },
+	"rating_count":  func(l *Lead) *string { return &l.RatingCount },
+	"price_level":   func(l *Lead) *string { return &l.PriceLevel },
+	"opening_hours": func(l *Lead) *string { return &l.OpeningHours },
+}
+
+// defaultPhoneRegex é usada em "phone" quando a regra não define Regex.
+const defaultPhoneRegex = `\(?\d{2}\)?\s*\d{4,5}[-\s]?\d{4}`
+
+// ParseDefinition lê e valida uma definição em JSON ou YAML. O formato é
+// detectado pelo primeiro caractere: "{" é JSON, o resto é YAML.
+func ParseDefinition(data []byte) (*Definition, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] != '{' {
+		var err error
+		if data, err = yamlToJSON(trimmed); err != nil {
+			return nil, err
+		}
+	}
+	var d Definition
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&d); err != nil {
+		return nil, err
+	}
+	if err := d.compile(); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// yamlToJSON converte uma definição YAML em JSON para que as duas passem pela
+// mesma decodificação estrita (campos desconhecidos são erro nos dois casos).
+func yamlToJSON(data []byte) ([]byte, error) {
+	var v map[string]any
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("yaml: %w", err)
+	}
+	return out, nil
+}
+
+func (d *Definition) compile() error {
+	if d.Name == "" {
+		return fmt.Errorf("definição sem name")
+	}
+	if len(d.URLs) == 0 {
+		return fmt.Errorf("%s: definição sem urls", d.Name)
+	}
+	switch d.Mode {
+	case "", "html":
+		d.Mode = "html"
+		if d.Items == "" {
+			return fmt.Errorf("%s: modo html exige items", d.Name)
+		}
+	case "jsonld":
+		if d.SchemaOrg {
+			return fmt.Errorf("%s: schema_org só se aplica ao modo html", d.Name)
+		}
+	default:
+		return fmt.Errorf("%s: mode inválido %q", d.Name, d.Mode)
+	}
+	if d.Mode == "html" && d.Fields["name"] == nil {
+		return fmt.Errorf("%s: definição sem regra para name", d.Name)
+	}
+
+	for i, raw := range d.URLs {
+		t, err := template.New(fmt.Sprintf("%s#%d", d.Name, i)).Funcs(definitionFuncs).Parse(raw)
+		if err != nil {
+			return fmt.Errorf("%s: url %d: %w", d.Name, i, err)
+		}
+		d.urls = append(d.urls, t)
+	}
+	if d.Pagination.URL != "" {
+		t, err := template.New(d.Name + "#page").Funcs(definitionFuncs).Parse(d.Pagination.URL)
+		if err != nil {
+			return fmt.Errorf("%s: pagination.url: %w", d.Name, err)
+		}
+		d.pageURL = t
+	}
+
+	for field, rule := range d.Fields {
+		if _, ok := definitionFields[field]; !ok && field != "phone" {
+			return fmt.Errorf("%s: campo desconhecido %q", d.Name, field)
+		}
+		if rule == nil {
+			return fmt.Errorf("%s: regra vazia para %q", d.Name, field)
+		}
+		if d.Mode == "jsonld" && rule.JSONLD == "" {
+			return fmt.Errorf("%s: campo %q sem caminho jsonld", d.Name, field)
+		}
+		pattern := rule.Regex
+		if pattern == "" && field == "phone" {
+			pattern = defaultPhoneRegex
+		}
+		if pattern != "" {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("%s: regex de %q: %w", d.Name, field, err)
+			}
+			rule.re = re
+		}
+	}
+
+	if d.MinName == 0 {
+		d.MinName = 3
+	}
+	if d.RateLimit.TimeoutSec == 0 {
+		d.RateLimit.TimeoutSec = 15
+	}
+	if d.RateLimit.Retries == 0 {
+		d.RateLimit.Retries = 3
+	}
+	d.skipNames = make(map[string]bool, len(d.SkipNames))
+	for _, n := range d.SkipNames {
+		d.skipNames[normalizeString(n)] = true
+	}
+	return nil
+}
+
+// ─── Carregamento ────────────────────────────────────────────────────────────
+
+//go:embed definitions/*.json definitions/*.yaml
+var embeddedDefinitions embed.FS
+
+// DefaultDefinitions retorna as definições embutidas no binário.
+func DefaultDefinitions() []*Definition {
+	entries, _ := embeddedDefinitions.ReadDir("definitions")
+	var defs []*Definition
+	for _, e := range entries {
+		data, err := embeddedDefinitions.ReadFile("definitions/" + e.Name())
+		if err != nil {
+			panic(err)
+		}
+		d, err := ParseDefinition(data)
+		if err != nil {
+			panic(fmt.Sprintf("definitions/%s: %v", e.Name(), err))
+		}
+		defs = append(defs, d)
+	}
+	return defs
+}
+
+// defaultDefinition retorna a definição embutida com o nome dado.
+func defaultDefinition(name string) *Definition {
+	for _, d := range DefaultDefinitions() {
+		if d.Name == name {
+			return d
+		}
+	}
+	panic("definição embutida não encontrada: " + name)
+}
+
+// LoadDefinitions lê todos os *.json, *.yaml e *.yml de dir, em ordem
+// alfabética.
+func LoadDefinitions(dir string) ([]*Definition, error) {
+	var paths []string
+	for _, ext := range []string{"*.json", "*.yaml", "*.yml"} {
+		m, err := filepath.Glob(filepath.Join(dir, ext))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, m...)
+	}
+	sort.Strings(paths)
+	var defs []*Definition
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		d, err := ParseDefinition(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(p), err)
+		}
+		defs = append(defs, d)
+	}
+	return defs, nil
+}
+
+// ApplyDefinitions substitui os scrapers cujo Name() coincide com o de uma
+// definição (para corrigir seletores sem recompilar) e acrescenta as
+// definições restantes como novas fontes.
+func ApplyDefinitions(searchers []Searcher, defs []*Definition) []Searcher {
+	byName := make(map[string]*Definition, len(defs))
+	for _, d := range defs {
+		byName[d.Name] = d
+	}
+	out := make([]Searcher, 0, len(searchers)+len(defs))
+	for _, s := range searchers {
+		if d, ok := byName[s.Name()]; ok {
+			out = append(out, NewDefinitionScraper(d))
+			delete(byName, d.Name)
+			continue
+		}
+		out = append(out, s)
+	}
+	for _, d := range defs {
+		if _, ok := byName[d.Name]; ok {
+			out = append(out, NewDefinitionScraper(d))
+		}
+	}
+	return out
+}
+
+// ─── Scraper ─────────────────────────────────────────────────────────────────
+
+// DefinitionScraper é um Searcher genérico guiado por uma Definition.
+type DefinitionScraper struct {
+	def    *Definition
+	client *http.Client
+}
+
+func NewDefinitionScraper(def *Definition) *DefinitionScraper {
+	return &DefinitionScraper{
+		def:    def,
+		client: &http.Client{Timeout: time.Duration(def.RateLimit.TimeoutSec) * time.Second},
+	}
+}
+
+func (s *DefinitionScraper) Name() string { return s.def.Name }
+
+func (s *DefinitionScraper) Search(ctx context.Context, query, location string) ([]*Lead, error) {
+	city, state := ParseLocation(location)
+	vars := definitionVars{
+		Query:     query,
+		QuerySlug: QuerySlug(query),
+		City:      city,
+		CitySlug:  CitySlug(city),
+		UF:        strings.ToUpper(state),
+		Location:  location,
+		Page:      1,
+	}
+
+	var lastErr error
+	for _, t := range s.def.urls {
+		found, err := s.fetch(ctx, t, vars, city, state)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if len(found) == 0 {
+			continue
+		}
+		return s.paginate(ctx, found, vars, city, state), nil
+	}
+	return nil, lastErr
+}
+
+// paginate busca as páginas 2..MaxPages até uma não trazer leads novos.
+func (s *DefinitionScraper) paginate(ctx context.Context, first []*Lead, vars definitionVars, city, state string) []*Lead {
+	seen := make(map[string]bool)
+	var all []*Lead
+	add := func(page []*Lead) int {
+		added := 0
+		for _, l := range page {
+			key := normalizeString(l.Name) + "|" + l.Phone
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			all = append(all, l)
+			added++
+		}
+		return added
+	}
+	add(first)
+
+	if s.def.pageURL == nil {
+		return all
+	}
+	for p := 2; p <= s.def.Pagination.MaxPages; p++ {
+		vars.Page = p
+		page, err := s.fetch(ctx, s.def.pageURL, vars, city, state)
+		if err != nil || add(page) == 0 {
+			break
+		}
+	}
+	return all
+}
+
+// fetch baixa uma página e extrai os leads conforme o modo da definição.
+func (s *DefinitionScraper) fetch(ctx context.Context, t *template.Template, vars definitionVars, city, state string) ([]*Lead, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, vars); err != nil {
+		return nil, err
+	}
+	rawURL := buf.String()
+
+	if d := s.def.RateLimit.DelayMs; d > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Duration(d) * time.Millisecond):
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	req.Header.Set("Accept-Language", "pt-BR,pt;q=0.9")
+	for k, v := range s.def.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := DoWithRetry(ctx, s.client, req, s.def.RateLimit.Retries)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: HTTP %d para %s", strings.ToLower(s.def.Name), resp.StatusCode, rawURL)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if s.def.Mode == "jsonld" {
+		return s.extractJSONLD(doc, city, state), nil
+	}
+	return s.extractHTML(doc, city, state), nil
+}
+
+// extractHTML extrai um lead por item. Com schema_org, os negócios em
+// schema.org vêm primeiro e os itens com o mesmo nome são ignorados.
+func (s *DefinitionScraper) extractHTML(doc *goquery.Document, city, state string) []*Lead {
+	var leads []*Lead
+	seen := make(map[string]bool)
+	if s.def.SchemaOrg {
+		for _, lead := range ExtractSchemaOrg(doc, s.def.Name) {
+			if lead.City == "" {
+				lead.City, lead.State = city, state
+			}
+			if s.accept(lead) {
+				seen[normalizeString(lead.Name)] = true
+				leads = append(leads, lead)
+			}
+		}
+	}
+	doc.Find(s.def.Items).Each(func(_ int, item *goquery.Selection) {
+		lead := s.newLead(city, state)
+		for field, rule := range s.def.Fields {
+			s.setField(lead, field, rule, htmlValues(item, rule))
+		}
+		if s.accept(lead) && !seen[normalizeString(lead.Name)] {
+			leads = append(leads, lead)
+		}
+	})
+	return leads
+}
+
+// htmlValues retorna o valor do primeiro seletor da regra que trouxer texto.
+func htmlValues(item *goquery.Selection, rule *FieldRule) []string {
+	read := func(sel *goquery.Selection) string {
+		if rule.Attr != "" {
+			v, _ := sel.Attr(rule.Attr)
+			return strings.TrimSpace(v)
+		}
+		return strings.TrimSpace(sel.Text())
+	}
+	if len(rule.Selectors) == 0 {
+		return []string{read(item)}
+	}
+	for _, css := range rule.Selectors {
+		if v := read(item.Find(css).First()); v != "" {
+			return []string{v}
+		}
+	}
+	return nil
+}
+
+// extractJSONLD parte do lead completo do extrator schema.org e aplica as
+// regras da definição por cima (caminhos não cobertos, regex de limpeza).
+func (s *DefinitionScraper) extractJSONLD(doc *goquery.Document, city, state string) []*Lead {
+	match := func(obj map[string]any) bool {
+		if len(s.def.Types) == 0 {
+			return isSchemaBusiness(obj)
+		}
+		for _, t := range schemaTypes(obj) {
+			for _, want := range s.def.Types {
+				if strings.EqualFold(t, want) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	var leads []*Lead
+	for _, obj := range schemaEntities(jsonLDBlocks(doc), match) {
+		lead := schemaLead(obj, s.def.Name)
+		if lead == nil {
+			lead = s.newLead("", "")
+		}
+		if lead.City == "" {
+			lead.City, lead.State = city, state
+		}
+		for field, rule := range s.def.Fields {
+			s.setField(lead, field, rule, jsonLDPath(obj, rule.JSONLD))
+		}
+		if s.accept(lead) {
+			leads = append(leads, lead)
+		}
+	}
+	return leads
+}
+
+// jsonLDPath segue um caminho "a.b.c" no objeto e retorna os valores
+// encontrados (arrays são achatados).
+func jsonLDPath(obj map[string]any, path string) []string {
+	var cur []any = []any{obj}
+	for _, key := range strings.Split(path, ".") {
+		var next []any
+		for _, c := range cur {
+			switch x := c.(type) {
+			case map[string]any:
+				if v, ok := x[key]; ok {
+					next = append(next, v)
+				}
+			case []any:
+				for _, e := range x {
+					if m, ok := e.(map[string]any); ok {
+						if v, ok := m[key]; ok {
+							next = append(next, v)
+						}
+					}
+				}
+			}
+		}
+		cur = next
+	}
+	var out []string
+	var flat func(v any)
+	flat = func(v any) {
+		switch x := v.(type) {
+		case string:
+			if s := strings.TrimSpace(x); s != "" {
+				out = append(out, s)
+			}
+		case float64:
+			out = append(out, fmt.Sprint(x))
+		case []any:
+			for _, e := range x {
+				flat(e)
+			}
+		}
+	}
+	for _, c := range cur {
+		flat(c)
+	}
+	return out
+}
+
+func (s *DefinitionScraper) newLead(city, state string) *Lead {
+	return &Lead{City: city, State: state, Source: s.def.Name}
+}
+
+// setField aplica a regex da regra aos valores e grava o resultado no lead.
+func (s *DefinitionScraper) setField(lead *Lead, field string, rule *FieldRule, values []string) {
+	if field == "phone" {
+		for _, v := range values {
+			for _, p := range rule.re.FindAllString(v, -1) {
+				lead.addScrapedPhone(p)
+			}
+		}
+		return
+	}
+	for _, v := range values {
+		if rule.re != nil {
+			m := rule.re.FindStringSubmatch(v)
+			switch {
+			case m == nil:
+				continue
+			case len(m) > 1:
+				v = m[1]
+			default:
+				v = m[0]
+			}
+		}
+		switch field {
+		case "rating_count":
+			v = onlyDigits(v)
+		case "opening_hours":
+			v = NormalizeOpeningHours(v)
+		}
+		if v = strings.TrimSpace(v); v != "" {
+			*definitionFields[field](lead) = v
+			return
+		}
+	}
+}
+
+// accept descarta resultados sem nome, curtos demais ou da lista skip_names.
+func (s *DefinitionScraper) accept(lead *Lead) bool {
+	lead.Name = strings.Join(strings.Fields(lead.Name), " ")
+	if len(lead.Name) < s.def.MinName {
+		return false
+	}
+	return !s.def.skipNames[normalizeString(lead.Name)]
+}
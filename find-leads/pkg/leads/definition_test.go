@@ -0,0 +1,350 @@
+package leads
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Os arquivos em testdata/definitions são páginas de resultados de cada guia
+// reduzidas aos elementos que as definições embutidas usam. Quando um guia
+// mudar o layout, atualize a captura junto com definitions/*.
+
+// fixtureTransport responde às URLs de pages com o arquivo correspondente em
+// testdata/definitions e com 404 às demais (o que encerra a paginação).
+type fixtureTransport struct {
+	pages     map[string]string
+	requested []string
+}
+
+func (f *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	u := req.URL.String()
+	f.requested = append(f.requested, u)
+	resp := &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("")), Request: req}
+	if name, ok := f.pages[u]; ok {
+		data, err := os.ReadFile(filepath.Join("testdata", "definitions", name))
+		if err != nil {
+			return nil, err
+		}
+		resp.StatusCode = http.StatusOK
+		resp.Body = io.NopCloser(strings.NewReader(string(data)))
+	}
+	return resp, nil
+}
+
+// definitionLead são os campos comparados nos testes de definição.
+type definitionLead struct {
+	Name, Phone, Phone2, Address, Category, Website, Rating string
+}
+
+func TestDefaultDefinitionsFixtures(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		pages     map[string]string
+		requested []string
+		want      []definitionLead
+	}{
+		{
+			name:  "Apontador",
+			query: "padaria",
+			pages: map[string]string{
+				"https://www.apontador.com.br/local/busca/?q=padaria&where=Londrina+PR": "apontador.html",
+			},
+			want: []definitionLead{
+				{Name: "Padaria Trigo de Ouro", Phone: "(43) 3325-4471", Address: "Av. Higienópolis, 1200 - Centro, Londrina - PR", Category: "Padarias e Confeitarias", Rating: "4.6"},
+				{Name: "Panificadora Estrela", Phone: "(43) 99812-3456", Address: "Rua Sergipe, 500 - Centro, Londrina - PR", Category: "Padarias"},
+			},
+		},
+		{
+			name:  "GuiaMais",
+			query: "padaria",
+			pages: map[string]string{
+				"https://www.guiamais.com.br/londrina-pr/padaria": "guiamais.html",
+			},
+			want: []definitionLead{
+				{Name: "Padaria Trigo de Ouro", Phone: "(43) 3325-4471", Phone2: "(43) 99812-3456", Address: "Av. Higienópolis, 1200 - Centro - Londrina/PR", Category: "Padarias", Website: "https://www.padariatrigo.com.br/"},
+				{Name: "Panificadora Estrela", Address: "Rua Sergipe, 500 - Centro - Londrina/PR", Category: "Panificadoras"},
+			},
+		},
+		{
+			name:  "TeleListas",
+			query: "padaria",
+			pages: map[string]string{
+				"http://www.telelistas.net/pr/londrina/padaria": "telelistas.html",
+			},
+			want: []definitionLead{
+				{Name: "Padaria Trigo de Ouro", Phone: "(43) 3325-4471", Address: "Av Higienópolis 1200, Centro"},
+				{Name: "Panificadora Estrela", Phone: "(43) 3324-1010", Address: "R Sergipe 500, Centro"},
+			},
+		},
+		{
+			name:  "Solutudo",
+			query: "padaria artesanal",
+			pages: map[string]string{
+				"https://www.solutudo.com.br/empresas/pr/londrina/busca?q=padaria+artesanal": "solutudo.html",
+			},
+			requested: []string{
+				"https://www.solutudo.com.br/empresas/pr/londrina/busca?q=padaria+artesanal",
+				"https://www.solutudo.com.br/empresas/pr/londrina/busca?q=padaria+artesanal&pagina=2",
+			},
+			want: []definitionLead{
+				{Name: "Padaria Trigo de Ouro", Phone: "(43) 3325-4471", Address: "Av. Higienópolis, 1200, Londrina, PR", Website: "https://www.padariatrigo.com.br/"},
+				{Name: "Panificadora Estrela", Phone: "(43) 3324-1010", Phone2: "(43) 99812-3456", Address: "Rua Sergipe, 500"},
+			},
+		},
+		{
+			name:  "AppLocal",
+			query: "padaria",
+			pages: map[string]string{
+				"https://applocal.com.br/empresas/padaria/londrina/pr": "applocal.html",
+			},
+			requested: []string{
+				"https://applocal.com.br/empresas/padaria/londrina/pr",
+				"https://applocal.com.br/empresas/padaria/londrina/pr/pagina/2",
+			},
+			// schema.org primeiro; o card de mesmo nome, a navegação e o
+			// title= em minúsculas ficam de fora.
+			want: []definitionLead{
+				{Name: "Padaria Trigo de Ouro", Phone: "(43) 3325-4471"},
+				{Name: "Panificadora Estrela"},
+				{Name: "Confeitaria Doce Mel"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def := defaultDefinition(tt.name)
+			def.RateLimit.DelayMs = 0
+			tr := &fixtureTransport{pages: tt.pages}
+			s := NewDefinitionScraper(def)
+			s.client = &http.Client{Transport: tr}
+
+			found, err := s.Search(context.Background(), tt.query, "Londrina - PR")
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			var got []definitionLead
+			for _, l := range found {
+				got = append(got, definitionLead{l.Name, l.Phone, l.Phone2, l.Address, l.Category, l.Website, l.Rating})
+				if l.Source != tt.name || l.City != "Londrina" || l.State != "PR" {
+					t.Errorf("%q: Source/City/State = %q/%q/%q", l.Name, l.Source, l.City, l.State)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("leads:\n got  %+v\n want %+v", got, tt.want)
+			}
+			if tt.requested != nil && !reflect.DeepEqual(tr.requested, tt.requested) {
+				t.Errorf("URLs pedidas:\n got  %q\n want %q", tr.requested, tt.requested)
+			}
+		})
+	}
+}
+
+const guiaJSON = `{
+  "name": "GuiaTeste",
+  "urls": ["https://guia.example/{{.CitySlug}}-{{lower .UF}}/{{.QuerySlug}}"],
+  "pagination": {"url": "https://guia.example/{{.CitySlug}}/{{.QuerySlug}}?p={{.Page}}", "max_pages": 4},
+  "items": ".empresa",
+  "fields": {
+    "name": {"selectors": ["h2", "a"]},
+    "phone": {},
+    "website": {"selectors": ["a.site"], "attr": "href", "regex": "^https?://.+"}
+  },
+  "rate_limit": {"delay_ms": 200},
+  "skip_names": ["Anuncie aqui"]
+}`
+
+const guiaYAML = `
+# mesma definição de guiaJSON
+name: GuiaTeste
+urls:
+  - "https://guia.example/{{.CitySlug}}-{{lower .UF}}/{{.QuerySlug}}"
+pagination:
+  url: "https://guia.example/{{.CitySlug}}/{{.QuerySlug}}?p={{.Page}}"
+  max_pages: 4
+items: .empresa
+fields:
+  name:
+    selectors: [h2, a]
+  phone: {}
+  website:
+    selectors: [a.site]
+    attr: href
+    regex: "^https?://.+"
+rate_limit:
+  delay_ms: 200
+skip_names: [Anuncie aqui]
+`
+
+func TestParseDefinitionYAML(t *testing.T) {
+	fromJSON, err := ParseDefinition([]byte(guiaJSON))
+	if err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+	fromYAML, err := ParseDefinition([]byte(guiaYAML))
+	if err != nil {
+		t.Fatalf("YAML: %v", err)
+	}
+	for _, d := range []*Definition{fromJSON, fromYAML} {
+		if d.Mode != "html" || d.MinName != 3 || d.RateLimit != (DefinitionRateLimit{DelayMs: 200, TimeoutSec: 15, Retries: 3}) {
+			t.Errorf("padrões não aplicados: mode=%q min_name=%d rate_limit=%+v", d.Mode, d.MinName, d.RateLimit)
+		}
+		if d.Fields["phone"].re == nil || d.Fields["website"].re == nil || d.pageURL == nil || len(d.urls) != 1 {
+			t.Errorf("%s: regex ou templates não compilados", d.Name)
+		}
+	}
+	pick := func(d *Definition) []any {
+		return []any{d.Name, d.URLs, d.Pagination, d.Items, d.SkipNames,
+			d.Fields["name"].Selectors, d.Fields["website"].Attr, d.Fields["website"].Regex}
+	}
+	if !reflect.DeepEqual(pick(fromJSON), pick(fromYAML)) {
+		t.Errorf("YAML difere do JSON:\n json %v\n yaml %v", pick(fromJSON), pick(fromYAML))
+	}
+}
+
+func TestParseDefinitionErrors(t *testing.T) {
+	tests := []struct {
+		name, data, want string
+	}{
+		{"sem name", `{"urls": ["https://x"]}`, "sem name"},
+		{"sem urls", `{"name": "X"}`, "sem urls"},
+		{"html sem items", `{"name": "X", "urls": ["https://x"], "fields": {"name": {}}}`, "exige items"},
+		{"html sem name", `{"name": "X", "urls": ["https://x"], "items": "li"}`, "sem regra para name"},
+		{"mode inválido", `{"name": "X", "urls": ["https://x"], "mode": "xml"}`, "mode inválido"},
+		{"campo JSON desconhecido", `{"name": "X", "urls": ["https://x"], "itens": "li"}`, `unknown field "itens"`},
+		{"campo YAML desconhecido", "name: X\nurls: [https://x]\nitens: li\n", `unknown field "itens"`},
+		{"YAML inválido", "name: [X\n", "yaml"},
+		{"campo do lead desconhecido", `{"name": "X", "urls": ["https://x"], "items": "li", "fields": {"name": {}, "fax": {}}}`, `campo desconhecido "fax"`},
+		{"regra vazia", `{"name": "X", "urls": ["https://x"], "items": "li", "fields": {"name": {}, "phone": null}}`, `regra vazia para "phone"`},
+		{"jsonld sem caminho", `{"name": "X", "urls": ["https://x"], "mode": "jsonld", "fields": {"email": {"regex": "@"}}}`, "sem caminho jsonld"},
+		{"jsonld com schema_org", `{"name": "X", "urls": ["https://x"], "mode": "jsonld", "schema_org": true}`, "schema_org só se aplica"},
+		{"regex inválida", `{"name": "X", "urls": ["https://x"], "items": "li", "fields": {"name": {"regex": "("}}}`, `regex de "name"`},
+		{"template inválido", `{"name": "X", "urls": ["https://x/{{.City"], "items": "li", "fields": {"name": {}}}`, "url 0"},
+		{"paginação inválida", `{"name": "X", "urls": ["https://x"], "pagination": {"url": "{{nada .Page}}"}, "items": "li", "fields": {"name": {}}}`, "pagination.url"},
+	}
+	for _, tt := range tests {
+		_, err := ParseDefinition([]byte(tt.data))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want contendo %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestDefinitionSelectors(t *testing.T) {
+	const page = `<ul>
+	<li class="empresa">
+	  <h2>  Padaria   Trigo de Ouro </h2>
+	  <a class="site" href="https://www.padariatrigo.com.br/">site</a>
+	  <span>Fone (43) 3325-4471 ou (43) 99812-3456</span>
+	</li>
+	<li class="empresa">
+	  <a href="/empresa/estrela">Panificadora Estrela</a>
+	  <a class="site" href="/interno">site</a>
+	</li>
+	<li class="empresa"><h2>Anuncie aqui</h2></li>
+	<li class="empresa"><h2>Oi</h2></li>
+	</ul>`
+	def, err := ParseDefinition([]byte(guiaJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := NewDefinitionScraper(def).extractHTML(doc, "Londrina", "PR")
+
+	var got []definitionLead
+	for _, l := range found {
+		got = append(got, definitionLead{Name: l.Name, Phone: l.Phone, Phone2: l.Phone2, Website: l.Website})
+	}
+	want := []definitionLead{
+		// espaços colapsados; todos os telefones do item
+		{Name: "Padaria Trigo de Ouro", Phone: "(43) 3325-4471", Phone2: "(43) 99812-3456", Website: "https://www.padariatrigo.com.br/"},
+		// sem h2, cai no segundo seletor; href relativo não casa a regex
+		{Name: "Panificadora Estrela"},
+		// "Anuncie aqui" (skip_names) e "Oi" (curto demais) descartados
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractHTML:\n got  %+v\n want %+v", got, want)
+	}
+
+	item := doc.Find("li.empresa").First()
+	for _, tt := range []struct {
+		rule *FieldRule
+		want []string
+	}{
+		{&FieldRule{Selectors: []string{".nada", "h2"}}, []string{"Padaria   Trigo de Ouro"}},
+		{&FieldRule{Selectors: []string{"a.site"}, Attr: "href"}, []string{"https://www.padariatrigo.com.br/"}},
+		{&FieldRule{Selectors: []string{".nada"}}, nil},
+	} {
+		if got := htmlValues(item, tt.rule); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("htmlValues(%+v) = %q, want %q", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestJSONLDPath(t *testing.T) {
+	obj := map[string]any{
+		"name":    "Padaria Trigo de Ouro",
+		"address": map[string]any{"streetAddress": "Av. Higienópolis, 1200"},
+		"contactPoint": []any{
+			map[string]any{"telephone": "(43) 3325-4471"},
+			map[string]any{"telephone": []any{"(43) 99812-3456", " "}},
+		},
+		"aggregateRating": map[string]any{"ratingValue": 4.5},
+	}
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"name", []string{"Padaria Trigo de Ouro"}},
+		{"address.streetAddress", []string{"Av. Higienópolis, 1200"}},
+		{"contactPoint.telephone", []string{"(43) 3325-4471", "(43) 99812-3456"}},
+		{"aggregateRating.ratingValue", []string{"4.5"}},
+		{"address.postalCode", nil},
+	}
+	for _, tt := range tests {
+		if got := jsonLDPath(obj, tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("jsonLDPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLoadDefinitions(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("b.yaml", strings.Replace(guiaYAML, "name: GuiaTeste", "name: B", 1))
+	write("a.json", strings.Replace(guiaJSON, `"GuiaTeste"`, `"A"`, 1))
+	write("c.yml", strings.Replace(guiaYAML, "name: GuiaTeste", "name: C", 1))
+	write("leia-me.txt", "ignorado")
+
+	defs, err := LoadDefinitions(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, d := range defs {
+		names = append(names, d.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"A", "B", "C"}) {
+		t.Errorf("LoadDefinitions = %v, want [A B C]", names)
+	}
+
+	write("d.yaml", "name: D\n")
+	if _, err := LoadDefinitions(dir); err == nil || !strings.HasPrefix(err.Error(), "d.yaml: ") {
+		t.Errorf("erro deveria citar o arquivo: %v", err)
+	}
+}
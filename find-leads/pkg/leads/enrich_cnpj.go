@@ -45,6 +45,7 @@ func EnrichCNPJ(ctx context.Context, lead *Lead) error {
 	lead.Municipio = c.Municipio
 	lead.UF = c.UF
 	lead.Partners = c.Socios
+	lead.SetField("email", &lead.Email, c.Email, "cnpj")
 
 	return nil
 }
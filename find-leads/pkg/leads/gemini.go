@@ -1,9 +1,7 @@
 package leads
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
@@ -11,87 +9,56 @@ import (
 	"time"
 )
 
-// GeminiScraper usa a API Gemini 1.5-flash (último recurso)
-type GeminiScraper struct {
+// GeminiClient é o LLMClient da API Gemini (generateContent).
+type GeminiClient struct {
 	APIKey  string
-	client  *http.Client
-	baseURL string
+	Model   string
+	BaseURL string
+	HTTP    *http.Client
 }
 
-func NewGeminiScraper(apiKey string) *GeminiScraper {
+func NewGeminiClient(apiKey, model string) *GeminiClient {
 	if apiKey == "" {
 		apiKey = os.Getenv("GEMINI_API_KEY")
 	}
-	return &GeminiScraper{
+	if model == "" {
+		model = envOr("GEMINI_MODEL", "gemini-2.0-flash")
+	}
+	return &GeminiClient{
 		APIKey:  apiKey,
-		client:  &http.Client{Timeout: 30 * time.Second},
-		baseURL: "https://generativelanguage.googleapis.com/v1beta/models/gemini-2.0-flash:generateContent",
+		Model:   model,
+		BaseURL: "https://generativelanguage.googleapis.com/v1beta/models",
+		HTTP:    &http.Client{Timeout: 30 * time.Second},
 	}
 }
 
-func (g *GeminiScraper) Name() string { return "Gemini AI (last resort)" }
+func (g *GeminiClient) Name() string { return "Gemini " + g.Model }
 
-func (g *GeminiScraper) Search(ctx context.Context, query, location string) ([]*Lead, error) {
+func (g *GeminiClient) Complete(ctx context.Context, r LLMRequest) (string, error) {
 	if g.APIKey == "" {
-		return nil, fmt.Errorf("GEMINI_API_KEY não configurada")
+		return "", fmt.Errorf("GEMINI_API_KEY não configurada")
 	}
-
-	city, state := ParseLocation(location)
-
-	// Coleta texto
-	rawText, _ := collectRawSearchText(ctx, query, city, state)
-
-	var prompt string
-	if len(rawText) >= 100 {
-		maxLen := len(rawText)
-		if maxLen > 4000 {
-			maxLen = 4000
-		}
-		prompt = fmt.Sprintf(`Extraia todos os estabelecimentos comerciais do tipo "%s" em "%s-%s" do texto abaixo.
-Retorne apenas um JSON array com campos: name, phone, address, website, email.
-Use "" para campos não disponíveis. NÃO inclua marketplaces online como Mercado Livre ou Amazon.
-
-Texto:
-%s`, query, city, state, rawText[:maxLen])
-	} else {
-		// Usa conhecimento interno sem contexto web
-		prompt = fmt.Sprintf(`Liste estabelecimentos comerciais do tipo "%s" em %s, %s, Brasil.
-Retorne apenas um JSON array com campos: name, phone, address, website, email.
-Use "" para campos não disponíveis.`, query, city, state)
+	genConfig := map[string]any{"temperature": r.Temperature}
+	if r.MaxTokens > 0 {
+		genConfig["maxOutputTokens"] = r.MaxTokens
 	}
-
-	body := map[string]interface{}{
-		"contents": []map[string]interface{}{
-			{
-				"parts": []map[string]string{
-					{"text": prompt},
-				},
-			},
-		},
-		"generationConfig": map[string]interface{}{
-			"temperature":     0.1,
-			"maxOutputTokens": 2048,
-		},
+	if r.Schema != nil {
+		genConfig["responseMimeType"] = "application/json"
+		genConfig["responseSchema"] = geminiSchema(r.Schema)
 	}
-
-	bodyBytes, _ := json.Marshal(body)
-	reqURL := fmt.Sprintf("%s?key=%s", g.baseURL, g.APIKey)
-
-	req, err := http.NewRequestWithContext(ctx, "POST", reqURL, bytes.NewReader(bodyBytes))
-	if err != nil {
-		return nil, err
+	body := map[string]any{
+		"contents": []map[string]any{
+			{"parts": []map[string]string{{"text": r.Prompt}}},
+		},
+		"generationConfig": genConfig,
 	}
-	req.Header.Set("Content-Type", "application/json")
-
-	time.Sleep(500 * time.Millisecond)
-
-	resp, err := g.client.Do(req)
-	if err != nil {
-		return nil, err
+	if r.System != "" {
+		body["systemInstruction"] = map[string]any{
+			"parts": []map[string]string{{"text": r.System}},
+		}
 	}
-	defer resp.Body.Close()
 
-	var geminiResp struct {
+	var out struct {
 		Candidates []struct {
 			Content struct {
 				Parts []struct {
@@ -100,27 +67,52 @@ Use "" para campos não disponíveis.`, query, city, state)
 			} `json:"content"`
 		} `json:"candidates"`
 	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&geminiResp); err != nil {
-		return nil, fmt.Errorf("gemini decode error (status %d): %w", resp.StatusCode, err)
+	reqURL := fmt.Sprintf("%s/%s:generateContent?key=%s", g.BaseURL, g.Model, g.APIKey)
+	if err := postJSON(ctx, g.HTTP, reqURL, nil, body, &out); err != nil {
+		return "", fmt.Errorf("gemini: %w", err)
 	}
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("gemini status %d", resp.StatusCode)
+	if len(out.Candidates) == 0 || len(out.Candidates[0].Content.Parts) == 0 {
+		return "", fmt.Errorf("gemini: resposta vazia (candidates=%d)", len(out.Candidates))
 	}
+	return out.Candidates[0].Content.Parts[0].Text, nil
+}
 
-	if len(geminiResp.Candidates) == 0 || len(geminiResp.Candidates[0].Content.Parts) == 0 {
-		return nil, fmt.Errorf("gemini resposta vazia (status %d, candidates=%d)", resp.StatusCode, len(geminiResp.Candidates))
+// geminiSchema converte o JSON Schema para o subconjunto OpenAPI aceito em
+// responseSchema: tipos em maiúsculas e sem additionalProperties.
+func geminiSchema(s map[string]any) map[string]any {
+	out := make(map[string]any, len(s))
+	for k, v := range s {
+		switch k {
+		case "additionalProperties":
+			continue
+		case "type":
+			if t, ok := v.(string); ok {
+				v = strings.ToUpper(t)
+			}
+		case "properties":
+			if props, ok := v.(map[string]any); ok {
+				conv := make(map[string]any, len(props))
+				for name, p := range props {
+					if pm, ok := p.(map[string]any); ok {
+						conv[name] = geminiSchema(pm)
+					}
+				}
+				v = conv
+			}
+		case "items":
+			if im, ok := v.(map[string]any); ok {
+				v = geminiSchema(im)
+			}
+		}
+		out[k] = v
 	}
+	return out
+}
 
-	content := geminiResp.Candidates[0].Content.Parts[0].Text
-
-	// Remove markdown code blocks se houver
-	content = strings.TrimSpace(content)
-	content = strings.TrimPrefix(content, "```json")
-	content = strings.TrimPrefix(content, "```")
-	content = strings.TrimSuffix(content, "```")
-	content = strings.TrimSpace(content)
-
-	return parseAILeads(content, city, state, "Gemini AI")
+// NewGeminiScraper extrai leads com o Gemini (último recurso).
+func NewGeminiScraper(apiKey string) *AIScraper {
+	s := NewAIScraper(NewGeminiClient(apiKey, ""), "Gemini AI")
+	s.name = "Gemini AI (last resort)"
+	s.MaxContext = 4000
+	return s
 }
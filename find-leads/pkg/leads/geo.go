@@ -0,0 +1,97 @@
+package leads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/lucasfdcampos/find-leads/pkg/ibge"
+)
+
+// DistanceKm retorna a distância em linha reta (Haversine) entre dois pontos.
+func DistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	return ibge.DistanceKm(lat1, lon1, lat2, lon2)
+}
+
+// HasCoords indica se o lead tem coordenadas (0,0 é tratado como ausente).
+func (l *Lead) HasCoords() bool {
+	return l.Lat != 0 || l.Lon != 0
+}
+
+// FilterByRadius mantém os leads a até radiusKm do centro e preenche
+// DistanceKm. Leads sem coordenadas são mantidos (não dá para saber).
+func FilterByRadius(leads []*Lead, lat, lon, radiusKm float64) []*Lead {
+	kept := leads[:0:0]
+	for _, l := range leads {
+		if !l.HasCoords() {
+			kept = append(kept, l)
+			continue
+		}
+		d := DistanceKm(lat, lon, l.Lat, l.Lon)
+		l.DistanceKm = math.Round(d*100) / 100
+		if radiusKm <= 0 || d <= radiusKm {
+			kept = append(kept, l)
+		}
+	}
+	return kept
+}
+
+// ─── Nominatim ───────────────────────────────────────────────────────────────
+
+// nominatimPlace é o primeiro resultado do Nominatim para uma cidade.
+type nominatimPlace struct {
+	Lat, Lon    float64
+	BoundingBox []string // [minlat, maxlat, minlon, maxlon]
+}
+
+// nominatimSearch geocodifica "cidade, UF, Brazil" via Nominatim.
+func nominatimSearch(ctx context.Context, city, state string) (*nominatimPlace, error) {
+	searchQuery := fmt.Sprintf("%s, %s, Brazil", city, state)
+	reqURL := fmt.Sprintf("https://nominatim.openstreetmap.org/search?q=%s&format=json&limit=1",
+		url.QueryEscape(searchQuery))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "find-leads/1.0")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := DoWithRetry(ctx, client, req, 3)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var results []struct {
+		BoundingBox []string `json:"boundingbox"`
+		Lat         string   `json:"lat"`
+		Lon         string   `json:"lon"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil || len(results) == 0 {
+		return nil, fmt.Errorf("cidade não encontrada no Nominatim")
+	}
+
+	p := &nominatimPlace{BoundingBox: results[0].BoundingBox}
+	p.Lat, _ = strconv.ParseFloat(results[0].Lat, 64)
+	p.Lon, _ = strconv.ParseFloat(results[0].Lon, 64)
+	return p, nil
+}
+
+// GeocodeCity retorna o centro (lat, lon) da cidade via Nominatim.
+func GeocodeCity(ctx context.Context, city, state string) (lat, lon float64, err error) {
+	p, err := nominatimSearch(ctx, city, state)
+	if err != nil {
+		return 0, 0, err
+	}
+	if p.Lat == 0 && p.Lon == 0 {
+		return 0, 0, fmt.Errorf("nominatim retornou coordenadas vazias para %s", city)
+	}
+	return p.Lat, p.Lon, nil
+}
@@ -9,6 +9,8 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/lucasfdcampos/find-leads/pkg/taxonomy"
 )
 
 // GeoapifyScraper busca estabelecimentos via Geoapify Places API
@@ -25,33 +27,13 @@ func NewGeoapifyScraper(apiKey string) *GeoapifyScraper {
 
 func (g *GeoapifyScraper) Name() string { return "Geoapify Places" }
 
-// queryParaGeoapifyCategory converte query para categoria Geoapify
+// queryParaGeoapifyCategory converte query para categorias Geoapify (via
+// taxonomia), separadas por vírgula como a API espera.
 func queryParaGeoapifyCategory(query string) string {
-	q := strings.ToLower(query)
-	switch {
-	case contains(q, "roupa", "vestuario", "moda", "confeccao"):
-		return "commercial.clothing"
-	case contains(q, "calcado", "sapato"):
-		return "commercial.clothing.shoes"
-	case contains(q, "farmacia", "drogaria"):
-		return "healthcare.pharmacy"
-	case contains(q, "restaurante", "lanchonete"):
-		return "catering.restaurant"
-	case contains(q, "supermercado", "mercado"):
-		return "commercial.supermarket"
-	case contains(q, "academia", "fitness"):
-		return "leisure.fitness"
-	case contains(q, "banco"):
-		return "commercial.financial.bank"
-	case contains(q, "padaria"):
-		return "catering.fast_food.bakery"
-	case contains(q, "barbearia", "cabeleireiro", "salao"):
-		return "service.beauty.hairdresser"
-	case contains(q, "pet", "veterinaria"):
-		return "service.animal_shelter"
-	default:
-		return "commercial"
+	if cats := taxonomy.GeoapifyCategories(query); len(cats) > 0 {
+		return strings.Join(cats, ",")
 	}
+	return "commercial"
 }
 
 func (g *GeoapifyScraper) Search(ctx context.Context, query, location string) ([]*Lead, error) {
@@ -104,6 +86,9 @@ func (g *GeoapifyScraper) Search(ctx context.Context, query, location string) ([
 				HouseNum   string   `json:"housenumber"`
 				City       string   `json:"city"`
 				Categories []string `json:"categories"`
+				Hours      string   `json:"opening_hours"`
+				Lat        float64  `json:"lat"`
+				Lon        float64  `json:"lon"`
 			} `json:"properties"`
 		} `json:"features"`
 	}
@@ -127,6 +112,10 @@ func (g *GeoapifyScraper) Search(ctx context.Context, query, location string) ([
 			City:    city,
 			State:   state,
 			Source:  "Geoapify",
+			Lat:     p.Lat,
+			Lon:     p.Lon,
+
+			OpeningHours: NormalizeOpeningHours(p.Hours),
 		}
 
 		if p.Street != "" {
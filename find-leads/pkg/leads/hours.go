@@ -0,0 +1,376 @@
+package leads
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule é o horário semanal de funcionamento: para cada dia da semana
+// (índice = time.Weekday, 0 = domingo) os intervalos em que o estabelecimento
+// está aberto. Dia sem intervalos = fechado.
+type Schedule [7][]TimeRange
+
+// TimeRange é um intervalo em minutos desde a meia-noite. Close acima de 1440
+// indica que o intervalo passa da meia-noite (18:00-02:00 → 1080-1560).
+type TimeRange struct {
+	Open  int
+	Close int
+}
+
+// minutesPerDay é o fim do dia em minutos (24:00).
+const minutesPerDay = 24 * 60
+
+// osmDays são as abreviações de dia do formato opening_hours do OSM, na
+// ordem de time.Weekday.
+var osmDays = [7]string{"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"}
+
+// weeklyKeys são as chaves de Schedule.Weekly, na ordem de time.Weekday.
+var weeklyKeys = [7]string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// ─── Parsing ─────────────────────────────────────────────────────────────────
+
+var (
+	// 08:00-18:00, 8h-18h, 8h30 às 18h, 08.00 - 12.00
+	reHoursRange = regexp.MustCompile(`(\d{1,2})(?:[:h.](\d{2}))?\s*h?\s*(?:-|as|ate|a|to)\s*(\d{1,2})(?:[:h.](\d{2}))?\s*h?`)
+	// "seg a sex", "Mo to Fr" → "seg-sex", "Mo-Fr"
+	reDayRangeWord = regexp.MustCompile(`\s+(?:a|ate|to)\s+`)
+	reDayFeira     = regexp.MustCompile(`-?\s*feiras?`)
+)
+
+var foldHours = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a",
+	"é", "e", "ê", "e", "í", "i", "ó", "o", "ô", "o", "ú", "u", "ç", "c",
+	"–", "-", "—", "-",
+)
+
+// ParseOpeningHours interpreta horários no formato opening_hours do OSM
+// (usado também pelo schema.org: "Mo-Fr 08:00-18:00; Sa 08:00-12:00",
+// "24/7", "Su off") e variações em português comuns em sites e diretórios
+// ("Seg a Sex 8h às 18h", "Sábado: 08:00 - 12:00"). Regras posteriores
+// sobrescrevem os dias que citam, como no OSM; feriados (PH) são ignorados.
+// Retorna false se nenhuma regra for reconhecida.
+func ParseOpeningHours(raw string) (*Schedule, bool) {
+	text := foldHours.Replace(strings.ToLower(strings.TrimSpace(raw)))
+	if text == "" {
+		return nil, false
+	}
+	var s Schedule
+	parsed := false
+	for _, rule := range splitHoursRules(text) {
+		if rule == "24/7" || strings.Contains(rule, "24 horas") {
+			for d := range s {
+				s[d] = []TimeRange{{0, minutesPerDay}}
+			}
+			parsed = true
+			continue
+		}
+
+		// dias antes do primeiro horário; sem horário, a regra fecha os dias
+		cut := strings.IndexAny(rule, "0123456789")
+		dayPart, timePart := rule, ""
+		if cut >= 0 {
+			dayPart, timePart = rule[:cut], rule[cut:]
+		}
+		days, ok := parseDaySelector(dayPart)
+		if !ok {
+			continue
+		}
+
+		var ranges []TimeRange
+		if timePart != "" {
+			ranges = parseTimeRanges(timePart)
+			if len(ranges) == 0 {
+				continue
+			}
+		} else if !isClosedRule(dayPart) {
+			continue
+		}
+		for _, d := range days {
+			s[d] = ranges
+		}
+		parsed = true
+	}
+	if !parsed {
+		return nil, false
+	}
+	return &s, true
+}
+
+// NormalizeOpeningHours devolve o horário no formato do OSM quando
+// reconhecido; caso contrário, o texto original sem espaços extras.
+func NormalizeOpeningHours(raw string) string {
+	raw = strings.Join(strings.Fields(raw), " ")
+	if s, ok := ParseOpeningHours(raw); ok && !s.IsEmpty() {
+		return s.String()
+	}
+	return raw
+}
+
+// splitHoursRules separa as regras por ";" ou quebra de linha e, dentro de
+// uma regra, por "," seguida de um novo seletor de dias ("Mo-Fr 08:00-18:00,
+// Sa 08:00-12:00"). Vírgulas entre horários ou entre dias ficam na regra.
+func splitHoursRules(text string) []string {
+	var rules []string
+	for _, part := range strings.FieldsFunc(text, func(r rune) bool { return r == ';' || r == '\n' || r == '|' }) {
+		start := 0
+		for i := 0; i < len(part); i++ {
+			if part[i] != ',' {
+				continue
+			}
+			prev := strings.TrimSpace(part[start:i])
+			next := strings.TrimSpace(part[i+1:])
+			// vírgula depois de um horário e antes de um dia inicia nova regra
+			if strings.ContainsAny(prev, "0123456789") && strings.ContainsAny(prev[len(prev)-1:], "0123456789h") &&
+				next != "" && !strings.ContainsAny(next[:1], "0123456789") {
+				rules = append(rules, prev)
+				start = i + 1
+			}
+		}
+		rules = append(rules, strings.TrimSpace(part[start:]))
+	}
+	out := rules[:0]
+	for _, r := range rules {
+		if r = strings.Trim(r, " :,."); r != "" {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// parseDaySelector lê "mo-fr", "mo,we,fr", "seg a sex", "sabado" etc. Vazio
+// significa todos os dias. Retorna false para seletores não reconhecidos ou
+// só de feriados.
+func parseDaySelector(sel string) ([]time.Weekday, bool) {
+	sel = reDayFeira.ReplaceAllString(sel, "")
+	sel = reDayRangeWord.ReplaceAllString(" "+sel+" ", "-")
+	for _, w := range []string{"off", "closed", "fechado", "de ", "das ", "diariamente", "todos os dias"} {
+		sel = strings.ReplaceAll(sel, w, " ")
+	}
+	sel = strings.Trim(sel, " :")
+	if sel == "" {
+		return allWeekdays(), true
+	}
+
+	var days []time.Weekday
+	for _, piece := range strings.FieldsFunc(sel, func(r rune) bool { return r == ',' || r == '/' || r == ' ' }) {
+		if piece == "e" {
+			continue
+		}
+		bounds := strings.SplitN(piece, "-", 2)
+		from, ok := parseWeekday(bounds[0])
+		if !ok {
+			return nil, false
+		}
+		if len(bounds) == 1 {
+			days = append(days, from)
+			continue
+		}
+		to, ok := parseWeekday(bounds[1])
+		if !ok {
+			return nil, false
+		}
+		for d := from; ; d = (d + 1) % 7 {
+			days = append(days, d)
+			if d == to {
+				break
+			}
+		}
+	}
+	return days, len(days) > 0
+}
+
+// parseWeekday reconhece dias em inglês (abreviação do OSM ou nome) e em
+// português.
+func parseWeekday(s string) (time.Weekday, bool) {
+	s = strings.Trim(s, " .:")
+	prefixes := []struct {
+		p string
+		d time.Weekday
+	}{
+		{"dom", time.Sunday}, {"seg", time.Monday}, {"ter", time.Tuesday},
+		{"qua", time.Wednesday}, {"qui", time.Thursday}, {"sex", time.Friday},
+		{"sab", time.Saturday},
+		{"su", time.Sunday}, {"mo", time.Monday}, {"tu", time.Tuesday},
+		{"we", time.Wednesday}, {"th", time.Thursday}, {"fr", time.Friday},
+		{"sa", time.Saturday},
+	}
+	if len(s) < 2 {
+		return 0, false
+	}
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p.p) {
+			return p.d, true
+		}
+	}
+	return 0, false
+}
+
+func allWeekdays() []time.Weekday {
+	return []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+}
+
+func isClosedRule(sel string) bool {
+	return strings.Contains(sel, "off") || strings.Contains(sel, "closed") || strings.Contains(sel, "fechado")
+}
+
+// parseTimeRanges lê todos os intervalos do texto ("08:00-12:00,14:00-18:00").
+func parseTimeRanges(s string) []TimeRange {
+	var out []TimeRange
+	for _, m := range reHoursRange.FindAllStringSubmatch(s, -1) {
+		start, ok1 := hhmm(m[1], m[2])
+		end, ok2 := hhmm(m[3], m[4])
+		if !ok1 || !ok2 {
+			continue
+		}
+		if end <= start {
+			end += minutesPerDay // passa da meia-noite
+		}
+		out = append(out, TimeRange{start, end})
+	}
+	return out
+}
+
+func hhmm(h, m string) (int, bool) {
+	hour, err := strconv.Atoi(h)
+	if err != nil || hour > 24 {
+		return 0, false
+	}
+	minute := 0
+	if m != "" {
+		if minute, err = strconv.Atoi(m); err != nil || minute > 59 {
+			return 0, false
+		}
+	}
+	if hour == 24 && minute > 0 {
+		return 0, false
+	}
+	return hour*60 + minute, true
+}
+
+// ─── Consulta e formatação ───────────────────────────────────────────────────
+
+// IsEmpty reporta se nenhum dia tem horário (fechado a semana toda).
+func (s *Schedule) IsEmpty() bool {
+	for _, r := range s {
+		if len(r) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Add inclui um intervalo no dia.
+func (s *Schedule) Add(day time.Weekday, r TimeRange) {
+	s[day] = append(s[day], r)
+}
+
+// OpenAt reporta se o estabelecimento está aberto no horário local t
+// (converta antes com BrazilTime). Considera intervalos do dia anterior
+// que passam da meia-noite.
+func (s *Schedule) OpenAt(t time.Time) bool {
+	m := t.Hour()*60 + t.Minute()
+	for _, r := range s[t.Weekday()] {
+		if m >= r.Open && m < r.Close {
+			return true
+		}
+	}
+	for _, r := range s[(t.Weekday()+6)%7] {
+		if m+minutesPerDay < r.Close {
+			return true
+		}
+	}
+	return false
+}
+
+// String formata no padrão opening_hours do OSM, agrupando dias consecutivos
+// com o mesmo horário: "Mo-Fr 08:00-18:00; Sa 08:00-12:00".
+func (s *Schedule) String() string {
+	if s.is247() {
+		return "24/7"
+	}
+	// segunda primeiro, como é costume no OSM
+	order := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}
+	var rules []string
+	for i := 0; i < len(order); {
+		ranges := formatRanges(s[order[i]])
+		j := i
+		for j+1 < len(order) && formatRanges(s[order[j+1]]) == ranges {
+			j++
+		}
+		if ranges != "" {
+			days := osmDays[order[i]]
+			switch {
+			case j == i+1:
+				days += "," + osmDays[order[j]]
+			case j > i:
+				days += "-" + osmDays[order[j]]
+			}
+			rules = append(rules, days+" "+ranges)
+		}
+		i = j + 1
+	}
+	return strings.Join(rules, "; ")
+}
+
+// Weekly devolve o horário por dia ("mon" → ["08:00-12:00", "14:00-18:00"]),
+// só com os dias em que abre.
+func (s *Schedule) Weekly() map[string][]string {
+	out := make(map[string][]string)
+	for d, ranges := range s {
+		for _, r := range ranges {
+			out[weeklyKeys[d]] = append(out[weeklyKeys[d]], r.String())
+		}
+	}
+	return out
+}
+
+func (s *Schedule) is247() bool {
+	for _, r := range s {
+		if len(r) != 1 || r[0].Open != 0 || r[0].Close < minutesPerDay {
+			return false
+		}
+	}
+	return true
+}
+
+// String formata o intervalo como "08:00-18:00" (fechamento após a
+// meia-noite volta ao relógio: "18:00-02:00").
+func (r TimeRange) String() string {
+	end := r.Close
+	if end > minutesPerDay {
+		end -= minutesPerDay
+	}
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", r.Open/60, r.Open%60, end/60, end%60)
+}
+
+func formatRanges(ranges []TimeRange) string {
+	parts := make([]string, len(ranges))
+	for i, r := range ranges {
+		parts[i] = r.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// ─── Fuso horário ────────────────────────────────────────────────────────────
+
+// ufUTCOffset são as UFs fora do horário de Brasília (UTC-3). O Brasil não
+// tem horário de verão desde 2019, então offsets fixos bastam e dispensam a
+// base tzdata no container.
+var ufUTCOffset = map[string]int{
+	"AC": -5,
+	"AM": -4, "RO": -4, "RR": -4, "MT": -4, "MS": -4,
+}
+
+// BrazilTime converte t para o horário local da UF (Brasília se vazia ou
+// desconhecida).
+func BrazilTime(t time.Time, uf string) time.Time {
+	uf = strings.ToUpper(strings.TrimSpace(uf))
+	offset, ok := ufUTCOffset[uf]
+	if !ok {
+		offset, uf = -3, "BRT"
+	}
+	return t.In(time.FixedZone(uf, offset*3600))
+}
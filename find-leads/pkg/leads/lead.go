@@ -1,9 +1,11 @@
 package leads
 
 import (
+	"errors"
 	"regexp"
-	"sort"
 	"strings"
+
+	phonepkg "github.com/lucasfdcampos/find-cnpj/pkg/phone"
 )
 
 // Lead representa um estabelecimento/lead encontrado
@@ -20,12 +22,34 @@ type Lead struct {
 	Email    string
 	CNPJ     string // CNPJ encontrado pelo scraper (se disponível)
 	Rating   string
-	Source   string
+	Source   string // fontes contribuintes unidas por "+" (ver Sources)
+
+	RatingCount string // número de avaliações por trás de Rating (só dígitos)
+	PriceLevel  string // faixa de preço como veio da fonte (ex: "$$", "R$ 20-50")
+
+	// Horário no formato opening_hours do OSM quando reconhecido (ex:
+	// "Mo-Fr 08:00-18:00; Sa 08:00-12:00"), senão como veio da fonte.
+	// Ver ParseOpeningHours e Lead.Schedule.
+	OpeningHours string
+
+	// Preenchidos na deduplicação (ver mergeLead)
+	Phones  []string // todos os telefones distintos; Phone e Phone2 são os dois primeiros
+	Sources []string // fontes que contribuíram para o lead, na ordem
+
+	// Coordenadas (Overpass, Geoapify, TomTom); 0,0 = desconhecido
+	Lat        float64
+	Lon        float64
+	DistanceKm float64 // distância ao centro da busca (ver FilterByRadius)
+
+	// Telefone principal normalizado (ver AnnotatePhone)
+	PhoneE164          string // ex: +5543999998888
+	PhoneType          string // mobile, landline, toll_free, shared_cost
+	PhoneStateMismatch bool   // DDD de outra UF que não a do lead
 
 	// Enriquecimento CNPJ (via find-cnpj)
 	RazaoSocial  string
 	NomeFantasia string
-	Situacao     string   // ex: ATIVA, BAIXADA, INAPTA
+	Situacao     string // ex: ATIVA, BAIXADA, INAPTA
 	CNAECode     string
 	CNAEDesc     string
 	Municipio    string
@@ -35,15 +59,91 @@ type Lead struct {
 	// Enriquecimento Instagram (via find-instagram)
 	Instagram string
 	Followers string
+
+	// Enriquecimento pelo site oficial (ver EnrichWebsite)
+	WhatsApp    string            // número com DDI, só dígitos (ex: 5543999998888)
+	SocialLinks map[string]string // rede → URL do perfil
+
+	// Leads vindos só de fontes de IA (ver VerifyAILeads): Unverified marca
+	// o lead e Checks lista as evidências que o mantiveram e as correções
+	// feitas (ex: "telefone +5543999998888 também em Solutudo").
+	Unverified bool
+	Checks     []string
+
+	// Provenance registra a origem dos campos vindos do merge entre fontes ou
+	// do enriquecimento (campo → fonte, ex: "address" → "TomTom Places",
+	// "email" → "website:search").
+	Provenance map[string]string
+}
+
+// SetField preenche *dst com value somente se estiver vazio, registrando a
+// origem do valor em Provenance. Retorna true se o campo foi preenchido.
+func (l *Lead) SetField(field string, dst *string, value, source string) bool {
+	value = strings.TrimSpace(value)
+	if *dst != "" || value == "" {
+		return false
+	}
+	*dst = value
+	l.setProvenance(field, source)
+	return true
+}
+
+func (l *Lead) setProvenance(field, source string) {
+	if l.Provenance == nil {
+		l.Provenance = make(map[string]string)
+	}
+	l.Provenance[field] = source
 }
 
 func (l *Lead) NormalizedName() string {
 	return normalizeString(l.Name)
 }
 
+// NormalizedPhone retorna a chave do telefone principal: E.164 quando o
+// número é válido, senão apenas os dígitos.
 func (l *Lead) NormalizedPhone() string {
-	re := regexp.MustCompile(`\D`)
-	return re.ReplaceAllString(l.Phone, "")
+	return phonepkg.Key(l.Phone)
+}
+
+// AnnotatePhone preenche PhoneE164, PhoneType e PhoneStateMismatch a partir
+// do telefone principal (ou do secundário, se o principal for inválido).
+// A UF comparada é a do cadastro CNPJ quando existir, senão a do scraper.
+func (l *Lead) AnnotatePhone() {
+	l.PhoneE164, l.PhoneType, l.PhoneStateMismatch = "", "", false
+	for _, raw := range []string{l.Phone, l.Phone2} {
+		n, err := phonepkg.Parse(raw)
+		if err != nil {
+			continue
+		}
+		l.PhoneE164 = n.E164()
+		l.PhoneType = string(n.Type)
+		uf := l.UF
+		if uf == "" {
+			uf = l.State
+		}
+		l.PhoneStateMismatch = !n.MatchesState(uf)
+		return
+	}
+}
+
+// WhatsAppLink retorna o link wa.me do lead: o WhatsApp encontrado no site
+// ou, na falta dele, o telefone principal quando for celular.
+func (l *Lead) WhatsAppLink() string {
+	if l.WhatsApp != "" {
+		return "https://wa.me/" + l.WhatsApp
+	}
+	for _, raw := range []string{l.Phone, l.Phone2} {
+		if n, err := phonepkg.Parse(raw); err == nil && n.Type == phonepkg.Mobile {
+			return n.WhatsAppLink()
+		}
+	}
+	return ""
+}
+
+// Schedule interpreta OpeningHours. Retorna false se o horário estiver vazio
+// ou não for reconhecido.
+func (l *Lead) Schedule() (*Schedule, bool) {
+	return ParseOpeningHours(l.OpeningHours)
 }
 
 func normalizeString(s string) string {
@@ -76,149 +176,16 @@ func normalizeString(s string) string {
 	return strings.TrimSpace(result)
 }
 
-// wordBagKey retorna uma chave canônica para deduplicação por "bag-of-words":
-// lowercase sem acentos, palavras ordenadas alfabeticamente.
-// Isso captura variações de ordem como "Loja de Roupas XYZ" == "XYZ Loja de Roupas".
-func wordBagKey(name string) string {
-	normalized := normalizeString(name)
-	words := strings.Fields(normalized)
-	if len(words) < 2 {
-		return "" // nomes de palavra única: sem vantagem em usar word-bag
-	}
-	sort.Strings(words)
-	return strings.Join(words, " ")
-}
-
-// Deduplicate remove leads duplicados priorizando os com mais dados
-func Deduplicate(leadsList []*Lead) []*Lead {
-	byPhone := make(map[string]*Lead)
-	byName := make(map[string]*Lead)
-	byWordBag := make(map[string]*Lead)
-	var result []*Lead
-
-	score := func(l *Lead) int {
-		s := 0
-		if l.Phone != "" {
-			s += 3
-		}
-		if l.Address != "" {
-			s += 2
-		}
-		if l.CNPJ != "" {
-			s += 2
-		}
-		if l.Website != "" {
-			s++
-		}
-		if l.Email != "" {
-			s++
-		}
-		if l.Category != "" {
-			s++
-		}
-		return s
-	}
-	// swap faz o incoming se tornar o lead canônico quando tem mais dados,
-	// preservando campos extras do existing que o incoming não tem.
-	swap := func(existing, incoming *Lead) {
-		if score(incoming) <= score(existing) {
-			return
-		}
-		// incoming é melhor: troca os valores in-place mantendo o ponteiro do result
-		oldSource := existing.Source
-		*existing = *incoming
-		// preserva source combinado
-		if !strings.Contains(existing.Source, oldSource) {
-			existing.Source = oldSource + "+" + incoming.Source
-		}
-	}
-	merge := func(existing, incoming *Lead) {
-		if existing.Phone == "" && incoming.Phone != "" {
-			existing.Phone = incoming.Phone
-		}
-		if existing.Phone2 == "" && incoming.Phone != "" && incoming.Phone != existing.Phone {
-			existing.Phone2 = incoming.Phone
-		}
-		if existing.Address == "" && incoming.Address != "" {
-			existing.Address = incoming.Address
-		}
-		if existing.Website == "" && incoming.Website != "" {
-			existing.Website = incoming.Website
-		}
-		if existing.Email == "" && incoming.Email != "" {
-			existing.Email = incoming.Email
-		}
-		if existing.CNPJ == "" && incoming.CNPJ != "" {
-			existing.CNPJ = incoming.CNPJ
-		}
-		if existing.Category == "" && incoming.Category != "" {
-			existing.Category = incoming.Category
-		}
-		if existing.Rating == "" && incoming.Rating != "" {
-			existing.Rating = incoming.Rating
-		}
-		if !strings.Contains(existing.Source, incoming.Source) {
-			existing.Source += "+" + incoming.Source
-		}
-	}
-
-	for _, lead := range leadsList {
-		if lead.Name == "" {
-			continue
-		}
-		phone := lead.NormalizedPhone()
-		name := lead.NormalizedName()
-		bagKey := wordBagKey(lead.Name)
-
-		if phone != "" && len(phone) >= 8 {
-			if existing, ok := byPhone[phone]; ok {
-				swap(existing, lead)
-				merge(existing, lead)
-				if name != "" {
-					byName[name] = existing
-				}
-				if bagKey != "" {
-					byWordBag[bagKey] = existing
-				}
-				continue
-			}
-		}
-		if name != "" {
-			if existing, ok := byName[name]; ok {
-				swap(existing, lead)
-				merge(existing, lead)
-				if phone != "" && len(phone) >= 8 {
-					byPhone[phone] = existing
-				}
-				if bagKey != "" {
-					byWordBag[bagKey] = existing
-				}
-				continue
-			}
-		}
-		if bagKey != "" {
-			if existing, ok := byWordBag[bagKey]; ok {
-				swap(existing, lead)
-				merge(existing, lead)
-				if phone != "" && len(phone) >= 8 {
-					byPhone[phone] = existing
-				}
-				if name != "" {
-					byName[name] = existing
-				}
-				continue
-			}
-		}
-		result = append(result, lead)
-		if phone != "" && len(phone) >= 8 {
-			byPhone[phone] = lead
-		}
-		if name != "" {
-			byName[name] = lead
-		}
-		if bagKey != "" {
-			byWordBag[bagKey] = lead
-		}
+// normalizePhone formata o telefone no padrão nacional ("(43) 99999-8888").
+// Números sem DDD são mantidos como vieram; DDD inválido ou número de
+// preenchimento retornam "".
+func normalizePhone(phone string) string {
+	n, err := phonepkg.Parse(phone)
+	switch {
+	case err == nil:
+		return n.Format()
+	case errors.Is(err, phonepkg.ErrLength):
+		return strings.TrimSpace(phone)
 	}
-	return result
+	return ""
 }
@@ -0,0 +1,229 @@
+package leads
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ─── Cliente LLM ─────────────────────────────────────────────────────────────
+
+// LLMRequest é uma chamada de completion com saída JSON.
+type LLMRequest struct {
+	System string // instruções de sistema (opcional)
+	Prompt string
+	// Schema é o JSON Schema da resposta. Com ele o provedor restringe a
+	// saída a JSON válido nesse formato (structured output).
+	Schema      map[string]any
+	SchemaName  string // nome do schema (OpenAI exige um)
+	MaxTokens   int
+	Temperature float64
+}
+
+// LLMClient é um provedor de LLM: endpoints compatíveis com a API de chat da
+// OpenAI (Groq, Ollama, llama.cpp) ou Gemini. Complete devolve o texto da
+// resposta, que deve ser JSON quando Schema foi informado.
+type LLMClient interface {
+	Name() string
+	Complete(ctx context.Context, req LLMRequest) (string, error)
+}
+
+// ─── OpenAI-compatível ───────────────────────────────────────────────────────
+
+// OpenAIClient fala com /chat/completions de qualquer servidor compatível
+// com a OpenAI: Groq, Ollama (http://localhost:11434/v1), llama.cpp server.
+//
+// Com Schema, pede response_format json_schema. Modelos sem structured
+// output (na Groq, llama-3.3-70b-versatile, o padrão) recusam com 400; aí o
+// cliente passa a usar json_object com o schema nas instruções de sistema.
+type OpenAIClient struct {
+	BaseURL string // até /v1, sem /chat/completions
+	APIKey  string // vazio para servidores locais
+	Model   string
+	Label   string // nome de exibição (padrão: o modelo)
+	HTTP    *http.Client
+
+	schemaRejected atomic.Bool // o modelo recusou json_schema
+}
+
+func NewOpenAIClient(baseURL, apiKey, model string) *OpenAIClient {
+	return &OpenAIClient{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		APIKey:  apiKey,
+		Model:   model,
+		HTTP:    &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+func (c *OpenAIClient) Name() string {
+	if c.Label != "" {
+		return c.Label
+	}
+	return c.Model
+}
+
+func (c *OpenAIClient) Complete(ctx context.Context, r LLMRequest) (string, error) {
+	useSchema := r.Schema != nil && !c.schemaRejected.Load()
+	content, err := c.complete(ctx, r, useSchema)
+	if useSchema && isSchemaRejection(err) {
+		c.schemaRejected.Store(true)
+		content, err = c.complete(ctx, r, false)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", c.Name(), err)
+	}
+	return content, nil
+}
+
+// complete faz uma chamada a /chat/completions. Sem jsonSchema, um Schema
+// vai nas instruções de sistema e a resposta é pedida como json_object.
+func (c *OpenAIClient) complete(ctx context.Context, r LLMRequest, jsonSchema bool) (string, error) {
+	system := r.System
+	if r.Schema != nil && !jsonSchema {
+		schema, err := json.Marshal(r.Schema)
+		if err != nil {
+			return "", err
+		}
+		if system != "" {
+			system += "\n\n"
+		}
+		system += "Responda apenas com um objeto JSON válido que siga este JSON Schema:\n" + string(schema)
+	}
+
+	var messages []map[string]string
+	if system != "" {
+		messages = append(messages, map[string]string{"role": "system", "content": system})
+	}
+	messages = append(messages, map[string]string{"role": "user", "content": r.Prompt})
+
+	body := map[string]any{
+		"model":       c.Model,
+		"messages":    messages,
+		"temperature": r.Temperature,
+	}
+	if r.MaxTokens > 0 {
+		body["max_tokens"] = r.MaxTokens
+	}
+	switch {
+	case r.Schema != nil && jsonSchema:
+		name := r.SchemaName
+		if name == "" {
+			name = "response"
+		}
+		body["response_format"] = map[string]any{
+			"type": "json_schema",
+			"json_schema": map[string]any{
+				"name":   name,
+				"schema": r.Schema,
+			},
+		}
+	case r.Schema != nil:
+		body["response_format"] = map[string]any{"type": "json_object"}
+	}
+
+	headers := map[string]string{}
+	if c.APIKey != "" {
+		headers["Authorization"] = "Bearer " + c.APIKey
+	}
+	var out struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := postJSON(ctx, c.HTTP, c.BaseURL+"/chat/completions", headers, body, &out); err != nil {
+		return "", err
+	}
+	if len(out.Choices) == 0 {
+		return "", fmt.Errorf("resposta vazia")
+	}
+	return out.Choices[0].Message.Content, nil
+}
+
+// isSchemaRejection reconhece o 400 de um modelo sem suporte a json_schema.
+func isSchemaRejection(err error) bool {
+	var se *statusError
+	if !errors.As(err, &se) || se.Status != http.StatusBadRequest {
+		return false
+	}
+	msg := strings.ToLower(se.Body)
+	return strings.Contains(msg, "json_schema") || strings.Contains(msg, "response_format")
+}
+
+// statusError é uma resposta HTTP diferente de 200, com o início do corpo.
+type statusError struct {
+	Status int
+	Body   string
+}
+
+func (e *statusError) Error() string { return fmt.Sprintf("status %d: %s", e.Status, e.Body) }
+
+// postJSON envia body como JSON e decodifica a resposta em out; status
+// diferente de 200 vira erro com o início do corpo.
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 300))
+		return &statusError{Status: resp.StatusCode, Body: strings.TrimSpace(string(msg))}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+	return nil
+}
+
+// ─── Fake ────────────────────────────────────────────────────────────────────
+
+// FakeLLM é um LLMClient em memória para testes: devolve Responses em ordem
+// (a última se repete) ou o resultado de Func, e guarda as chamadas feitas.
+type FakeLLM struct {
+	Responses []string
+	Func      func(LLMRequest) (string, error)
+
+	mu       sync.Mutex
+	Requests []LLMRequest
+}
+
+func (f *FakeLLM) Name() string { return "fake" }
+
+func (f *FakeLLM) Complete(_ context.Context, r LLMRequest) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := len(f.Requests)
+	f.Requests = append(f.Requests, r)
+	if f.Func != nil {
+		return f.Func(r)
+	}
+	if len(f.Responses) == 0 {
+		return "", fmt.Errorf("fake: sem respostas")
+	}
+	if n >= len(f.Responses) {
+		n = len(f.Responses) - 1
+	}
+	return f.Responses[n], nil
+}
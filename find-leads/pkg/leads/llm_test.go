@@ -0,0 +1,145 @@
+package leads
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// chatServer simula /chat/completions: reject decide, pelo corpo do pedido,
+// se responde 400 com a mensagem dada; senão devolve content. Os corpos
+// recebidos ficam em requests.
+type chatServer struct {
+	mu       sync.Mutex
+	requests []map[string]any
+}
+
+func (s *chatServer) start(t *testing.T, reject func(body map[string]any) string, content string) *OpenAIClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" || r.Header.Get("Authorization") != "Bearer chave" {
+			http.Error(w, "rota ou chave inesperada", http.StatusNotFound)
+			return
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, body)
+		s.mu.Unlock()
+		if msg := reject(body); msg != "" {
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []any{map[string]any{"message": map[string]any{"content": content}}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return NewOpenAIClient(srv.URL+"/v1/", "chave", "llama-3.3-70b-versatile")
+}
+
+func responseFormat(body map[string]any) string {
+	rf, _ := body["response_format"].(map[string]any)
+	t, _ := rf["type"].(string)
+	return t
+}
+
+func systemMessage(body map[string]any) string {
+	msgs, _ := body["messages"].([]any)
+	for _, m := range msgs {
+		if m, ok := m.(map[string]any); ok && m["role"] == "system" {
+			s, _ := m["content"].(string)
+			return s
+		}
+	}
+	return ""
+}
+
+var llmTestSchema = map[string]any{
+	"type":       "object",
+	"properties": map[string]any{"leads": map[string]any{"type": "array"}},
+	"required":   []any{"leads"},
+}
+
+// Mensagem da Groq para modelos sem structured output.
+const groqSchemaRejection = `{"error":{"message":"This model does not support response format ` + "`json_schema`" + `. See supported models at https://console.groq.com/docs/structured-outputs","type":"invalid_request_error"}}`
+
+func TestOpenAIClientFallsBackToJSONObject(t *testing.T) {
+	var s chatServer
+	c := s.start(t, func(body map[string]any) string {
+		if responseFormat(body) == "json_schema" {
+			return groqSchemaRejection
+		}
+		return ""
+	}, `{"leads": []}`)
+
+	req := LLMRequest{System: "Extraia empresas.", Prompt: "Padarias em Londrina", Schema: llmTestSchema, SchemaName: "leads"}
+	for i := 0; i < 2; i++ {
+		got, err := c.Complete(context.Background(), req)
+		if err != nil || got != `{"leads": []}` {
+			t.Fatalf("Complete #%d = %q, %v", i+1, got, err)
+		}
+	}
+
+	// json_schema recusado uma vez; depois só json_object.
+	var formats []string
+	for _, b := range s.requests {
+		formats = append(formats, responseFormat(b))
+	}
+	if strings.Join(formats, ",") != "json_schema,json_object,json_object" {
+		t.Errorf("response_format enviados = %v", formats)
+	}
+	if sys := systemMessage(s.requests[0]); sys != "Extraia empresas." {
+		t.Errorf("system com json_schema = %q", sys)
+	}
+	sys := systemMessage(s.requests[1])
+	if !strings.HasPrefix(sys, "Extraia empresas.\n\n") || !strings.Contains(sys, "JSON") || !strings.Contains(sys, `"required":["leads"]`) {
+		t.Errorf("system com json_object deveria trazer o schema: %q", sys)
+	}
+}
+
+func TestOpenAIClientJSONSchema(t *testing.T) {
+	var s chatServer
+	c := s.start(t, func(map[string]any) string { return "" }, `{"leads": []}`)
+
+	if _, err := c.Complete(context.Background(), LLMRequest{Prompt: "x", Schema: llmTestSchema}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Complete(context.Background(), LLMRequest{Prompt: "y"}); err != nil {
+		t.Fatal(err)
+	}
+	rf, _ := s.requests[0]["response_format"].(map[string]any)
+	js, _ := rf["json_schema"].(map[string]any)
+	if rf["type"] != "json_schema" || js["name"] != "response" || js["schema"] == nil {
+		t.Errorf("response_format = %v", rf)
+	}
+	if systemMessage(s.requests[0]) != "" {
+		t.Errorf("sem System não deveria haver mensagem de sistema: %v", s.requests[0]["messages"])
+	}
+	if _, ok := s.requests[1]["response_format"]; ok {
+		t.Errorf("sem Schema não deveria haver response_format: %v", s.requests[1])
+	}
+}
+
+func TestOpenAIClientOtherErrors(t *testing.T) {
+	var s chatServer
+	c := s.start(t, func(map[string]any) string {
+		return `{"error":{"message":"The model ` + "`llama-9`" + ` does not exist","type":"invalid_request_error"}}`
+	}, "")
+	c.Label = "Groq"
+
+	_, err := c.Complete(context.Background(), LLMRequest{Prompt: "x", Schema: llmTestSchema})
+	if err == nil || !strings.HasPrefix(err.Error(), "Groq: status 400: ") {
+		t.Errorf("err = %v", err)
+	}
+	if len(s.requests) != 1 {
+		t.Errorf("400 sem relação com o schema não deveria repetir o pedido: %d pedidos", len(s.requests))
+	}
+}
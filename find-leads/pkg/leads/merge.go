@@ -0,0 +1,244 @@
+package leads
+
+import (
+	"strings"
+
+	phonepkg "github.com/lucasfdcampos/find-cnpj/pkg/phone"
+)
+
+// ─── Confiança por fonte ─────────────────────────────────────────────────────
+
+// sourceTrust é a confiança padrão de cada fonte (maior = mais confiável).
+// APIs de mapas têm endereço e coordenadas geocodificados; guias trazem
+// telefone conferido; buscadores e IA extraem de trechos de texto.
+var sourceTrust = map[string]int{
+	"TomTom Places": 90,
+	"Geoapify":      85,
+	"OpenStreetMap": 80,
+	"Solutudo":      70,
+	"GuiaMais":      70,
+	"Apontador":     65,
+	"TeleListas":    65,
+	"AppLocal":      60,
+	"DuckDuckGo":    30,
+	"Bing":          30,
+	"Brave Search":  30,
+	"Yandex":        30,
+	"SearXNG":       30,
+	"Mojeek":        30,
+	"Swisscows":     30,
+	"Groq AI":       10,
+	"Gemini AI":     10,
+	"LLM local":     10,
+}
+
+// fieldTrust sobrescreve sourceTrust para campos específicos.
+var fieldTrust = map[string]map[string]int{
+	// Listas telefônicas e guias conferem o telefone; no OSM ele costuma faltar
+	// ou estar desatualizado.
+	"phone": {"TeleListas": 88, "GuiaMais": 86, "Solutudo": 84, "Apontador": 82, "OpenStreetMap": 60},
+	// As tags do OSM (shop=, amenity=) são mais precisas que as categorias dos guias.
+	"category": {"OpenStreetMap": 92},
+}
+
+// defaultTrust é a confiança de fontes desconhecidas (ex: enriquecimento).
+const defaultTrust = 40
+
+// SourceTrust retorna a confiança da fonte para o campo (ver fieldTrust).
+func SourceTrust(source, field string) int {
+	if ranks, ok := fieldTrust[field]; ok {
+		if r, ok := ranks[source]; ok {
+			return r
+		}
+	}
+	if r, ok := sourceTrust[source]; ok {
+		return r
+	}
+	return defaultTrust
+}
+
+// ─── Merge campo a campo ─────────────────────────────────────────────────────
+
+// mergeLead incorpora incoming em existing campo a campo: cada campo fica com
+// o valor da fonte mais confiável para ele (empate mantém o existente), a
+// fonte vencedora vai para Provenance, todos os telefones distintos vão para
+// Phones e as fontes para Sources.
+func mergeLead(existing, incoming *Lead) {
+	existing.normalizeSources()
+	incoming.normalizeSources()
+	existing.normalizePhones()
+	incoming.normalizePhones()
+
+	fields := []struct {
+		name     string
+		dst, src *string
+	}{
+		{"name", &existing.Name, &incoming.Name},
+		{"address", &existing.Address, &incoming.Address},
+		{"category", &existing.Category, &incoming.Category},
+		{"website", &existing.Website, &incoming.Website},
+		{"email", &existing.Email, &incoming.Email},
+		{"cnpj", &existing.CNPJ, &incoming.CNPJ},
+		{"rating", &existing.Rating, &incoming.Rating},
+		{"rating_count", &existing.RatingCount, &incoming.RatingCount},
+		{"price_level", &existing.PriceLevel, &incoming.PriceLevel},
+		{"opening_hours", &existing.OpeningHours, &incoming.OpeningHours},
+		{"whatsapp", &existing.WhatsApp, &incoming.WhatsApp},
+		{"instagram", &existing.Instagram, &incoming.Instagram},
+		{"city", &existing.City, &incoming.City},
+		{"state", &existing.State, &incoming.State},
+	}
+	for _, f := range fields {
+		if *f.src == "" {
+			continue
+		}
+		inSrc := incoming.fieldSource(f.name)
+		if *f.dst == "" || SourceTrust(inSrc, f.name) > SourceTrust(existing.fieldSource(f.name), f.name) {
+			*f.dst = *f.src
+			existing.setProvenance(f.name, inSrc)
+		}
+	}
+
+	if incoming.HasCoords() {
+		inSrc := incoming.fieldSource("coords")
+		if !existing.HasCoords() || SourceTrust(inSrc, "coords") > SourceTrust(existing.fieldSource("coords"), "coords") {
+			existing.Lat, existing.Lon = incoming.Lat, incoming.Lon
+			existing.setProvenance("coords", inSrc)
+		}
+	}
+
+	// Telefones: todos os distintos; o principal é o da fonte mais confiável.
+	inPhoneSrc := incoming.fieldSource("phone")
+	promote := len(incoming.Phones) > 0 &&
+		(len(existing.Phones) == 0 || SourceTrust(inPhoneSrc, "phone") > SourceTrust(existing.fieldSource("phone"), "phone"))
+	for _, p := range incoming.Phones {
+		existing.AddPhone(p, inPhoneSrc)
+	}
+	if promote {
+		existing.setPrimaryPhone(incoming.Phones[0], inPhoneSrc)
+	}
+
+	for _, s := range incoming.Sources {
+		existing.addSource(s)
+	}
+
+	// Um lead de IA unido a um de outra fonte deixa de ser não verificado.
+	existing.Unverified = existing.Unverified && incoming.Unverified
+	existing.addChecks(incoming.Checks...)
+}
+
+// fieldSource retorna a fonte do valor atual do campo: a registrada em
+// Provenance ou, se não houver, a primeira fonte do lead.
+func (l *Lead) fieldSource(field string) string {
+	if s, ok := l.Provenance[field]; ok {
+		return s
+	}
+	if len(l.Sources) > 0 {
+		return l.Sources[0]
+	}
+	return firstSource(l.Source)
+}
+
+// normalizeSources preenche Sources a partir de Source ("A+B") quando vazio.
+func (l *Lead) normalizeSources() {
+	if len(l.Sources) > 0 || l.Source == "" {
+		return
+	}
+	for _, s := range strings.Split(l.Source, "+") {
+		l.addSource(s)
+	}
+}
+
+// addSource registra uma fonte contribuinte e mantém Source = "A+B+C".
+func (l *Lead) addSource(src string) {
+	src = strings.TrimSpace(src)
+	if src == "" {
+		return
+	}
+	for _, s := range l.Sources {
+		if s == src {
+			return
+		}
+	}
+	l.Sources = append(l.Sources, src)
+	l.Source = strings.Join(l.Sources, "+")
+}
+
+// normalizePhones garante que Phone e Phone2 estejam em Phones.
+func (l *Lead) normalizePhones() {
+	for _, p := range []string{l.Phone, l.Phone2} {
+		l.appendPhone(p)
+	}
+}
+
+// AddPhone registra um telefone no lead se ainda não existir (comparando
+// pela chave E.164). Phone e Phone2 são preenchidos na ordem, com a origem
+// em Provenance. Retorna true se o telefone era novo.
+func (l *Lead) AddPhone(raw, source string) bool {
+	l.normalizePhones()
+	if !l.appendPhone(raw) {
+		return false
+	}
+	raw = strings.TrimSpace(raw)
+	switch {
+	case l.Phone == "":
+		l.Phone = raw
+		l.setProvenance("phone", source)
+	case l.Phone2 == "":
+		l.Phone2 = raw
+		l.setProvenance("phone2", source)
+	}
+	return true
+}
+
+// addScrapedPhone normaliza e registra um telefone extraído pela própria
+// fonte do lead (sem Provenance).
+func (l *Lead) addScrapedPhone(raw string) {
+	n := normalizePhone(raw)
+	if n == "" || !l.appendPhone(n) {
+		return
+	}
+	switch {
+	case l.Phone == "":
+		l.Phone = n
+	case l.Phone2 == "":
+		l.Phone2 = n
+	}
+}
+
+func (l *Lead) appendPhone(raw string) bool {
+	raw = strings.TrimSpace(raw)
+	key := phonepkg.Key(raw)
+	if key == "" {
+		return false
+	}
+	for _, p := range l.Phones {
+		if phonepkg.Key(p) == key {
+			return false
+		}
+	}
+	l.Phones = append(l.Phones, raw)
+	return true
+}
+
+// setPrimaryPhone move raw para o início de Phones e refaz Phone/Phone2.
+func (l *Lead) setPrimaryPhone(raw, source string) {
+	key := phonepkg.Key(raw)
+	if phonepkg.Key(l.Phone) == key {
+		return
+	}
+	prevSrc := l.fieldSource("phone")
+	for i, p := range l.Phones {
+		if phonepkg.Key(p) == key {
+			copy(l.Phones[1:i+1], l.Phones[:i])
+			l.Phones[0] = p
+			break
+		}
+	}
+	l.Phone, l.Phone2 = l.Phones[0], ""
+	if len(l.Phones) > 1 {
+		l.Phone2 = l.Phones[1]
+	}
+	l.setProvenance("phone", source)
+	l.setProvenance("phone2", prevSrc)
+}
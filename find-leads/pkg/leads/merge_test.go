@@ -0,0 +1,155 @@
+package leads
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSourceTrust(t *testing.T) {
+	tests := []struct {
+		source, field string
+		want          int
+	}{
+		{"TomTom Places", "address", 90},
+		{"OpenStreetMap", "address", 80},
+		{"OpenStreetMap", "phone", 60}, // fieldTrust
+		{"TeleListas", "phone", 88},
+		{"OpenStreetMap", "category", 92},
+		{"Groq AI", "name", 10},
+		{"website:search", "email", defaultTrust},
+	}
+	for _, tt := range tests {
+		if got := SourceTrust(tt.source, tt.field); got != tt.want {
+			t.Errorf("SourceTrust(%q, %q) = %d, want %d", tt.source, tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestMergeLeadHigherTrustWins(t *testing.T) {
+	// O lead mantido veio do Solutudo (70); o TomTom (90) vence em endereço e
+	// nome, o OSM só na categoria (92 contra 70) e perde no telefone (60 contra 84).
+	existing := &Lead{
+		Name:     "Padaria Trigo de Ouro",
+		Address:  "R. Sergipe 500",
+		Category: "Padarias",
+		Phone:    "(43) 3325-4471",
+		Source:   "Solutudo",
+	}
+	tomtom := &Lead{
+		Name:    "Padaria Trigo De Ouro",
+		Address: "Rua Sergipe, 500 - Centro",
+		Lat:     -23.3105, Lon: -51.1628,
+		Source: "TomTom Places",
+	}
+	osm := &Lead{
+		Name:     "Trigo de Ouro",
+		Category: "bakery",
+		Phone:    "(43) 99812-3456",
+		Lat:      -23.3000, Lon: -51.1000,
+		Source: "OpenStreetMap",
+	}
+	mergeLead(existing, tomtom)
+	mergeLead(existing, osm)
+
+	want := map[string]string{
+		"Name":     "Padaria Trigo De Ouro",
+		"Address":  "Rua Sergipe, 500 - Centro",
+		"Category": "bakery",
+		"Phone":    "(43) 3325-4471",
+		"Phone2":   "(43) 99812-3456",
+		"Source":   "Solutudo+TomTom Places+OpenStreetMap",
+	}
+	got := map[string]string{
+		"Name": existing.Name, "Address": existing.Address, "Category": existing.Category,
+		"Phone": existing.Phone, "Phone2": existing.Phone2, "Source": existing.Source,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("lead unido:\n got  %v\n want %v", got, want)
+	}
+	if existing.Lat != -23.3105 || existing.Lon != -51.1628 {
+		t.Errorf("coordenadas = %v,%v, want as do TomTom", existing.Lat, existing.Lon)
+	}
+
+	wantProv := map[string]string{
+		"name":     "TomTom Places",
+		"address":  "TomTom Places",
+		"coords":   "TomTom Places",
+		"category": "OpenStreetMap",
+		"phone2":   "OpenStreetMap",
+	}
+	for field, src := range wantProv {
+		if existing.Provenance[field] != src {
+			t.Errorf("Provenance[%q] = %q, want %q", field, existing.Provenance[field], src)
+		}
+	}
+	if _, ok := existing.Provenance["phone"]; ok {
+		t.Errorf("telefone do Solutudo não deveria ter Provenance: %v", existing.Provenance)
+	}
+}
+
+func TestMergeLeadFillsEmptyFromLowerTrust(t *testing.T) {
+	existing := &Lead{
+		Name:    "Padaria Trigo de Ouro",
+		Address: "Rua Sergipe, 500 - Centro",
+		Source:  "TomTom Places",
+	}
+	ai := &Lead{
+		Name:       "Trigo de Ouro Panificadora",
+		Address:    "Sergipe 500",
+		Website:    "https://padariatrigo.com.br",
+		Email:      "contato@padariatrigo.com.br",
+		Phone:      "(43) 3325-4471",
+		Source:     "Groq AI",
+		Unverified: true,
+		Checks:     []string{"nome no texto da busca"},
+	}
+	mergeLead(existing, ai)
+
+	if existing.Name != "Padaria Trigo de Ouro" || existing.Address != "Rua Sergipe, 500 - Centro" {
+		t.Errorf("fonte menos confiável sobrescreveu campos preenchidos: %+v", existing)
+	}
+	if existing.Website != ai.Website || existing.Email != ai.Email || existing.Phone != ai.Phone {
+		t.Errorf("campos vazios não preenchidos pela IA: %+v", existing)
+	}
+	for _, field := range []string{"website", "email", "phone"} {
+		if existing.Provenance[field] != "Groq AI" {
+			t.Errorf("Provenance[%q] = %q, want Groq AI", field, existing.Provenance[field])
+		}
+	}
+	if existing.Unverified {
+		t.Error("lead unido a outra fonte não deveria ficar Unverified")
+	}
+	if !reflect.DeepEqual(existing.Checks, ai.Checks) {
+		t.Errorf("Checks = %v", existing.Checks)
+	}
+}
+
+func TestMergeLeadTieKeepsExisting(t *testing.T) {
+	existing := &Lead{Name: "Padaria Trigo de Ouro", Address: "Rua Sergipe, 500", Source: "Solutudo"}
+	other := &Lead{Name: "Trigo de Ouro", Address: "Av. Higienópolis, 1200", Source: "GuiaMais"}
+	mergeLead(existing, other)
+
+	if existing.Name != "Padaria Trigo de Ouro" || existing.Address != "Rua Sergipe, 500" {
+		t.Errorf("empate deveria manter o existente: %+v", existing)
+	}
+	if !reflect.DeepEqual(existing.Sources, []string{"Solutudo", "GuiaMais"}) {
+		t.Errorf("Sources = %v", existing.Sources)
+	}
+}
+
+func TestMergeLeadPromotesTrustedPhone(t *testing.T) {
+	// O telefone do OSM (60) entra primeiro; o da TeleListas (88) vira o principal.
+	existing := &Lead{Name: "Padaria Trigo de Ouro", Phone: "(43) 99812-3456", Source: "OpenStreetMap"}
+	tele := &Lead{Name: "Padaria Trigo de Ouro", Phone: "(43) 3325-4471", Source: "TeleListas"}
+	mergeLead(existing, tele)
+
+	if existing.Phone != "(43) 3325-4471" || existing.Phone2 != "(43) 99812-3456" {
+		t.Errorf("Phone = %q, Phone2 = %q", existing.Phone, existing.Phone2)
+	}
+	if existing.Provenance["phone"] != "TeleListas" || existing.Provenance["phone2"] != "OpenStreetMap" {
+		t.Errorf("Provenance = %v", existing.Provenance)
+	}
+	if !reflect.DeepEqual(existing.Phones, []string{"(43) 3325-4471", "(43) 99812-3456"}) {
+		t.Errorf("Phones = %v", existing.Phones)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/csv"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -62,10 +64,12 @@ func SaveCSV(leads []*Lead, filename string) error {
 	defer w.Flush()
 
 	header := []string{
-		"#", "Nome", "Telefone", "Telefone2", "Endereco", "Cidade", "Estado",
+		"#", "Nome", "Telefone", "Telefone2", "OutrosTelefones", "TelefoneE164", "TipoTelefone", "Endereco", "Cidade", "Estado",
+		"Latitude", "Longitude", "DistanciaKm",
 		"Categoria", "Website", "Email", "CNPJ", "RazaoSocial", "NomeFantasia",
 		"Situacao", "CNAECode", "CNAEDesc", "Municipio", "UF", "Socios",
-		"Instagram", "Seguidores", "Avaliacao", "Fontes",
+		"Instagram", "Seguidores", "WhatsApp", "Redes", "Avaliacao", "NumAvaliacoes", "FaixaPreco", "Horario", "Fontes",
+		"NaoVerificado", "Verificacao",
 	}
 	if err := w.Write(header); err != nil {
 		return err
@@ -77,9 +81,15 @@ func SaveCSV(leads []*Lead, filename string) error {
 			l.Name,
 			l.Phone,
 			l.Phone2,
+			strings.Join(extraPhones(l), " | "),
+			l.PhoneE164,
+			l.PhoneType,
 			l.Address,
 			l.City,
 			l.State,
+			formatCoord(l.Lat, l.HasCoords()),
+			formatCoord(l.Lon, l.HasCoords()),
+			formatCoord(l.DistanceKm, l.DistanceKm > 0),
 			l.Category,
 			l.Website,
 			l.Email,
@@ -94,8 +104,15 @@ func SaveCSV(leads []*Lead, filename string) error {
 			strings.Join(l.Partners, " | "),
 			l.Instagram,
 			l.Followers,
+			l.WhatsAppLink(),
+			joinSocialLinks(l.SocialLinks),
 			l.Rating,
+			l.RatingCount,
+			l.PriceLevel,
+			l.OpeningHours,
 			l.Source,
+			yesNo(l.Unverified),
+			strings.Join(l.Checks, " | "),
 		}
 		if err := w.Write(row); err != nil {
 			return err
@@ -105,6 +122,27 @@ func SaveCSV(leads []*Lead, filename string) error {
 	return nil
 }
 
+func yesNo(b bool) string {
+	if b {
+		return "sim"
+	}
+	return ""
+}
+
+// joinSocialLinks serializa as redes sociais em ordem estável: "facebook=URL | instagram=URL".
+func joinSocialLinks(links map[string]string) string {
+	networks := make([]string, 0, len(links))
+	for n := range links {
+		networks = append(networks, n)
+	}
+	sort.Strings(networks)
+	parts := make([]string, 0, len(networks))
+	for _, n := range networks {
+		parts = append(parts, n+"="+links[n])
+	}
+	return strings.Join(parts, " | ")
+}
+
 func truncate(s string, n int) string {
 	if len(s) <= n {
 		return s
@@ -112,7 +150,23 @@ func truncate(s string, n int) string {
 	return s[:n-1] + "…"
 }
 
+// extraPhones retorna os telefones além de Phone e Phone2.
+func extraPhones(l *Lead) []string {
+	if len(l.Phones) <= 2 {
+		return nil
+	}
+	return l.Phones[2:]
+}
+
 func firstSource(s string) string {
 	parts := strings.SplitN(s, "+", 2)
 	return parts[0]
 }
+
+// formatCoord formata coordenadas/distâncias; vazio quando ausente.
+func formatCoord(v float64, ok bool) string {
+	if !ok {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -6,8 +6,14 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
 	"time"
+
+	cnpjpkg "github.com/lucasfdcampos/find-cnpj/pkg/cnpj"
+	phonepkg "github.com/lucasfdcampos/find-cnpj/pkg/phone"
+	"github.com/lucasfdcampos/find-leads/pkg/ibge"
+	"github.com/lucasfdcampos/find-leads/pkg/taxonomy"
 )
 
 // OverpassScraper busca estabelecimentos via OpenStreetMap Overpass API (gratuito)
@@ -16,205 +22,270 @@ type OverpassScraper struct{}
 func NewOverpassScraper() *OverpassScraper { return &OverpassScraper{} }
 func (o *OverpassScraper) Name() string    { return "OpenStreetMap (Overpass)" }
 
-// categoriaParaOSM converte query para tags OSM relevantes
+const overpassURL = "https://overpass-api.de/api/interpreter"
+
+// osmKeys são as chaves principais de estabelecimentos no OSM, na ordem em
+// que definem a categoria do lead.
+var osmKeys = []string{"shop", "amenity", "craft", "office", "healthcare", "tourism", "leisure"}
+
+// reOSMTag reconhece buscas que já são uma tag OSM ("shop=bakery", "craft").
+var reOSMTag = regexp.MustCompile(`^(shop|amenity|craft|office|healthcare|tourism|leisure)(?:\s*=\s*([a-z0-9_;]+))?$`)
+
+// categoriaParaOSM converte query para filtros Overpass: a própria tag, se a
+// busca for uma ("shop=bakery"); as tags da taxonomia; ou, para buscas não
+// reconhecidas, qualquer estabelecimento cujo nome contenha a busca.
 func categoriaParaOSM(query string) []string {
-	q := strings.ToLower(query)
-	tags := []string{}
-
-	switch {
-	case contains(q, "roupa", "vestuario", "moda", "confeccao", "outfit"):
-		tags = append(tags, `"shop"="clothes"`, `"shop"="boutique"`, `"shop"="fashion"`)
-	case contains(q, "calcado", "sapato", "tenis"):
-		tags = append(tags, `"shop"="shoes"`)
-	case contains(q, "farmacia", "drogaria"):
-		tags = append(tags, `"amenity"="pharmacy"`)
-	case contains(q, "restaurante", "lanchonete", "comida"):
-		tags = append(tags, `"amenity"="restaurant"`, `"amenity"="fast_food"`, `"amenity"="cafe"`)
-	case contains(q, "supermercado", "mercado", "mercadinho"):
-		tags = append(tags, `"shop"="supermarket"`, `"shop"="convenience"`)
-	case contains(q, "academia", "fitness", "musculacao"):
-		tags = append(tags, `"leisure"="fitness_centre"`, `"leisure"="sports_centre"`)
-	case contains(q, "banco", "financeira"):
-		tags = append(tags, `"amenity"="bank"`)
-	case contains(q, "padaria", "confeitaria"):
-		tags = append(tags, `"shop"="bakery"`)
-	case contains(q, "barbearia", "cabeleireiro", "salao"):
-		tags = append(tags, `"shop"="hairdresser"`, `"shop"="barber"`)
-	case contains(q, "pet", "veterinaria", "animal"):
-		tags = append(tags, `"shop"="pet"`, `"amenity"="veterinary"`)
-	default:
-		tags = append(tags, `"shop"`, `"amenity"`)
-	}
-
-	return tags
+	q := strings.ToLower(strings.TrimSpace(query))
+	if m := reOSMTag.FindStringSubmatch(q); m != nil {
+		if m[2] == "" {
+			return []string{fmt.Sprintf(`["%s"]`, m[1])}
+		}
+		return []string{fmt.Sprintf(`["%s"="%s"]`, m[1], m[2])}
+	}
+	if tags := taxonomy.OSMTags(query); len(tags) > 0 {
+		filters := make([]string, len(tags))
+		for i, t := range tags {
+			filters[i] = "[" + t + "]"
+		}
+		return filters
+	}
+	return []string{fmt.Sprintf(`[~"^(%s)$"~"."]["name"~"%s",i]`, strings.Join(osmKeys, "|"), accentRegex(q))}
 }
 
-func contains(s string, keywords ...string) bool {
-	for _, k := range keywords {
-		if strings.Contains(s, k) {
-			return true
+// accentRegex escapa a busca para regex do Overpass tolerando acentos
+// ("padaria" casa "Padaría", "acougue" casa "Açougue").
+func accentRegex(s string) string {
+	classes := map[rune]string{
+		'a': "[aáàâã]", 'á': "[aáàâã]", 'à': "[aáàâã]", 'â': "[aáàâã]", 'ã': "[aáàâã]",
+		'e': "[eéê]", 'é': "[eéê]", 'ê': "[eéê]",
+		'i': "[ií]", 'í': "[ií]",
+		'o': "[oóôõ]", 'ó': "[oóôõ]", 'ô': "[oóôõ]", 'õ': "[oóôõ]",
+		'u': "[uúü]", 'ú': "[uúü]", 'ü': "[uúü]",
+		'c': "[cç]", 'ç': "[cç]",
+	}
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case classes[r] != "":
+			b.WriteString(classes[r])
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == ' ':
+			b.WriteRune(r)
+		default:
+			b.WriteString(".")
 		}
 	}
-	return false
+	return b.String()
+}
+
+// overpassScope delimita a busca: a área do município (limite
+// administrativo com o código IBGE) ou, em último caso, o bbox do Nominatim,
+// que inclui pedaços dos municípios vizinhos.
+type overpassScope struct {
+	setup  string // statements antes da busca (ex: definição da área)
+	filter string // filtro espacial de cada statement
 }
 
 func (o *OverpassScraper) Search(ctx context.Context, query, location string) ([]*Lead, error) {
 	city, state := ParseLocation(location)
+	filters := categoriaParaOSM(query)
+
+	if m, ok := ibge.Default().Find(city, state); ok {
+		scope := overpassScope{
+			setup:  fmt.Sprintf(`area["IBGE:GEOCODIGO"="%s"]["boundary"="administrative"]->.city;.city out ids;`, m.Code),
+			filter: "area.city",
+		}
+		leads, found, err := overpassRun(ctx, scope, filters, city, state)
+		if err != nil || found {
+			return leads, err
+		}
+		// município sem limite com código IBGE no OSM: cai no bbox
+	}
 
-	// Primeiro geocodifica a cidade via Nominatim para obter bbox
 	bbox, err := nominatimBBox(ctx, city, state)
 	if err != nil {
 		return nil, fmt.Errorf("erro geocodificando cidade: %w", err)
 	}
+	leads, _, err := overpassRun(ctx, overpassScope{filter: bbox}, filters, city, state)
+	return leads, err
+}
 
-	tags := categoriaParaOSM(query)
-	if len(tags) == 0 {
-		return nil, fmt.Errorf("nenhuma tag OSM mapeada para query")
-	}
+// overpassElement é um node, way ou relation com "out center" (ways e
+// relations trazem o centróide em Center).
+type overpassElement struct {
+	Type   string  `json:"type"`
+	ID     int64   `json:"id"`
+	Lat    float64 `json:"lat"`
+	Lon    float64 `json:"lon"`
+	Center *struct {
+		Lat float64 `json:"lat"`
+		Lon float64 `json:"lon"`
+	} `json:"center"`
+	Tags map[string]string `json:"tags"`
+}
 
-	// Monta query Overpass
-	var nodeBlocks strings.Builder
-	for _, tag := range tags {
-		nodeBlocks.WriteString(fmt.Sprintf(`node[%s](%s);`, tag, bbox))
-		nodeBlocks.WriteString(fmt.Sprintf(`way[%s](%s);`, tag, bbox))
+// overpassRun executa a busca no escopo. areaFound indica se a área do
+// município existe no OSM (sempre false para bbox).
+func overpassRun(ctx context.Context, scope overpassScope, filters []string, city, state string) (leads []*Lead, areaFound bool, err error) {
+	var sets strings.Builder
+	for _, f := range filters {
+		fmt.Fprintf(&sets, `nwr%s(%s);`, f, scope.filter)
 	}
+	// "out center" inclui lat/lon dos nodes e o centróide de ways e relations
+	overpassQuery := fmt.Sprintf(`[out:json][timeout:60];%s(%s);out center;`, scope.setup, sets.String())
 
-	overpassQuery := fmt.Sprintf(`[out:json][timeout:30];(%s);out body;`, nodeBlocks.String())
-
-	reqURL := "https://overpass-api.de/api/interpreter?data=" + url.QueryEscape(overpassQuery)
+	reqURL := overpassURL + "?data=" + url.QueryEscape(overpassQuery)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	req.Header.Set("User-Agent", "find-leads/1.0 (business lead finder)")
 
 	time.Sleep(1 * time.Second)
 
-	client := &http.Client{Timeout: 35 * time.Second}
+	client := &http.Client{Timeout: 65 * time.Second}
 	resp, err := DoWithRetry(ctx, client, req, 3)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("overpass status %d", resp.StatusCode)
+		return nil, false, fmt.Errorf("overpass status %d", resp.StatusCode)
 	}
 
 	var result struct {
-		Elements []struct {
-			Type string `json:"type"`
-			ID   int64  `json:"id"`
-			Tags struct {
-				Name    string `json:"name"`
-				Phone   string `json:"phone"`
-				Phone2  string `json:"phone:2"`
-				Website string `json:"website"`
-				Email   string `json:"email"`
-				Street  string `json:"addr:street"`
-				HouseNr string `json:"addr:housenumber"`
-				City    string `json:"addr:city"`
-				Shop    string `json:"shop"`
-				Amenity string `json:"amenity"`
-			} `json:"tags"`
-		} `json:"elements"`
+		Elements []overpassElement `json:"elements"`
 	}
-
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	var leads []*Lead
 	for _, el := range result.Elements {
-		if el.Tags.Name == "" {
+		if el.Type == "area" {
+			areaFound = true
 			continue
 		}
-
-		lead := &Lead{
-			Name:    el.Tags.Name,
-			Phone:   normalizePhone(el.Tags.Phone),
-			Phone2:  normalizePhone(el.Tags.Phone2),
-			Website: el.Tags.Website,
-			Email:   el.Tags.Email,
-			City:    city,
-			State:   state,
-			Source:  "OpenStreetMap",
+		lead := overpassLead(el.Tags, city, state)
+		if lead == nil {
+			continue
 		}
+		lead.Lat, lead.Lon = el.Lat, el.Lon
+		if el.Center != nil {
+			lead.Lat, lead.Lon = el.Center.Lat, el.Center.Lon
+		}
+		leads = append(leads, lead)
+	}
+	return leads, areaFound, nil
+}
 
-		if el.Tags.Street != "" {
-			lead.Address = el.Tags.Street
-			if el.Tags.HouseNr != "" {
-				lead.Address += ", " + el.Tags.HouseNr
+// overpassLead converte as tags de um elemento em Lead, lendo também as
+// variantes contact:*, o endereço completo (rua, número, bairro, CEP), marca,
+// horário e CNPJ (ref:vatin e afins).
+func overpassLead(tags map[string]string, city, state string) *Lead {
+	first := func(keys ...string) string {
+		for _, k := range keys {
+			if v := strings.TrimSpace(tags[k]); v != "" {
+				return v
 			}
 		}
+		return ""
+	}
 
-		if el.Tags.Shop != "" {
-			lead.Category = el.Tags.Shop
-		} else if el.Tags.Amenity != "" {
-			lead.Category = el.Tags.Amenity
+	name := first("name", "brand", "official_name")
+	if name == "" {
+		return nil
+	}
+	lead := &Lead{
+		Name:    name,
+		Website: first("website", "contact:website", "url"),
+		Email:   first("email", "contact:email"),
+		City:    city,
+		State:   state,
+		Source:  "OpenStreetMap",
+
+		OpeningHours: NormalizeOpeningHours(tags["opening_hours"]),
+	}
+	// Telefones: várias chaves e vários números por chave ("a;b")
+	for _, k := range []string{"phone", "contact:phone", "mobile", "contact:mobile", "phone:2", "contact:whatsapp", "whatsapp"} {
+		for _, p := range strings.FieldsFunc(tags[k], func(r rune) bool { return r == ';' || r == ',' }) {
+			lead.addScrapedPhone(p)
+		}
+	}
+	if wa, _, _ := strings.Cut(first("contact:whatsapp", "whatsapp"), ";"); wa != "" {
+		if n, err := phonepkg.Parse(wa); err == nil && n.DDD != "" {
+			lead.WhatsApp = "55" + n.DDD + n.Subscriber
 		}
-
-		leads = append(leads, lead)
 	}
 
-	return leads, nil
-}
+	lead.Address = osmAddress(tags)
 
-// nominatimBBox retorna "sul,oeste,norte,leste" para a cidade via Nominatim
-func nominatimBBox(ctx context.Context, city, state string) (string, error) {
-	searchQuery := fmt.Sprintf("%s, %s, Brazil", city, state)
-	reqURL := fmt.Sprintf("https://nominatim.openstreetmap.org/search?q=%s&format=json&limit=1",
-		url.QueryEscape(searchQuery))
+	for _, k := range osmKeys {
+		if v := tags[k]; v != "" && v != "yes" {
+			lead.Category = v
+			break
+		}
+	}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
-	if err != nil {
-		return "", err
+	for _, k := range []string{"ref:vatin", "ref:CNPJ", "ref:cnpj", "cnpj", "CNPJ"} {
+		if found := cnpjpkg.ExtractAllCNPJs(tags[k]); len(found) > 0 {
+			lead.CNPJ = found[0].Formatted
+			break
+		}
 	}
-	req.Header.Set("User-Agent", "find-leads/1.0")
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := DoWithRetry(ctx, client, req, 3)
-	if err != nil {
-		return "", err
+	for network, keys := range map[string][]string{
+		"instagram": {"contact:instagram", "instagram"},
+		"facebook":  {"contact:facebook", "facebook"},
+	} {
+		v := first(keys...)
+		if v == "" {
+			continue
+		}
+		if !strings.Contains(v, "/") {
+			v = "https://www." + network + ".com/" + strings.TrimPrefix(v, "@")
+		}
+		schemaSocial(lead, v)
 	}
-	defer resp.Body.Close()
+	return lead
+}
 
-	var results []struct {
-		BoundingBox []string `json:"boundingbox"`
-		Lat         string   `json:"lat"`
-		Lon         string   `json:"lon"`
+// osmAddress monta "Rua X, 123 - Bairro, 86000-000" a partir das tags addr:*
+// (ou usa addr:full quando só ela existe).
+func osmAddress(tags map[string]string) string {
+	street := strings.TrimSpace(tags["addr:street"])
+	if street == "" {
+		street = strings.TrimSpace(tags["addr:place"])
+	}
+	if street == "" {
+		return strings.TrimSpace(tags["addr:full"])
+	}
+	addr := street
+	if nr := strings.TrimSpace(tags["addr:housenumber"]); nr != "" {
+		addr += ", " + nr
 	}
+	if sub := strings.TrimSpace(tags["addr:suburb"]); sub != "" {
+		addr += " - " + sub
+	}
+	if cep := strings.TrimSpace(tags["addr:postcode"]); cep != "" {
+		addr += ", " + cep
+	}
+	return addr
+}
 
-	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil || len(results) == 0 {
-		return "", fmt.Errorf("cidade não encontrada no Nominatim")
+// nominatimBBox retorna "sul,oeste,norte,leste" para a cidade via Nominatim
+func nominatimBBox(ctx context.Context, city, state string) (string, error) {
+	p, err := nominatimSearch(ctx, city, state)
+	if err != nil {
+		return "", err
 	}
 
-	bb := results[0].BoundingBox
+	bb := p.BoundingBox
 	if len(bb) < 4 {
 		// Fallback: bbox 0.05 graus em volta do centro
-		lat := results[0].Lat
-		lon := results[0].Lon
-		return fmt.Sprintf("%s,%s,%s,%s",
-			subFloat(lat, 0.05),
-			subFloat(lon, 0.05),
-			addFloat(lat, 0.05),
-			addFloat(lon, 0.05),
-		), nil
+		return fmt.Sprintf("%f,%f,%f,%f", p.Lat-0.05, p.Lon-0.05, p.Lat+0.05, p.Lon+0.05), nil
 	}
 
 	// Nominatim retorna [minlat, maxlat, minlon, maxlon]
 	return fmt.Sprintf("%s,%s,%s,%s", bb[0], bb[2], bb[1], bb[3]), nil
 }
-
-func subFloat(s string, delta float64) string {
-	var f float64
-	fmt.Sscanf(s, "%f", &f)
-	return fmt.Sprintf("%f", f-delta)
-}
-
-func addFloat(s string, delta float64) string {
-	var f float64
-	fmt.Sscanf(s, "%f", &f)
-	return fmt.Sprintf("%f", f+delta)
-}
@@ -0,0 +1,533 @@
+package leads
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	cnpjpkg "github.com/lucasfdcampos/find-cnpj/pkg/cnpj"
+	igpkg "github.com/lucasfdcampos/find-instagram/pkg/instagram"
+)
+
+// ─── schema.org ──────────────────────────────────────────────────────────────
+
+// Guias e sites de empresas descrevem o negócio em schema.org de três formas:
+// JSON-LD (<script type="application/ld+json">), microdata (itemscope/itemprop)
+// e RDFa (typeof/property). As três são convertidas para a mesma forma do
+// JSON-LD (propriedade → valor, com "@type") e depois para *Lead.
+
+// schemaBusinessTypes são LocalBusiness e seus subtipos no schema.org,
+// agrupados pelo tipo pai. Organization e Corporation ficam de fora: em guias
+// descrevem o próprio guia (publisher), não um resultado.
+var schemaBusinessTypes = map[string]bool{
+	"localbusiness": true, "animalshelter": true, "archiveorganization": true,
+	"childcare": true, "dentist": true, "drycleaningorlaundry": true,
+	"employmentagency": true, "internetcafe": true, "library": true,
+	"professionalservice": true, "radiostation": true, "realestateagent": true,
+	"recyclingcenter": true, "selfstorage": true, "shoppingcenter": true,
+	"televisionstation": true, "touristinformationcenter": true, "travelagency": true,
+	// AutomotiveBusiness
+	"automotivebusiness": true, "autobodyshop": true, "autodealer": true,
+	"autopartsstore": true, "autorental": true, "autorepair": true, "autowash": true,
+	"gasstation": true, "motorcycledealer": true, "motorcyclerepair": true,
+	// EmergencyService
+	"emergencyservice": true, "firestation": true, "hospital": true, "policestation": true,
+	// EntertainmentBusiness
+	"entertainmentbusiness": true, "adultentertainment": true, "amusementpark": true,
+	"artgallery": true, "casino": true, "comedyclub": true, "movietheater": true,
+	"nightclub": true,
+	// FinancialService
+	"financialservice": true, "accountingservice": true, "automatedteller": true,
+	"bankorcreditunion": true, "insuranceagency": true,
+	// FoodEstablishment
+	"foodestablishment": true, "bakery": true, "barorpub": true, "brewery": true,
+	"cafeorcoffeeshop": true, "distillery": true, "fastfoodrestaurant": true,
+	"icecreamshop": true, "restaurant": true, "winery": true,
+	// GovernmentOffice
+	"governmentoffice": true, "postoffice": true,
+	// HealthAndBeautyBusiness
+	"healthandbeautybusiness": true, "beautysalon": true, "dayspa": true,
+	"hairsalon": true, "healthclub": true, "nailsalon": true, "tattooparlor": true,
+	// HomeAndConstructionBusiness
+	"homeandconstructionbusiness": true, "electrician": true, "generalcontractor": true,
+	"hvacbusiness": true, "housepainter": true, "locksmith": true,
+	"movingcompany": true, "plumber": true, "roofingcontractor": true,
+	// LegalService
+	"legalservice": true, "attorney": true, "notary": true,
+	// LodgingBusiness
+	"lodgingbusiness": true, "bedandbreakfast": true, "campground": true,
+	"hostel": true, "hotel": true, "motel": true, "resort": true, "skiresort": true,
+	"vacationrental": true,
+	// MedicalBusiness
+	"medicalbusiness": true, "communityhealth": true, "dermatology": true,
+	"dietnutrition": true, "emergency": true, "geriatric": true, "gynecologic": true,
+	"medicalclinic": true, "covidtestingfacility": true, "midwifery": true,
+	"nursing": true, "obstetric": true, "oncologic": true, "optician": true,
+	"optometric": true, "otolaryngologic": true, "pediatric": true, "pharmacy": true,
+	"physician": true, "individualphysician": true, "physiciansoffice": true,
+	"physiotherapy": true, "plasticsurgery": true, "podiatric": true,
+	"primarycare": true, "psychiatric": true, "publichealth": true,
+	// SportsActivityLocation
+	"sportsactivitylocation": true, "bowlingalley": true, "exercisegym": true,
+	"golfcourse": true, "publicswimmingpool": true, "sportsclub": true,
+	"stadiumorarena": true, "tenniscomplex": true,
+	// Store
+	"store": true, "bikestore": true, "bookstore": true, "clothingstore": true,
+	"computerstore": true, "conveniencestore": true, "departmentstore": true,
+	"electronicsstore": true, "florist": true, "furniturestore": true,
+	"gardenstore": true, "grocerystore": true, "hardwarestore": true,
+	"hobbyshop": true, "homegoodsstore": true, "jewelrystore": true,
+	"liquorstore": true, "mensclothingstore": true, "mobilephonestore": true,
+	"movierentalstore": true, "musicstore": true, "officeequipmentstore": true,
+	"outletstore": true, "pawnshop": true, "petstore": true, "shoestore": true,
+	"sportinggoodsstore": true, "tireshop": true, "toystore": true,
+	"wholesalestore": true,
+}
+
+// schemaSiteOwnerTypes são aceitos além de schemaBusinessTypes no site da
+// própria empresa, onde o publisher é o negócio (ver siteExtractor).
+var schemaSiteOwnerTypes = map[string]bool{
+	"organization": true, "corporation": true, "medicalorganization": true,
+	"veterinarycare": true,
+}
+
+// ExtractSchemaOrg retorna um *Lead para cada negócio descrito em JSON-LD,
+// microdata ou RDFa na página (nome, telefones, endereço, coordenadas, site,
+// e-mail, CNPJ, nota, horário e redes sociais). Source é atribuída a todos.
+// Deve ser chamada antes de remover os <script> do documento.
+func ExtractSchemaOrg(doc *goquery.Document, source string) []*Lead {
+	return extractSchemaOrg(doc, source, isSchemaBusiness)
+}
+
+// extractSchemaOrg é ExtractSchemaOrg com o filtro de entidades dado.
+func extractSchemaOrg(doc *goquery.Document, source string, match func(map[string]any) bool) []*Lead {
+	var roots []any
+	roots = append(roots, jsonLDBlocks(doc)...)
+	roots = append(roots, microdataItems(doc)...)
+	roots = append(roots, rdfaItems(doc)...)
+
+	seen := make(map[string]bool)
+	var leads []*Lead
+	for _, obj := range schemaEntities(roots, match) {
+		l := schemaLead(obj, source)
+		if l == nil {
+			continue
+		}
+		key := normalizeString(l.Name) + "|" + l.Phone + "|" + l.Address
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		leads = append(leads, l)
+	}
+	return leads
+}
+
+// ─── Formatos ────────────────────────────────────────────────────────────────
+
+// jsonLDBlocks decodifica os <script type="application/ld+json"> da página.
+// Quebras de linha cruas dentro de strings (JSON inválido, mas comum) são
+// toleradas.
+func jsonLDBlocks(doc *goquery.Document) []any {
+	var out []any
+	doc.Find(`script[type="application/ld+json"]`).Each(func(_ int, s *goquery.Selection) {
+		raw := strings.TrimSpace(s.Text())
+		var data any
+		if err := json.Unmarshal([]byte(raw), &data); err != nil {
+			cleaned := strings.NewReplacer("\r", " ", "\n", " ", "\t", " ").Replace(raw)
+			if json.Unmarshal([]byte(cleaned), &data) != nil {
+				return
+			}
+		}
+		out = append(out, data)
+	})
+	return out
+}
+
+// microdataItems converte os itemscope de topo (sem itemprop) em objetos.
+func microdataItems(doc *goquery.Document) []any {
+	var out []any
+	doc.Find("[itemscope]").Each(func(_ int, s *goquery.Selection) {
+		if _, nested := s.Attr("itemprop"); nested {
+			return
+		}
+		out = append(out, readScope(s, microdataSyntax))
+	})
+	return out
+}
+
+// rdfaItems converte os typeof de topo (sem property) em objetos.
+func rdfaItems(doc *goquery.Document) []any {
+	var out []any
+	doc.Find("[typeof]").Each(func(_ int, s *goquery.Selection) {
+		if _, nested := s.Attr("property"); nested {
+			return
+		}
+		out = append(out, readScope(s, rdfaSyntax))
+	})
+	return out
+}
+
+// schemaSyntax diferencia microdata de RDFa na leitura dos elementos.
+type schemaSyntax struct {
+	scope, typ, prop string
+}
+
+var (
+	microdataSyntax = schemaSyntax{scope: "itemscope", typ: "itemtype", prop: "itemprop"}
+	rdfaSyntax      = schemaSyntax{scope: "typeof", typ: "typeof", prop: "property"}
+)
+
+// readScope lê as propriedades de um escopo sem entrar em escopos aninhados,
+// que viram objetos próprios quando têm propriedade.
+func readScope(scope *goquery.Selection, syn schemaSyntax) map[string]any {
+	obj := map[string]any{}
+	var types []any
+	for _, t := range strings.Fields(scope.AttrOr(syn.typ, "")) {
+		types = append(types, schemaTerm(t))
+	}
+	if len(types) > 0 {
+		obj["@type"] = types
+	}
+
+	var walk func(parent *goquery.Selection)
+	walk = func(parent *goquery.Selection) {
+		parent.Children().Each(func(_ int, c *goquery.Selection) {
+			_, isScope := c.Attr(syn.scope)
+			if props := c.AttrOr(syn.prop, ""); props != "" {
+				var v any
+				if isScope {
+					v = readScope(c, syn)
+				} else {
+					v = schemaLiteral(c)
+				}
+				for _, p := range strings.Fields(props) {
+					p = schemaTerm(p)
+					obj[p] = appendValue(obj[p], v)
+				}
+			}
+			if !isScope {
+				walk(c)
+			}
+		})
+	}
+	walk(scope)
+	return obj
+}
+
+// schemaTerm reduz "https://schema.org/Store" e "schema:name" ao termo.
+func schemaTerm(t string) string {
+	if i := strings.LastIndexAny(t, "/#:"); i >= 0 {
+		return t[i+1:]
+	}
+	return t
+}
+
+// schemaLiteral lê o valor de um elemento com itemprop/property.
+func schemaLiteral(s *goquery.Selection) string {
+	if v, ok := s.Attr("content"); ok {
+		return strings.TrimSpace(v)
+	}
+	attr := ""
+	switch goquery.NodeName(s) {
+	case "a", "link", "area":
+		attr = "href"
+	case "img", "audio", "video", "source", "iframe", "embed":
+		attr = "src"
+	case "time":
+		attr = "datetime"
+	case "data", "meter":
+		attr = "value"
+	}
+	if v, ok := s.Attr(attr); ok && attr != "" {
+		return strings.TrimSpace(v)
+	}
+	return strings.Join(strings.Fields(s.Text()), " ")
+}
+
+func appendValue(cur, v any) any {
+	switch c := cur.(type) {
+	case nil:
+		return v
+	case []any:
+		return append(c, v)
+	default:
+		return []any{c, v}
+	}
+}
+
+// ─── Entidades ───────────────────────────────────────────────────────────────
+
+// schemaEntities percorre os objetos (arrays, @graph, ItemList...) e retorna
+// os que match aceita, sem descer dentro deles.
+func schemaEntities(roots []any, match func(map[string]any) bool) []map[string]any {
+	var out []map[string]any
+	var walk func(v any)
+	walk = func(v any) {
+		switch x := v.(type) {
+		case []any:
+			for _, e := range x {
+				walk(e)
+			}
+		case map[string]any:
+			if match(x) {
+				out = append(out, x)
+				return
+			}
+			keys := make([]string, 0, len(x))
+			for k := range x {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys) // ordem estável entre execuções
+			for _, k := range keys {
+				walk(x[k])
+			}
+		}
+	}
+	for _, r := range roots {
+		walk(r)
+	}
+	return out
+}
+
+// schemaTypes retorna os @type do objeto (string ou lista).
+func schemaTypes(obj map[string]any) []string {
+	var types []string
+	for _, v := range schemaStrings(obj["@type"]) {
+		types = append(types, schemaTerm(v))
+	}
+	return types
+}
+
+// isSchemaBusiness aceita objetos com nome de LocalBusiness ou subtipo.
+func isSchemaBusiness(obj map[string]any) bool {
+	return schemaNamedOfType(obj, schemaBusinessTypes)
+}
+
+// isSchemaSiteOwner aceita também a Organization que publica o site.
+func isSchemaSiteOwner(obj map[string]any) bool {
+	return isSchemaBusiness(obj) || schemaNamedOfType(obj, schemaSiteOwnerTypes)
+}
+
+func schemaNamedOfType(obj map[string]any, types map[string]bool) bool {
+	if schemaString(obj, "name") == "" && schemaString(obj, "legalName") == "" {
+		return false
+	}
+	for _, t := range schemaTypes(obj) {
+		if types[strings.ToLower(t)] {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaStrings achata um valor em strings (números viram texto; objetos
+// com "@value", "@id" ou "name" usam esse campo).
+func schemaStrings(v any) []string {
+	var out []string
+	var flat func(v any)
+	flat = func(v any) {
+		switch x := v.(type) {
+		case string:
+			if s := strings.TrimSpace(html.UnescapeString(x)); s != "" {
+				out = append(out, s)
+			}
+		case float64:
+			out = append(out, strconv.FormatFloat(x, 'f', -1, 64))
+		case []any:
+			for _, e := range x {
+				flat(e)
+			}
+		case map[string]any:
+			for _, k := range []string{"@value", "name", "@id"} {
+				if e, ok := x[k]; ok {
+					flat(e)
+					return
+				}
+			}
+		}
+	}
+	flat(v)
+	return out
+}
+
+// schemaString retorna o primeiro valor textual da propriedade.
+func schemaString(obj map[string]any, key string) string {
+	if s := schemaStrings(obj[key]); len(s) > 0 {
+		return s[0]
+	}
+	return ""
+}
+
+// schemaObject retorna o primeiro objeto da propriedade.
+func schemaObject(obj map[string]any, key string) map[string]any {
+	switch x := obj[key].(type) {
+	case map[string]any:
+		return x
+	case []any:
+		for _, e := range x {
+			if m, ok := e.(map[string]any); ok {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+// schemaFloat lê números que vêm como número ou texto ("-23,31").
+func schemaFloat(obj map[string]any, key string) (float64, bool) {
+	s := strings.ReplaceAll(schemaString(obj, key), ",", ".")
+	f, err := strconv.ParseFloat(s, 64)
+	return f, err == nil
+}
+
+// ─── Conversão para Lead ─────────────────────────────────────────────────────
+
+func schemaLead(obj map[string]any, source string) *Lead {
+	l := &Lead{Source: source}
+	l.Name = schemaString(obj, "name")
+	if l.Name == "" {
+		l.Name = schemaString(obj, "legalName")
+	}
+	l.Name = strings.Join(strings.Fields(l.Name), " ")
+	if len(l.Name) < 3 {
+		return nil
+	}
+
+	for _, p := range schemaStrings(obj["telephone"]) {
+		l.addScrapedPhone(p)
+	}
+	if email := schemaString(obj, "email"); email != "" {
+		email = strings.TrimPrefix(strings.ToLower(email), "mailto:")
+		if reSiteEmail.MatchString(email) {
+			l.Email = email
+		}
+	}
+
+	if addr := schemaObject(obj, "address"); addr != nil {
+		schemaAddress(l, addr)
+	} else {
+		l.Address = schemaString(obj, "address")
+	}
+
+	geo := schemaObject(obj, "geo")
+	if geo == nil {
+		geo = obj
+	}
+	lat, okLat := schemaFloat(geo, "latitude")
+	lon, okLon := schemaFloat(geo, "longitude")
+	if okLat && okLon && (lat != 0 || lon != 0) {
+		l.Lat, l.Lon = lat, lon
+	}
+
+	if site := schemaString(obj, "url"); site != "" {
+		if home, host, ok := normalizeSiteURL(site); ok && isOfficialSiteHost(host) {
+			l.Website = home
+		}
+	}
+
+	for _, key := range []string{"taxID", "vatID"} {
+		if found := cnpjpkg.ExtractAllCNPJs(schemaString(obj, key)); len(found) > 0 {
+			l.CNPJ = found[0].Formatted
+			break
+		}
+	}
+
+	if r := schemaObject(obj, "aggregateRating"); r != nil {
+		l.Rating = schemaString(r, "ratingValue")
+		for _, key := range []string{"reviewCount", "ratingCount"} {
+			if n := onlyDigits(schemaString(r, key)); n != "" {
+				l.RatingCount = n
+				break
+			}
+		}
+	}
+	l.PriceLevel = schemaString(obj, "priceRange")
+
+	l.OpeningHours = schemaOpeningHours(obj)
+
+	for _, link := range schemaStrings(obj["sameAs"]) {
+		schemaSocial(l, link)
+	}
+	return l
+}
+
+// schemaOpeningHours lê openingHoursSpecification (dia, abre, fecha) ou,
+// na falta dele, openingHours no formato do OSM, e devolve o horário
+// normalizado (ver NormalizeOpeningHours).
+func schemaOpeningHours(obj map[string]any) string {
+	var sched Schedule
+	for _, spec := range schemaEntities([]any{obj["openingHoursSpecification"]}, func(map[string]any) bool { return true }) {
+		start, ok1 := schemaClock(schemaString(spec, "opens"))
+		end, ok2 := schemaClock(schemaString(spec, "closes"))
+		if !ok1 || !ok2 {
+			continue
+		}
+		if end <= start {
+			end += minutesPerDay
+		}
+		for _, day := range schemaStrings(spec["dayOfWeek"]) {
+			if d, ok := parseWeekday(strings.ToLower(schemaTerm(day))); ok {
+				sched.Add(d, TimeRange{start, end})
+			}
+		}
+	}
+	if !sched.IsEmpty() {
+		return sched.String()
+	}
+	return NormalizeOpeningHours(strings.Join(schemaStrings(obj["openingHours"]), "; "))
+}
+
+// schemaClock lê "08:00" ou "08:00:00".
+func schemaClock(s string) (int, bool) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) < 2 {
+		return 0, false
+	}
+	return hhmm(parts[0], parts[1])
+}
+
+// schemaAddress preenche endereço, cidade e UF a partir de um PostalAddress.
+func schemaAddress(l *Lead, addr map[string]any) {
+	var parts []string
+	for _, key := range []string{"streetAddress", "addressLocality", "addressRegion", "postalCode"} {
+		if v := schemaString(addr, key); v != "" {
+			parts = append(parts, v)
+		}
+	}
+	l.Address = strings.Join(parts, ", ")
+	l.City = schemaString(addr, "addressLocality")
+	if uf := strings.ToUpper(schemaString(addr, "addressRegion")); len(uf) == 2 {
+		l.State = uf
+	}
+}
+
+// schemaSocial registra perfis do sameAs (Instagram vira o handle do lead).
+func schemaSocial(l *Lead, link string) {
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	network, ok := socialHosts[host]
+	if !ok || strings.Trim(u.Path, "/") == "" {
+		return
+	}
+	if network == "instagram" && l.Instagram == "" {
+		if handle := igpkg.NormalizeHandle(link); igpkg.IsValidHandle(handle) {
+			l.Instagram = "@" + handle
+		}
+	}
+	if l.SocialLinks == nil {
+		l.SocialLinks = make(map[string]string)
+	}
+	if _, exists := l.SocialLinks[network]; !exists {
+		l.SocialLinks[network] = fmt.Sprintf("https://%s%s", host, strings.TrimRight(u.Path, "/"))
+	}
+}
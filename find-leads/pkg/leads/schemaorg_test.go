@@ -0,0 +1,160 @@
+package leads
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// schemaLeadFields são os campos comparados nos testes de schema.org.
+type schemaLeadFields struct {
+	Name, Phone, Address, City, State, Website, Email, CNPJ, Rating, RatingCount, OpeningHours, Instagram string
+	Lat, Lon                                                                                              float64
+}
+
+func schemaFields(l *Lead) schemaLeadFields {
+	return schemaLeadFields{
+		l.Name, l.Phone, l.Address, l.City, l.State, l.Website, l.Email, l.CNPJ,
+		l.Rating, l.RatingCount, l.OpeningHours, l.Instagram, l.Lat, l.Lon,
+	}
+}
+
+func parseSchemaPage(t *testing.T, page string) *goquery.Document {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestExtractSchemaOrg(t *testing.T) {
+	trigo := schemaLeadFields{
+		Name: "Padaria Trigo de Ouro", Phone: "(43) 3325-4471",
+		Address: "Av. Higienópolis, 1200, Londrina, PR, 86020-080", City: "Londrina", State: "PR",
+		Website: "https://www.padariatrigo.com.br/", Email: "contato@padariatrigo.com.br",
+		Rating: "4.6", RatingCount: "128", Instagram: "@padariatrigo",
+		Lat: -23.3105, Lon: -51.1628,
+	}
+	tests := []struct {
+		name string
+		page string
+		want []schemaLeadFields
+	}{
+		{
+			name: "JSON-LD em @graph com o publisher",
+			page: `<html><head><script type="application/ld+json">
+			{"@context": "https://schema.org", "@graph": [
+			  {"@type": "WebSite", "name": "Guia Londrina", "url": "https://guialondrina.com.br"},
+			  {"@type": "Organization", "name": "Guia Londrina", "telephone": "(43) 3000-0000",
+			   "url": "https://guialondrina.com.br"},
+			  {"@type": "Bakery", "name": "Padaria Trigo de Ouro",
+			   "telephone": "+55 43 3325-4471", "email": "mailto:Contato@PadariaTrigo.com.br",
+			   "url": "https://www.padariatrigo.com.br/cardapio",
+			   "address": {"@type": "PostalAddress", "streetAddress": "Av. Higienópolis, 1200",
+			     "addressLocality": "Londrina", "addressRegion": "PR", "postalCode": "86020-080"},
+			   "geo": {"@type": "GeoCoordinates", "latitude": -23.3105, "longitude": "-51,1628"},
+			   "aggregateRating": {"ratingValue": "4.6", "reviewCount": "128"},
+			   "sameAs": ["https://www.instagram.com/padariatrigo/"]}
+			]}
+			</script></head><body></body></html>`,
+			want: []schemaLeadFields{trigo},
+		},
+		{
+			name: "microdata",
+			page: `<html><body>
+			<div itemscope itemtype="https://schema.org/Organization">
+			  <span itemprop="name">Guia Londrina</span>
+			</div>
+			<div itemscope itemtype="https://schema.org/ClothingStore">
+			  <h2 itemprop="name">Dimazzo Menswear</h2>
+			  <a itemprop="telephone" href="tel:+554333241010">(43) 3324-1010</a>
+			  <meta itemprop="taxID" content="11.222.333/0001-81">
+			  <div itemprop="address" itemscope itemtype="https://schema.org/PostalAddress">
+			    <span itemprop="streetAddress">Rua Sergipe, 500</span>
+			    <span itemprop="addressLocality">Londrina</span>
+			    <span itemprop="addressRegion">pr</span>
+			  </div>
+			  <time itemprop="openingHours" datetime="Mo-Fr 09:00-18:00">seg a sex</time>
+			</div>
+			</body></html>`,
+			want: []schemaLeadFields{{
+				Name: "Dimazzo Menswear", Phone: "(43) 3324-1010",
+				Address: "Rua Sergipe, 500, Londrina, pr", City: "Londrina", State: "PR",
+				CNPJ: "11.222.333/0001-81", OpeningHours: NormalizeOpeningHours("Mo-Fr 09:00-18:00"),
+			}},
+		},
+		{
+			name: "RDFa",
+			page: `<html><body vocab="https://schema.org/">
+			<div typeof="Corporation"><span property="name">Guia Londrina S.A.</span></div>
+			<div typeof="schema:Restaurant">
+			  <span property="name">Cantina Bella Napoli</span>
+			  <span property="telephone">(43) 3322-5566</span>
+			  <div property="address" typeof="PostalAddress">
+			    <span property="streetAddress">Rua Pernambuco, 1020</span>
+			    <span property="addressLocality">Londrina</span>
+			  </div>
+			  <a property="url" href="https://www.instagram.com/bellanapoli">Instagram</a>
+			</div>
+			</body></html>`,
+			want: []schemaLeadFields{{
+				Name: "Cantina Bella Napoli", Phone: "(43) 3322-5566",
+				Address: "Rua Pernambuco, 1020, Londrina", City: "Londrina",
+			}},
+		},
+		{
+			name: "só o publisher",
+			page: `<html><head><script type="application/ld+json">
+			[{"@type": "Organization", "name": "Solutudo", "telephone": "(11) 4003-1234"},
+			 {"@type": ["Corporation"], "legalName": "Solutudo Ltda"},
+			 {"@type": "LocalBusiness", "telephone": "(43) 3325-4471"}]
+			</script></head><body></body></html>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []schemaLeadFields
+			for _, l := range ExtractSchemaOrg(parseSchemaPage(t, tt.page), "Guia") {
+				if l.Source != "Guia" {
+					t.Errorf("%q: Source = %q", l.Name, l.Source)
+				}
+				got = append(got, schemaFields(l))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractSchemaOrg:\n got  %+v\n want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsSchemaBusiness(t *testing.T) {
+	tests := []struct {
+		obj       map[string]any
+		business  bool
+		siteOwner bool
+	}{
+		{map[string]any{"@type": "LocalBusiness", "name": "X Ltda"}, true, true},
+		{map[string]any{"@type": "https://schema.org/HairSalon", "name": "X"}, true, true},
+		{map[string]any{"@type": []any{"Place", "autorepair"}, "legalName": "X"}, true, true},
+		{map[string]any{"@type": "Organization", "name": "X"}, false, true},
+		{map[string]any{"@type": "Corporation", "name": "X"}, false, true},
+		{map[string]any{"@type": "VeterinaryCare", "name": "X"}, false, true},
+		// Service e OnlineBusiness não são LocalBusiness
+		{map[string]any{"@type": "Service", "name": "Entrega"}, false, false},
+		{map[string]any{"@type": "OnlineBusiness", "name": "X"}, false, false},
+		{map[string]any{"@type": "WebSite", "name": "X"}, false, false},
+		// sem nome
+		{map[string]any{"@type": "Restaurant"}, false, false},
+	}
+	for _, tt := range tests {
+		if got := isSchemaBusiness(tt.obj); got != tt.business {
+			t.Errorf("isSchemaBusiness(%v) = %v, want %v", tt.obj, got, tt.business)
+		}
+		if got := isSchemaSiteOwner(tt.obj); got != tt.siteOwner {
+			t.Errorf("isSchemaSiteOwner(%v) = %v, want %v", tt.obj, got, tt.siteOwner)
+		}
+	}
+}
@@ -3,13 +3,11 @@ package leads
 import (
 	"context"
 	"fmt"
-	"net/http"
-	"net/url"
 	"regexp"
 	"strings"
-	"time"
 
-	"github.com/PuerkitoBio/goquery"
+	"github.com/lucasfdcampos/find-leads/pkg/taxonomy"
+	"github.com/lucasfdcampos/serp/pkg/serp"
 )
 
 // junkLabels são palavras-chave de UI que indicam que o texto não é nome de empresa
@@ -122,10 +120,8 @@ func (d *DDGLeadScraper) Name() string   { return "DuckDuckGo" }
 
 func (d *DDGLeadScraper) Search(ctx context.Context, query, location string) ([]*Lead, error) {
 	city, state := ParseLocation(location)
-	q := fmt.Sprintf(`"%s" "%s" "%s" telefone`, query, city, state)
-	// Usa versão lite do DDG que tem menos bot-detection
-	return searchEngineLeads(ctx, "https://lite.duckduckgo.com/lite/?q="+url.QueryEscape(q),
-		"td.result-snippet, .result-snippet, td", city, state, "DuckDuckGo", 1500*time.Millisecond)
+	q := fmt.Sprintf(`%s "%s" "%s" telefone`, expandedQuery(query), city, state)
+	return searchEngineLeads(ctx, serp.DuckDuckGo, q, city, state)
 }
 
 // ─── Bing ────────────────────────────────────────────────────────────────────
@@ -137,9 +133,8 @@ func (b *BingLeadScraper) Name() string    { return "Bing" }
 
 func (b *BingLeadScraper) Search(ctx context.Context, query, location string) ([]*Lead, error) {
 	city, state := ParseLocation(location)
-	q := fmt.Sprintf(`"%s" "%s" "%s" telefone`, query, city, state)
-	return searchEngineLeads(ctx, "https://www.bing.com/search?q="+url.QueryEscape(q),
-		".b_caption p, .b_snippet, .b_dList", city, state, "Bing", 1*time.Second)
+	q := fmt.Sprintf(`%s "%s" "%s" telefone`, expandedQuery(query), city, state)
+	return searchEngineLeads(ctx, serp.Bing, q, city, state)
 }
 
 // ─── Brave ───────────────────────────────────────────────────────────────────
@@ -151,9 +146,8 @@ func (b *BraveLeadScraper) Name() string     { return "Brave Search" }
 
 func (b *BraveLeadScraper) Search(ctx context.Context, query, location string) ([]*Lead, error) {
 	city, state := ParseLocation(location)
-	q := fmt.Sprintf(`"%s" "%s" "%s" telefone`, query, city, state)
-	return searchEngineLeads(ctx, "https://search.brave.com/search?q="+url.QueryEscape(q),
-		".snippet-description, .snippet-content, .result-description, .fdb", city, state, "Brave", 2500*time.Millisecond)
+	q := fmt.Sprintf(`%s "%s" "%s" telefone`, expandedQuery(query), city, state)
+	return searchEngineLeads(ctx, serp.Brave, q, city, state)
 }
 
 // ─── Yandex ──────────────────────────────────────────────────────────────────
@@ -166,20 +160,13 @@ func (y *YandexLeadScraper) Name() string      { return "Yandex" }
 func (y *YandexLeadScraper) Search(ctx context.Context, query, location string) ([]*Lead, error) {
 	city, state := ParseLocation(location)
 	q := fmt.Sprintf(`%s %s-%s telefone Brasil`, query, city, state)
-	return searchEngineLeads(ctx,
-		fmt.Sprintf("https://yandex.com/search/?text=%s&lr=102", url.QueryEscape(q)),
-		".Organic-Text, .OrganicText, .ExtendedText, .serp-item__text", city, state, "Yandex", 2000*time.Millisecond)
+	return searchEngineLeads(ctx, serp.Yandex, q, city, state)
 }
 
 // ─── SearXNG ──────────────────────────────────────────────────────────────────
 
-// searxngInstances lists public SearXNG instances to try in order.
-var searxngInstances = []string{
-	"https://searx.be",
-	"https://search.bus-hit.me",
-	"https://paulgo.io",
-}
-
+// SearXNGLeadScraper busca nas instâncias públicas (serp.SearXNGInstances),
+// tentadas em ordem pelo cliente de busca.
 type SearXNGLeadScraper struct{}
 
 func NewSearXNGLeadScraper() *SearXNGLeadScraper { return &SearXNGLeadScraper{} }
@@ -187,16 +174,8 @@ func (s *SearXNGLeadScraper) Name() string       { return "SearXNG" }
 
 func (s *SearXNGLeadScraper) Search(ctx context.Context, query, location string) ([]*Lead, error) {
 	city, state := ParseLocation(location)
-	q := fmt.Sprintf(`"%s" "%s" telefone`, query, city)
-	for _, instance := range searxngInstances {
-		searchURL := fmt.Sprintf("%s/search?q=%s&language=pt-BR&format=html", instance, url.QueryEscape(q))
-		leads, err := searchEngineLeads(ctx, searchURL,
-			".result-content, .result_header, .result-description", city, state, "SearXNG", 1500*time.Millisecond)
-		if err == nil && len(leads) > 0 {
-			return leads, nil
-		}
-	}
-	return nil, fmt.Errorf("SearXNG: no results from any instance")
+	q := fmt.Sprintf(`%s "%s" telefone`, expandedQuery(query), city)
+	return searchEngineLeads(ctx, serp.SearXNG, q, city, state)
 }
 
 // ─── Mojeek ───────────────────────────────────────────────────────────────────
@@ -208,10 +187,8 @@ func (m *MojeekLeadScraper) Name() string      { return "Mojeek" }
 
 func (m *MojeekLeadScraper) Search(ctx context.Context, query, location string) ([]*Lead, error) {
 	city, state := ParseLocation(location)
-	q := fmt.Sprintf(`"%s" "%s" telefone`, query, city)
-	return searchEngineLeads(ctx,
-		"https://www.mojeek.com/search?q="+url.QueryEscape(q),
-		".result-text, .result__body, .result-wrap p", city, state, "Mojeek", 1500*time.Millisecond)
+	q := fmt.Sprintf(`%s "%s" telefone`, expandedQuery(query), city)
+	return searchEngineLeads(ctx, serp.Mojeek, q, city, state)
 }
 
 // ─── Swisscows ────────────────────────────────────────────────────────────────
@@ -224,39 +201,31 @@ func (s *SwisscowsLeadScraper) Name() string         { return "Swisscows" }
 func (s *SwisscowsLeadScraper) Search(ctx context.Context, query, location string) ([]*Lead, error) {
 	city, state := ParseLocation(location)
 	q := fmt.Sprintf(`%s %s telefone Brasil`, query, city)
-	return searchEngineLeads(ctx,
-		"https://swisscows.com/web?query="+url.QueryEscape(q)+"&region=pt-BR",
-		".web-results .item-body, .result-item .description", city, state, "Swisscows", 2000*time.Millisecond)
+	return searchEngineLeads(ctx, serp.Swisscows, q, city, state)
 }
 
 // ─── Helper genérico ─────────────────────────────────────────────────────────
 
-func searchEngineLeads(ctx context.Context, searchURL, selector, city, state, source string, delay time.Duration) ([]*Lead, error) {
-	time.Sleep(delay)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
-	req.Header.Set("Accept-Language", "pt-BR,pt;q=0.9")
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
-
-	client := &http.Client{Timeout: 15 * time.Second}
-	resp, err := DoWithRetry(ctx, client, req, 3)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("status %d", resp.StatusCode)
+// expandedQuery monta a parte da busca referente ao negócio: o termo entre
+// aspas ou, se a taxonomia conhece a categoria, o termo e dois sinônimos
+// ligados por OR — ("loja de roupas" OR "moda" OR "boutique").
+func expandedQuery(query string) string {
+	terms := taxonomy.Expand(query, 2)
+	if len(terms) == 1 {
+		return `"` + terms[0] + `"`
 	}
+	return `("` + strings.Join(terms, `" OR "`) + `")`
+}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+// searchEngineLeads busca no motor e extrai um lead de cada resultado que
+// tenha telefone ou e-mail no título/snippet. Se nenhum resultado render lead,
+// cai na extração por texto corrido da página.
+func searchEngineLeads(ctx context.Context, engine serp.Engine, query, city, state string) ([]*Lead, error) {
+	page, err := serp.Search(ctx, engine, query)
 	if err != nil {
 		return nil, err
 	}
+	source := engine.Name()
 
 	phoneRe := regexp.MustCompile(`\(?\d{2}\)?[\s.]?\d{4,5}[-\s.]?\d{4}`)
 	emailRe := regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`)
@@ -302,75 +271,33 @@ func searchEngineLeads(ctx context.Context, searchURL, selector, city, state, so
 	}
 
 	var leads []*Lead
+	for _, r := range page.Results {
+		combined := r.Title + "\n" + r.Snippet
 
-	// Tenta extrair resultados estruturados (título + snippet por resultado)
-	// Seletores para diferentes motores
-	resultSelectors := []struct{ container, title, snippet string }{
-		// DuckDuckGo
-		{".result", ".result__title", ".result__snippet"},
-		// Bing
-		{"#b_results .b_algo", "h2", ".b_caption p"},
-		// Brave
-		{"[data-type='web'] .snippet", ".heading-results a, .result-title a, h3", ".snippet-description, .result-description"},
-		// Brave alternativo
-		{".card", "h3, .title", ".description, .snippet-description"},
-	}
-
-	for _, rs := range resultSelectors {
-		count := 0
-		doc.Find(rs.container).Each(func(_ int, item *goquery.Selection) {
-			titleText := strings.TrimSpace(item.Find(rs.title).First().Text())
-			snippetText := strings.TrimSpace(item.Find(rs.snippet).First().Text())
-			combined := titleText + "\n" + snippetText
-
-			phones := phoneRe.FindAllString(combined, 2)
-			emails := emailRe.FindAllString(combined, 1)
-			if len(phones) == 0 && len(emails) == 0 {
-				return
-			}
+		phones := phoneRe.FindAllString(combined, 2)
+		emails := emailRe.FindAllString(combined, 1)
+		if len(phones) == 0 && len(emails) == 0 {
+			continue
+		}
 
-			lead := &Lead{City: city, State: state, Source: source}
-			if len(phones) > 0 {
-				lead.Phone = normalizePhone(phones[0])
-				if len(phones) > 1 {
-					lead.Phone2 = normalizePhone(phones[1])
-				}
+		lead := &Lead{City: city, State: state, Source: source}
+		if len(phones) > 0 {
+			lead.Phone = normalizePhone(phones[0])
+			if len(phones) > 1 {
+				lead.Phone2 = normalizePhone(phones[1])
 			}
-			if len(emails) > 0 {
-				lead.Email = emails[0]
-			}
-			lead.Name = extractName(titleText, snippetText)
-			leads = append(leads, lead)
-			count++
-		})
-		if count > 0 {
-			break
 		}
+		if len(emails) > 0 {
+			lead.Email = emails[0]
+		}
+		lead.Name = extractName(r.Title, r.Snippet)
+		leads = append(leads, lead)
 	}
 
-	// Fallback: extração por texto corrido do seletor original
+	// Fallback: extração por texto corrido (resultados linha a linha ou o
+	// texto da página, se o layout não foi reconhecido)
 	if len(leads) == 0 {
-		var allText strings.Builder
-		doc.Find(selector).Each(func(i int, s *goquery.Selection) {
-			allText.WriteString(s.Text())
-			allText.WriteString("\n")
-		})
-		// Segundo fallback: divs de conteúdo
-		if allText.Len() < 50 {
-			doc.Find(".content, .description, p, li, [class*='snippet'], [class*='result'], [class*='caption']").Each(func(i int, s *goquery.Selection) {
-				t := strings.TrimSpace(s.Text())
-				if len(t) > 10 && len(t) < 500 {
-					allText.WriteString(t)
-					allText.WriteString("\n")
-				}
-			})
-		}
-		// Terceiro fallback: texto completo do body
-		if allText.Len() < 50 {
-			bodyText := strings.TrimSpace(doc.Find("body").Text())
-			allText.WriteString(bodyText)
-		}
-		leads = extractLeadsFromText(allText.String(), city, state, source)
+		leads = extractLeadsFromText(page.Text(), city, state, source)
 	}
 
 	return leads, nil
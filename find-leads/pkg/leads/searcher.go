@@ -7,6 +7,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/lucasfdcampos/find-leads/pkg/ibge"
 )
 
 // Searcher interface que todas as fontes devem implementar
@@ -21,30 +23,21 @@ type SearchResult struct {
 	Leads  []*Lead
 	Err    error
 	Took   time.Duration
+	// Discarded são os leads de IA da fonte descartados por VerifyAILeads.
+	Discarded []AIDiscard
 }
 
-// ParseLocation divide "Arapongas-PR" em cidade e estado
+// ParseLocation divide "Arapongas-PR" em cidade e estado.
+//
+// Quando a cidade está na tabela do IBGE, retorna o nome canônico
+// ("Sta. Terezinha de Itaipu" → "Santa Terezinha de Itaipu"; com a tabela
+// completa, também erros de digitação como "Londrna, PR" → "Londrina").
+// Caso contrário, só separa a UF (sigla ou nome do estado).
 func ParseLocation(location string) (city, state string) {
-	location = strings.TrimSpace(location)
-
-	// Normalise separators: "Arapongas - PR", "Arapongas-PR", "Arapongas, PR", "Arapongas,PR"
-	// Replace any combination of spaces/commas/dashes around a 2-letter state code.
-	// Strategy: split on comma or dash (allowing surrounding spaces), then check last token.
-	re := regexp.MustCompile(`[\s,\-]+`)
-	tokens := re.Split(location, -1)
-
-	if len(tokens) >= 2 {
-		last := strings.ToUpper(tokens[len(tokens)-1])
-		if len(last) == 2 && last >= "AA" && last <= "ZZ" {
-			state = last
-			city = strings.Join(tokens[:len(tokens)-1], " ")
-			return
-		}
+	if m, err := ibge.Default().Resolve(location); err == nil {
+		return m.Name, m.UF
 	}
-
-	// Fallback: entire string is the city
-	city = location
-	return
+	return ibge.SplitLocation(location)
 }
 
 // CitySlug converte nome de cidade para URL slug
@@ -65,6 +58,13 @@ func QuerySlug(q string) string {
 
 // SearchAll executa todas as fontes concorrentemente (máx 5 simultâneas) e retorna leads deduplicados
 func SearchAll(ctx context.Context, query, location string, searchers ...Searcher) ([]*Lead, []SearchResult) {
+	found, results, _ := SearchAllWithMerges(ctx, query, location, DefaultDedupConfig(), searchers...)
+	return found, results
+}
+
+// SearchAllWithMerges é SearchAll com limiares de deduplicação configuráveis;
+// retorna também o registro dos merges (ver DeduplicateWithLog).
+func SearchAllWithMerges(ctx context.Context, query, location string, cfg DedupConfig, searchers ...Searcher) ([]*Lead, []SearchResult, []MergeRecord) {
 	const maxConcurrent = 5
 
 	results := make([]SearchResult, len(searchers))
@@ -118,9 +118,25 @@ func SearchAll(ctx context.Context, query, location string, searchers ...Searche
 		}
 	}
 
-	deduplicated := Deduplicate(allLeads)
-	return deduplicated, results
+	// A verificação vem antes do merge: depois dele, o lead de IA
+	// corroborado já foi absorvido pelo lead do diretório e o não
+	// corroborado não tem mais com o que ser comparado.
+	verified, discarded := VerifyAILeads(allLeads)
+	deduplicated, merges := DeduplicateWithLog(verified, cfg)
+	for _, d := range discarded {
+		for i := range results {
+			if results[i].Source == firstSource(d.Source) {
+				results[i].Discarded = append(results[i].Discarded, d)
+				break
+			}
+		}
+	}
+	for _, l := range deduplicated {
+		l.AnnotatePhone()
+	}
+	return deduplicated, results, merges
 }
+
 // ─── Enrichment ───────────────────────────────────────────────────────────────
 
 // EnrichOptions configura o enriquecimento de leads pós-descoberta.
@@ -133,9 +149,14 @@ type EnrichOptions struct {
 	CNPJWorkers int
 	// InstagramWorkers define o número de goroutines para enriquecimento Instagram (padrão 4).
 	InstagramWorkers int
+	// Website ativa a descoberta + crawl do site oficial (e-mail, telefones, WhatsApp, redes).
+	// Roda depois de CNPJ e Instagram, que fornecem pistas (e-mail do cadastro, link da bio).
+	Website bool
+	// WebsiteWorkers define o número de goroutines para enriquecimento pelo site (padrão 4).
+	WebsiteWorkers int
 }
 
-// EnrichAll enriquece concorrentemente uma fatia de leads com CNPJ e/ou Instagram.
+// EnrichAll enriquece concorrentemente uma fatia de leads com CNPJ, Instagram e/ou site.
 // Os leads são modificados in-place.
 func EnrichAll(ctx context.Context, leads []*Lead, opts EnrichOptions) {
 	if opts.CNPJWorkers <= 0 {
@@ -144,12 +165,22 @@ func EnrichAll(ctx context.Context, leads []*Lead, opts EnrichOptions) {
 	if opts.InstagramWorkers <= 0 {
 		opts.InstagramWorkers = 4
 	}
+	if opts.WebsiteWorkers <= 0 {
+		opts.WebsiteWorkers = 4
+	}
 	if opts.CNPJ {
 		enrichConcurrent(ctx, leads, opts.CNPJWorkers, EnrichCNPJ)
 	}
 	if opts.Instagram {
 		enrichConcurrent(ctx, leads, opts.InstagramWorkers, EnrichInstagram)
 	}
+	if opts.Website {
+		enrichConcurrent(ctx, leads, opts.WebsiteWorkers, EnrichWebsite)
+	}
+	// O telefone pode ter vindo do site e a UF do cadastro CNPJ
+	for _, l := range leads {
+		l.AnnotatePhone()
+	}
 }
 
 // enrichConcurrent executa fn para cada lead com um pool de workers.
@@ -169,4 +200,4 @@ func enrichConcurrent(ctx context.Context, leads []*Lead, workers int, fn func(c
 		}(l)
 	}
 	wg.Wait()
-}
\ No newline at end of file
+}
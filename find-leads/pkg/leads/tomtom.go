@@ -11,6 +11,8 @@ import (
 "strconv"
 "strings"
 "time"
+
+"github.com/lucasfdcampos/find-leads/pkg/taxonomy"
 )
 
 const (
@@ -67,6 +69,10 @@ params.Set("countrySet", "BR")
 params.Set("limit", strconv.Itoa(tomtomMaxLimit))
 params.Set("language", "pt-BR")
 params.Set("typeahead", "false")
+params.Set("openingHours", "nextSevenDays")
+if cats := taxonomy.TomTomCategories(query); len(cats) > 0 {
+params.Set("categorySet", strings.Join(cats, ","))
+}
 
 if hasCoords {
 params.Set("lat", strconv.FormatFloat(lat, 'f', 6, 64))
@@ -140,6 +146,10 @@ Address: addr,
 City:    resultCity,
 State:   resultState,
 Source:  "TomTom Places",
+Lat:     r.Position.Lat,
+Lon:     r.Position.Lon,
+
+OpeningHours: r.POI.OpeningHours.format(),
 })
 }
 
@@ -153,14 +163,59 @@ Results []tomtomResult `json:"results"`
 }
 
 type tomtomResult struct {
-POI     tomtomPOI     `json:"poi"`
-Address tomtomAddress `json:"address"`
+POI      tomtomPOI      `json:"poi"`
+Address  tomtomAddress  `json:"address"`
+Position tomtomPosition `json:"position"`
+}
+
+type tomtomPosition struct {
+Lat float64 `json:"lat"`
+Lon float64 `json:"lon"`
 }
 
 type tomtomPOI struct {
-Name  string `json:"name"`
-Phone string `json:"phone"`
-URL   string `json:"url"`
+Name         string             `json:"name"`
+Phone        string             `json:"phone"`
+URL          string             `json:"url"`
+OpeningHours tomtomOpeningHours `json:"openingHours"`
+}
+
+// tomtomOpeningHours vem com openingHours=nextSevenDays: intervalos com
+// data e hora para cada um dos próximos sete dias.
+type tomtomOpeningHours struct {
+TimeRanges []struct {
+StartTime tomtomDateTime `json:"startTime"`
+EndTime   tomtomDateTime `json:"endTime"`
+} `json:"timeRanges"`
+}
+
+type tomtomDateTime struct {
+Date   string `json:"date"` // 2006-01-02
+Hour   int    `json:"hour"`
+Minute int    `json:"minute"`
+}
+
+// format converte os intervalos dos próximos sete dias no horário semanal
+// (dia da semana da data de abertura), no formato do OSM.
+func (h tomtomOpeningHours) format() string {
+var sched Schedule
+for _, r := range h.TimeRanges {
+start, err1 := time.Parse("2006-01-02", r.StartTime.Date)
+end, err2 := time.Parse("2006-01-02", r.EndTime.Date)
+if err1 != nil || err2 != nil {
+continue
+}
+from := r.StartTime.Hour*60 + r.StartTime.Minute
+to := int(end.Sub(start).Hours())*60 + r.EndTime.Hour*60 + r.EndTime.Minute
+if to <= from || to > 2*minutesPerDay {
+continue
+}
+sched.Add(start.Weekday(), TimeRange{from, to})
+}
+if sched.IsEmpty() {
+return ""
+}
+return sched.String()
 }
 
 type tomtomAddress struct {
@@ -0,0 +1,209 @@
+package leads
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	phonepkg "github.com/lucasfdcampos/find-cnpj/pkg/phone"
+)
+
+// ─── Verificação de leads de IA ──────────────────────────────────────────────
+
+// aiSources são as fontes registradas por NewAIScraper.
+var aiSources sync.Map
+
+// IsAISource indica se a fonte é um scraper de IA (ver NewAIScraper).
+func IsAISource(source string) bool {
+	_, ok := aiSources.Load(source)
+	return ok
+}
+
+// checkNameInText é a evidência registrada pelo AIScraper quando o nome
+// devolvido pelo modelo aparece no texto de busca enviado a ele.
+const checkNameInText = "nome no texto da busca"
+
+// AIDiscard é um lead de IA descartado por VerifyAILeads.
+type AIDiscard struct {
+	Name   string
+	Source string
+	Reason string
+}
+
+func (d AIDiscard) String() string {
+	return fmt.Sprintf("%q [%s]: %s", d.Name, d.Source, d.Reason)
+}
+
+// aiOnly indica se todas as fontes do lead são de IA.
+func (l *Lead) aiOnly() bool {
+	l.normalizeSources()
+	if len(l.Sources) == 0 {
+		return false
+	}
+	for _, s := range l.Sources {
+		if !IsAISource(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// VerifyAILeads confere os leads que vieram só de fontes de IA, que podem
+// ter sido inventados pelo modelo (ex: listados de memória, sem texto de
+// busca). Cada um é marcado Unverified, tem os campos conferidos (telefone
+// com DDD de outra UF é removido) e só é mantido quando algo o corrobora:
+// o nome aparece no texto de busca enviado ao modelo, ou telefone, CNPJ ou
+// site coincidem com um lead de outra fonte. As evidências e correções vão
+// para Checks; os descartados voltam com o motivo.
+//
+// Deve rodar sobre os leads de todas as fontes antes de DeduplicateWithLog,
+// para comparar cada lead de IA com os leads das outras fontes. Leads que já
+// têm outra fonte passam sem alteração; um lead de IA mantido que depois é
+// absorvido por outra fonte deixa de ser Unverified (ver mergeLead).
+func VerifyAILeads(list []*Lead) ([]*Lead, []AIDiscard) {
+	idx := newCorroborationIndex(list)
+	kept := make([]*Lead, 0, len(list))
+	var discarded []AIDiscard
+	for _, l := range list {
+		if !l.aiOnly() {
+			kept = append(kept, l)
+			continue
+		}
+		l.Unverified = true
+		fixes := checkAIFields(l)
+		l.addChecks(fixes...)
+
+		evidence := idx.corroborate(l)
+		l.addChecks(evidence...)
+		if len(evidence) == 0 && !l.hasCheck(checkNameInText) {
+			reason := "nenhum campo corroborado: nome fora do texto de busca e telefone, CNPJ e site sem outra fonte"
+			if len(fixes) > 0 {
+				reason += " (" + strings.Join(fixes, "; ") + ")"
+			}
+			discarded = append(discarded, AIDiscard{Name: l.Name, Source: l.Source, Reason: reason})
+			continue
+		}
+		kept = append(kept, l)
+	}
+	return kept, discarded
+}
+
+// checkAIFields remove os campos inconsistentes do lead de IA e devolve as
+// correções feitas.
+func checkAIFields(l *Lead) []string {
+	var fixes []string
+	l.normalizePhones()
+	var phones []string
+	for _, p := range l.Phones {
+		n, err := phonepkg.Parse(p)
+		if err != nil {
+			phones = append(phones, p) // sem DDD reconhecível: não há o que conferir
+			continue
+		}
+		if !n.MatchesState(l.State) {
+			fixes = append(fixes, fmt.Sprintf("telefone %s removido: DDD %s é de %s, não de %s", n.Format(), n.DDD, n.UF, l.State))
+			continue
+		}
+		phones = append(phones, p)
+	}
+	l.Phones, l.Phone, l.Phone2 = phones, "", ""
+	if len(phones) > 0 {
+		l.Phone = phones[0]
+	}
+	if len(phones) > 1 {
+		l.Phone2 = phones[1]
+	}
+
+	if l.Website != "" {
+		if _, _, ok := normalizeSiteURL(l.Website); !ok {
+			fixes = append(fixes, fmt.Sprintf("site %q removido: URL inválida", l.Website))
+			l.Website = ""
+		}
+	}
+	if l.CNPJ != "" && len(onlyDigits(l.CNPJ)) != 14 {
+		fixes = append(fixes, fmt.Sprintf("CNPJ %q removido: não tem 14 dígitos", l.CNPJ))
+		l.CNPJ = ""
+	}
+	return fixes
+}
+
+func (l *Lead) hasCheck(check string) bool {
+	for _, c := range l.Checks {
+		if c == check {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Lead) addChecks(checks ...string) {
+	for _, c := range checks {
+		if !l.hasCheck(c) {
+			l.Checks = append(l.Checks, c)
+		}
+	}
+}
+
+// corroborationIndex guarda telefones, CNPJs e sites dos leads que têm ao
+// menos uma fonte que não é de IA, com a fonte de cada um.
+type corroborationIndex map[string]string
+
+func newCorroborationIndex(list []*Lead) corroborationIndex {
+	idx := make(corroborationIndex)
+	for _, l := range list {
+		if l.aiOnly() {
+			continue
+		}
+		src := firstSource(l.Source)
+		for _, k := range corroborationKeys(l) {
+			if _, ok := idx[k]; !ok {
+				idx[k] = src
+			}
+		}
+	}
+	return idx
+}
+
+// corroborate devolve as evidências de l encontradas no índice.
+func (idx corroborationIndex) corroborate(l *Lead) []string {
+	var evidence []string
+	for _, k := range corroborationKeys(l) {
+		if src, ok := idx[k]; ok {
+			field, value, _ := strings.Cut(k, ":")
+			evidence = append(evidence, fmt.Sprintf("%s %s também em %s", field, value, src))
+		}
+	}
+	return evidence
+}
+
+func corroborationKeys(l *Lead) []string {
+	var keys []string
+	for _, p := range l.Phones {
+		if k := phonepkg.Key(p); len(k) >= 8 {
+			keys = append(keys, "telefone:"+k)
+		}
+	}
+	if len(l.Phones) == 0 {
+		for _, p := range []string{l.Phone, l.Phone2} {
+			if k := phonepkg.Key(p); len(k) >= 8 {
+				keys = append(keys, "telefone:"+k)
+			}
+		}
+	}
+	if d := onlyDigits(l.CNPJ); len(d) == 14 {
+		keys = append(keys, "CNPJ:"+d)
+	}
+	if d := siteDomain(l.Website); d != "" {
+		keys = append(keys, "site:"+d)
+	}
+	return keys
+}
+
+// nameInText indica se o nome (sem sufixos societários) aparece no texto.
+func nameInText(name, text string) bool {
+	core := strings.Join(coreNameTokens(name), " ")
+	if core == "" {
+		return false
+	}
+	return strings.Contains(" "+normalizeString(text)+" ", " "+core+" ")
+}
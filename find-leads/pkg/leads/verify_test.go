@@ -0,0 +1,88 @@
+package leads
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// staticSearcher devolve sempre os mesmos leads.
+type staticSearcher struct {
+	name  string
+	leads []Lead
+}
+
+func (s staticSearcher) Name() string { return s.name }
+
+func (s staticSearcher) Search(context.Context, string, string) ([]*Lead, error) {
+	out := make([]*Lead, len(s.leads))
+	for i := range s.leads {
+		l := s.leads[i]
+		l.Source = s.name
+		out[i] = &l
+	}
+	return out, nil
+}
+
+func TestSearchAllVerifiesAILeadsBeforeMerge(t *testing.T) {
+	const ai = "AI Verify Test"
+	aiSources.Store(ai, true)
+
+	directory := staticSearcher{name: "Diretório", leads: []Lead{
+		{Name: "Padaria Trigo de Ouro", Phone: "(43) 3325-4471", City: "Londrina", State: "PR"},
+		{Name: "Confeitaria Doce Lar", Website: "https://www.docelar.com.br", City: "Londrina", State: "PR"},
+	}}
+	model := staticSearcher{name: ai, leads: []Lead{
+		// mesmo telefone do diretório: corroborado e absorvido pelo merge
+		{Name: "Trigo de Ouro Panificadora", Phone: "43 3325-4471", City: "Londrina", State: "PR"},
+		// mesmo site, nome diferente: corroborado, mas não é o mesmo negócio
+		{Name: "Bolos da Vovó", Website: "docelar.com.br/bolos", City: "Londrina", State: "PR"},
+		// nada em comum com outras fontes: descartado
+		{Name: "Padaria Fantasma", Phone: "(43) 3344-5521", City: "Londrina", State: "PR"},
+	}}
+
+	found, results, merges := SearchAllWithMerges(context.Background(), "padaria", "Londrina - PR", DefaultDedupConfig(), directory, model)
+
+	byName := make(map[string]*Lead)
+	for _, l := range found {
+		byName[l.Name] = l
+	}
+	if len(found) != 3 {
+		t.Fatalf("%d leads, want 3: %v", len(found), found)
+	}
+	if len(merges) != 1 {
+		t.Errorf("merges = %v, want 1", merges)
+	}
+
+	trigo := byName["Padaria Trigo de Ouro"]
+	if trigo == nil {
+		t.Fatal("lead do diretório sumiu")
+	}
+	if trigo.Unverified {
+		t.Error("lead com fonte de diretório não deveria ser Unverified")
+	}
+	if !strings.Contains(strings.Join(trigo.Checks, ","), "também em Diretório") {
+		t.Errorf("Checks = %v, want evidência do telefone", trigo.Checks)
+	}
+
+	bolos := byName["Bolos da Vovó"]
+	if bolos == nil || !bolos.Unverified {
+		t.Fatalf("lead de IA corroborado pelo site: %+v", bolos)
+	}
+	if !strings.Contains(strings.Join(bolos.Checks, ","), "site docelar.com.br também em Diretório") {
+		t.Errorf("Checks = %v, want evidência do site", bolos.Checks)
+	}
+
+	if _, ok := byName["Padaria Fantasma"]; ok {
+		t.Error("lead de IA sem evidência deveria ser descartado")
+	}
+	var discarded []AIDiscard
+	for _, r := range results {
+		if r.Source == ai {
+			discarded = r.Discarded
+		}
+	}
+	if len(discarded) != 1 || discarded[0].Name != "Padaria Fantasma" {
+		t.Errorf("Discarded = %v, want Padaria Fantasma", discarded)
+	}
+}
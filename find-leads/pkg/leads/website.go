@@ -0,0 +1,587 @@
+package leads
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	cnpjpkg "github.com/lucasfdcampos/find-cnpj/pkg/cnpj"
+	phonepkg "github.com/lucasfdcampos/find-cnpj/pkg/phone"
+	igpkg "github.com/lucasfdcampos/find-instagram/pkg/instagram"
+	"github.com/lucasfdcampos/serp/pkg/serp"
+)
+
+// ─── Tipos ───────────────────────────────────────────────────────────────────
+
+// Origem do site oficial (valor de WebsiteInfo.FoundVia).
+const (
+	SiteViaLead      = "lead"          // já veio do scraper
+	SiteViaEmail     = "email_domain"  // domínio do e-mail (lead ou CNPJ)
+	SiteViaInstagram = "instagram_bio" // link da bio do Instagram
+	SiteViaSearch    = "search"        // resultado de busca
+)
+
+// WebsiteHints são as pistas disponíveis para descobrir o site oficial.
+type WebsiteHints struct {
+	Website   string // site já conhecido
+	Email     string // e-mail do lead ou do cadastro CNPJ
+	Instagram string // handle do Instagram (para o link da bio)
+}
+
+// SiteCandidate é um possível site oficial e a pista que o originou.
+type SiteCandidate struct {
+	URL string
+	Via string
+}
+
+// WebsiteInfo é o que foi extraído do site oficial de um lead.
+type WebsiteInfo struct {
+	URL      string            // home do site (após redirects)
+	FoundVia string            // ver SiteVia*
+	Pages    []string          // páginas visitadas
+	Emails   []string          // e-mails encontrados (mailto + texto)
+	Phones   []string          // telefones formatados "(XX) XXXX-XXXX"
+	WhatsApp []string          // números (só dígitos, com 55) de links wa.me / api.whatsapp.com
+	CNPJs    []string          // CNPJs válidos, formatados
+	Socials  map[string]string // rede → URL (instagram, facebook, linkedin, tiktok, youtube, twitter)
+	Business *Lead             // negócio descrito em schema.org no site, se houver (ver ExtractSchemaOrg)
+}
+
+// WebsiteOptions limita o crawl.
+type WebsiteOptions struct {
+	MaxPages    int           // páginas por site, incluindo a home (padrão 4)
+	PageTimeout time.Duration // timeout por página (padrão 10s)
+	NoSearch    bool          // não usa buscadores na descoberta
+}
+
+func (o WebsiteOptions) withDefaults() WebsiteOptions {
+	if o.MaxPages <= 0 {
+		o.MaxPages = 4
+	}
+	if o.PageTimeout <= 0 {
+		o.PageTimeout = 10 * time.Second
+	}
+	return o
+}
+
+// ─── Descoberta ──────────────────────────────────────────────────────────────
+
+// freeEmailDomains são provedores de e-mail gratuitos — o domínio não é o site da empresa.
+var freeEmailDomains = map[string]bool{
+	"gmail.com": true, "googlemail.com": true, "hotmail.com": true, "hotmail.com.br": true,
+	"outlook.com": true, "outlook.com.br": true, "live.com": true, "msn.com": true,
+	"yahoo.com": true, "yahoo.com.br": true, "ymail.com": true, "icloud.com": true,
+	"me.com": true, "bol.com.br": true, "uol.com.br": true, "terra.com.br": true,
+	"ig.com.br": true, "globo.com": true, "globomail.com": true, "r7.com": true,
+	"zipmail.com.br": true, "protonmail.com": true, "proton.me": true,
+}
+
+// notOfficialSiteHosts são domínios que nunca são o site próprio de um negócio:
+// redes sociais, diretórios, marketplaces, encurtadores e agregadores de links.
+var notOfficialSiteHosts = []string{
+	"facebook.com", "instagram.com", "linkedin.com", "twitter.com", "x.com",
+	"tiktok.com", "youtube.com", "pinterest.com", "wa.me", "whatsapp.com",
+	"linktr.ee", "linktree.com", "beacons.ai", "bio.link", "taplink.cc", "bit.ly",
+	"google.com", "google.com.br", "goo.gl", "g.page", "maps.app.goo.gl", "bing.com", "duckduckgo.com",
+	"wikipedia.org", "reclameaqui.com.br", "tripadvisor.com", "tripadvisor.com.br",
+	"ifood.com.br", "mercadolivre.com.br", "shopee.com.br", "olx.com.br", "americanas.com.br",
+	"solutudo.com.br", "guiamais.com.br", "apontador.com.br", "telelistas.net",
+	"applocal.com.br", "listamais.com.br", "encontra", "cylex", "hotfrog",
+	"cnpj.biz", "cnpja.com", "econodata.com.br", "casadosdados.com.br", "cnpj.info",
+	"empresascnpj.com", "serasaexperian.com.br", "consultas.plus", "informecadastral",
+	"doctoralia.com.br", "booking.com", "yelp.com", "foursquare.com", "waze.com",
+}
+
+// isOfficialSiteHost informa se o host pode ser o site próprio de um negócio.
+func isOfficialSiteHost(host string) bool {
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+	if host == "" || !strings.Contains(host, ".") {
+		return false
+	}
+	for _, blocked := range notOfficialSiteHosts {
+		// Só rótulos inteiros: "x.com" bloqueia "m.x.com", não "lojax.com.br"
+		if host == blocked || strings.HasSuffix(host, "."+blocked) {
+			return false
+		}
+		if !strings.Contains(blocked, ".") && strings.Contains(host, blocked) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeSiteURL garante esquema http(s) e retorna a URL da home.
+func normalizeSiteURL(raw string) (string, string, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", "", false
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", "", false
+	}
+	return u.Scheme + "://" + strings.ToLower(u.Host) + "/", strings.ToLower(u.Hostname()), true
+}
+
+// DiscoverWebsite lista os candidatos a site oficial, na ordem em que devem
+// ser tentados: site já conhecido → domínio do e-mail → link da bio do
+// Instagram → resultados de busca. Candidatos com o mesmo host aparecem uma vez.
+func DiscoverWebsite(ctx context.Context, name, city, state string, hints WebsiteHints, opts WebsiteOptions) []SiteCandidate {
+	var out []SiteCandidate
+	seen := make(map[string]bool)
+	add := func(raw, via string) {
+		home, host, ok := normalizeSiteURL(raw)
+		if !ok || !isOfficialSiteHost(host) || seen[strings.TrimPrefix(host, "www.")] {
+			return
+		}
+		seen[strings.TrimPrefix(host, "www.")] = true
+		out = append(out, SiteCandidate{URL: home, Via: via})
+	}
+
+	add(hints.Website, SiteViaLead)
+
+	if at := strings.LastIndex(hints.Email, "@"); at > 0 {
+		domain := strings.ToLower(strings.TrimSpace(hints.Email[at+1:]))
+		if !freeEmailDomains[domain] {
+			add(domain, SiteViaEmail)
+		}
+	}
+
+	if hints.Instagram != "" {
+		bctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+		if link, err := igpkg.FetchBioLink(bctx, hints.Instagram); err == nil {
+			add(link, SiteViaInstagram)
+		}
+		cancel()
+	}
+
+	if !opts.NoSearch && len(out) == 0 && name != "" {
+		for _, link := range searchOfficialSite(ctx, name, city, state) {
+			add(link, SiteViaSearch)
+		}
+	}
+	return out
+}
+
+// searchOfficialSite busca "nome cidade" e retorna links cujo domínio contém
+// uma palavra significativa do nome do negócio.
+func searchOfficialSite(ctx context.Context, name, city, state string) []string {
+	q := fmt.Sprintf(`%s %s %s site oficial`, name, city, state)
+
+	var nameTokens []string
+	for _, w := range strings.Fields(normalizeString(name)) {
+		if len(w) >= 4 {
+			nameTokens = append(nameTokens, w)
+		}
+	}
+	if len(nameTokens) == 0 {
+		return nil
+	}
+
+	for _, engine := range []serp.Engine{serp.DuckDuckGo, serp.Bing} {
+		page, err := serp.Search(ctx, engine, q)
+		if err != nil {
+			continue
+		}
+
+		var links []string
+		for _, r := range page.Results {
+			u, err := url.Parse(r.URL)
+			if err != nil || u.Host == "" || !isOfficialSiteHost(u.Hostname()) {
+				continue
+			}
+			host := strings.NewReplacer(".", "", "-", "").Replace(strings.ToLower(u.Hostname()))
+			for _, tok := range nameTokens {
+				if strings.Contains(host, tok) {
+					links = append(links, r.URL)
+					break
+				}
+			}
+		}
+		if len(links) > 0 {
+			if len(links) > 2 {
+				links = links[:2]
+			}
+			return links
+		}
+	}
+	return nil
+}
+
+// ─── Crawl ───────────────────────────────────────────────────────────────────
+
+// sitePageKeywords identificam links internos com dados de contato, em ordem de prioridade.
+var sitePageKeywords = []string{"contato", "contact", "fale-conosco", "faleconosco", "atendimento", "sobre", "about", "quem-somos", "empresa", "institucional"}
+
+// sitePageFallbacks são caminhos tentados quando a home não linka páginas de contato.
+var sitePageFallbacks = []string{"/contato", "/sobre", "/fale-conosco"}
+
+var (
+	reSiteEmail    = regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`)
+	reSitePhone    = regexp.MustCompile(`(?:\+?55[\s.]?)?\(?\b\d{2}\)?[\s.]?9?\d{4}[-\s.]?\d{4}\b`)
+	reWhatsAppPath = regexp.MustCompile(`(?i)(?:wa\.me/|whatsapp\.com/send/?\?(?:.*&)?phone=|whatsapp\.com/\?phone=)\+?(\d{10,13})`)
+)
+
+// socialHosts mapeia domínios de redes sociais para o nome da rede.
+var socialHosts = map[string]string{
+	"instagram.com": "instagram", "facebook.com": "facebook", "fb.com": "facebook",
+	"linkedin.com": "linkedin", "tiktok.com": "tiktok", "youtube.com": "youtube",
+	"twitter.com": "twitter", "x.com": "twitter",
+}
+
+// socialJunkPaths são links de compartilhamento/posts, não perfis.
+var socialJunkPaths = []string{"/sharer", "/share", "/intent/", "/p/", "/reel/", "/watch", "/plugins/", "/dialog/", "/tr?", "/embed"}
+
+// CrawlWebsite visita a home do site e até MaxPages-1 páginas internas de
+// contato/sobre, extraindo e-mails, telefones, WhatsApp, CNPJs e redes sociais.
+func CrawlWebsite(ctx context.Context, siteURL string, opts WebsiteOptions) (*WebsiteInfo, error) {
+	opts = opts.withDefaults()
+
+	home, _, ok := normalizeSiteURL(siteURL)
+	if !ok {
+		return nil, fmt.Errorf("url inválida: %q", siteURL)
+	}
+
+	client := &http.Client{
+		Timeout: opts.PageTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		},
+	}
+
+	doc, finalURL, err := fetchSitePage(ctx, client, home)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &WebsiteInfo{URL: finalURL.Scheme + "://" + finalURL.Host + "/", Socials: make(map[string]string)}
+	ex := newSiteExtractor(info)
+	info.Pages = append(info.Pages, finalURL.String())
+	ex.extract(doc)
+
+	// Links internos de contato/sobre, priorizados pela ordem das palavras-chave
+	type pageLink struct {
+		url  string
+		prio int
+	}
+	var internal []pageLink
+	seen := map[string]bool{finalURL.String(): true}
+	doc.Find("a[href]").Each(func(_ int, s *goquery.Selection) {
+		href, _ := s.Attr("href")
+		u, err := finalURL.Parse(strings.TrimSpace(href))
+		if err != nil || !sameSite(u.Hostname(), finalURL.Hostname()) {
+			return
+		}
+		u.Fragment = ""
+		key := strings.ToLower(u.Path + " " + normalizeString(s.Text()))
+		for prio, kw := range sitePageKeywords {
+			if strings.Contains(key, kw) || strings.Contains(key, strings.ReplaceAll(kw, "-", " ")) {
+				if !seen[u.String()] {
+					seen[u.String()] = true
+					internal = append(internal, pageLink{u.String(), prio})
+				}
+				return
+			}
+		}
+	})
+	sort.SliceStable(internal, func(i, j int) bool { return internal[i].prio < internal[j].prio })
+
+	var next []string
+	for _, l := range internal {
+		next = append(next, l.url)
+	}
+	if len(next) == 0 {
+		for _, p := range sitePageFallbacks {
+			if u, err := finalURL.Parse(p); err == nil {
+				next = append(next, u.String())
+			}
+		}
+	}
+
+	for _, pageURL := range next {
+		if len(info.Pages) >= opts.MaxPages || ctx.Err() != nil {
+			break
+		}
+		pdoc, purl, err := fetchSitePage(ctx, client, pageURL)
+		if err != nil {
+			continue
+		}
+		info.Pages = append(info.Pages, purl.String())
+		ex.extract(pdoc)
+	}
+
+	return info, nil
+}
+
+// fetchSitePage baixa uma página HTML (até 1 MB).
+func fetchSitePage(ctx context.Context, client *http.Client, pageURL string) (*goquery.Document, *url.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("Accept-Language", "pt-BR,pt;q=0.9")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("status %d em %s", resp.StatusCode, pageURL)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.Contains(ct, "html") {
+		return nil, nil, fmt.Errorf("conteúdo não-HTML (%s) em %s", ct, pageURL)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, nil, err
+	}
+	return doc, resp.Request.URL, nil
+}
+
+// sameSite compara hosts ignorando o prefixo www.
+func sameSite(a, b string) bool {
+	return strings.TrimPrefix(strings.ToLower(a), "www.") == strings.TrimPrefix(strings.ToLower(b), "www.")
+}
+
+// siteExtractor acumula os dados de todas as páginas sem duplicar.
+type siteExtractor struct {
+	info *WebsiteInfo
+	seen map[string]bool
+}
+
+func newSiteExtractor(info *WebsiteInfo) *siteExtractor {
+	return &siteExtractor{info: info, seen: make(map[string]bool)}
+}
+
+func (e *siteExtractor) once(kind, v string) bool {
+	k := kind + ":" + v
+	if e.seen[k] {
+		return false
+	}
+	e.seen[k] = true
+	return true
+}
+
+func (e *siteExtractor) extract(doc *goquery.Document) {
+	// schema.org (JSON-LD, microdata, RDFa) antes de remover os <script>
+	for _, b := range extractSchemaOrg(doc, "", isSchemaSiteOwner) {
+		for _, p := range b.Phones {
+			e.addPhone(p)
+		}
+		if b.Email != "" {
+			e.addEmail(b.Email)
+		}
+		if b.CNPJ != "" {
+			if c := cnpjpkg.ExtractAllCNPJs(b.CNPJ); len(c) > 0 && e.once("cnpj", c[0].Number) {
+				e.info.CNPJs = append(e.info.CNPJs, c[0].Formatted)
+			}
+		}
+		for _, link := range b.SocialLinks {
+			e.addSocial(link)
+		}
+		if e.info.Business == nil {
+			e.info.Business = b
+		}
+	}
+
+	// Links: mailto, tel, WhatsApp, redes sociais
+	doc.Find("a[href]").Each(func(_ int, s *goquery.Selection) {
+		href := strings.TrimSpace(s.AttrOr("href", ""))
+		lower := strings.ToLower(href)
+		switch {
+		case strings.HasPrefix(lower, "mailto:"):
+			addr := strings.SplitN(href[len("mailto:"):], "?", 2)[0]
+			e.addEmail(addr)
+		case strings.HasPrefix(lower, "tel:"):
+			e.addPhone(href[len("tel:"):])
+		case strings.Contains(lower, "wa.me/") || strings.Contains(lower, "whatsapp.com/"):
+			if m := reWhatsAppPath.FindStringSubmatch(href); m != nil {
+				e.addWhatsApp(m[1])
+			}
+		default:
+			e.addSocial(href)
+		}
+	})
+
+	doc.Find("script, style, noscript").Remove()
+	text := doc.Find("body").Text()
+
+	for _, m := range reSiteEmail.FindAllString(text, -1) {
+		e.addEmail(m)
+	}
+	for _, m := range reSitePhone.FindAllString(text, -1) {
+		e.addPhone(m)
+	}
+	for _, c := range cnpjpkg.ExtractAllCNPJs(text) {
+		if e.once("cnpj", c.Number) {
+			e.info.CNPJs = append(e.info.CNPJs, c.Formatted)
+		}
+	}
+}
+
+func (e *siteExtractor) addEmail(addr string) {
+	addr = strings.ToLower(strings.Trim(strings.TrimSpace(addr), ".,;:"))
+	if !reSiteEmail.MatchString(addr) {
+		return
+	}
+	// Descarta falsos positivos comuns (assets "logo@2x.png", placeholders, trackers)
+	for _, junk := range []string{".png", ".jpg", ".jpeg", ".gif", ".webp", ".svg", "example.", "seudominio", "seuemail", "email@", "sentry", "wixpress"} {
+		if strings.Contains(addr, junk) {
+			return
+		}
+	}
+	if e.once("email", addr) {
+		e.info.Emails = append(e.info.Emails, addr)
+	}
+}
+
+func (e *siteExtractor) addPhone(raw string) {
+	n, err := phonepkg.Parse(raw)
+	if err != nil {
+		return
+	}
+	if e.once("phone", n.E164()) {
+		e.info.Phones = append(e.info.Phones, n.Format())
+	}
+}
+
+// addWhatsApp aceita celulares e fixos (WhatsApp Business funciona em linha fixa).
+func (e *siteExtractor) addWhatsApp(number string) {
+	n, err := phonepkg.Parse(number)
+	if err != nil || n.DDD == "" {
+		return
+	}
+	digits := "55" + n.DDD + n.Subscriber
+	if e.once("wa", digits) {
+		e.info.WhatsApp = append(e.info.WhatsApp, digits)
+	}
+}
+
+func (e *siteExtractor) addSocial(href string) {
+	u, err := url.Parse(href)
+	if err != nil || u.Host == "" {
+		return
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	host = strings.TrimPrefix(host, "m.")
+	network, ok := socialHosts[host]
+	if !ok || strings.Trim(u.Path, "/") == "" {
+		return
+	}
+	lowerPath := strings.ToLower(u.Path + "?" + u.RawQuery)
+	for _, junk := range socialJunkPaths {
+		if strings.Contains(lowerPath, junk) {
+			return
+		}
+	}
+	if _, exists := e.info.Socials[network]; !exists {
+		e.info.Socials[network] = "https://" + host + strings.TrimRight(u.Path, "/")
+	}
+}
+
+// ─── Enriquecimento ──────────────────────────────────────────────────────────
+
+// FindWebsite descobre o site oficial e faz o crawl do primeiro candidato
+// que responder.
+func FindWebsite(ctx context.Context, name, city, state string, hints WebsiteHints, opts WebsiteOptions) (*WebsiteInfo, error) {
+	opts = opts.withDefaults()
+	candidates := DiscoverWebsite(ctx, name, city, state, hints, opts)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("site oficial não encontrado para %q", name)
+	}
+
+	var lastErr error
+	for _, c := range candidates {
+		info, err := CrawlWebsite(ctx, c.URL, opts)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		info.FoundVia = c.Via
+		return info, nil
+	}
+	return nil, fmt.Errorf("nenhum site candidato respondeu para %q: %w", name, lastErr)
+}
+
+// EnrichWebsite encontra o site oficial do lead e preenche apenas os campos
+// vazios (Website, Email, Phone/Phone2, WhatsApp, CNPJ, Instagram, redes
+// sociais e, via schema.org, endereço, horário e coordenadas), registrando a
+// origem de cada um em Provenance.
+func EnrichWebsite(ctx context.Context, lead *Lead) error {
+	tctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	hints := WebsiteHints{Website: lead.Website, Email: lead.Email, Instagram: lead.Instagram}
+	info, err := FindWebsite(tctx, lead.Name, lead.City, lead.State, hints, WebsiteOptions{})
+	if err != nil {
+		return err
+	}
+	ApplyWebsiteInfo(lead, info)
+	return nil
+}
+
+// ApplyWebsiteInfo copia os dados do site para os campos vazios do lead.
+func ApplyWebsiteInfo(lead *Lead, info *WebsiteInfo) {
+	src := "website:" + info.FoundVia
+
+	lead.SetField("website", &lead.Website, info.URL, src)
+	if len(info.Emails) > 0 {
+		lead.SetField("email", &lead.Email, info.Emails[0], src)
+	}
+	for _, p := range info.Phones {
+		lead.AddPhone(p, src)
+	}
+	if len(info.WhatsApp) > 0 {
+		lead.SetField("whatsapp", &lead.WhatsApp, info.WhatsApp[0], src)
+	}
+	if len(info.CNPJs) == 1 {
+		// Com vários CNPJs na página (ex: rodapé de agência) não dá para saber qual é o do lead
+		lead.SetField("cnpj", &lead.CNPJ, info.CNPJs[0], src)
+	}
+	if b := info.Business; b != nil {
+		lead.SetField("address", &lead.Address, b.Address, src)
+		lead.SetField("opening_hours", &lead.OpeningHours, b.OpeningHours, src)
+		lead.SetField("price_level", &lead.PriceLevel, b.PriceLevel, src)
+		if lead.Rating == "" && b.Rating != "" {
+			lead.SetField("rating", &lead.Rating, b.Rating, src)
+			lead.SetField("rating_count", &lead.RatingCount, b.RatingCount, src)
+		}
+		if !lead.HasCoords() && b.HasCoords() {
+			lead.Lat, lead.Lon = b.Lat, b.Lon
+			lead.setProvenance("coords", src)
+		}
+	}
+	if ig, ok := info.Socials["instagram"]; ok && lead.Instagram == "" {
+		if handle := igpkg.NormalizeHandle(ig); igpkg.IsValidHandle(handle) {
+			lead.SetField("instagram", &lead.Instagram, "@"+handle, src)
+		}
+	}
+	for network, link := range info.Socials {
+		if lead.SocialLinks == nil {
+			lead.SocialLinks = make(map[string]string)
+		}
+		if _, exists := lead.SocialLinks[network]; !exists {
+			lead.SocialLinks[network] = link
+			lead.setProvenance("social_links."+network, src)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package leads
+
+import "testing"
+
+func TestIsOfficialSiteHost(t *testing.T) {
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"padariatrigo.com.br", true},
+		{"www.padariatrigo.com.br", true},
+		// rótulos inteiros: domínios bloqueados dentro do nome não contam
+		{"lojax.com.br", true},
+		{"lojax.com", true},
+		{"mywa.me.com.br", true},
+		{"bookingfacil.com.br", true},
+		// bloqueados e seus subdomínios
+		{"x.com", false},
+		{"www.x.com", false},
+		{"m.facebook.com", false},
+		{"instagram.com", false},
+		{"maps.app.goo.gl", false},
+		{"google.com.br", false},
+		{"www.google.com.br", false},
+		{"londrina.solutudo.com.br", false},
+		{"WWW.TripAdvisor.com.br", false},
+		// sem ponto no nome do bloqueio: qualquer ocorrência
+		{"www.cylex.com.br", false},
+		{"encontralondrina.com.br", false},
+		// hosts inválidos
+		{"", false},
+		{"localhost", false},
+	}
+	for _, tt := range tests {
+		if got := isOfficialSiteHost(tt.host); got != tt.want {
+			t.Errorf("isOfficialSiteHost(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeSiteURL(t *testing.T) {
+	tests := []struct {
+		raw, home, host string
+		ok              bool
+	}{
+		{"padariatrigo.com.br/contato", "https://padariatrigo.com.br/", "padariatrigo.com.br", true},
+		{" http://WWW.Padaria.com.br ", "http://www.padaria.com.br/", "www.padaria.com.br", true},
+		{"ftp://padaria.com.br", "", "", false},
+		{"", "", "", false},
+	}
+	for _, tt := range tests {
+		home, host, ok := normalizeSiteURL(tt.raw)
+		if home != tt.home || host != tt.host || ok != tt.ok {
+			t.Errorf("normalizeSiteURL(%q) = %q, %q, %v, want %q, %q, %v", tt.raw, home, host, ok, tt.home, tt.host, tt.ok)
+		}
+	}
+}
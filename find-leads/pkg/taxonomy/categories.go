@@ -0,0 +1,583 @@
+package taxonomy
+
+// categories é a tabela de categorias. Sinônimos podem ter acento e plural
+// (a comparação normaliza); NameKeywords só recebe termos fortes o bastante
+// para classificar um negócio pelo nome ("drogaria" sim, "moda" não).
+//
+// TomTom: 7310 oficina, 7311 posto, 7314 hotel/motel, 7315 restaurante,
+// 7320 centro esportivo, 7321 hospital, 7326 farmácia, 7328 banco,
+// 7332 mercado, 9361 loja, 9376 café/bar, 9663 serviço de saúde.
+var categories = []Category{
+	// ── Vestuário / Moda ────────────────────────────────────────────────────
+	{
+		Key: "vestuario", Name: "loja de roupas",
+		Synonyms: []string{"roupas", "vestuário", "moda", "moda feminina", "moda masculina", "moda infantil", "confecção", "boutique", "brechó", "multimarcas", "outfit"},
+		OSM:      []string{`"shop"="clothes"`, `"shop"="boutique"`, `"shop"="fashion"`},
+		Geoapify: []string{"commercial.clothing"},
+		TomTom:   []string{"9361"},
+		CNAE:     []string{"4781", "1412", "1411", "1413", "4642", "4644"},
+	},
+	{
+		Key: "moda_praia", Name: "moda praia",
+		Synonyms: []string{"biquíni", "roupa de banho", "moda fitness", "moda praia e fitness"},
+		OSM:      []string{`"shop"="clothes"`, `"shop"="swimwear"`},
+		Geoapify: []string{"commercial.clothing"},
+		TomTom:   []string{"9361"},
+		CNAE:     []string{"4781", "1412"},
+	},
+	{
+		Key: "calcados", Name: "loja de calçados",
+		Synonyms: []string{"calçados", "sapatos", "sapataria", "tênis"},
+		OSM:      []string{`"shop"="shoes"`},
+		Geoapify: []string{"commercial.clothing.shoes"},
+		TomTom:   []string{"9361"},
+		CNAE:     []string{"4782"},
+	},
+	{
+		Key: "joalheria", Name: "joalheria",
+		Synonyms: []string{"joias", "semijoias", "relojoaria", "bijuteria"},
+		OSM:      []string{`"shop"="jewelry"`},
+		Geoapify: []string{"commercial.jewelry"},
+		TomTom:   []string{"9361"},
+		CNAE:     []string{"4783"},
+	},
+
+	// ── Alimentação ─────────────────────────────────────────────────────────
+	{
+		Key: "restaurante", Name: "restaurante",
+		Synonyms:     []string{"lanchonete", "comida", "pizzaria", "hamburgueria", "churrascaria", "marmitaria", "sushi", "self service"},
+		NameKeywords: []string{"restaurante", "churrascaria", "lanchonete", "pizzaria", "hamburgueria", "lancheria"},
+		OSM:          []string{`"amenity"="restaurant"`, `"amenity"="fast_food"`, `"amenity"="cafe"`},
+		Geoapify:     []string{"catering.restaurant"},
+		TomTom:       []string{"7315"},
+		CNAE:         []string{"5611", "5612"},
+	},
+	{
+		Key: "bar", Name: "bar",
+		Synonyms:     []string{"boteco", "choperia", "pub", "cervejaria"},
+		NameKeywords: []string{"bar", "boteco", "choperia"},
+		OSM:          []string{`"amenity"="bar"`, `"amenity"="pub"`},
+		Geoapify:     []string{"catering.bar", "catering.pub"},
+		TomTom:       []string{"9376"},
+		CNAE:         []string{"5611", "5612"},
+	},
+	{
+		Key: "cafeteria", Name: "cafeteria",
+		Synonyms:     []string{"café", "coffee shop"},
+		NameKeywords: []string{"cafeteria"},
+		OSM:          []string{`"amenity"="cafe"`},
+		Geoapify:     []string{"catering.cafe"},
+		TomTom:       []string{"9376"},
+		CNAE:         []string{"5612"},
+	},
+	{
+		Key: "padaria", Name: "padaria",
+		Synonyms:     []string{"confeitaria", "panificadora", "doceria", "panificação"},
+		NameKeywords: []string{"padaria", "confeitaria", "panificadora"},
+		OSM:          []string{`"shop"="bakery"`, `"shop"="pastry"`},
+		Geoapify:     []string{"catering.fast_food.bakery"},
+		CNAE:         []string{"1091", "4721"},
+	},
+	{
+		Key: "sorveteria", Name: "sorveteria",
+		Synonyms:     []string{"gelateria", "açaí", "sorvetes"},
+		NameKeywords: []string{"sorveteria"},
+		OSM:          []string{`"amenity"="ice_cream"`},
+		Geoapify:     []string{"catering.ice_cream"},
+		CNAE:         []string{"5611", "1053"},
+	},
+	{
+		Key: "supermercado", Name: "supermercado",
+		Synonyms:     []string{"mercado", "mercadinho", "hipermercado", "atacarejo"},
+		NameKeywords: []string{"supermercado", "hipermercado"},
+		OSM:          []string{`"shop"="supermarket"`, `"shop"="convenience"`},
+		Geoapify:     []string{"commercial.supermarket"},
+		TomTom:       []string{"7332"},
+		CNAE:         []string{"4711", "4712"},
+	},
+	{
+		// "empório" fica fora de NameKeywords: ambíguo (Empório do Jeans x Empório do Café)
+		Key: "mercearia", Name: "mercearia",
+		Synonyms:     []string{"empório", "delicatessen", "rotisseria"},
+		NameKeywords: []string{"mercearia", "rotisseria"},
+		OSM:          []string{`"shop"="convenience"`, `"shop"="deli"`},
+		CNAE:         []string{"4712", "4721"},
+	},
+	{
+		Key: "acougue", Name: "açougue",
+		Synonyms:     []string{"casa de carnes", "boutique de carnes"},
+		NameKeywords: []string{"açougue", "casa de carnes"},
+		OSM:          []string{`"shop"="butcher"`},
+		CNAE:         []string{"4722"},
+	},
+	{
+		Key: "peixaria", Name: "peixaria",
+		Synonyms:     []string{"pescados", "frutos do mar"},
+		NameKeywords: []string{"peixaria"},
+		OSM:          []string{`"shop"="seafood"`},
+		CNAE:         []string{"4723"},
+	},
+	{
+		Key: "hortifruti", Name: "hortifruti",
+		Synonyms:     []string{"quitanda", "sacolão", "frutaria", "verdurão"},
+		NameKeywords: []string{"hortifruti", "sacolão"},
+		OSM:          []string{`"shop"="greengrocer"`},
+		CNAE:         []string{"4724"},
+	},
+	{
+		Key: "frigorifico", Name: "frigorífico",
+		Synonyms:     []string{"abatedouro"},
+		NameKeywords: []string{"frigorífico", "abatedouro"},
+		CNAE:         []string{"1012", "1013"},
+	},
+	{
+		Key: "buffet", Name: "buffet",
+		Synonyms: []string{"espaço de eventos", "salão de festas", "casa de festas"},
+		OSM:      []string{`"amenity"="events_venue"`},
+		CNAE:     []string{"5620", "8230"},
+	},
+
+	// ── Saúde ───────────────────────────────────────────────────────────────
+	{
+		Key: "farmacia", Name: "farmácia",
+		Synonyms:     []string{"drogaria", "farmácia de manipulação"},
+		NameKeywords: []string{"farmácia", "drogaria"},
+		OSM:          []string{`"amenity"="pharmacy"`},
+		Geoapify:     []string{"healthcare.pharmacy"},
+		TomTom:       []string{"7326"},
+		CNAE:         []string{"4771"},
+	},
+	{
+		Key: "clinica", Name: "clínica",
+		Synonyms:     []string{"clínica médica", "consultório", "médico", "policlínica"},
+		NameKeywords: []string{"clínica"},
+		OSM:          []string{`"amenity"="clinic"`, `"amenity"="doctors"`},
+		Geoapify:     []string{"healthcare.clinic_or_praxis"},
+		TomTom:       []string{"9663"},
+		CNAE:         []string{"8630", "8621", "8622"},
+	},
+	{
+		Key: "odontologia", Name: "dentista",
+		Synonyms:     []string{"odontologia", "clínica odontológica", "consultório odontológico", "ortodontia"},
+		NameKeywords: []string{"dentista", "odontologia", "odonto"},
+		OSM:          []string{`"amenity"="dentist"`},
+		Geoapify:     []string{"healthcare.dentist"},
+		CNAE:         []string{"8630"},
+	},
+	{
+		Key: "hospital", Name: "hospital",
+		Synonyms:     []string{"pronto socorro", "pronto atendimento"},
+		NameKeywords: []string{"hospital"},
+		OSM:          []string{`"amenity"="hospital"`},
+		Geoapify:     []string{"healthcare.hospital"},
+		TomTom:       []string{"7321"},
+		CNAE:         []string{"8610"},
+	},
+	{
+		Key: "laboratorio", Name: "laboratório",
+		Synonyms:     []string{"laboratório clínico", "análises clínicas"},
+		NameKeywords: []string{"laboratório"},
+		OSM:          []string{`"healthcare"="laboratory"`},
+		CNAE:         []string{"8640"},
+	},
+	{
+		Key: "psicologia", Name: "psicólogo",
+		Synonyms: []string{"psicologia", "terapia", "psicoterapia"},
+		OSM:      []string{`"healthcare"="psychotherapist"`},
+		CNAE:     []string{"8630", "8650"},
+	},
+	{
+		Key: "fisioterapia", Name: "fisioterapia",
+		Synonyms: []string{"fisioterapeuta", "pilates", "nutricionista", "nutrição", "fonoaudiologia"},
+		OSM:      []string{`"healthcare"="physiotherapist"`},
+		CNAE:     []string{"8650"},
+	},
+	{
+		Key: "optica", Name: "óptica",
+		Synonyms:     []string{"ótica", "óculos"},
+		NameKeywords: []string{"óptica", "ótica"},
+		OSM:          []string{`"shop"="optician"`},
+		Geoapify:     []string{"commercial.health_and_beauty.optician"},
+		CNAE:         []string{"4774"},
+	},
+	{
+		Key: "pet", Name: "pet shop",
+		Synonyms:     []string{"petshop", "pet", "veterinária", "veterinário", "clínica veterinária", "banho e tosa", "animais"},
+		NameKeywords: []string{"pet shop", "petshop", "veterinária", "veterinário"},
+		OSM:          []string{`"shop"="pet"`, `"amenity"="veterinary"`},
+		Geoapify:     []string{"pet.shop", "pet.veterinary"},
+		CNAE:         []string{"4789", "7500"},
+	},
+
+	// ── Beleza / Bem-estar ──────────────────────────────────────────────────
+	{
+		Key: "beleza", Name: "salão de beleza",
+		Synonyms:     []string{"cabeleireiro", "cabeleireira", "barbearia", "salão", "estética", "manicure", "esmalteria", "depilação"},
+		NameKeywords: []string{"salão", "barbearia", "estética", "cabeleireiro", "cabeleireira"},
+		OSM:          []string{`"shop"="hairdresser"`, `"shop"="barber"`, `"shop"="beauty"`},
+		Geoapify:     []string{"service.beauty.hairdresser"},
+		CNAE:         []string{"9602"},
+	},
+	{
+		Key: "spa", Name: "spa",
+		Synonyms: []string{"day spa", "massagem", "massoterapia"},
+		OSM:      []string{`"shop"="massage"`, `"leisure"="spa"`},
+		Geoapify: []string{"service.beauty.spa"},
+		CNAE:     []string{"9609"},
+	},
+	{
+		Key: "academia", Name: "academia",
+		Synonyms: []string{"fitness", "musculação", "crossfit", "academia de dança", "academia de natação", "escola de natação"},
+		OSM:      []string{`"leisure"="fitness_centre"`, `"leisure"="sports_centre"`},
+		Geoapify: []string{"leisure.fitness"},
+		TomTom:   []string{"7320"},
+		CNAE:     []string{"9313"},
+	},
+	{
+		Key: "quadra", Name: "quadra esportiva",
+		Synonyms: []string{"quadra de futebol", "futebol society", "quadra de areia", "beach tennis"},
+		OSM:      []string{`"leisure"="pitch"`},
+		CNAE:     []string{"9313", "9311"},
+	},
+
+	// ── Automotivo ──────────────────────────────────────────────────────────
+	{
+		Key: "oficina", Name: "oficina mecânica",
+		Synonyms:     []string{"oficina", "mecânica", "funilaria", "auto center", "lavagem", "lava rápido", "lava jato", "auto elétrica"},
+		NameKeywords: []string{"oficina", "mecânica", "funilaria", "auto center"},
+		OSM:          []string{`"shop"="car_repair"`},
+		Geoapify:     []string{"service.vehicle.repair"},
+		TomTom:       []string{"7310"},
+		CNAE:         []string{"4520"},
+	},
+	{
+		Key: "borracharia", Name: "borracharia",
+		Synonyms:     []string{"pneus", "loja de pneus"},
+		NameKeywords: []string{"borracharia"},
+		OSM:          []string{`"shop"="tyres"`},
+		CNAE:         []string{"4530"},
+	},
+	{
+		Key: "autopecas", Name: "autopeças",
+		Synonyms:     []string{"peças automotivas", "peças para carros", "acessórios automotivos", "motopeças"},
+		NameKeywords: []string{"autopeças", "motopeças"},
+		OSM:          []string{`"shop"="car_parts"`},
+		Geoapify:     []string{"commercial.vehicle"},
+		TomTom:       []string{"9361"},
+		CNAE:         []string{"4530", "4541", "4542"},
+	},
+	{
+		Key: "concessionaria", Name: "concessionária",
+		Synonyms:     []string{"revenda de veículos", "veículos", "automóveis", "caminhões", "seminovos"},
+		NameKeywords: []string{"concessionária", "veículos", "automóveis", "caminhões", "caminhão"},
+		OSM:          []string{`"shop"="car"`},
+		CNAE:         []string{"4511", "4512"},
+	},
+	{
+		Key: "estacionamento", Name: "estacionamento",
+		OSM:  []string{`"amenity"="parking"`},
+		CNAE: []string{"5223"},
+	},
+	{
+		Key: "locadora", Name: "locadora de veículos",
+		Synonyms:     []string{"locadora", "aluguel de carros", "rent a car"},
+		NameKeywords: []string{"locadora de veículos", "locadora de carros"},
+		OSM:          []string{`"amenity"="car_rental"`},
+		CNAE:         []string{"7711"},
+	},
+	{
+		Key: "posto", Name: "posto de combustível",
+		Synonyms:     []string{"posto de gasolina", "posto"},
+		NameKeywords: []string{"auto posto", "posto de combustível"},
+		OSM:          []string{`"amenity"="fuel"`},
+		TomTom:       []string{"7311"},
+		CNAE:         []string{"4731"},
+	},
+
+	// ── Transporte / Logística ──────────────────────────────────────────────
+	{
+		Key: "transportadora", Name: "transportadora",
+		Synonyms:     []string{"transporte de cargas", "frete", "mudanças"},
+		NameKeywords: []string{"transportadora", "transportes"},
+		CNAE:         []string{"4930", "4921", "4922"},
+	},
+	{
+		Key: "entregas", Name: "motoboy",
+		Synonyms:     []string{"courier", "entregas", "tele entrega"},
+		NameKeywords: []string{"motoboy"},
+		CNAE:         []string{"5310", "5320"},
+	},
+	{
+		Key: "logistica", Name: "logística",
+		Synonyms:     []string{"armazém", "depósito", "armazenagem"},
+		NameKeywords: []string{"armazém", "depósito"},
+		CNAE:         []string{"5211", "5229"},
+	},
+
+	// ── Construção / Casa ───────────────────────────────────────────────────
+	{
+		Key: "construcao", Name: "construtora",
+		Synonyms:     []string{"construção", "construção civil", "empreiteira", "reformas"},
+		NameKeywords: []string{"construção", "construtora"},
+		CNAE:         []string{"4120", "4399"},
+	},
+	{
+		Key: "material_construcao", Name: "materiais de construção",
+		Synonyms:     []string{"material de construção", "ferragens", "ferragista", "home center", "depósito de materiais"},
+		NameKeywords: []string{"materiais de construção", "material de construção"},
+		OSM:          []string{`"shop"="doityourself"`, `"shop"="hardware"`},
+		Geoapify:     []string{"commercial.houseware_and_hardware"},
+		TomTom:       []string{"9361"},
+		CNAE:         []string{"4744"},
+	},
+	{
+		Key: "madeireira", Name: "madeireira",
+		Synonyms:     []string{"serraria"},
+		NameKeywords: []string{"madeireira", "serraria"},
+		CNAE:         []string{"1610"},
+	},
+	{
+		Key: "marmoraria", Name: "marmoraria",
+		Synonyms:     []string{"mármores e granitos"},
+		NameKeywords: []string{"marmoraria"},
+		CNAE:         []string{"2391"},
+	},
+	{
+		Key: "eletrica", Name: "elétrica",
+		Synonyms: []string{"eletricista", "material elétrico", "instalações elétricas"},
+		OSM:      []string{`"shop"="electrical"`},
+		CNAE:     []string{"4321", "4742"},
+	},
+	{
+		Key: "encanamento", Name: "encanamento",
+		Synonyms: []string{"encanador", "hidráulica"},
+		CNAE:     []string{"4322"},
+	},
+	{
+		Key: "pintura", Name: "pintura",
+		Synonyms: []string{"pintor", "pinturas"},
+		CNAE:     []string{"4330"},
+	},
+	{
+		Key: "marcenaria", Name: "marcenaria",
+		Synonyms: []string{"marceneiro", "móveis planejados"},
+		CNAE:     []string{"1610", "1622", "3101"},
+	},
+	{
+		Key: "moveis", Name: "loja de móveis",
+		Synonyms: []string{"móveis", "colchões", "estofados"},
+		OSM:      []string{`"shop"="furniture"`, `"shop"="bed"`},
+		Geoapify: []string{"commercial.furniture_and_interior"},
+		TomTom:   []string{"9361"},
+		CNAE:     []string{"4754", "3101", "3102", "3103"},
+	},
+	{
+		Key: "decoracao", Name: "decoração",
+		Synonyms: []string{"artigos de decoração", "design de interiores"},
+		OSM:      []string{`"shop"="interior_decoration"`},
+		CNAE:     []string{"4759", "7490"},
+	},
+	{
+		Key: "arquitetura", Name: "arquitetura",
+		Synonyms: []string{"arquiteto", "escritório de arquitetura"},
+		OSM:      []string{`"office"="architect"`},
+		CNAE:     []string{"7111"},
+	},
+	{
+		Key: "engenharia", Name: "engenharia",
+		Synonyms: []string{"engenheiro", "escritório de engenharia"},
+		CNAE:     []string{"7112"},
+	},
+	{
+		Key: "imobiliaria", Name: "imobiliária",
+		Synonyms: []string{"corretor de imóveis", "imóveis"},
+		OSM:      []string{`"office"="estate_agent"`},
+		CNAE:     []string{"6811", "6821"},
+	},
+	{
+		Key: "condominio", Name: "condomínio",
+		Synonyms: []string{"administradora de condomínios"},
+		CNAE:     []string{"8110"},
+	},
+
+	// ── Educação ────────────────────────────────────────────────────────────
+	{
+		Key: "escola", Name: "escola",
+		Synonyms:     []string{"colégio", "creche", "educação infantil", "berçário"},
+		NameKeywords: []string{"escola", "colégio"},
+		OSM:          []string{`"amenity"="school"`, `"amenity"="kindergarten"`},
+		Geoapify:     []string{"education.school"},
+		CNAE:         []string{"8511", "8512", "8513"},
+	},
+	{
+		Key: "faculdade", Name: "faculdade",
+		Synonyms:     []string{"universidade", "ensino superior", "centro universitário"},
+		NameKeywords: []string{"faculdade", "universidade"},
+		OSM:          []string{`"amenity"="university"`, `"amenity"="college"`},
+		Geoapify:     []string{"education.university", "education.college"},
+		CNAE:         []string{"8530"},
+	},
+	{
+		Key: "curso", Name: "curso",
+		Synonyms: []string{"escola de idiomas", "escola de inglês", "escola de música", "cursos livres", "autoescola", "curso preparatório"},
+		OSM:      []string{`"amenity"="language_school"`, `"amenity"="music_school"`, `"amenity"="driving_school"`},
+		CNAE:     []string{"8599"},
+	},
+
+	// ── Tecnologia / Serviços ───────────────────────────────────────────────
+	{
+		Key: "software", Name: "software",
+		Synonyms: []string{"ti", "tecnologia da informação", "desenvolvimento de software", "desenvolvimento de sistemas"},
+		CNAE:     []string{"6201", "6202", "6209"},
+	},
+	{
+		Key: "informatica", Name: "informática",
+		Synonyms: []string{"computadores", "assistência técnica de computadores", "manutenção de computadores"},
+		OSM:      []string{`"shop"="computer"`},
+		CNAE:     []string{"4751", "9521"},
+	},
+	{
+		Key: "internet", Name: "provedor de internet",
+		Synonyms: []string{"internet", "telecomunicações", "fibra óptica"},
+		CNAE:     []string{"6110", "6120"},
+	},
+	{
+		Key: "consultoria", Name: "consultoria",
+		Synonyms: []string{"consultoria empresarial", "assessoria empresarial"},
+		CNAE:     []string{"7020", "6920"},
+	},
+	{
+		Key: "contabilidade", Name: "contabilidade",
+		Synonyms: []string{"contador", "escritório de contabilidade", "escritório contábil"},
+		OSM:      []string{`"office"="accountant"`},
+		CNAE:     []string{"6920"},
+	},
+	{
+		Key: "advocacia", Name: "advocacia",
+		Synonyms: []string{"advogado", "escritório de advocacia"},
+		OSM:      []string{`"office"="lawyer"`},
+		CNAE:     []string{"6911"},
+	},
+	{
+		Key: "seguranca", Name: "segurança",
+		Synonyms: []string{"vigilância", "segurança privada", "monitoramento"},
+		CNAE:     []string{"8011", "8012"},
+	},
+	{
+		Key: "banco", Name: "banco",
+		Synonyms: []string{"financeira", "cooperativa de crédito", "correspondente bancário"},
+		OSM:      []string{`"amenity"="bank"`},
+		Geoapify: []string{"commercial.financial.bank"},
+		TomTom:   []string{"7328"},
+		CNAE:     []string{"642", "643"},
+	},
+	{
+		Key: "grafica", Name: "gráfica",
+		Synonyms:     []string{"tipografia", "impressão", "gráfica rápida", "copiadora"},
+		NameKeywords: []string{"gráfica", "tipografia"},
+		OSM:          []string{`"shop"="copyshop"`, `"craft"="printer"`},
+		CNAE:         []string{"1811", "1812", "1813"},
+	},
+	{
+		Key: "fotografia", Name: "fotografia",
+		Synonyms: []string{"fotógrafo", "estúdio fotográfico"},
+		OSM:      []string{`"shop"="photo"`, `"craft"="photographer"`},
+		CNAE:     []string{"7420"},
+	},
+
+	// ── Turismo / Lazer ─────────────────────────────────────────────────────
+	{
+		Key: "hospedagem", Name: "hotel",
+		Synonyms:     []string{"pousada", "motel", "hostel", "hospedagem"},
+		NameKeywords: []string{"hotel", "pousada", "motel", "hostel"},
+		OSM:          []string{`"tourism"="hotel"`, `"tourism"="guest_house"`, `"tourism"="motel"`, `"tourism"="hostel"`},
+		Geoapify:     []string{"accommodation"},
+		TomTom:       []string{"7314"},
+		CNAE:         []string{"5510"},
+	},
+	{
+		Key: "agencia_viagens", Name: "agência de viagens",
+		Synonyms: []string{"agência de turismo", "turismo"},
+		OSM:      []string{`"shop"="travel_agency"`},
+		CNAE:     []string{"7911", "7912"},
+	},
+	{
+		Key: "cinema", Name: "cinema",
+		OSM:  []string{`"amenity"="cinema"`},
+		CNAE: []string{"5914"},
+	},
+	{
+		Key: "teatro", Name: "teatro",
+		OSM:  []string{`"amenity"="theatre"`},
+		CNAE: []string{"9001"},
+	},
+
+	// ── Outros varejos ──────────────────────────────────────────────────────
+	{
+		Key: "papelaria", Name: "papelaria",
+		Synonyms: []string{"material escolar", "artigos de escritório"},
+		OSM:      []string{`"shop"="stationery"`},
+		Geoapify: []string{"commercial.stationery"},
+		CNAE:     []string{"4761"},
+	},
+	{
+		Key: "livraria", Name: "livraria",
+		Synonyms: []string{"livros", "sebo"},
+		OSM:      []string{`"shop"="books"`},
+		Geoapify: []string{"commercial.books"},
+		CNAE:     []string{"4761"},
+	},
+	{
+		Key: "floricultura", Name: "floricultura",
+		Synonyms: []string{"flores", "arranjos florais"},
+		OSM:      []string{`"shop"="florist"`},
+		Geoapify: []string{"commercial.florist"},
+		CNAE:     []string{"4789"},
+	},
+	{
+		Key: "brinquedos", Name: "loja de brinquedos",
+		Synonyms: []string{"brinquedos"},
+		OSM:      []string{`"shop"="toys"`},
+		Geoapify: []string{"commercial.toy_and_game"},
+		CNAE:     []string{"4763"},
+	},
+	{
+		Key: "eletronicos", Name: "eletrônicos",
+		Synonyms: []string{"eletrodomésticos", "celular", "loja de celulares", "assistência técnica de celular", "smartphones"},
+		OSM:      []string{`"shop"="electronics"`, `"shop"="mobile_phone"`, `"shop"="appliance"`},
+		TomTom:   []string{"9361"},
+		CNAE:     []string{"4752", "4753"},
+	},
+	{
+		Key: "instrumentos_musicais", Name: "instrumentos musicais",
+		Synonyms: []string{"loja de instrumentos", "loja de música"},
+		OSM:      []string{`"shop"="musical_instrument"`},
+		CNAE:     []string{"4756"},
+	},
+	{
+		Key: "artigos_religiosos", Name: "artigos religiosos",
+		OSM:  []string{`"shop"="religion"`},
+		CNAE: []string{"4789"},
+	},
+
+	// ── Agro / Campo ────────────────────────────────────────────────────────
+	{
+		Key: "agropecuaria", Name: "agropecuária",
+		Synonyms:     []string{"casa agropecuária", "produtos agropecuários", "insumos agrícolas"},
+		NameKeywords: []string{"agropecuária"},
+		OSM:          []string{`"shop"="agrarian"`},
+		CNAE:         []string{"4612", "4623", "4683"},
+	},
+	{
+		Key: "fazenda", Name: "fazenda",
+		Synonyms:     []string{"sítio", "produtor rural"},
+		NameKeywords: []string{"fazenda"},
+		CNAE:         []string{"0111"},
+	},
+	{
+		Key: "granja", Name: "granja",
+		Synonyms:     []string{"avicultura", "aviário"},
+		NameKeywords: []string{"granja"},
+		CNAE:         []string{"0155"},
+	},
+}
@@ -0,0 +1,284 @@
+// Package taxonomy é a tabela central de categorias de negócio: para cada
+// categoria, os termos que o usuário digita (sinônimos), as palavras que no
+// nome de um estabelecimento indicam a categoria, as tags do OpenStreetMap,
+// as categorias da Geoapify e da TomTom e os prefixos CNAE compatíveis.
+//
+// Todas as fontes (Overpass, Geoapify, TomTom, buscadores) e filtros (nome,
+// CNAE) consultam a mesma tabela, então "autopeças", "auto peças" e
+// "Autopecas" levam às mesmas tags, categorias e CNAEs.
+//
+// A comparação ignora acentos, caixa, pontuação, plural simples ("roupas" →
+// "roupa", "salões" → "salao") e espaços entre palavras ("auto peças" =
+// "autopeças").
+package taxonomy
+
+import (
+	"sort"
+	"strings"
+)
+
+// Category é uma categoria de negócio e seus mapeamentos por fonte.
+type Category struct {
+	Key          string   // identificador estável (ex: "vestuario")
+	Name         string   // termo canônico em português (ex: "loja de roupas")
+	Synonyms     []string // outros termos de busca equivalentes a Name
+	NameKeywords []string // palavras que, no nome do negócio, indicam a categoria
+	OSM          []string // filtros Overpass (ex: `"shop"="clothes"`)
+	Geoapify     []string // categorias da Geoapify Places (ex: "commercial.clothing")
+	TomTom       []string // IDs de categoria de POI da TomTom (parâmetro categorySet)
+	CNAE         []string // prefixos CNAE compatíveis
+}
+
+// maxWindow é o maior número de palavras consecutivas comparadas com um termo.
+const maxWindow = 4
+
+// term é um sinônimo ou palavra-chave pré-processado para comparação.
+type term struct {
+	cat     *Category
+	text    string // como cadastrado na tabela
+	compact string // palavras normalizadas, sem espaços
+}
+
+var (
+	synonymTerms []term // ordenados do mais longo para o mais curto
+	nameTerms    []term
+	byKey        = map[string]*Category{}
+)
+
+func init() {
+	for i := range categories {
+		c := &categories[i]
+		byKey[c.Key] = c
+		for _, s := range append([]string{c.Name}, c.Synonyms...) {
+			synonymTerms = append(synonymTerms, newTerm(c, s))
+		}
+		for _, s := range c.NameKeywords {
+			nameTerms = append(nameTerms, newTerm(c, s))
+		}
+	}
+	byLength := func(ts []term) func(i, j int) bool {
+		return func(i, j int) bool { return len(ts[i].compact) > len(ts[j].compact) }
+	}
+	sort.SliceStable(synonymTerms, byLength(synonymTerms))
+	sort.SliceStable(nameTerms, byLength(nameTerms))
+}
+
+func newTerm(c *Category, s string) term {
+	return term{cat: c, text: s, compact: strings.Join(Words(s), "")}
+}
+
+// All retorna todas as categorias.
+func All() []Category { return categories }
+
+// ByKey retorna a categoria pelo identificador.
+func ByKey(key string) (*Category, bool) {
+	c, ok := byKey[key]
+	return c, ok
+}
+
+// Match retorna as categorias citadas na busca, da mais específica (termo mais
+// longo) para a menos específica. Termos contidos num termo mais longo já
+// encontrado são ignorados: "moda praia" é só moda praia, não vestuário.
+func Match(query string) []*Category {
+	return match(query, synonymTerms)
+}
+
+// MatchName retorna as categorias indicadas pelas palavras-chave presentes no
+// nome de um estabelecimento ("Drogaria São João" → farmácia).
+func MatchName(name string) []*Category {
+	return match(name, nameTerms)
+}
+
+// KeywordMatch é uma palavra-chave encontrada no nome de um estabelecimento.
+type KeywordMatch struct {
+	Category *Category
+	Keyword  string // como cadastrada em NameKeywords (ex: "drogaria")
+}
+
+// MatchNameKeywords é MatchName com a palavra-chave que indicou cada
+// categoria (a primeira encontrada, da mais longa para a mais curta).
+func MatchNameKeywords(name string) []KeywordMatch {
+	hits := matchTerms(name, nameTerms)
+	out := make([]KeywordMatch, len(hits))
+	for i, t := range hits {
+		out[i] = KeywordMatch{Category: t.cat, Keyword: t.text}
+	}
+	return out
+}
+
+// Lookup retorna a categoria mais específica da busca.
+func Lookup(query string) (*Category, bool) {
+	if cats := Match(query); len(cats) > 0 {
+		return cats[0], true
+	}
+	return nil, false
+}
+
+func match(text string, terms []term) []*Category {
+	hits := matchTerms(text, terms)
+	if len(hits) == 0 {
+		return nil
+	}
+	out := make([]*Category, len(hits))
+	for i, t := range hits {
+		out[i] = t.cat
+	}
+	return out
+}
+
+// matchTerms retorna o primeiro termo encontrado de cada categoria.
+func matchTerms(text string, terms []term) []term {
+	words := Words(text)
+	if len(words) == 0 {
+		return nil
+	}
+	// janelas de 1..maxWindow palavras consecutivas, sem espaços
+	type span struct{ start, end int }
+	windows := make(map[string][]span)
+	for i := range words {
+		joined := ""
+		for j := i; j < len(words) && j < i+maxWindow; j++ {
+			joined += words[j]
+			windows[joined] = append(windows[joined], span{i, j + 1})
+		}
+	}
+
+	used := make([]bool, len(words))
+	seen := make(map[*Category]bool)
+	var out []term
+	for _, t := range terms {
+		for _, sp := range windows[t.compact] {
+			free := true
+			for k := sp.start; k < sp.end; k++ {
+				if used[k] {
+					free = false
+					break
+				}
+			}
+			if !free {
+				continue
+			}
+			for k := sp.start; k < sp.end; k++ {
+				used[k] = true
+			}
+			if !seen[t.cat] {
+				seen[t.cat] = true
+				out = append(out, t)
+			}
+		}
+	}
+	return out
+}
+
+// ─── Mapeamentos por fonte ───────────────────────────────────────────────────
+
+// OSMTags retorna os filtros Overpass das categorias da busca (nil se
+// nenhuma categoria for reconhecida).
+func OSMTags(query string) []string {
+	return collect(Match(query), func(c *Category) []string { return c.OSM })
+}
+
+// GeoapifyCategories retorna as categorias Geoapify das categorias da busca.
+func GeoapifyCategories(query string) []string {
+	return collect(Match(query), func(c *Category) []string { return c.Geoapify })
+}
+
+// TomTomCategories retorna os IDs de categoria TomTom das categorias da busca.
+func TomTomCategories(query string) []string {
+	return collect(Match(query), func(c *Category) []string { return c.TomTom })
+}
+
+// CNAEPrefixes retorna os prefixos CNAE compatíveis com a busca (nil se a
+// busca não for reconhecida — quem filtra deve então aceitar qualquer CNAE).
+func CNAEPrefixes(query string) []string {
+	return collect(Match(query), func(c *Category) []string { return c.CNAE })
+}
+
+// NameCNAEPrefixes retorna os prefixos CNAE indicados pelo nome do negócio.
+func NameCNAEPrefixes(name string) []string {
+	return collect(MatchName(name), func(c *Category) []string { return c.CNAE })
+}
+
+// Expand retorna a busca seguida de até max sinônimos da categoria mais
+// específica, sem repetir termos equivalentes à busca.
+func Expand(query string, max int) []string {
+	out := []string{strings.TrimSpace(query)}
+	c, ok := Lookup(query)
+	if !ok {
+		return out
+	}
+	seen := map[string]bool{strings.Join(Words(query), ""): true}
+	for _, s := range append([]string{c.Name}, c.Synonyms...) {
+		if len(out) > max {
+			break
+		}
+		k := strings.Join(Words(s), "")
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		out = append(out, s)
+	}
+	return out
+}
+
+func collect(cats []*Category, field func(*Category) []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, c := range cats {
+		for _, v := range field(c) {
+			if !seen[v] {
+				seen[v] = true
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
+
+// ─── Normalização ────────────────────────────────────────────────────────────
+
+var foldAccents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// Words normaliza o texto em palavras comparáveis: minúsculas, sem acentos,
+// sem pontuação e no singular.
+func Words(s string) []string {
+	s = foldAccents.Replace(strings.ToLower(s))
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	for i, w := range fields {
+		fields[i] = singular(w)
+	}
+	return fields
+}
+
+// singular remove o plural regular do português. Não precisa ser exato:
+// termos e buscas passam pela mesma regra.
+func singular(w string) string {
+	if len(w) <= 3 {
+		return w
+	}
+	switch {
+	case strings.HasSuffix(w, "oes"), strings.HasSuffix(w, "aes"):
+		return w[:len(w)-3] + "ao"
+	case strings.HasSuffix(w, "eis"):
+		return w[:len(w)-3] + "el"
+	case strings.HasSuffix(w, "ais"), strings.HasSuffix(w, "ois"):
+		return w[:len(w)-2] + "l"
+	case strings.HasSuffix(w, "res"), strings.HasSuffix(w, "zes"):
+		return w[:len(w)-2]
+	case strings.HasSuffix(w, "ns"):
+		return w[:len(w)-2] + "m"
+	case strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss"):
+		return w[:len(w)-1]
+	}
+	return w
+}
@@ -0,0 +1,62 @@
+// Command seed-cnae loads the CNAE 2.3 hierarchy into the lead_api.cnaes
+// collection.
+//
+// Usage:
+//
+//	go run ./cmd/seed-cnae                  # embedded table
+//	go run ./cmd/seed-cnae -file cnae23.csv # table in the same layout
+//
+// MONGO_URI selects the server (default mongodb://localhost:27017) and
+// CNAE_FILE is used when -file is not given. A table without all
+// cnae.Subclasses subclasses is refused, so queries are never grounded on
+// an incomplete collection. Seeding is idempotent: each node is upserted by
+// code.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/lucasfdcampos/lead-api/internal/cnae"
+	"github.com/lucasfdcampos/lead-api/internal/store"
+)
+
+func main() {
+	log.SetFlags(0)
+	file := flag.String("file", os.Getenv("CNAE_FILE"), "CNAE table in the cnae23.csv layout (default: embedded table)")
+	flag.Parse()
+
+	ds := cnae.Default()
+	if *file != "" {
+		var err error
+		if ds, err = cnae.LoadFile(*file); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if ds.Partial() {
+		log.Fatalf("cnae: the table is incomplete (%d of %d subclasses); regenerate it with go generate ./internal/cnae", ds.SubclassCount(), cnae.Subclasses)
+	}
+
+	mongoURI := os.Getenv("MONGO_URI")
+	if mongoURI == "" {
+		mongoURI = "mongodb://localhost:27017"
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	mc, err := store.New(ctx, mongoURI)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer mc.Disconnect(context.Background())
+
+	rows := ds.Records()
+	changed, err := mc.SeedCNAEs(ctx, rows)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("cnaes: %d nodes seeded (%d inserted or updated)", len(rows), changed)
+}
@@ -6,6 +6,7 @@ require (
 	github.com/lucasfdcampos/find-cnpj v0.0.0
 	github.com/lucasfdcampos/find-instagram v0.0.0
 	github.com/lucasfdcampos/find-leads v0.0.0
+	github.com/lucasfdcampos/serp v0.0.0
 	github.com/redis/go-redis/v9 v9.18.0
 	go.mongodb.org/mongo-driver v1.17.3
 )
@@ -35,10 +36,12 @@ require (
 	golang.org/x/sync v0.18.0 // indirect
 	golang.org/x/sys v0.38.0 // indirect
 	golang.org/x/text v0.31.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 replace (
 	github.com/lucasfdcampos/find-cnpj => ../find-cnpj
 	github.com/lucasfdcampos/find-instagram => ../find-instagram
 	github.com/lucasfdcampos/find-leads => ../find-leads
+	github.com/lucasfdcampos/serp => ../serp
 )
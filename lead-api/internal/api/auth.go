@@ -0,0 +1,100 @@
+package api
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TenantKeys maps API keys to the tenants they authenticate, configured
+// through TENANT_API_KEYS. Rule presets and suppression lists are scoped by
+// the tenant of the request's key; X-Tenant-ID alone never selects a tenant.
+type TenantKeys struct {
+	keys []tenantKey
+}
+
+type tenantKey struct {
+	tenant string
+	key    []byte
+}
+
+// ParseTenantKeys parses "tenant:key" pairs separated by commas
+// ("acme:3f9c…,globex:81ab…"). An empty string returns nil (single tenant).
+func ParseTenantKeys(s string) (*TenantKeys, error) {
+	var tk TenantKeys
+	seen := make(map[string]bool)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		tenant, key, ok := strings.Cut(pair, ":")
+		tenant, key = strings.TrimSpace(tenant), strings.TrimSpace(key)
+		if !ok || !validName.MatchString(tenant) {
+			return nil, fmt.Errorf("tenant keys: %q is not tenant:key (tenant is 1-64 letters, digits, '.', '_' or '-')", tenant)
+		}
+		if len(key) < 16 {
+			return nil, fmt.Errorf("tenant keys: the key of %q must have at least 16 characters", tenant)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("tenant keys: the key of %q is also used by another tenant", tenant)
+		}
+		seen[key] = true
+		tk.keys = append(tk.keys, tenantKey{tenant: tenant, key: []byte(key)})
+	}
+	if len(tk.keys) == 0 {
+		return nil, nil
+	}
+	return &tk, nil
+}
+
+// Len returns the number of configured keys.
+func (tk *TenantKeys) Len() int {
+	if tk == nil {
+		return 0
+	}
+	return len(tk.keys)
+}
+
+// lookup returns the tenant of key. Every key is compared, in constant time.
+func (tk *TenantKeys) lookup(key string) (string, bool) {
+	tenant, found := "", false
+	for _, k := range tk.keys {
+		if subtle.ConstantTimeCompare(k.key, []byte(key)) == 1 {
+			tenant, found = k.tenant, true
+		}
+	}
+	return tenant, found
+}
+
+// tenantID returns the request's tenant and, when it can't be established,
+// the status to answer with.
+//
+// Without keys configured there is a single tenant, defaultTenant, and an
+// X-Tenant-ID other than it is rejected instead of silently sharing its
+// lists. With keys, the request must carry one as "Authorization: Bearer
+// <key>" and the tenant is the key's; an X-Tenant-ID naming another tenant
+// is a 403.
+func (h *Handler) tenantID(r *http.Request) (string, int, error) {
+	claimed := r.Header.Get(tenantHeader)
+	if h.tenants.Len() == 0 {
+		if claimed != "" && claimed != defaultTenant {
+			return "", http.StatusUnauthorized, fmt.Errorf("%s requires an API key; configure TENANT_API_KEYS", tenantHeader)
+		}
+		return defaultTenant, 0, nil
+	}
+
+	key, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || key == "" {
+		return "", http.StatusUnauthorized, fmt.Errorf("missing API key: send Authorization: Bearer <key>")
+	}
+	tenant, ok := h.tenants.lookup(strings.TrimSpace(key))
+	if !ok {
+		return "", http.StatusUnauthorized, fmt.Errorf("invalid API key")
+	}
+	if claimed != "" && claimed != tenant {
+		return "", http.StatusForbidden, fmt.Errorf("the API key does not belong to tenant %q", claimed)
+	}
+	return tenant, 0, nil
+}
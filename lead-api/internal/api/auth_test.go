@@ -0,0 +1,92 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const (
+	acmeKey   = "acme-0123456789abcdef"
+	globexKey = "globex-0123456789abcdef"
+)
+
+func TestParseTenantKeys(t *testing.T) {
+	tk, err := ParseTenantKeys(" acme:" + acmeKey + " , globex:" + globexKey + ",")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tk.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", tk.Len())
+	}
+	if tenant, ok := tk.lookup(globexKey); !ok || tenant != "globex" {
+		t.Errorf("lookup(globexKey) = %q, %v", tenant, ok)
+	}
+	if _, ok := tk.lookup("acme"); ok {
+		t.Error("lookup accepted a tenant name as key")
+	}
+
+	if tk, err := ParseTenantKeys(" "); err != nil || tk != nil || tk.Len() != 0 {
+		t.Errorf("empty: %v, %v", tk, err)
+	}
+	for _, bad := range []string{
+		"acme",                                   // no key
+		"acme:short",                             // key too short
+		"ac me:" + acmeKey,                       // invalid tenant
+		"acme:" + acmeKey + ",globex:" + acmeKey, // shared key
+	} {
+		if _, err := ParseTenantKeys(bad); err == nil {
+			t.Errorf("ParseTenantKeys(%q) accepted", bad)
+		}
+	}
+}
+
+func TestTenantID(t *testing.T) {
+	keys, err := ParseTenantKeys("acme:" + acmeKey + ",globex:" + globexKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		keys       *TenantKeys
+		auth       string
+		header     string
+		wantTenant string
+		wantStatus int
+	}{
+		{name: "single tenant", wantTenant: defaultTenant},
+		{name: "single tenant, default header", header: defaultTenant, wantTenant: defaultTenant},
+		{name: "single tenant, other header", header: "acme", wantStatus: http.StatusUnauthorized},
+		{name: "keys, no auth", keys: keys, wantStatus: http.StatusUnauthorized},
+		{name: "keys, header only", keys: keys, header: "acme", wantStatus: http.StatusUnauthorized},
+		{name: "keys, wrong key", keys: keys, auth: "Bearer acme-wrong-0123456789", wantStatus: http.StatusUnauthorized},
+		{name: "keys, not bearer", keys: keys, auth: acmeKey, wantStatus: http.StatusUnauthorized},
+		{name: "keys, valid", keys: keys, auth: "Bearer " + acmeKey, wantTenant: "acme"},
+		{name: "keys, matching header", keys: keys, auth: "Bearer " + globexKey, header: "globex", wantTenant: "globex"},
+		{name: "keys, other tenant's header", keys: keys, auth: "Bearer " + acmeKey, header: "globex", wantStatus: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(nil, nil, nil, tt.keys)
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/suppression-lists", nil)
+			if tt.auth != "" {
+				r.Header.Set("Authorization", tt.auth)
+			}
+			if tt.header != "" {
+				r.Header.Set(tenantHeader, tt.header)
+			}
+
+			tenant, status, err := h.tenantID(r)
+			if tt.wantStatus != 0 {
+				if err == nil || status != tt.wantStatus {
+					t.Errorf("tenantID() = %q, %d, %v, want status %d", tenant, status, err, tt.wantStatus)
+				}
+				return
+			}
+			if err != nil || tenant != tt.wantTenant {
+				t.Errorf("tenantID() = %q, %v, want %q", tenant, err, tt.wantTenant)
+			}
+		})
+	}
+}
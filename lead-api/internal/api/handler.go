@@ -1,25 +1,55 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"regexp"
+	"strconv"
 	"time"
 
+	leadsearch "github.com/lucasfdcampos/find-leads/pkg/leads"
+	"github.com/lucasfdcampos/serp/pkg/serp"
+
 	"github.com/lucasfdcampos/lead-api/internal/cache"
 	"github.com/lucasfdcampos/lead-api/internal/domain"
+	"github.com/lucasfdcampos/lead-api/internal/emailcheck"
+	"github.com/lucasfdcampos/lead-api/internal/filter"
+	"github.com/lucasfdcampos/lead-api/internal/location"
 	"github.com/lucasfdcampos/lead-api/internal/pipeline"
+	"github.com/lucasfdcampos/lead-api/internal/rules"
 	"github.com/lucasfdcampos/lead-api/internal/store"
+	"github.com/lucasfdcampos/lead-api/internal/suppression"
+)
+
+// maxRadiusKm bounds radius_km; discovery is per city, so larger radii
+// would not find more leads anyway.
+const maxRadiusKm = 200
+
+// tenantHeader optionally names the tenant of the request, which must be
+// the one of its API key (see tenantID); requests without keys configured
+// belong to defaultTenant.
+const (
+	tenantHeader  = "X-Tenant-ID"
+	defaultTenant = "default"
 )
 
+// validName matches tenant IDs and preset names.
+var validName = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
 // Handler holds the HTTP dependencies.
 type Handler struct {
-	redis *cache.Client
-	mongo *store.Client
+	redis   *cache.Client
+	mongo   *store.Client
+	emails  *emailcheck.Validator // shared so MX verdicts are memoized across requests
+	llm     leadsearch.LLMClient  // query understanding; nil disables it
+	tenants *TenantKeys           // API keys; nil means a single tenant
 }
 
-// NewHandler creates a new Handler.
-func NewHandler(redis *cache.Client, mongo *store.Client) *Handler {
-	return &Handler{redis: redis, mongo: mongo}
+// NewHandler creates a new Handler. llm and tenants may be nil.
+func NewHandler(redis *cache.Client, mongo *store.Client, llm leadsearch.LLMClient, tenants *TenantKeys) *Handler {
+	return &Handler{redis: redis, mongo: mongo, emails: emailcheck.New(nil), llm: llm, tenants: tenants}
 }
 
 // errResponse writes a JSON error body.
@@ -32,16 +62,41 @@ func errResponse(w http.ResponseWriter, status int, msg string) {
 // Health godoc
 //
 //	GET /health
+//
+//	search_engines reports, per search engine already queried by this
+//	process, request counts and whether it is cooling down after a block.
 func (h *Handler) Health(w http.ResponseWriter, _ *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok", "time": time.Now().UTC().Format(time.RFC3339)})
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"status":         "ok",
+		"time":           time.Now().UTC().Format(time.RFC3339),
+		"search_engines": serp.Default().Health(),
+	})
 }
 
 // Search godoc
 //
 //	POST /api/v1/search
 //
-//	Request body: { "query": "...", "location": "...", "enrich_cnpj": true, "enrich_instagram": false }
+//	Request body: { "query": "...", "location": "...", "enrich_cnpj": true, "enrich_instagram": false,
+//	                "radius_km": 5, "center": { "lat": -23.31, "lon": -51.16 },
+//	                "region": "norte do parana", "expand_radius_km": 30,
+//	                "open_now": true, "open_at": "2026-10-19T09:30",
+//	                "include_discarded": true,
+//	                "rules": ["situacao == \"ATIVA\"", "followers >= 500"], "rules_preset": "..." }
+//
+//	location is required unless region is set. region (UF, mesorregião,
+//	microrregião or metro area) and expand_radius_km fan the search out to
+//	several cities; they are mutually exclusive. open_now / open_at keep only
+//	leads open at that time (open_at: RFC 3339 or local Brasília time); leads
+//	without recognizable opening hours are kept. include_discarded (also
+//	accepted as ?include_discarded=true) adds discarded_leads: the leads the
+//	filters removed, each with filter, reason and details. rules (see
+//	internal/rules) keep only the leads for which every rule is true;
+//	rules_preset prepends the rules saved under that name by the tenant of
+//	the API key (see tenantID; 401 without a valid one when TENANT_API_KEYS
+//	is set). Rules that don't compile are a 400. Leads on the tenant's
+//	suppression lists are removed; suppressed counts them.
 //	Response:     SearchResponse JSON
 func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -58,14 +113,85 @@ func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
 		errResponse(w, http.StatusBadRequest, "query is required")
 		return
 	}
-	if req.Location == "" {
-		errResponse(w, http.StatusBadRequest, "location is required")
+	if v := r.URL.Query().Get("include_discarded"); v != "" {
+		include, err := strconv.ParseBool(v)
+		if err != nil {
+			errResponse(w, http.StatusBadRequest, "include_discarded must be true or false")
+			return
+		}
+		req.IncludeDiscarded = req.IncludeDiscarded || include
+	}
+	if req.Location == "" && req.Region == "" {
+		errResponse(w, http.StatusBadRequest, "location or region is required")
+		return
+	}
+	if req.ExpandRadiusKm < 0 || req.ExpandRadiusKm > maxRadiusKm {
+		errResponse(w, http.StatusBadRequest, fmt.Sprintf("expand_radius_km must be between 0 and %d", maxRadiusKm))
+		return
+	}
+	if _, err := location.Resolve(req); err != nil {
+		errResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.RadiusKm < 0 || req.RadiusKm > maxRadiusKm {
+		errResponse(w, http.StatusBadRequest, fmt.Sprintf("radius_km must be between 0 and %d", maxRadiusKm))
+		return
+	}
+	if c := req.Center; c != nil && (c.Lat < -90 || c.Lat > 90 || c.Lon < -180 || c.Lon > 180) {
+		errResponse(w, http.StatusBadRequest, "center must have lat in [-90, 90] and lon in [-180, 180]")
 		return
 	}
 
+	if req.OpenNow && req.OpenAt != "" {
+		errResponse(w, http.StatusBadRequest, "open_now and open_at are mutually exclusive")
+		return
+	}
+	if req.OpenAt != "" {
+		if _, err := filter.ParseOpenAt(req.OpenAt); err != nil {
+			errResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	tenant, status, err := h.tenantID(r)
+	if err != nil {
+		errResponse(w, status, err.Error())
+		return
+	}
+	if req.RulesPreset != "" {
+		if h.mongo == nil {
+			errResponse(w, http.StatusServiceUnavailable, "mongodb not configured")
+			return
+		}
+		preset, err := h.mongo.GetRulePreset(r.Context(), tenant, req.RulesPreset)
+		if err != nil {
+			errResponse(w, http.StatusInternalServerError, "failed to load rules_preset: "+err.Error())
+			return
+		}
+		if preset == nil {
+			errResponse(w, http.StatusBadRequest, fmt.Sprintf("rules_preset %q not found", req.RulesPreset))
+			return
+		}
+		req.Rules = append(append([]string(nil), preset.Rules...), req.Rules...)
+	}
+	if _, err := rules.CompileAll(req.Rules); err != nil {
+		errResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	suppress, err := h.suppression(r.Context(), tenant)
+	if err != nil {
+		errResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	req.Suppression = suppress.Fingerprint()
+
 	cfg := pipeline.Config{
-		Redis: h.redis,
-		Mongo: h.mongo,
+		Redis:    h.redis,
+		Mongo:    h.mongo,
+		Email:    h.emails,
+		LLM:      h.llm,
+		Suppress: suppress,
 	}
 
 	resp, err := pipeline.Run(r.Context(), req, cfg)
@@ -82,7 +208,12 @@ func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
 //
 //	DELETE /api/v1/search/cache
 //
-//	Query params: query, location, enrich_cnpj (0|1), enrich_instagram (0|1)
+//	Query params: query, location, enrich_cnpj (0|1), enrich_instagram (0|1),
+//	              enrich_website (0|1), radius_km, center_lat, center_lon,
+//	              region, expand_radius_km
+//
+//	The key is the one of the API key tenant's searches, which depends on
+//	its current suppression lists.
 func (h *Handler) InvalidateCache(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -95,15 +226,43 @@ func (h *Handler) InvalidateCache(w http.ResponseWriter, r *http.Request) {
 
 	q := r.URL.Query()
 	query := q.Get("query")
-	location := q.Get("location")
-	if query == "" || location == "" {
-		errResponse(w, http.StatusBadRequest, "query and location are required")
+	loc := q.Get("location")
+	if query == "" || (loc == "" && q.Get("region") == "") {
+		errResponse(w, http.StatusBadRequest, "query and location (or region) are required")
 		return
 	}
 
-	ec := q.Get("enrich_cnpj") == "1"
-	ei := q.Get("enrich_instagram") == "1"
-	key := cache.SearchKey(query, location, ec, ei)
+	req := domain.SearchRequest{
+		Query:           query,
+		Location:        loc,
+		Region:          q.Get("region"),
+		EnrichCNPJ:      q.Get("enrich_cnpj") == "1",
+		EnrichInstagram: q.Get("enrich_instagram") == "1",
+		EnrichWebsite:   q.Get("enrich_website") == "1",
+	}
+	req.RadiusKm, _ = strconv.ParseFloat(q.Get("radius_km"), 64)
+	req.ExpandRadiusKm, _ = strconv.ParseFloat(q.Get("expand_radius_km"), 64)
+	if q.Get("center_lat") != "" || q.Get("center_lon") != "" {
+		lat, errLat := strconv.ParseFloat(q.Get("center_lat"), 64)
+		lon, errLon := strconv.ParseFloat(q.Get("center_lon"), 64)
+		if errLat != nil || errLon != nil {
+			errResponse(w, http.StatusBadRequest, "center_lat and center_lon must both be numbers")
+			return
+		}
+		req.Center = &domain.GeoPoint{Lat: lat, Lon: lon}
+	}
+	tenant, status, err := h.tenantID(r)
+	if err != nil {
+		errResponse(w, status, err.Error())
+		return
+	}
+	suppress, err := h.suppression(r.Context(), tenant)
+	if err != nil {
+		errResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	req.Suppression = suppress.Fingerprint()
+	key := cache.SearchKey(query, loc, req.Variant())
 
 	if err := h.redis.DeleteSearch(r.Context(), key); err != nil {
 		errResponse(w, http.StatusInternalServerError, "failed to delete cache key: "+err.Error())
@@ -113,3 +272,16 @@ func (h *Handler) InvalidateCache(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(map[string]string{"status": "deleted", "key": key})
 }
+
+// suppression loads the tenant's suppression lists. Returns nil when
+// MongoDB is not configured or the tenant has none.
+func (h *Handler) suppression(ctx context.Context, tenant string) (*suppression.Matcher, error) {
+	if h.mongo == nil {
+		return nil, nil
+	}
+	lists, err := h.mongo.ListSuppressionLists(ctx, tenant)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load suppression lists: %w", err)
+	}
+	return suppression.New(lists)
+}
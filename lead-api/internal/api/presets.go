@@ -0,0 +1,126 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/lucasfdcampos/lead-api/internal/domain"
+	"github.com/lucasfdcampos/lead-api/internal/rules"
+)
+
+// RulePresets godoc
+//
+//	GET /api/v1/rules/presets
+//
+//	Lists the rule presets of the tenant of the API key ("default" when
+//	TENANT_API_KEYS is not set).
+//	Response:     [RulePreset, ...]
+func (h *Handler) RulePresets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.mongo == nil {
+		errResponse(w, http.StatusServiceUnavailable, "mongodb not configured")
+		return
+	}
+	tenant, status, err := h.tenantID(r)
+	if err != nil {
+		errResponse(w, status, err.Error())
+		return
+	}
+
+	presets, err := h.mongo.ListRulePresets(r.Context(), tenant)
+	if err != nil {
+		errResponse(w, http.StatusInternalServerError, "failed to list rule presets: "+err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(presets)
+}
+
+// RulePreset godoc
+//
+//	GET    /api/v1/rules/presets/{name}
+//	PUT    /api/v1/rules/presets/{name}
+//	DELETE /api/v1/rules/presets/{name}
+//
+//	PUT body: { "description": "...", "rules": ["situacao == \"ATIVA\"", "has(phone)"] }
+//
+//	Presets belong to the tenant of the API key and are used by its
+//	searches through rules_preset. PUT creates or replaces the preset; its
+//	rules must compile (400 otherwise).
+//	Response:     RulePreset JSON (GET, PUT); {"status": "deleted"} (DELETE)
+func (h *Handler) RulePreset(w http.ResponseWriter, r *http.Request) {
+	if h.mongo == nil {
+		errResponse(w, http.StatusServiceUnavailable, "mongodb not configured")
+		return
+	}
+	tenant, status, err := h.tenantID(r)
+	if err != nil {
+		errResponse(w, status, err.Error())
+		return
+	}
+	name := r.PathValue("name")
+	if !validName.MatchString(name) {
+		errResponse(w, http.StatusBadRequest, "preset name must be 1-64 letters, digits, '.', '_' or '-'")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		preset, err := h.mongo.GetRulePreset(r.Context(), tenant, name)
+		if err != nil {
+			errResponse(w, http.StatusInternalServerError, "failed to load rule preset: "+err.Error())
+			return
+		}
+		if preset == nil {
+			errResponse(w, http.StatusNotFound, fmt.Sprintf("rule preset %q not found", name))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(preset)
+
+	case http.MethodPut:
+		var body struct {
+			Description string   `json:"description"`
+			Rules       []string `json:"rules"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			errResponse(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+			return
+		}
+		if len(body.Rules) == 0 {
+			errResponse(w, http.StatusBadRequest, "rules is required")
+			return
+		}
+		if _, err := rules.CompileAll(body.Rules); err != nil {
+			errResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		preset := &domain.RulePreset{Tenant: tenant, Name: name, Description: body.Description, Rules: body.Rules}
+		if err := h.mongo.SaveRulePreset(r.Context(), preset); err != nil {
+			errResponse(w, http.StatusInternalServerError, "failed to save rule preset: "+err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(preset)
+
+	case http.MethodDelete:
+		found, err := h.mongo.DeleteRulePreset(r.Context(), tenant, name)
+		if err != nil {
+			errResponse(w, http.StatusInternalServerError, "failed to delete rule preset: "+err.Error())
+			return
+		}
+		if !found {
+			errResponse(w, http.StatusNotFound, fmt.Sprintf("rule preset %q not found", name))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"status": "deleted"})
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -19,6 +19,10 @@ func NewServer(addr string, h *Handler) *Server {
 	mux.HandleFunc("/health", h.Health)
 	mux.HandleFunc("/api/v1/search", h.Search)
 	mux.HandleFunc("/api/v1/search/cache", h.InvalidateCache)
+	mux.HandleFunc("/api/v1/rules/presets", h.RulePresets)
+	mux.HandleFunc("/api/v1/rules/presets/{name}", h.RulePreset)
+	mux.HandleFunc("/api/v1/suppression-lists", h.SuppressionLists)
+	mux.HandleFunc("/api/v1/suppression-lists/{name}", h.SuppressionList)
 
 	return &Server{
 		srv: &http.Server{
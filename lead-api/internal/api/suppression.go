@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/lucasfdcampos/lead-api/internal/domain"
+	"github.com/lucasfdcampos/lead-api/internal/suppression"
+)
+
+// SuppressionLists godoc
+//
+//	GET /api/v1/suppression-lists
+//
+//	Lists the suppression lists of the tenant of the API key ("default"
+//	when TENANT_API_KEYS is not set).
+//	Response:     [SuppressionList, ...]
+func (h *Handler) SuppressionLists(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.mongo == nil {
+		errResponse(w, http.StatusServiceUnavailable, "mongodb not configured")
+		return
+	}
+	tenant, status, err := h.tenantID(r)
+	if err != nil {
+		errResponse(w, status, err.Error())
+		return
+	}
+
+	lists, err := h.mongo.ListSuppressionLists(r.Context(), tenant)
+	if err != nil {
+		errResponse(w, http.StatusInternalServerError, "failed to list suppression lists: "+err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(lists)
+}
+
+// SuppressionList godoc
+//
+//	GET    /api/v1/suppression-lists/{name}
+//	PUT    /api/v1/suppression-lists/{name}
+//	DELETE /api/v1/suppression-lists/{name}
+//
+//	PUT body: { "description": "clientes ativos",
+//	            "cnpj_roots": ["12345678"], "phones": ["(43) 99999-8888"],
+//	            "instagram": ["@loja"], "domains": ["loja.com.br"],
+//	            "name_patterns": ["^pizzaria bella"] }
+//
+//	Every search of the tenant of the API key drops the leads
+//	matching any entry of its lists. PUT creates or replaces the list;
+//	entries are normalized (CNPJ roots to 8 digits, phones to E.164) and
+//	invalid ones are a 400.
+//	Response:     SuppressionList JSON (GET, PUT); {"status": "deleted"} (DELETE)
+func (h *Handler) SuppressionList(w http.ResponseWriter, r *http.Request) {
+	if h.mongo == nil {
+		errResponse(w, http.StatusServiceUnavailable, "mongodb not configured")
+		return
+	}
+	tenant, status, err := h.tenantID(r)
+	if err != nil {
+		errResponse(w, status, err.Error())
+		return
+	}
+	name := r.PathValue("name")
+	if !validName.MatchString(name) {
+		errResponse(w, http.StatusBadRequest, "list name must be 1-64 letters, digits, '.', '_' or '-'")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		list, err := h.mongo.GetSuppressionList(r.Context(), tenant, name)
+		if err != nil {
+			errResponse(w, http.StatusInternalServerError, "failed to load suppression list: "+err.Error())
+			return
+		}
+		if list == nil {
+			errResponse(w, http.StatusNotFound, fmt.Sprintf("suppression list %q not found", name))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(list)
+
+	case http.MethodPut:
+		var list domain.SuppressionList
+		if err := json.NewDecoder(r.Body).Decode(&list); err != nil {
+			errResponse(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+			return
+		}
+		list.Tenant, list.Name = tenant, name
+		if err := suppression.Normalize(&list); err != nil {
+			errResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := h.mongo.SaveSuppressionList(r.Context(), &list); err != nil {
+			errResponse(w, http.StatusInternalServerError, "failed to save suppression list: "+err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(list)
+
+	case http.MethodDelete:
+		found, err := h.mongo.DeleteSuppressionList(r.Context(), tenant, name)
+		if err != nil {
+			errResponse(w, http.StatusInternalServerError, "failed to delete suppression list: "+err.Error())
+			return
+		}
+		if !found {
+			errResponse(w, http.StatusNotFound, fmt.Sprintf("suppression list %q not found", name))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"status": "deleted"})
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -1,7 +1,7 @@
 // Package cache provides a Redis-backed caching layer.
 //
 // Key strategy:
-//   - Search results:       lead:search:v1:{sha256(query+location+flags)} → TTL 24 h
+//   - Search results:       lead:search:v1:{sha256(query+location+variant)} → TTL 24 h
 //   - Per-lead enrichment:  lead:enrich:v1:{sha256(name+city)}            → TTL 7 d
 package cache
 
@@ -55,9 +55,10 @@ func normalizeKey(s string) string {
 
 // ─── Search cache ──────────────────────────────────────────────────────────────
 
-// SearchKey returns the cache key for a search.
-func SearchKey(query, location string, enrichCNPJ, enrichInstagram bool) string {
-	raw := fmt.Sprintf("%s|%s|cnpj=%v|ig=%v", normalizeKey(query), normalizeKey(location), enrichCNPJ, enrichInstagram)
+// SearchKey returns the cache key for a search. variant is
+// domain.SearchRequest.Variant() (enrichment flags, radius, ...).
+func SearchKey(query, location, variant string) string {
+	raw := fmt.Sprintf("%s|%s|%s", normalizeKey(query), normalizeKey(location), variant)
 	h := sha256.Sum256([]byte(raw))
 	return searchPrefix + fmt.Sprintf("%x", h)
 }
@@ -89,17 +90,28 @@ func (c *Client) DeleteSearch(ctx context.Context, key string) error {
 
 // EnrichedLead is the structure stored per lead in cache.
 type EnrichedLead struct {
-	CNPJ         string   `json:"cnpj,omitempty"`
-	RazaoSocial  string   `json:"razao_social,omitempty"`
-	NomeFantasia string   `json:"nome_fantasia,omitempty"`
-	Situacao     string   `json:"situacao,omitempty"`
-	Partners     []string `json:"partners,omitempty"`
-	CNAECode     string   `json:"cnae_code,omitempty"`
-	CNAEDesc     string   `json:"cnae_desc,omitempty"`
-	Municipio    string   `json:"municipio,omitempty"`
-	UF           string   `json:"uf,omitempty"`
-	Instagram    string   `json:"instagram,omitempty"`
-	Followers    string   `json:"followers,omitempty"`
+	CNPJ          string   `json:"cnpj,omitempty"`
+	RazaoSocial   string   `json:"razao_social,omitempty"`
+	NomeFantasia  string   `json:"nome_fantasia,omitempty"`
+	Situacao      string   `json:"situacao,omitempty"`
+	Partners      []string `json:"partners,omitempty"`
+	CNAECode      string   `json:"cnae_code,omitempty"`
+	CNAEDesc      string   `json:"cnae_desc,omitempty"`
+	CNAESecondary []string `json:"cnae_secondary,omitempty"`
+	Municipio     string   `json:"municipio,omitempty"`
+	UF            string   `json:"uf,omitempty"`
+	Email         string   `json:"email,omitempty"`
+	Instagram     string   `json:"instagram,omitempty"`
+	Followers     string   `json:"followers,omitempty"`
+
+	// Site oficial (chave "web:"+name)
+	Website     string            `json:"website,omitempty"`
+	WebsiteVia  string            `json:"website_via,omitempty"`
+	Emails      []string          `json:"emails,omitempty"`
+	Phones      []string          `json:"phones,omitempty"`
+	WhatsApp    []string          `json:"whatsapp,omitempty"`
+	SiteCNPJs   []string          `json:"site_cnpjs,omitempty"`
+	SocialLinks map[string]string `json:"social_links,omitempty"`
 }
 
 // EnrichmentKey returns cache key for per-lead enrichment data.
@@ -1,69 +1,30 @@
 // Package cnae – discovery.go
-// Discovers CNAE codes for a given query by scraping DuckDuckGo search results.
+// Discovers CNAE codes for a given query from DuckDuckGo and Mojeek search results.
 // The result is cached in MongoDB (cnae_hints collection) to avoid repeated lookups.
 package cnae
 
 import (
 	"context"
 	"fmt"
-	"net/http"
-	"net/url"
 	"regexp"
-	"strings"
 	"time"
 
-	"github.com/PuerkitoBio/goquery"
+	"github.com/lucasfdcampos/serp/pkg/serp"
 )
 
 // cnaeCodeRe matches CNAE code patterns like 4781-4/00, 47.81-4/00, 4781, 47814
 var cnaeCodeRe = regexp.MustCompile(`\b(\d{4}[\d\.\-\/]{0,5})\b`)
 
 // DiscoverFromSearch searches DuckDuckGo for `"<query>" CNAE Brasil` and extracts
-// all CNAE codes found in the results snippets.
+// all CNAE codes found in the results snippets, adding a Mojeek search as a
+// second source.
 // Returns (codes, rawSnippet, error). On failure it returns empty codes (no error) so the
 // pipeline can fall back to the static map gracefully.
 func DiscoverFromSearch(ctx context.Context, query string) (codes []string, snippet string, err error) {
-	q := fmt.Sprintf(`"%s" CNAE Brasil atividade econômica`, query)
-	searchURL := "https://lite.duckduckgo.com/lite/?q=" + url.QueryEscape(q)
-
-	tctx, cancel := context.WithTimeout(ctx, 15*time.Second)
-	defer cancel()
-
-	req, err := http.NewRequestWithContext(tctx, "GET", searchURL, nil)
-	if err != nil {
-		return nil, "", nil //nolint – non-fatal
-	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 Chrome/120.0.0.0 Safari/537.36")
-	req.Header.Set("Accept-Language", "pt-BR,pt;q=0.9")
-
-	time.Sleep(800 * time.Millisecond)
-
-	client := &http.Client{Timeout: 12 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, "", nil
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, "", nil
-	}
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return nil, "", nil
-	}
-
-	var sb strings.Builder
-	doc.Find("td.result-snippet, .result-snippet, td").Each(func(_ int, s *goquery.Selection) {
-		sb.WriteString(s.Text())
-		sb.WriteString("\n")
-	})
-	raw := sb.String()
+	raw := searchText(ctx, serp.DuckDuckGo, fmt.Sprintf(`"%s" CNAE Brasil atividade econômica`, query), 15*time.Second)
 
 	// Additionally try Mojeek as a second source
-	mojeekSnippet := discoverFromMojeek(ctx, query)
-	if mojeekSnippet != "" {
+	if mojeekSnippet := searchText(ctx, serp.Mojeek, fmt.Sprintf(`%s CNAE atividade`, query), 10*time.Second); mojeekSnippet != "" {
 		raw += "\n" + mojeekSnippet
 	}
 
@@ -71,44 +32,17 @@ func DiscoverFromSearch(ctx context.Context, query string) (codes []string, snip
 	return codes, raw, nil
 }
 
-// discoverFromMojeek performs a CNAE discovery search on Mojeek.
-func discoverFromMojeek(ctx context.Context, query string) string {
-	q := fmt.Sprintf(`%s CNAE atividade`, query)
-	searchURL := "https://www.mojeek.com/search?q=" + url.QueryEscape(q)
-
-	tctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+// searchText returns the titles and snippets of one search, or "" when the
+// engine fails, is cooling down or times out.
+func searchText(ctx context.Context, engine serp.Engine, q string, timeout time.Duration) string {
+	tctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(tctx, "GET", searchURL, nil)
-	if err != nil {
-		return ""
-	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 Chrome/120.0.0.0 Safari/537.36")
-	req.Header.Set("Accept-Language", "pt-BR,pt;q=0.9")
-
-	time.Sleep(500 * time.Millisecond)
-	client := &http.Client{Timeout: 8 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return ""
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return ""
-	}
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	page, err := serp.Search(tctx, engine, q)
 	if err != nil {
 		return ""
 	}
-
-	var sb strings.Builder
-	doc.Find(".result-text, .result__body, p").Each(func(_ int, s *goquery.Selection) {
-		sb.WriteString(s.Text())
-		sb.WriteString("\n")
-	})
-	return sb.String()
+	return page.Text()
 }
 
 // extractCNAECodes parses raw text and returns unique 4-digit CNAE code prefixes.
@@ -0,0 +1,510 @@
+// Package cnae – hierarchy.go
+// The CNAE 2.3 structure (seção → divisão → grupo → classe → subclasse) with
+// the official descriptions, used to seed the cnaes collection and to match
+// CNAE codes at a configurable level of the hierarchy.
+//
+// cnae23.csv (IBGE "estrutura detalhada" layout) is generated by
+// internal/gen (go generate) from the IBGE CNAE API; LoadFile + SetDefault
+// swap the table at runtime. A table with fewer than Subclasses subclasses
+// is partial (Partial), and cmd/seed-cnae refuses to seed from it.
+package cnae
+
+//go:generate go run ./internal/gen -out cnae23.csv
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lucasfdcampos/lead-api/internal/domain"
+)
+
+//go:embed cnae23.csv
+var cnaeCSV []byte
+
+// Subclasses is the number of subclasses in CNAE 2.3.
+const Subclasses = 1332
+
+// ─── Levels ───────────────────────────────────────────────────────────────────
+
+// Level is a level of the CNAE hierarchy.
+type Level int
+
+const (
+	Section  Level = iota // seção: letter A–U
+	Division              // divisão: 2 digits ("47")
+	Group                 // grupo: 3 digits ("47.8")
+	Class                 // classe: 4 digits + check digit ("47.81-4")
+	Subclass              // subclasse: 7 digits ("4781-4/00")
+)
+
+var levelNames = [...]string{"secao", "divisao", "grupo", "classe", "subclasse"}
+
+// String returns the level name as used in the CSV header and in the cnaes
+// collection ("secao", "divisao", ...).
+func (l Level) String() string {
+	if l < Section || l > Subclass {
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel accepts the Portuguese level names (with or without accents)
+// and their English equivalents.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "secao", "seção", "section":
+		return Section, nil
+	case "divisao", "divisão", "division":
+		return Division, nil
+	case "grupo", "group":
+		return Group, nil
+	case "classe", "class":
+		return Class, nil
+	case "subclasse", "subclass":
+		return Subclass, nil
+	}
+	return 0, fmt.Errorf("cnae: unknown hierarchy level %q (want secao, divisao, grupo, classe or subclasse)", s)
+}
+
+// matchDigits is how many leading digits identify a code at level l. The
+// class check digit is derived from the first four, so classes compare on
+// four digits — the same prefixes the category taxonomy uses.
+func matchDigits(l Level) int {
+	switch l {
+	case Division:
+		return 2
+	case Group:
+		return 3
+	case Class:
+		return 4
+	default:
+		return 7
+	}
+}
+
+// ─── Dataset ──────────────────────────────────────────────────────────────────
+
+// Entry is a node of the CNAE hierarchy.
+type Entry struct {
+	Code      string // digits only ("4781400", "47814", "478", "47"); the letter for sections
+	Formatted string // official notation ("4781-4/00", "47.81-4", "47.8", "47", "G")
+	Level     Level
+	Desc      string
+	Parent    string // Code of the parent; empty for sections
+}
+
+// Dataset is a CNAE table indexed by code.
+type Dataset struct {
+	all      []Entry
+	byCode   map[string]int
+	byClass4 map[string]int // class code without the check digit
+	children map[string][]int
+	subs     int // number of subclasses
+}
+
+var (
+	embeddedOnce sync.Once
+	embeddedSet  *Dataset
+	override     atomic.Pointer[Dataset]
+)
+
+// Default returns the table set with SetDefault or, when none was set, the
+// embedded one.
+func Default() *Dataset {
+	if ds := override.Load(); ds != nil {
+		return ds
+	}
+	embeddedOnce.Do(func() {
+		ds, err := Parse(bytes.NewReader(cnaeCSV))
+		if err != nil {
+			panic("cnae: invalid embedded table: " + err.Error())
+		}
+		embeddedSet = ds
+	})
+	return embeddedSet
+}
+
+// SetDefault replaces the table returned by Default (e.g. the full table
+// loaded with LoadFile at startup).
+func SetDefault(ds *Dataset) {
+	override.Store(ds)
+}
+
+// LoadFile reads a full table in the cnae23.csv layout.
+func LoadFile(path string) (*Dataset, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cnae: %w", err)
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse reads a table in the IBGE "estrutura detalhada" layout: a header
+// with secao, divisao, grupo, classe, subclasse and denominacao, separated
+// by ";" or ",", and one node per row with only its own code column filled.
+// Rows must come in hierarchy order (each node after its parent).
+func Parse(r io.Reader) (*Dataset, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("cnae: %w", err)
+	}
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if first, _, _ := bytes.Cut(data, []byte("\n")); bytes.Count(first, []byte(";")) > bytes.Count(first, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("cnae: reading header: %w", err)
+	}
+	col := make(map[string]int, len(header))
+	for i, h := range header {
+		col[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))] = i
+	}
+	levelCols := make([]int, len(levelNames))
+	for i, name := range levelNames {
+		c, ok := col[name]
+		if !ok {
+			return nil, fmt.Errorf("cnae: missing column %q", name)
+		}
+		levelCols[i] = c
+	}
+	descCol, ok := col["denominacao"]
+	if !ok {
+		return nil, errors.New(`cnae: missing column "denominacao"`)
+	}
+
+	ds := &Dataset{
+		byCode:   make(map[string]int),
+		byClass4: make(map[string]int),
+		children: make(map[string][]int),
+	}
+	var current [len(levelNames)]string // last code seen at each level
+	line := 1
+	for {
+		rec, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("cnae: line %d: %w", line, err)
+		}
+		e, ok := parseRow(rec, levelCols, descCol)
+		if !ok {
+			continue // blank or note row
+		}
+		if e.Level > Section {
+			e.Parent = current[e.Level-1]
+			if e.Parent == "" {
+				return nil, fmt.Errorf("cnae: line %d: %s %s has no parent", line, e.Level, e.Formatted)
+			}
+		}
+		current[e.Level] = e.Code
+		for l := e.Level + 1; l <= Subclass; l++ {
+			current[l] = ""
+		}
+		if _, dup := ds.byCode[e.Code]; dup {
+			continue
+		}
+		idx := len(ds.all)
+		ds.all = append(ds.all, e)
+		ds.byCode[e.Code] = idx
+		switch e.Level {
+		case Class:
+			ds.byClass4[e.Code[:4]] = idx
+		case Subclass:
+			ds.subs++
+		}
+		if e.Parent != "" {
+			ds.children[e.Parent] = append(ds.children[e.Parent], idx)
+		}
+	}
+	if len(ds.all) == 0 {
+		return nil, errors.New("cnae: empty table")
+	}
+	return ds, nil
+}
+
+func parseRow(rec []string, levelCols []int, descCol int) (Entry, bool) {
+	field := func(i int) string {
+		if i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+	desc := field(descCol)
+	for l := Subclass; l >= Section; l-- {
+		formatted := field(levelCols[l])
+		if formatted == "" {
+			continue
+		}
+		code := strings.ToUpper(formatted)
+		if l > Section {
+			code = digits(formatted)
+		}
+		if !validCode(code, l) || desc == "" {
+			return Entry{}, false
+		}
+		return Entry{Code: code, Formatted: formatted, Level: l, Desc: desc}, true
+	}
+	return Entry{}, false
+}
+
+func validCode(code string, l Level) bool {
+	switch l {
+	case Section:
+		return len(code) == 1 && code[0] >= 'A' && code[0] <= 'Z'
+	case Class:
+		return len(code) == 5
+	default:
+		return len(code) == matchDigits(l)
+	}
+}
+
+// Partial reports whether the table has fewer than Subclasses subclasses.
+// In a partial table, a code not found is not necessarily an invalid code.
+func (d *Dataset) Partial() bool {
+	return d.subs < Subclasses
+}
+
+// SubclassCount returns the number of subclasses in the table.
+func (d *Dataset) SubclassCount() int {
+	return d.subs
+}
+
+// All returns every entry in hierarchy order.
+func (d *Dataset) All() []Entry {
+	return d.all
+}
+
+// Get looks a code up in any notation: "G", "47", "47.8", "47.81-4",
+// "4781" (class without check digit) or "4781-4/00".
+func (d *Dataset) Get(code string) (Entry, bool) {
+	code = Normalize(code)
+	if i, ok := d.byCode[code]; ok {
+		return d.all[i], true
+	}
+	if len(code) == 4 {
+		if i, ok := d.byClass4[code]; ok {
+			return d.all[i], true
+		}
+	}
+	return Entry{}, false
+}
+
+// Ancestors returns the parent, grandparent, ... of code, up to its section.
+// Nil when code is unknown.
+func (d *Dataset) Ancestors(code string) []Entry {
+	e, ok := d.Get(code)
+	if !ok {
+		return nil
+	}
+	var out []Entry
+	for e.Parent != "" {
+		if e, ok = d.Get(e.Parent); !ok {
+			break
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// Children returns the entries directly below code.
+func (d *Dataset) Children(code string) []Entry {
+	e, ok := d.Get(code)
+	if !ok {
+		return nil
+	}
+	idx := d.children[e.Code]
+	out := make([]Entry, len(idx))
+	for i, j := range idx {
+		out[i] = d.all[j]
+	}
+	return out
+}
+
+// Descendants returns every entry below code, depth first.
+func (d *Dataset) Descendants(code string) []Entry {
+	var out []Entry
+	for _, c := range d.Children(code) {
+		out = append(out, c)
+		out = append(out, d.Descendants(c.Code)...)
+	}
+	return out
+}
+
+// At returns the ancestor (or the entry itself) of code at level l.
+func (d *Dataset) At(code string, l Level) (Entry, bool) {
+	e, ok := d.Get(code)
+	if !ok {
+		return Entry{}, false
+	}
+	if e.Level == l {
+		return e, true
+	}
+	for _, a := range d.Ancestors(code) {
+		if a.Level == l {
+			return a, true
+		}
+	}
+	return Entry{}, false
+}
+
+// SameAt reports whether a and b fall under the same node at level l.
+// Below the section level codes are compared by prefix, so it works for
+// codes missing from a partial table; sections are looked up by division.
+func (d *Dataset) SameAt(a, b string, l Level) bool {
+	ka, kb := d.key(a, l), d.key(b, l)
+	return ka != "" && ka == kb
+}
+
+// SameGroup reports whether a and b are in the same CNAE group.
+func (d *Dataset) SameGroup(a, b string) bool {
+	return d.SameAt(a, b, Group)
+}
+
+// key is the identifier of code at level l: its section letter, or its
+// first matchDigits(l) digits. Empty when code is shorter than the level.
+func (d *Dataset) key(code string, l Level) string {
+	code = Normalize(code)
+	if code == "" {
+		return ""
+	}
+	if l == Section {
+		if isSection(code) {
+			return code
+		}
+		if len(code) < 2 {
+			return ""
+		}
+		if e, ok := d.At(code[:2], Section); ok {
+			return e.Code
+		}
+		return ""
+	}
+	if isSection(code) {
+		return ""
+	}
+	n := matchDigits(l)
+	if len(code) < n {
+		return ""
+	}
+	return code[:n]
+}
+
+// Search returns up to limit subclasses whose description contains any of
+// the keywords (case-insensitive), in table order.
+func (d *Dataset) Search(keywords []string, limit int) []Entry {
+	var kws []string
+	for _, kw := range keywords {
+		if kw = strings.ToLower(strings.TrimSpace(kw)); kw != "" {
+			kws = append(kws, kw)
+		}
+	}
+	var out []Entry
+	for _, e := range d.all {
+		if e.Level != Subclass {
+			continue
+		}
+		desc := strings.ToLower(e.Desc)
+		for _, kw := range kws {
+			if strings.Contains(desc, kw) {
+				out = append(out, e)
+				break
+			}
+		}
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+	}
+	return out
+}
+
+// Records returns the table as cnaes collection documents.
+func (d *Dataset) Records() []domain.CNAE {
+	out := make([]domain.CNAE, len(d.all))
+	for i, e := range d.all {
+		out[i] = domain.CNAE{
+			Code:      e.Code,
+			Formatted: e.Formatted,
+			Level:     e.Level.String(),
+			Desc:      e.Desc,
+			Parent:    e.Parent,
+		}
+	}
+	return out
+}
+
+// ─── Matching ─────────────────────────────────────────────────────────────────
+
+// Normalize returns the digits of a CNAE code, or the upper-case letter of
+// a section code.
+func Normalize(code string) string {
+	code = strings.TrimSpace(code)
+	if len(code) == 1 && isSection(strings.ToUpper(code)) {
+		return strings.ToUpper(code)
+	}
+	return digits(code)
+}
+
+func isSection(code string) bool {
+	return len(code) == 1 && code[0] >= 'A' && code[0] <= 'Z'
+}
+
+var matchLevel atomic.Int32
+
+func init() { matchLevel.Store(int32(Class)) }
+
+// MatchLevel returns the hierarchy level IsCompatible matches codes at
+// (default Class).
+func MatchLevel() Level {
+	return Level(matchLevel.Load())
+}
+
+// SetMatchLevel changes the level IsCompatible matches codes at (e.g. from
+// CNAE_MATCH_LEVEL at startup).
+func SetMatchLevel(l Level) {
+	matchLevel.Store(int32(l))
+}
+
+// MatchAt reports whether code falls under any of the compatible codes at
+// level l, and returns the compatible code it matched. Compatible codes may
+// be given at any level: one more specific than l is widened to l (at the
+// Group level "4781" accepts every 478x code), one less specific keeps its
+// own width ("47" accepts the whole division at any level).
+func MatchAt(code string, compatible []string, l Level) (string, bool) {
+	code = Normalize(code)
+	if code == "" {
+		return "", false
+	}
+	ds := Default()
+	for _, c := range compatible {
+		n := Normalize(c)
+		if n == "" {
+			continue
+		}
+		if l == Section || isSection(n) {
+			if ds.SameAt(code, n, Section) {
+				return c, true
+			}
+			continue
+		}
+		cut := min(matchDigits(l), len(n))
+		if len(code) >= cut && code[:cut] == n[:cut] {
+			return c, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,215 @@
+// Command gen regenerates cnae23.csv with the whole CNAE 2.3 hierarchy.
+//
+//	go generate ./internal/cnae
+//
+// Sections, divisions, groups, classes and subclasses come from the IBGE
+// CNAE API (/api/v2/cnae/subclasses). The API returns every description in
+// upper case; descriptions already in the current table are kept, and new
+// ones below the division level are written in sentence case, as in the
+// IBGE "estrutura detalhada" spreadsheet.
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const subclassesURL = "https://servicodados.ibge.gov.br/api/v2/cnae/subclasses"
+
+// header is the layout read by cnae.Parse.
+var header = []string{"secao", "divisao", "grupo", "classe", "subclasse", "denominacao"}
+
+func main() {
+	out := flag.String("out", "cnae23.csv", "generated file (the current table is read from it first)")
+	src := flag.String("subclasses", subclassesURL, "URL or file with the JSON of /api/v2/cnae/subclasses")
+	flag.Parse()
+
+	current, err := readCurrent(*out)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var subs []subclass
+	if err := fetchJSON(*src, &subs); err != nil {
+		log.Fatal(err)
+	}
+	rows, err := build(subs, current)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		log.Fatal(err)
+	}
+	w := csv.NewWriter(f)
+	w.Comma = ';'
+	_ = w.Write(header)
+	_ = w.WriteAll(rows)
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%s: %d subclasses, %d rows", *out, len(subs), len(rows))
+}
+
+// node is the id/description pair every level of the API shares.
+type node struct {
+	ID        string `json:"id"`
+	Descricao string `json:"descricao"`
+}
+
+// subclass is an item of /api/v2/cnae/subclasses.
+type subclass struct {
+	node
+	Classe struct {
+		node
+		Grupo struct {
+			node
+			Divisao struct {
+				node
+				Secao node `json:"secao"`
+			} `json:"divisao"`
+		} `json:"grupo"`
+	} `json:"classe"`
+}
+
+// build returns the rows of cnae23.csv in hierarchy order, each node once.
+// current (code → description, digits only; the letter for sections) keeps
+// the descriptions of the table being replaced.
+func build(subs []subclass, current map[string]string) ([][]string, error) {
+	sort.Slice(subs, func(i, j int) bool { return subs[i].ID < subs[j].ID })
+	var rows [][]string
+	seen := make(map[string]bool)
+	add := func(level int, n node, formatted string) {
+		if seen[n.ID] {
+			return
+		}
+		seen[n.ID] = true
+		desc, ok := current[n.ID]
+		if !ok {
+			desc = strings.TrimSpace(n.Descricao)
+			if level > 1 {
+				desc = sentenceCase(desc)
+			}
+		}
+		row := make([]string, len(header))
+		row[level], row[len(header)-1] = formatted, desc
+		rows = append(rows, row)
+	}
+	for _, s := range subs {
+		c := s.Classe
+		g := c.Grupo
+		d := g.Divisao
+		if len(s.ID) != 7 || len(c.ID) != 5 || len(g.ID) != 3 || len(d.ID) != 2 || len(d.Secao.ID) != 1 ||
+			!strings.HasPrefix(s.ID, c.ID) || !strings.HasPrefix(c.ID, g.ID) || !strings.HasPrefix(g.ID, d.ID) {
+			return nil, fmt.Errorf("subclass with an inconsistent hierarchy: %+v", s)
+		}
+		if s.Descricao == "" {
+			return nil, fmt.Errorf("subclass %s without a description", s.ID)
+		}
+		add(0, d.Secao, d.Secao.ID)
+		add(1, d.node, d.ID)
+		add(2, g.node, g.ID[:2]+"."+g.ID[2:])
+		add(3, c.node, c.ID[:2]+"."+c.ID[2:4]+"-"+c.ID[4:])
+		add(4, s.node, s.ID[:4]+"-"+s.ID[4:5]+"/"+s.ID[5:])
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("no subclasses")
+	}
+	return rows, nil
+}
+
+// sentenceCase lowers an upper-case description but its first letter
+// ("COMÉRCIO VAREJISTA DE CALÇADOS" → "Comércio varejista de calçados").
+func sentenceCase(s string) string {
+	if strings.ToUpper(s) != s {
+		return s // already mixed case
+	}
+	s = strings.ToLower(s)
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[n:]
+}
+
+// readCurrent reads the descriptions of the current table, keyed by code
+// (digits only; the letter for sections).
+func readCurrent(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	r.Comma = ';'
+	r.FieldsPerRecord = -1
+	recs, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	out := make(map[string]string)
+	for i, rec := range recs {
+		if i == 0 || len(rec) < len(header) {
+			continue
+		}
+		for _, code := range rec[:len(header)-1] {
+			if code != "" {
+				out[digits(code)] = rec[len(header)-1]
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+// digits drops the punctuation of a formatted code ("47.81-4" → "47814");
+// section letters are kept.
+func digits(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == '/' {
+			return -1
+		}
+		return r
+	}, code)
+}
+
+func fetchJSON(src string, v any) error {
+	rc, err := open(src)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := json.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("%s: %w", src, err)
+	}
+	return nil
+}
+
+// open opens a local file or downloads an http(s) URL.
+func open(src string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		return os.Open(src)
+	}
+	client := &http.Client{Timeout: 2 * time.Minute}
+	resp, err := client.Get(src)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: status %d", src, resp.StatusCode)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Layout of /api/v2/cnae/subclasses (trimmed).
+const subclassesJSON = `[
+	{"id": "4782202", "descricao": "COMÉRCIO VAREJISTA DE ARTIGOS DE VIAGEM",
+	 "classe": {"id": "47822", "descricao": "COMÉRCIO VAREJISTA DE CALÇADOS E ARTIGOS DE VIAGEM",
+	   "grupo": {"id": "478", "descricao": "COMÉRCIO VAREJISTA DE PRODUTOS NOVOS NÃO ESPECIFICADOS ANTERIORMENTE E DE PRODUTOS USADOS",
+	     "divisao": {"id": "47", "descricao": "COMÉRCIO VAREJISTA",
+	       "secao": {"id": "G", "descricao": "COMÉRCIO; REPARAÇÃO DE VEÍCULOS AUTOMOTORES E MOTOCICLETAS"}}}}},
+	{"id": "4782201", "descricao": "COMÉRCIO VAREJISTA DE CALÇADOS",
+	 "classe": {"id": "47822", "descricao": "COMÉRCIO VAREJISTA DE CALÇADOS E ARTIGOS DE VIAGEM",
+	   "grupo": {"id": "478", "descricao": "COMÉRCIO VAREJISTA DE PRODUTOS NOVOS NÃO ESPECIFICADOS ANTERIORMENTE E DE PRODUTOS USADOS",
+	     "divisao": {"id": "47", "descricao": "COMÉRCIO VAREJISTA",
+	       "secao": {"id": "G", "descricao": "COMÉRCIO; REPARAÇÃO DE VEÍCULOS AUTOMOTORES E MOTOCICLETAS"}}}}},
+	{"id": "0111301", "descricao": "CULTIVO DE ARROZ",
+	 "classe": {"id": "01113", "descricao": "CULTIVO DE CEREAIS",
+	   "grupo": {"id": "011", "descricao": "PRODUÇÃO DE LAVOURAS TEMPORÁRIAS",
+	     "divisao": {"id": "01", "descricao": "AGRICULTURA, PECUÁRIA E SERVIÇOS RELACIONADOS",
+	       "secao": {"id": "A", "descricao": "AGRICULTURA, PECUÁRIA, PRODUÇÃO FLORESTAL, PESCA E AQÜICULTURA"}}}}}
+]`
+
+const currentCSV = "secao;divisao;grupo;classe;subclasse;denominacao\n" +
+	"G;;;;;\"COMÉRCIO; REPARAÇÃO DE VEÍCULOS AUTOMOTORES E MOTOCICLETAS\"\n" +
+	";47;;;;COMÉRCIO VAREJISTA\n" +
+	";;47.8;;;Comércio varejista de produtos novos não especificados anteriormente e de produtos usados\n" +
+	";;;;4782-2/01;Comércio varejista de calçados\n"
+
+func TestBuild(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cnae23.csv")
+	if err := os.WriteFile(path, []byte(currentCSV), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	current, err := readCurrent(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var subs []subclass
+	if err := json.Unmarshal([]byte(subclassesJSON), &subs); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := build(subs, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"A", "", "", "", "", "AGRICULTURA, PECUÁRIA, PRODUÇÃO FLORESTAL, PESCA E AQÜICULTURA"},
+		{"", "01", "", "", "", "AGRICULTURA, PECUÁRIA E SERVIÇOS RELACIONADOS"},
+		{"", "", "01.1", "", "", "Produção de lavouras temporárias"},
+		{"", "", "", "01.11-3", "", "Cultivo de cereais"},
+		{"", "", "", "", "0111-3/01", "Cultivo de arroz"},
+		{"G", "", "", "", "", "COMÉRCIO; REPARAÇÃO DE VEÍCULOS AUTOMOTORES E MOTOCICLETAS"},
+		{"", "47", "", "", "", "COMÉRCIO VAREJISTA"},
+		{"", "", "47.8", "", "", "Comércio varejista de produtos novos não especificados anteriormente e de produtos usados"},
+		{"", "", "", "47.82-2", "", "Comércio varejista de calçados e artigos de viagem"},
+		{"", "", "", "", "4782-2/01", "Comércio varejista de calçados"},
+		{"", "", "", "", "4782-2/02", "Comércio varejista de artigos de viagem"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("build:\n got  %q\n want %q", rows, want)
+	}
+
+	subs[0].Classe.ID = "47811"
+	if _, err := build(subs, current); err == nil {
+		t.Error("build with a subclass outside its class should fail")
+	}
+	if cur, err := readCurrent(filepath.Join(t.TempDir(), "missing.csv")); err != nil || len(cur) != 0 {
+		t.Errorf("readCurrent(missing file) = %v, %v", cur, err)
+	}
+}
@@ -0,0 +1,223 @@
+// Package cnae – understand.go
+// Maps free-text queries ("lugar pra consertar celular") to a normalized
+// category, synonyms and candidate CNAE subclasses with an LLM, grounded on
+// the CNAE reference table. Like DiscoverFromSearch, the result is cached in
+// the cnae_hints collection by the pipeline.
+package cnae
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	leadsearch "github.com/lucasfdcampos/find-leads/pkg/leads"
+	"github.com/lucasfdcampos/find-leads/pkg/taxonomy"
+
+	"github.com/lucasfdcampos/lead-api/internal/domain"
+)
+
+// MinConfidence is the confidence a candidate subclass needs to be used as
+// a CNAE hint code.
+const MinConfidence = 0.5
+
+// groundingRows bounds the table excerpt sent in the prompt.
+const groundingRows = 60
+
+// Table is the CNAE reference the LLM answer is grounded on (implemented by
+// store.Client over the cnaes collection).
+type Table interface {
+	SearchCNAEs(ctx context.Context, keywords []string, limit int) ([]domain.CNAE, error)
+	CNAEsByCode(ctx context.Context, codes []string) ([]domain.CNAE, error)
+}
+
+// Understanding is the LLM's reading of a search query.
+type Understanding struct {
+	Category   string
+	Synonyms   []string
+	Candidates []domain.CNAECandidate // most confident first
+}
+
+// Codes returns the candidate subclasses with at least MinConfidence.
+func (u *Understanding) Codes() []string {
+	var codes []string
+	for _, c := range u.Candidates {
+		if c.Confidence >= MinConfidence {
+			codes = append(codes, c.Code)
+		}
+	}
+	return codes
+}
+
+//go:embed prompts/understand_query.tmpl
+var promptFS embed.FS
+
+var understandPrompt = template.Must(template.ParseFS(promptFS, "prompts/understand_query.tmpl"))
+
+var understandSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"category": map[string]any{"type": "string"},
+		"synonyms": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		"cnaes": map[string]any{
+			"type": "array",
+			"items": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"code":        map[string]any{"type": "string"},
+					"description": map[string]any{"type": "string"},
+					"confidence":  map[string]any{"type": "number"},
+				},
+				"required":             []string{"code", "description", "confidence"},
+				"additionalProperties": false,
+			},
+		},
+	},
+	"required":             []string{"category", "synonyms", "cnaes"},
+	"additionalProperties": false,
+}
+
+// Understand asks the LLM what kind of business the query describes. The
+// prompt carries the rows of the CNAE table related to the query, and the
+// answer is checked against the table: codes it doesn't know are dropped
+// and descriptions are replaced by the official ones (see ground). When the
+// table is empty or nil (reference not seeded) the prompt falls back to the
+// embedded hierarchy and the LLM codes are kept as returned.
+func Understand(ctx context.Context, llm leadsearch.LLMClient, table Table, query string) (*Understanding, error) {
+	var excerpt []domain.CNAE
+	if table != nil {
+		excerpt, _ = table.SearchCNAEs(ctx, groundingKeywords(query), groundingRows)
+	}
+	loaded := len(excerpt) > 0
+	if !loaded {
+		for _, e := range Default().Search(groundingKeywords(query), groundingRows) {
+			excerpt = append(excerpt, domain.CNAE{Code: e.Code, Desc: e.Desc})
+		}
+	}
+
+	var sb strings.Builder
+	if err := understandPrompt.Execute(&sb, struct {
+		Query string
+		Table []domain.CNAE
+	}{query, excerpt}); err != nil {
+		return nil, err
+	}
+
+	content, err := llm.Complete(ctx, leadsearch.LLMRequest{
+		Prompt:      sb.String(),
+		Schema:      understandSchema,
+		SchemaName:  "query_understanding",
+		MaxTokens:   1024,
+		Temperature: 0,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cnae: understand %q: %w", query, err)
+	}
+
+	var out struct {
+		Category string   `json:"category"`
+		Synonyms []string `json:"synonyms"`
+		CNAEs    []struct {
+			Code        string  `json:"code"`
+			Description string  `json:"description"`
+			Confidence  float64 `json:"confidence"`
+		} `json:"cnaes"`
+	}
+	content = strings.TrimSpace(content)
+	content = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(content, "```json"), "```"), "```")
+	if err := json.Unmarshal([]byte(content), &out); err != nil {
+		return nil, fmt.Errorf("cnae: understand %q: invalid JSON: %w", query, err)
+	}
+
+	u := &Understanding{Category: strings.TrimSpace(out.Category), Synonyms: out.Synonyms}
+	seen := make(map[string]bool)
+	for _, c := range out.CNAEs {
+		code := digits(c.Code)
+		if len(code) != 7 || seen[code] {
+			continue
+		}
+		seen[code] = true
+		u.Candidates = append(u.Candidates, domain.CNAECandidate{
+			Code:       code,
+			Desc:       strings.TrimSpace(c.Description),
+			Confidence: min(max(c.Confidence, 0), 1),
+		})
+	}
+	u.Candidates = ground(ctx, table, u.Candidates, loaded)
+	sort.SliceStable(u.Candidates, func(i, j int) bool {
+		return u.Candidates[i].Confidence > u.Candidates[j].Confidence
+	})
+	return u, nil
+}
+
+// ground keeps the candidates found in the CNAE table, with the official
+// description. loaded says the table is known to have rows; otherwise an
+// empty lookup means the reference was never loaded and nothing is dropped.
+func ground(ctx context.Context, table Table, cands []domain.CNAECandidate, loaded bool) []domain.CNAECandidate {
+	if table == nil || len(cands) == 0 {
+		return cands
+	}
+	codes := make([]string, len(cands))
+	for i, c := range cands {
+		codes[i] = c.Code
+	}
+	rows, err := table.CNAEsByCode(ctx, codes)
+	if err != nil || (len(rows) == 0 && !loaded) {
+		return cands
+	}
+	official := make(map[string]string, len(rows))
+	for _, r := range rows {
+		official[digits(r.Code)] = r.Desc
+	}
+	kept := cands[:0]
+	for _, c := range cands {
+		if desc, ok := official[c.Code]; ok {
+			c.Desc = desc
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// groundingKeywords returns the query words (and the synonyms of the
+// taxonomy category it matches, if any) used to pick the table excerpt.
+func groundingKeywords(query string) []string {
+	stopwords := map[string]bool{"loja": true, "lugar": true, "para": true, "onde": true, "perto": true}
+	seen := make(map[string]bool)
+	var kws []string
+	add := func(text string) {
+		for _, w := range strings.Fields(strings.ToLower(text)) {
+			w = strings.Trim(w, ".,;:!?\"'()")
+			// "celulares" → "celular": matches both forms in the descriptions
+			if len(w) > 4 && strings.HasSuffix(w, "s") {
+				w = w[:len(w)-1]
+			}
+			if len(w) < 4 || stopwords[w] || seen[w] {
+				continue
+			}
+			seen[w] = true
+			kws = append(kws, w)
+		}
+	}
+	add(query)
+	if c, ok := taxonomy.Lookup(query); ok {
+		add(c.Name)
+		for _, s := range c.Synonyms {
+			add(s)
+		}
+	}
+	return kws
+}
+
+func digits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
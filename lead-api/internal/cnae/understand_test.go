@@ -0,0 +1,99 @@
+package cnae
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	leadsearch "github.com/lucasfdcampos/find-leads/pkg/leads"
+
+	"github.com/lucasfdcampos/lead-api/internal/domain"
+)
+
+// memTable is a Table over the rows of a Dataset, like the cnaes collection
+// seeded from it.
+type memTable []domain.CNAE
+
+func (t memTable) SearchCNAEs(_ context.Context, keywords []string, limit int) ([]domain.CNAE, error) {
+	var out []domain.CNAE
+	for _, r := range t {
+		if r.Level != Subclass.String() {
+			continue
+		}
+		for _, kw := range keywords {
+			if strings.Contains(strings.ToLower(r.Desc), strings.ToLower(kw)) {
+				out = append(out, r)
+				break
+			}
+		}
+		if len(out) == limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (t memTable) CNAEsByCode(_ context.Context, codes []string) ([]domain.CNAE, error) {
+	want := make(map[string]bool, len(codes))
+	for _, c := range codes {
+		want[c] = true
+	}
+	var out []domain.CNAE
+	for _, r := range t {
+		if want[r.Code] {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func TestUnderstandGroundsOnTable(t *testing.T) {
+	table := memTable(Default().Records())
+	llm := &leadsearch.FakeLLM{Responses: []string{`{
+		"category": "assistência técnica de celular",
+		"synonyms": ["conserto de celular"],
+		"cnaes": [
+			{"code": "9512-6/00", "description": "conserto de celular", "confidence": 0.9},
+			{"code": "9512699", "description": "inventado", "confidence": 0.8},
+			{"code": "9599900", "description": "grupo inexistente", "confidence": 0.7},
+			{"code": "12", "description": "curto demais", "confidence": 0.9}
+		]
+	}`}}
+
+	u, err := Understand(context.Background(), llm, table, "conserto de celular")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(llm.Requests[0].Prompt, "9521500 Reparação e manutenção de equipamentos eletroeletrônicos") {
+		t.Error("prompt without the table excerpt")
+	}
+
+	want := []domain.CNAECandidate{
+		// found: official description
+		{Code: "9512600", Desc: "Reparação e manutenção de equipamentos de comunicação", Confidence: 0.9},
+		// 9512699 and 9599900 are not CNAE subclasses: dropped
+	}
+	if !reflect.DeepEqual(u.Candidates, want) {
+		t.Errorf("Candidates:\n got  %+v\n want %+v", u.Candidates, want)
+	}
+	if got := u.Codes(); !reflect.DeepEqual(got, []string{"9512600"}) {
+		t.Errorf("Codes() = %v", got)
+	}
+}
+
+func TestUnderstandWithoutTable(t *testing.T) {
+	llm := &leadsearch.FakeLLM{Responses: []string{
+		"```json\n{\"category\": \"padaria\", \"synonyms\": [], \"cnaes\": [{\"code\": \"1091102\", \"description\": \"padaria\", \"confidence\": 0.4}]}\n```",
+	}}
+	u, err := Understand(context.Background(), llm, nil, "padaria")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(u.Candidates) != 1 || u.Candidates[0].Desc != "padaria" {
+		t.Errorf("Candidates = %+v, want the LLM answer as returned", u.Candidates)
+	}
+	if len(u.Codes()) != 0 {
+		t.Errorf("Codes() = %v, want none below MinConfidence", u.Codes())
+	}
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // SearchRequest é o corpo da requisição POST /api/v1/search
 type SearchRequest struct {
@@ -8,6 +12,71 @@ type SearchRequest struct {
 	Location        string `json:"location"`
 	EnrichCNPJ      bool   `json:"enrich_cnpj"`
 	EnrichInstagram bool   `json:"enrich_instagram"`
+	EnrichWebsite   bool   `json:"enrich_website"`
+
+	// Busca por raio: mantém só leads a até RadiusKm do centro. Sem Center,
+	// o centro é o da cidade em Location (geocodificada via Nominatim).
+	// Leads sem coordenadas são mantidos.
+	RadiusKm float64   `json:"radius_km,omitempty"`
+	Center   *GeoPoint `json:"center,omitempty"`
+
+	// Busca em várias cidades: Region expande UF ("PR"), mesorregião
+	// ("Norte Central Paranaense", "Norte do Paraná"), microrregião ou região
+	// metropolitana ("RM de Londrina"); ExpandRadiusKm inclui as cidades a até
+	// N km da cidade em Location. Usar um ou outro. Com uma tabela do IBGE
+	// parcial, a resposta vem com PartialCities.
+	Region         string  `json:"region,omitempty"`
+	ExpandRadiusKm float64 `json:"expand_radius_km,omitempty"`
+
+	// Só estabelecimentos abertos agora (OpenNow) ou num horário (OpenAt:
+	// RFC 3339 ou "2006-01-02T15:04" no horário de Brasília). Aplicado sobre
+	// o resultado em cache, por isso fora de Variant. Leads sem horário
+	// reconhecido são mantidos.
+	OpenNow bool   `json:"open_now,omitempty"`
+	OpenAt  string `json:"open_at,omitempty"`
+
+	// Devolve também os leads removidos pelos filtros, com o motivo
+	// (DiscardedLeads). Não muda o resultado, por isso fora de Variant.
+	IncludeDiscarded bool `json:"include_discarded,omitempty"`
+
+	// Regras do usuário (ver internal/rules), ex: `situacao == "ATIVA"`,
+	// `followers >= 500`. Todas precisam ser verdadeiras para o lead ficar.
+	// RulesPreset usa as regras salvas com esse nome pelo tenant, antes das
+	// de Rules. Aplicadas sobre o resultado em cache, por isso fora de Variant.
+	Rules       []string `json:"rules,omitempty"`
+	RulesPreset string   `json:"rules_preset,omitempty"`
+
+	// Fingerprint das listas de exclusão do tenant (ver internal/suppression),
+	// preenchido pela API. Muda o resultado, por isso entra em Variant.
+	Suppression string `json:"-"`
+}
+
+// Variant identifica as opções que mudam o resultado de uma busca além de
+// query/location. Compõe as chaves de cache (Redis e MongoDB).
+func (r SearchRequest) Variant() string {
+	v := fmt.Sprintf("cnpj=%v|ig=%v|web=%v", r.EnrichCNPJ, r.EnrichInstagram, r.EnrichWebsite)
+	if r.RadiusKm > 0 {
+		v += fmt.Sprintf("|r=%g", r.RadiusKm)
+	}
+	if r.Center != nil {
+		v += fmt.Sprintf("|c=%.5f,%.5f", r.Center.Lat, r.Center.Lon)
+	}
+	if r.Region != "" {
+		v += "|region=" + strings.ToLower(strings.TrimSpace(r.Region))
+	}
+	if r.ExpandRadiusKm > 0 {
+		v += fmt.Sprintf("|expand=%g", r.ExpandRadiusKm)
+	}
+	if r.Suppression != "" {
+		v += "|supp=" + r.Suppression
+	}
+	return v
+}
+
+// GeoPoint é uma coordenada geográfica (WGS84).
+type GeoPoint struct {
+	Lat float64 `json:"lat" bson:"lat"`
+	Lon float64 `json:"lon" bson:"lon"`
 }
 
 // Lead é o lead enriquecido retornado pela API
@@ -22,59 +91,196 @@ type Lead struct {
 	Category string `json:"category,omitempty"`
 	Website  string `json:"website,omitempty"`
 	Email    string `json:"email,omitempty"`
-	Source   string `json:"source,omitempty"`
+	Source   string `json:"source,omitempty"` // fontes unidas por "+" (ver Sources)
+
+	// Horário no formato opening_hours do OSM quando reconhecido (ex:
+	// "Mo-Fr 08:00-18:00; Sa 08:00-12:00"), senão como veio da fonte, e o
+	// horário semanal interpretado ("mon" → ["08:00-12:00", "14:00-18:00"]).
+	OpeningHours    string              `json:"opening_hours,omitempty"`
+	OpeningSchedule map[string][]string `json:"opening_schedule,omitempty"`
+
+	// Avaliação (nota e número de avaliações) e faixa de preço das fontes
+	Rating      string `json:"rating,omitempty"`
+	RatingCount int    `json:"rating_count,omitempty"`
+	PriceLevel  string `json:"price_level,omitempty"` // ex: "$$", "R$ 20-50"
+
+	// Todos os telefones distintos (Phone e Phone2 são os dois primeiros) e as
+	// fontes que contribuíram para o lead após a deduplicação
+	Phones  []string `json:"phones,omitempty"`
+	Sources []string `json:"sources,omitempty"`
+
+	// Telefone principal normalizado
+	PhoneE164          string `json:"phone_e164,omitempty"`           // ex: +5543999998888
+	PhoneType          string `json:"phone_type,omitempty"`           // mobile | landline | toll_free | shared_cost
+	PhoneStateMismatch bool   `json:"phone_state_mismatch,omitempty"` // DDD de outra UF
+	WhatsAppLink       string `json:"whatsapp_link,omitempty"`        // https://wa.me/... (WhatsApp do site ou celular)
+
+	// Cidade da busca que encontrou o lead (só em buscas multi-cidade)
+	SearchCity string `json:"search_city,omitempty"`
+
+	// Coordenadas (Overpass, Geoapify, TomTom) e distância ao centro da busca
+	Lat        float64  `json:"lat,omitempty"`
+	Lon        float64  `json:"lon,omitempty"`
+	DistanceKm *float64 `json:"distance_km,omitempty"`
+
+	// Validação do e-mail (sintaxe, MX, descartável, conta de função)
+	EmailStatus string   `json:"email_status,omitempty"` // valid | risky | invalid | unknown
+	EmailFlags  []string `json:"email_flags,omitempty"`  // ex: ["free", "role"]
 
 	// Dados do enriquecimento CNPJ
-	CNPJ         string   `json:"cnpj,omitempty"`
-	RazaoSocial  string   `json:"razao_social,omitempty"`
-	NomeFantasia string   `json:"nome_fantasia,omitempty"`
-	Situacao     string   `json:"situacao,omitempty"`
-	Partners     []string `json:"partners,omitempty"`
-	CNAEMatch    *bool    `json:"cnae_match,omitempty"`
-	CNAEDesc     string   `json:"cnae_desc,omitempty"`
-	Municipio    string   `json:"municipio,omitempty"`
-	UF           string   `json:"uf,omitempty"`
+	CNPJ            string   `json:"cnpj,omitempty"`
+	RazaoSocial     string   `json:"razao_social,omitempty"`
+	NomeFantasia    string   `json:"nome_fantasia,omitempty"`
+	Situacao        string   `json:"situacao,omitempty"`
+	Partners        []string `json:"partners,omitempty"`
+	CNAECode        string   `json:"cnae_code,omitempty"`      // CNAE principal, 7 dígitos
+	CNAESecondary   []string `json:"cnae_secondary,omitempty"` // CNAEs secundários
+	CNAEMatch       *bool    `json:"cnae_match,omitempty"`
+	CNAEMatchedCode string   `json:"cnae_matched_code,omitempty"` // código do lead (principal ou secundário) compatível com a busca
+	CNAEDesc        string   `json:"cnae_desc,omitempty"`
+	Municipio       string   `json:"municipio,omitempty"`
+	UF              string   `json:"uf,omitempty"`
+	IBGECode        string   `json:"ibge_code,omitempty"` // código do Municipio na tabela do IBGE
 
 	// Dados do enriquecimento Instagram
 	Instagram string `json:"instagram,omitempty"`
 	Followers string `json:"followers,omitempty"`
+
+	// Dados do enriquecimento pelo site oficial
+	WhatsApp    string            `json:"whatsapp,omitempty"`
+	SocialLinks map[string]string `json:"social_links,omitempty"`
+
+	// Lead vindo só de fontes de IA: as evidências que o mantiveram e as
+	// correções feitas (ex: "telefone +5543999998888 também em Solutudo")
+	Unverified bool     `json:"unverified,omitempty"`
+	Checks     []string `json:"checks,omitempty"`
+
+	// Provenance indica a origem dos campos vindos do merge entre fontes ou do
+	// enriquecimento (ex: "address" → "TomTom Places", "email" → "website:search").
+	Provenance map[string]string `json:"provenance,omitempty"`
 }
 
 // SearchResponse é a resposta da API
 type SearchResponse struct {
-	Query         string    `json:"query"`
-	Location      string    `json:"location"`
-	Total         int       `json:"total"`
-	Discarded     int       `json:"discarded,omitempty"` // leads filtrados por cidade/CNAE
-	Cached        bool      `json:"cached"`
-	SearchID      string    `json:"search_id,omitempty"`
-	CNAEHintCodes []string  `json:"cnae_hint_codes,omitempty"`
-	StartedAt     time.Time `json:"started_at"`
-	DurationMs    int64     `json:"duration_ms"`
-	Leads         []Lead    `json:"leads"`
+	Query         string         `json:"query"`
+	Location      string         `json:"location"`
+	Total         int            `json:"total"`
+	Discarded     int            `json:"discarded,omitempty"`    // leads filtrados por cidade/CNAE
+	DiscardedBy   map[string]int `json:"discarded_by,omitempty"` // descartes por filtro (ex: "category" → 3)
+	Suppressed    int            `json:"suppressed,omitempty"`   // leads removidos pelas listas de exclusão
+	Cached        bool           `json:"cached"`
+	SearchID      string         `json:"search_id,omitempty"`
+	CNAEHintCodes []string       `json:"cnae_hint_codes,omitempty"`
+	Center        *GeoPoint      `json:"center,omitempty"`         // centro usado no cálculo de distância
+	RadiusKm      float64        `json:"radius_km,omitempty"`      // raio aplicado
+	Cities        []CityCount    `json:"cities,omitempty"`         // contagem por cidade (busca multi-cidade)
+	PartialCities bool           `json:"partial_cities,omitempty"` // região/raio expandidos sobre a tabela parcial do IBGE: faltam cidades
+	OpenAt        *time.Time     `json:"open_at,omitempty"`        // horário usado pelo filtro open_now/open_at
+	Merges        []Merge        `json:"merges,omitempty"`         // leads unidos na deduplicação
+	AIDiscards    []AIDiscard    `json:"ai_discards,omitempty"`    // leads de IA sem corroboração
+	StartedAt     time.Time      `json:"started_at"`
+	DurationMs    int64          `json:"duration_ms"`
+	Leads         []Lead         `json:"leads"`
+
+	// Leads removidos pelos filtros (só com include_discarded)
+	DiscardedLeads []DiscardedLead `json:"discarded_leads,omitempty"`
+}
+
+// Discard explica por que um filtro removeu um lead.
+type Discard struct {
+	Filter  string            `bson:"filter"            json:"filter"`            // name_relevance | radius | location | category | open_at | rules | suppression
+	Reason  string            `bson:"reason"            json:"reason"`            // código do motivo (ex: "cnae_mismatch")
+	Details map[string]string `bson:"details,omitempty" json:"details,omitempty"` // ex: {"cnae_code": "5611201", "compatible": "4781"}
+}
+
+// DiscardedLead é um lead removido por um filtro, com o motivo.
+type DiscardedLead struct {
+	Lead    Lead `bson:"lead" json:"lead"`
+	Discard `bson:",inline"`
+}
+
+// RulePreset é um conjunto nomeado de regras de filtro de um tenant
+// (collection: rule_presets, único por tenant + name).
+type RulePreset struct {
+	Tenant      string    `bson:"tenant"                json:"tenant"`
+	Name        string    `bson:"name"                  json:"name"`
+	Description string    `bson:"description,omitempty" json:"description,omitempty"`
+	Rules       []string  `bson:"rules"                 json:"rules"`
+	UpdatedAt   time.Time `bson:"updated_at"            json:"updated_at"`
+}
+
+// SuppressionList é uma lista de exclusão de um tenant (collection:
+// suppression_lists, única por tenant + name), ex: clientes, concorrentes,
+// pedidos de não contato. Leads que batem com qualquer entrada são removidos
+// das buscas do tenant.
+type SuppressionList struct {
+	Tenant       string    `bson:"tenant"                  json:"tenant"`
+	Name         string    `bson:"name"                    json:"name"`
+	Description  string    `bson:"description,omitempty"   json:"description,omitempty"`
+	CNPJRoots    []string  `bson:"cnpj_roots,omitempty"    json:"cnpj_roots,omitempty"`    // 8 primeiros dígitos do CNPJ (todas as filiais)
+	Phones       []string  `bson:"phones,omitempty"        json:"phones,omitempty"`        // E.164
+	Instagram    []string  `bson:"instagram,omitempty"     json:"instagram,omitempty"`     // handles, sem "@"
+	Domains      []string  `bson:"domains,omitempty"       json:"domains,omitempty"`       // ex: "loja.com.br" (e subdomínios)
+	NamePatterns []string  `bson:"name_patterns,omitempty" json:"name_patterns,omitempty"` // regex RE2, sem diferenciar maiúsculas
+	UpdatedAt    time.Time `bson:"updated_at"              json:"updated_at"`
+}
+
+// CityCount resume uma cidade de uma busca multi-cidade.
+type CityCount struct {
+	City  string `bson:"city"  json:"city"`
+	UF    string `bson:"uf"    json:"uf"`
+	Found int    `bson:"found" json:"found"` // leads descobertos (após dedup entre cidades)
+	Leads int    `bson:"leads" json:"leads"` // leads na resposta final
+}
+
+// Merge explica a união de dois leads duplicados na descoberta.
+type Merge struct {
+	Kept    string   `bson:"kept"              json:"kept"`              // lead mantido (nome antes do merge)
+	Merged  string   `bson:"merged"            json:"merged"`            // lead absorvido
+	Sources []string `bson:"sources"           json:"sources"`           // fontes de kept e merged
+	Score   float64  `bson:"score"             json:"score"`             // similaridade dos nomes (0–1)
+	Reasons []string `bson:"reasons,omitempty" json:"reasons,omitempty"` // ex: ["telefone +5543999998888", "nome 0.94"]
+}
+
+// AIDiscard explica o descarte de um lead de IA não corroborado.
+type AIDiscard struct {
+	Name   string `bson:"name"   json:"name"`
+	Source string `bson:"source" json:"source"`
+	Reason string `bson:"reason" json:"reason"`
 }
 
 // StoredSearch é o documento de metadados da busca salvo no MongoDB (collection: searches).
 // Os leads ficam na collection separada "results", referenciados pelo SearchID.
 type StoredSearch struct {
-	ID              string    `bson:"_id,omitempty"        json:"id"`
-	Query           string    `bson:"query"                json:"query"`
-	Location        string    `bson:"location"             json:"location"`
-	EnrichCNPJ      bool      `bson:"enrich_cnpj"          json:"enrich_cnpj"`
-	EnrichInstagram bool      `bson:"enrich_instagram"     json:"enrich_instagram"`
-	Total           int       `bson:"total"                json:"total"`
-	Discarded       int       `bson:"discarded"            json:"discarded"`
-	DurationMs      int64     `bson:"duration_ms"          json:"duration_ms"`
-	CNAEHintCodes   []string  `bson:"cnae_hint_codes,omitempty" json:"cnae_hint_codes,omitempty"`
-	CreatedAt       time.Time `bson:"created_at"           json:"created_at"`
-	ExpiresAt       time.Time `bson:"expires_at"           json:"expires_at"`
+	ID              string         `bson:"_id,omitempty"        json:"id"`
+	Query           string         `bson:"query"                json:"query"`
+	Location        string         `bson:"location"             json:"location"`
+	EnrichCNPJ      bool           `bson:"enrich_cnpj"          json:"enrich_cnpj"`
+	EnrichInstagram bool           `bson:"enrich_instagram"     json:"enrich_instagram"`
+	EnrichWebsite   bool           `bson:"enrich_website"       json:"enrich_website"`
+	RadiusKm        float64        `bson:"radius_km,omitempty"  json:"radius_km,omitempty"`
+	Center          *GeoPoint      `bson:"center,omitempty"     json:"center,omitempty"`
+	Region          string         `bson:"region,omitempty"     json:"region,omitempty"`
+	Cities          []CityCount    `bson:"cities,omitempty"   json:"cities,omitempty"`
+	Variant         string         `bson:"variant"              json:"variant"` // SearchRequest.Variant()
+	Total           int            `bson:"total"                json:"total"`
+	Discarded       int            `bson:"discarded"            json:"discarded"`
+	DiscardedBy     map[string]int `bson:"discarded_by,omitempty" json:"discarded_by,omitempty"`
+	DurationMs      int64          `bson:"duration_ms"          json:"duration_ms"`
+	CNAEHintCodes   []string       `bson:"cnae_hint_codes,omitempty" json:"cnae_hint_codes,omitempty"`
+	Merges          []Merge        `bson:"merges,omitempty"     json:"merges,omitempty"`
+	AIDiscards      []AIDiscard    `bson:"ai_discards,omitempty" json:"ai_discards,omitempty"`
+	CreatedAt       time.Time      `bson:"created_at"           json:"created_at"`
+	ExpiresAt       time.Time      `bson:"expires_at"           json:"expires_at"`
 }
 
 // StoredResult é um lead individual vinculado a uma busca (collection: results).
+// Leads removidos pelos filtros também são salvos, com Discard preenchido.
 type StoredResult struct {
 	ID        string    `bson:"_id,omitempty"  json:"id"`
 	SearchID  string    `bson:"search_id"      json:"search_id"`
 	Lead      Lead      `bson:"lead"           json:"lead"`
+	Discard   *Discard  `bson:"discard,omitempty" json:"discard,omitempty"`
 	CreatedAt time.Time `bson:"created_at"     json:"created_at"`
 	ExpiresAt time.Time `bson:"expires_at"     json:"expires_at"`
 }
@@ -85,6 +291,29 @@ type CNAEHintDoc struct {
 	Codes     []string  `bson:"codes"      json:"codes"`
 	Snippet   string    `bson:"snippet"    json:"snippet"`
 	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+
+	// Preenchidos quando a query foi interpretada pelo LLM (Via = "llm"; a
+	// descoberta por buscadores usa "search")
+	Via        string          `bson:"via,omitempty"        json:"via,omitempty"`
+	Category   string          `bson:"category,omitempty"   json:"category,omitempty"` // categoria normalizada (ex: "assistência técnica de celular")
+	Synonyms   []string        `bson:"synonyms,omitempty"   json:"synonyms,omitempty"`
+	Candidates []CNAECandidate `bson:"candidates,omitempty" json:"candidates,omitempty"`
+}
+
+// CNAECandidate é uma subclasse CNAE proposta para a query.
+type CNAECandidate struct {
+	Code       string  `bson:"code"       json:"code"` // subclasse, só dígitos (ex: "9512600")
+	Desc       string  `bson:"desc"       json:"desc"`
+	Confidence float64 `bson:"confidence" json:"confidence"` // 0–1
+}
+
+// CNAE é um nó da hierarquia CNAE 2.3 (collection: cnaes, _id = codigo).
+type CNAE struct {
+	Code      string `bson:"codigo"                     json:"codigo"`                     // só dígitos (ex: "4781400"); letra para seções
+	Formatted string `bson:"codigo_formatado,omitempty" json:"codigo_formatado,omitempty"` // notação oficial (ex: "4781-4/00")
+	Level     string `bson:"nivel,omitempty"            json:"nivel,omitempty"`            // secao, divisao, grupo, classe ou subclasse
+	Desc      string `bson:"descricao"                  json:"descricao"`
+	Parent    string `bson:"pai,omitempty"              json:"pai,omitempty"` // codigo do nó acima
 }
 
 // CachedEnrichment é o cache por lead individual no MongoDB (collection: enrichments)
@@ -0,0 +1,303 @@
+// Package emailcheck validates e-mail addresses collected by the scrapers.
+//
+// Checks, in order:
+//
+//  1. Syntax   – local part + domain (RFC 5322 subset, no quoted strings)
+//  2. Domain   – disposable providers, free-mail providers
+//  3. Local    – role accounts (contato@, vendas@, ...)
+//  4. DNS      – MX records (falls back to A/AAAA per RFC 5321 §5.1)
+//
+// DNS is resolved through the Resolver interface, so tests can plug a fake
+// resolver and run without network access. *net.Resolver satisfies it.
+package emailcheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Status is the deliverability verdict for an address.
+type Status string
+
+const (
+	StatusValid   Status = "valid"   // syntax ok and the domain accepts mail
+	StatusRisky   Status = "risky"   // deliverable, but disposable provider
+	StatusInvalid Status = "invalid" // bad syntax or domain without mail servers
+	StatusUnknown Status = "unknown" // DNS lookup failed (timeout, SERVFAIL)
+)
+
+// Flags attached to a Result. A result may carry several flags.
+const (
+	FlagSyntax     = "syntax"      // malformed address
+	FlagNoMX       = "no_mx"       // domain has no MX nor A/AAAA records, or a null MX
+	FlagImplicitMX = "implicit_mx" // no MX, but the domain resolves (mail goes to A/AAAA)
+	FlagDNSError   = "dns_error"   // lookup failed for reasons other than NXDOMAIN
+	FlagDisposable = "disposable"  // throwaway mailbox provider
+	FlagFree       = "free"        // free-mail provider (gmail, hotmail, ...)
+	FlagRole       = "role"        // role account (contato@, vendas@, ...)
+)
+
+// Result is the outcome of validating one address.
+type Result struct {
+	Email  string   `json:"email"`
+	Status Status   `json:"status"`
+	Flags  []string `json:"flags,omitempty"`
+	MX     string   `json:"mx,omitempty"` // preferred mail exchanger, when found
+}
+
+// Has reports whether the result carries the given flag.
+func (r Result) Has(flag string) bool {
+	for _, f := range r.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolver is the subset of *net.Resolver used by the validator.
+type Resolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// Validator checks addresses and memoizes DNS verdicts per domain.
+// It is safe for concurrent use.
+type Validator struct {
+	resolver Resolver
+	timeout  time.Duration
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	domains map[string]domainVerdict
+}
+
+type domainVerdict struct {
+	mx      string
+	flags   []string
+	status  Status
+	checked time.Time
+}
+
+// New creates a Validator. A nil resolver uses net.DefaultResolver.
+func New(r Resolver) *Validator {
+	if r == nil {
+		r = net.DefaultResolver
+	}
+	return &Validator{
+		resolver: r,
+		timeout:  5 * time.Second,
+		ttl:      6 * time.Hour,
+		domains:  make(map[string]domainVerdict),
+	}
+}
+
+// reEmail accepts the dot-atom form used by virtually every real mailbox.
+var reEmail = regexp.MustCompile(`^[a-z0-9!#$%&'*+/=?^_{|}~-]+(\.[a-z0-9!#$%&'*+/=?^_{|}~-]+)*@([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// Normalize trims, lowercases and strips a leading "mailto:".
+func Normalize(email string) string {
+	email = strings.TrimSpace(email)
+	if len(email) >= 7 && strings.EqualFold(email[:7], "mailto:") {
+		email = email[7:]
+	}
+	if i := strings.IndexByte(email, '?'); i >= 0 {
+		email = email[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// ValidSyntax reports whether email is a well-formed address.
+func ValidSyntax(email string) bool {
+	email = Normalize(email)
+	if len(email) > 254 {
+		return false
+	}
+	at := strings.LastIndexByte(email, '@')
+	if at < 1 || at > 64 {
+		return false
+	}
+	return reEmail.MatchString(email)
+}
+
+// Check validates a single address.
+func (v *Validator) Check(ctx context.Context, email string) Result {
+	email = Normalize(email)
+	res := Result{Email: email}
+
+	if !ValidSyntax(email) {
+		res.Status = StatusInvalid
+		res.Flags = []string{FlagSyntax}
+		return res
+	}
+
+	at := strings.LastIndexByte(email, '@')
+	local, domain := email[:at], email[at+1:]
+
+	if IsDisposable(domain) {
+		res.Flags = append(res.Flags, FlagDisposable)
+	}
+	if IsFreeMail(domain) {
+		res.Flags = append(res.Flags, FlagFree)
+	}
+	if IsRoleAccount(local) {
+		res.Flags = append(res.Flags, FlagRole)
+	}
+
+	dv := v.lookupDomain(ctx, domain)
+	res.MX = dv.mx
+	res.Flags = append(res.Flags, dv.flags...)
+	res.Status = dv.status
+	if res.Status == StatusValid && res.Has(FlagDisposable) {
+		res.Status = StatusRisky
+	}
+	return res
+}
+
+// lookupDomain resolves the mail servers for domain, using the memo when fresh.
+// Free-mail domains skip DNS: they are known to accept mail.
+func (v *Validator) lookupDomain(ctx context.Context, domain string) domainVerdict {
+	if IsFreeMail(domain) {
+		return domainVerdict{status: StatusValid}
+	}
+
+	v.mu.Lock()
+	if dv, ok := v.domains[domain]; ok && time.Since(dv.checked) < v.ttl {
+		v.mu.Unlock()
+		return dv
+	}
+	v.mu.Unlock()
+
+	dv := v.resolve(ctx, domain)
+	dv.checked = time.Now()
+
+	// Transient failures are not memoized so a later call can retry.
+	if dv.status != StatusUnknown {
+		v.mu.Lock()
+		v.domains[domain] = dv
+		v.mu.Unlock()
+	}
+	return dv
+}
+
+func (v *Validator) resolve(ctx context.Context, domain string) domainVerdict {
+	tctx, cancel := context.WithTimeout(ctx, v.timeout)
+	defer cancel()
+
+	mxs, err := v.resolver.LookupMX(tctx, domain)
+	if err == nil && len(mxs) > 0 {
+		best := mxs[0]
+		for _, mx := range mxs[1:] {
+			if mx.Pref < best.Pref {
+				best = mx
+			}
+		}
+		host := strings.TrimSuffix(best.Host, ".")
+		// Null MX (RFC 7505): the domain explicitly accepts no mail.
+		if host == "" {
+			return domainVerdict{status: StatusInvalid, flags: []string{FlagNoMX}}
+		}
+		return domainVerdict{status: StatusValid, mx: host}
+	}
+	if err != nil && !isNotFound(err) {
+		return domainVerdict{status: StatusUnknown, flags: []string{FlagDNSError}}
+	}
+
+	// No MX: RFC 5321 implicit MX — deliver to the domain's A/AAAA.
+	addrs, err := v.resolver.LookupHost(tctx, domain)
+	if err == nil && len(addrs) > 0 {
+		return domainVerdict{status: StatusValid, mx: domain, flags: []string{FlagImplicitMX}}
+	}
+	if err != nil && !isNotFound(err) {
+		return domainVerdict{status: StatusUnknown, flags: []string{FlagDNSError}}
+	}
+	return domainVerdict{status: StatusInvalid, flags: []string{FlagNoMX}}
+}
+
+// isNotFound reports whether err means the name has no such records.
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsNotFound
+	}
+	return false
+}
+
+// ─── Domain and local-part lists ──────────────────────────────────────────────
+
+// disposableDomains are throwaway mailbox providers.
+var disposableDomains = map[string]bool{
+	"mailinator.com": true, "guerrillamail.com": true, "guerrillamail.net": true,
+	"sharklasers.com": true, "10minutemail.com": true, "10minutemail.net": true,
+	"temp-mail.org": true, "tempmail.com": true, "tempmail.net": true,
+	"throwawaymail.com": true, "yopmail.com": true, "yopmail.net": true,
+	"getnada.com": true, "nada.email": true, "trashmail.com": true,
+	"dispostable.com": true, "maildrop.cc": true, "mailnesia.com": true,
+	"fakeinbox.com": true, "emailondeck.com": true, "mintemail.com": true,
+	"mohmal.com": true, "tempr.email": true, "discard.email": true,
+	"spamgourmet.com": true, "mytemp.email": true, "burnermail.io": true,
+	"moakt.com": true, "tempail.com": true, "emailfake.com": true,
+}
+
+// freeMailDomains are free-mail providers: the address belongs to a person or
+// a small business without its own domain.
+var freeMailDomains = map[string]bool{
+	"gmail.com": true, "googlemail.com": true,
+	"hotmail.com": true, "hotmail.com.br": true, "outlook.com": true,
+	"outlook.com.br": true, "live.com": true, "msn.com": true,
+	"yahoo.com": true, "yahoo.com.br": true, "ymail.com": true,
+	"icloud.com": true, "me.com": true, "mac.com": true,
+	"uol.com.br": true, "bol.com.br": true, "terra.com.br": true,
+	"ig.com.br": true, "r7.com": true, "globo.com": true, "globomail.com": true,
+	"zipmail.com.br": true, "oi.com.br": true, "aol.com": true,
+	"protonmail.com": true, "proton.me": true, "gmx.com": true,
+	"zoho.com": true, "yandex.com": true,
+}
+
+// roleLocalParts are mailboxes that belong to a function, not a person.
+var roleLocalParts = map[string]bool{
+	"contato": true, "contact": true, "contatos": true, "faleconosco": true,
+	"vendas": true, "sales": true, "comercial": true, "orcamento": true,
+	"atendimento": true, "sac": true, "suporte": true, "support": true,
+	"info": true, "informacoes": true, "adm": true, "admin": true,
+	"administrativo": true, "administracao": true, "financeiro": true,
+	"cobranca": true, "compras": true, "rh": true, "recrutamento": true,
+	"marketing": true, "imprensa": true, "juridico": true, "fiscal": true,
+	"contabilidade": true, "diretoria": true, "secretaria": true,
+	"recepcao": true, "loja": true, "pedidos": true, "reservas": true,
+	"agendamento": true, "ouvidoria": true, "noreply": true, "no-reply": true,
+	"naoresponda": true, "nao-responda": true, "postmaster": true,
+	"webmaster": true, "hostmaster": true, "abuse": true, "office": true,
+	"hello": true, "ola": true, "geral": true,
+}
+
+// IsDisposable reports whether domain is a throwaway mailbox provider.
+func IsDisposable(domain string) bool {
+	return disposableDomains[strings.ToLower(domain)]
+}
+
+// IsFreeMail reports whether domain is a free-mail provider.
+func IsFreeMail(domain string) bool {
+	return freeMailDomains[strings.ToLower(domain)]
+}
+
+// IsRoleAccount reports whether the local part is a role mailbox.
+// Tags ("vendas+site") and separators ("vendas.loja1") are ignored.
+func IsRoleAccount(local string) bool {
+	local = strings.ToLower(local)
+	if i := strings.IndexByte(local, '+'); i >= 0 {
+		local = local[:i]
+	}
+	if roleLocalParts[local] {
+		return true
+	}
+	if i := strings.IndexAny(local, "._-"); i > 0 {
+		return roleLocalParts[local[:i]]
+	}
+	return false
+}
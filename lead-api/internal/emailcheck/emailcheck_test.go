@@ -0,0 +1,195 @@
+package emailcheck
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+)
+
+// fakeResolver answers from fixed tables and counts lookups per domain.
+// Domains present in the error maps fail with that error instead.
+type fakeResolver struct {
+	mx      map[string][]*net.MX
+	hosts   map[string][]string
+	mxErr   map[string]error
+	hostErr map[string]error
+	calls   map[string]int
+}
+
+func (f *fakeResolver) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	if f.calls == nil {
+		f.calls = make(map[string]int)
+	}
+	f.calls[name]++
+	if err := f.mxErr[name]; err != nil {
+		return nil, err
+	}
+	if mxs, ok := f.mx[name]; ok {
+		return mxs, nil
+	}
+	return nil, notFound(name)
+}
+
+func (f *fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if err := f.hostErr[host]; err != nil {
+		return nil, err
+	}
+	if addrs, ok := f.hosts[host]; ok {
+		return addrs, nil
+	}
+	return nil, notFound(host)
+}
+
+func notFound(name string) error {
+	return &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func servFail(name string) error {
+	return &net.DNSError{Err: "server misbehaving", Name: name, IsTemporary: true}
+}
+
+func TestCheck(t *testing.T) {
+	r := &fakeResolver{
+		mx: map[string][]*net.MX{
+			"padaria.com.br": {{Host: "mx.padaria.com.br.", Pref: 10}},
+			"multi.com.br": {
+				{Host: "backup.multi.com.br.", Pref: 20},
+				{Host: "primary.multi.com.br.", Pref: 5},
+				{Host: "other.multi.com.br.", Pref: 10},
+			},
+			"nullmx.com.br":  {{Host: ".", Pref: 0}},
+			"mailinator.com": {{Host: "mail.mailinator.com.", Pref: 10}},
+		},
+		hosts: map[string][]string{
+			"implicit.com.br": {"203.0.113.10"},
+		},
+		mxErr: map[string]error{
+			"servfail.com.br": servFail("servfail.com.br"),
+		},
+		hostErr: map[string]error{
+			"hostfail.com.br": servFail("hostfail.com.br"),
+		},
+	}
+	v := New(r)
+
+	tests := []struct {
+		email  string
+		status Status
+		mx     string
+		flags  []string
+	}{
+		{"joao@padaria.com.br", StatusValid, "mx.padaria.com.br", nil},
+		{"Mailto:Joao@Padaria.com.br?subject=oi", StatusValid, "mx.padaria.com.br", nil},
+		{"joao@multi.com.br", StatusValid, "primary.multi.com.br", nil},
+		{"joao@nullmx.com.br", StatusInvalid, "", []string{FlagNoMX}},
+		{"joao@implicit.com.br", StatusValid, "implicit.com.br", []string{FlagImplicitMX}},
+		{"joao@nxdomain.com.br", StatusInvalid, "", []string{FlagNoMX}},
+		{"joao@servfail.com.br", StatusUnknown, "", []string{FlagDNSError}},
+		{"joao@hostfail.com.br", StatusUnknown, "", []string{FlagDNSError}},
+		{"joao@mailinator.com", StatusRisky, "mail.mailinator.com", []string{FlagDisposable}},
+		{"maria@gmail.com", StatusValid, "", []string{FlagFree}},
+		{"vendas@padaria.com.br", StatusValid, "mx.padaria.com.br", []string{FlagRole}},
+		{"joao.padaria.com.br", StatusInvalid, "", []string{FlagSyntax}},
+		{"joao@@padaria.com.br", StatusInvalid, "", []string{FlagSyntax}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			got := v.Check(context.Background(), tt.email)
+			if got.Status != tt.status {
+				t.Errorf("Status = %q, want %q", got.Status, tt.status)
+			}
+			if got.MX != tt.mx {
+				t.Errorf("MX = %q, want %q", got.MX, tt.mx)
+			}
+			if !reflect.DeepEqual(got.Flags, tt.flags) {
+				t.Errorf("Flags = %v, want %v", got.Flags, tt.flags)
+			}
+		})
+	}
+}
+
+func TestCheckMemoizesDomainVerdicts(t *testing.T) {
+	r := &fakeResolver{
+		mx: map[string][]*net.MX{
+			"padaria.com.br": {{Host: "mx.padaria.com.br.", Pref: 10}},
+		},
+		mxErr: map[string]error{
+			"servfail.com.br": servFail("servfail.com.br"),
+		},
+	}
+	v := New(r)
+	ctx := context.Background()
+
+	for _, email := range []string{"a@padaria.com.br", "b@padaria.com.br", "c@padaria.com.br"} {
+		v.Check(ctx, email)
+	}
+	if n := r.calls["padaria.com.br"]; n != 1 {
+		t.Errorf("padaria.com.br resolved %d times, want 1", n)
+	}
+
+	// NXDOMAIN is a definitive answer and is memoized too.
+	v.Check(ctx, "a@nxdomain.com.br")
+	v.Check(ctx, "b@nxdomain.com.br")
+	if n := r.calls["nxdomain.com.br"]; n != 1 {
+		t.Errorf("nxdomain.com.br resolved %d times, want 1", n)
+	}
+
+	// SERVFAIL is transient: every call retries.
+	v.Check(ctx, "a@servfail.com.br")
+	v.Check(ctx, "b@servfail.com.br")
+	if n := r.calls["servfail.com.br"]; n != 2 {
+		t.Errorf("servfail.com.br resolved %d times, want 2", n)
+	}
+
+	// Free-mail domains never hit DNS.
+	v.Check(ctx, "maria@gmail.com")
+	if n := r.calls["gmail.com"]; n != 0 {
+		t.Errorf("gmail.com resolved %d times, want 0", n)
+	}
+}
+
+func TestIsRoleAccount(t *testing.T) {
+	tests := []struct {
+		local string
+		want  bool
+	}{
+		{"contato", true},
+		{"Vendas", true},
+		{"vendas+site", true},
+		{"vendas.loja1", true},
+		{"suporte_ti", true},
+		{"no-reply", true},
+		{"contato+orcamento.2024", true},
+		{"joao", false},
+		{"joao.vendas", false},
+		{"vendasonline", false},
+		{".vendas", false},
+	}
+	for _, tt := range tests {
+		if got := IsRoleAccount(tt.local); got != tt.want {
+			t.Errorf("IsRoleAccount(%q) = %v, want %v", tt.local, got, tt.want)
+		}
+	}
+}
+
+func TestValidSyntax(t *testing.T) {
+	tests := []struct {
+		email string
+		want  bool
+	}{
+		{"joao@padaria.com.br", true},
+		{"joao.silva+leads@padaria.com.br", true},
+		{" MAILTO:Joao@Padaria.COM.BR ", true},
+		{"joao@padaria", false},
+		{"@padaria.com.br", false},
+		{"joao..silva@padaria.com.br", false},
+		{"joao@-padaria.com.br", false},
+		{"joão@padaria.com.br", false},
+	}
+	for _, tt := range tests {
+		if got := ValidSyntax(tt.email); got != tt.want {
+			t.Errorf("ValidSyntax(%q) = %v, want %v", tt.email, got, tt.want)
+		}
+	}
+}
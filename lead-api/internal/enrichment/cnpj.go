@@ -1,4 +1,4 @@
-// Package enrichment provides per-lead CNPJ and Instagram enrichment logic.
+// Package enrichment provides per-lead CNPJ, Instagram and website enrichment logic.
 package enrichment
 
 import (
@@ -16,16 +16,18 @@ import (
 
 // CNPJResult holds CNPJ enrichment data for a single lead.
 type CNPJResult struct {
-	CNPJ         string
-	RazaoSocial  string
-	NomeFantasia string
-	Situacao     string
-	Partners     []string
-	CNAECode     string
-	CNAEDesc     string
-	CNAEMatch    bool
-	Municipio    string
-	UF           string
+	CNPJ          string
+	RazaoSocial   string
+	NomeFantasia  string
+	Situacao      string
+	Partners      []string
+	CNAECode      string
+	CNAEDesc      string
+	CNAESecondary []string // secondary CNAE codes (7 digits)
+	CNAEMatch     bool     // primary or a secondary code compatible with the query
+	Municipio     string
+	UF            string
+	Email         string // e-mail do cadastro na Receita
 }
 
 // EnrichCNPJ looks up and enriches CNPJ data for a given lead name + city.
@@ -44,18 +46,20 @@ func EnrichCNPJ(
 	// L1 – Redis
 	if rdb != nil {
 		if cached, err := rdb.GetEnrichment(ctx, cacheKey); err == nil && cached != nil && cached.CNPJ != "" {
-			match := cnae.IsCompatible(query, cached.CNAECode)
+			match := cnae.IsCompatible(query, append([]string{cached.CNAECode}, cached.CNAESecondary...)...)
 			return &CNPJResult{
-				CNPJ:         cached.CNPJ,
-				RazaoSocial:  cached.RazaoSocial,
-				NomeFantasia: cached.NomeFantasia,
-				Situacao:     cached.Situacao,
-				Partners:     cached.Partners,
-				CNAECode:     cached.CNAECode,
-				CNAEDesc:     cached.CNAEDesc,
-				CNAEMatch:    match,
-				Municipio:    cached.Municipio,
-				UF:           cached.UF,
+				CNPJ:          cached.CNPJ,
+				RazaoSocial:   cached.RazaoSocial,
+				NomeFantasia:  cached.NomeFantasia,
+				Situacao:      cached.Situacao,
+				Partners:      cached.Partners,
+				CNAECode:      cached.CNAECode,
+				CNAEDesc:      cached.CNAEDesc,
+				CNAESecondary: cached.CNAESecondary,
+				CNAEMatch:     match,
+				Municipio:     cached.Municipio,
+				UF:            cached.UF,
+				Email:         cached.Email,
 			}, nil
 		}
 	}
@@ -63,32 +67,36 @@ func EnrichCNPJ(
 	// L2 – MongoDB
 	if mdb != nil {
 		if cached, err := mdb.GetEnrichment(ctx, cacheKey); err == nil && cached != nil && cached.CNPJ != "" {
-			match := cnae.IsCompatible(query, cached.CNAECode)
+			match := cnae.IsCompatible(query, append([]string{cached.CNAECode}, cached.CNAESecondary...)...)
 			// Warm Redis
 			if rdb != nil {
 				_ = rdb.SetEnrichment(ctx, cacheKey, &cache.EnrichedLead{
-					CNPJ:         cached.CNPJ,
-					RazaoSocial:  cached.RazaoSocial,
-					NomeFantasia: cached.NomeFantasia,
-					Situacao:     cached.Situacao,
-					Partners:     cached.Partners,
-					CNAECode:     cached.CNAECode,
-					CNAEDesc:     cached.CNAEDesc,
-					Municipio:    cached.Municipio,
-					UF:           cached.UF,
+					CNPJ:          cached.CNPJ,
+					RazaoSocial:   cached.RazaoSocial,
+					NomeFantasia:  cached.NomeFantasia,
+					Situacao:      cached.Situacao,
+					Partners:      cached.Partners,
+					CNAECode:      cached.CNAECode,
+					CNAEDesc:      cached.CNAEDesc,
+					CNAESecondary: cached.CNAESecondary,
+					Municipio:     cached.Municipio,
+					UF:            cached.UF,
+					Email:         cached.Email,
 				})
 			}
 			return &CNPJResult{
-				CNPJ:         cached.CNPJ,
-				RazaoSocial:  cached.RazaoSocial,
-				NomeFantasia: cached.NomeFantasia,
-				Situacao:     cached.Situacao,
-				Partners:     cached.Partners,
-				CNAECode:     cached.CNAECode,
-				CNAEDesc:     cached.CNAEDesc,
-				CNAEMatch:    match,
-				Municipio:    cached.Municipio,
-				UF:           cached.UF,
+				CNPJ:          cached.CNPJ,
+				RazaoSocial:   cached.RazaoSocial,
+				NomeFantasia:  cached.NomeFantasia,
+				Situacao:      cached.Situacao,
+				Partners:      cached.Partners,
+				CNAECode:      cached.CNAECode,
+				CNAEDesc:      cached.CNAEDesc,
+				CNAESecondary: cached.CNAESecondary,
+				CNAEMatch:     match,
+				Municipio:     cached.Municipio,
+				UF:            cached.UF,
+				Email:         cached.Email,
 			}, nil
 		}
 	}
@@ -119,45 +127,51 @@ func EnrichCNPJ(
 	cnaeCode := strings.TrimSpace(result.CNPJ.CNAE)
 
 	out := &CNPJResult{
-		CNPJ:         result.CNPJ.Formatted,
-		RazaoSocial:  result.CNPJ.RazaoSocial,
-		NomeFantasia: result.CNPJ.NomeFantasia,
-		Situacao:     result.CNPJ.Situacao,
-		Partners:     result.CNPJ.Socios,
-		CNAECode:     cnaeCode,
-		CNAEDesc:     result.CNPJ.CNAEDesc,
-		CNAEMatch:    cnae.IsCompatible(query, cnaeCode),
-		Municipio:    result.CNPJ.Municipio,
-		UF:           result.CNPJ.UF,
+		CNPJ:          result.CNPJ.Formatted,
+		RazaoSocial:   result.CNPJ.RazaoSocial,
+		NomeFantasia:  result.CNPJ.NomeFantasia,
+		Situacao:      result.CNPJ.Situacao,
+		Partners:      result.CNPJ.Socios,
+		CNAECode:      cnaeCode,
+		CNAEDesc:      result.CNPJ.CNAEDesc,
+		CNAESecondary: result.CNPJ.CNAEsSecundarios,
+		CNAEMatch:     cnae.IsCompatible(query, append([]string{cnaeCode}, result.CNPJ.CNAEsSecundarios...)...),
+		Municipio:     result.CNPJ.Municipio,
+		UF:            result.CNPJ.UF,
+		Email:         result.CNPJ.Email,
 	}
 
 	// Persist to caches
 	enriched := &cache.EnrichedLead{
-		CNPJ:         out.CNPJ,
-		RazaoSocial:  out.RazaoSocial,
-		NomeFantasia: out.NomeFantasia,
-		Situacao:     out.Situacao,
-		Partners:     out.Partners,
-		CNAECode:     out.CNAECode,
-		CNAEDesc:     out.CNAEDesc,
-		Municipio:    out.Municipio,
-		UF:           out.UF,
+		CNPJ:          out.CNPJ,
+		RazaoSocial:   out.RazaoSocial,
+		NomeFantasia:  out.NomeFantasia,
+		Situacao:      out.Situacao,
+		Partners:      out.Partners,
+		CNAECode:      out.CNAECode,
+		CNAEDesc:      out.CNAEDesc,
+		CNAESecondary: out.CNAESecondary,
+		Municipio:     out.Municipio,
+		UF:            out.UF,
+		Email:         out.Email,
 	}
 	if rdb != nil {
 		_ = rdb.SetEnrichment(ctx, cacheKey, enriched)
 	}
 	if mdb != nil {
 		_ = mdb.SaveEnrichment(ctx, &store.CachedEnrichment{
-			Key:          cacheKey,
-			CNPJ:         enriched.CNPJ,
-			RazaoSocial:  enriched.RazaoSocial,
-			NomeFantasia: enriched.NomeFantasia,
-			Situacao:     enriched.Situacao,
-			Partners:     enriched.Partners,
-			CNAECode:     enriched.CNAECode,
-			CNAEDesc:     enriched.CNAEDesc,
-			Municipio:    enriched.Municipio,
-			UF:           enriched.UF,
+			Key:           cacheKey,
+			CNPJ:          enriched.CNPJ,
+			RazaoSocial:   enriched.RazaoSocial,
+			NomeFantasia:  enriched.NomeFantasia,
+			Situacao:      enriched.Situacao,
+			Partners:      enriched.Partners,
+			CNAECode:      enriched.CNAECode,
+			CNAEDesc:      enriched.CNAEDesc,
+			CNAESecondary: enriched.CNAESecondary,
+			Municipio:     enriched.Municipio,
+			UF:            enriched.UF,
+			Email:         enriched.Email,
 		})
 	}
 
@@ -0,0 +1,108 @@
+package enrichment
+
+import (
+	"context"
+	"time"
+
+	"github.com/lucasfdcampos/lead-api/internal/cache"
+	"github.com/lucasfdcampos/lead-api/internal/store"
+
+	leadsearch "github.com/lucasfdcampos/find-leads/pkg/leads"
+)
+
+// WebsiteResult holds what was extracted from a lead's official website.
+type WebsiteResult = leadsearch.WebsiteInfo
+
+// EnrichWebsite discovers the official website for a lead and crawls a
+// bounded set of pages (home, contato, sobre).
+// Discovery order: known website → e-mail domain (lead or CNPJ) → Instagram
+// bio link → search results.
+// Cache strategy:
+//  1. Redis (L1)
+//  2. MongoDB (L2)
+//  3. Live discovery + crawl via find-leads
+func EnrichWebsite(
+	ctx context.Context,
+	name, city, state string,
+	hints leadsearch.WebsiteHints,
+	rdb *cache.Client,
+	mdb *store.Client,
+) (*WebsiteResult, error) {
+	cacheKey := cache.EnrichmentKey("web:"+name, city)
+
+	// L1 – Redis
+	if rdb != nil {
+		if cached, err := rdb.GetEnrichment(ctx, cacheKey); err == nil && cached != nil && cached.Website != "" {
+			return websiteFromCache(cached), nil
+		}
+	}
+
+	// L2 – MongoDB
+	if mdb != nil {
+		if cached, err := mdb.GetEnrichment(ctx, cacheKey); err == nil && cached != nil && cached.Website != "" {
+			el := &cache.EnrichedLead{
+				Website:     cached.Website,
+				WebsiteVia:  cached.WebsiteVia,
+				Emails:      cached.Emails,
+				Phones:      cached.Phones,
+				WhatsApp:    cached.WhatsApp,
+				SiteCNPJs:   cached.SiteCNPJs,
+				SocialLinks: cached.SocialLinks,
+			}
+			// Warm Redis
+			if rdb != nil {
+				_ = rdb.SetEnrichment(ctx, cacheKey, el)
+			}
+			return websiteFromCache(el), nil
+		}
+	}
+
+	// Live discovery + crawl
+	tctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	info, err := leadsearch.FindWebsite(tctx, name, city, state, hints, leadsearch.WebsiteOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Persist to caches
+	enriched := &cache.EnrichedLead{
+		Website:     info.URL,
+		WebsiteVia:  info.FoundVia,
+		Emails:      info.Emails,
+		Phones:      info.Phones,
+		WhatsApp:    info.WhatsApp,
+		SiteCNPJs:   info.CNPJs,
+		SocialLinks: info.Socials,
+	}
+	if rdb != nil {
+		_ = rdb.SetEnrichment(ctx, cacheKey, enriched)
+	}
+	if mdb != nil {
+		_ = mdb.SaveEnrichment(ctx, &store.CachedEnrichment{
+			Key:         cacheKey,
+			Website:     enriched.Website,
+			WebsiteVia:  enriched.WebsiteVia,
+			Emails:      enriched.Emails,
+			Phones:      enriched.Phones,
+			WhatsApp:    enriched.WhatsApp,
+			SiteCNPJs:   enriched.SiteCNPJs,
+			SocialLinks: enriched.SocialLinks,
+		})
+	}
+
+	return info, nil
+}
+
+func websiteFromCache(c *cache.EnrichedLead) *WebsiteResult {
+	return &WebsiteResult{
+		URL:      c.Website,
+		FoundVia: c.WebsiteVia,
+		Emails:   c.Emails,
+		Phones:   c.Phones,
+		WhatsApp: c.WhatsApp,
+		CNPJs:    c.SiteCNPJs,
+		Socials:  c.SocialLinks,
+	}
+}
@@ -1,103 +1,70 @@
 // Package filter provides post-discovery, pre-response lead filtering.
 //
-// Three passes are available:
+// Seven passes are available:
 //  1. ByNameRelevance – always-on; uses business name keywords to discard
 //     leads that clearly belong to a different category than the query.
 //  2. ByLocation      – post-CNPJ; discards leads whose enriched Municipio
-//     doesn't match the requested city.
-//  3. ByCategory      – post-CNPJ; discards leads whose CNAE code is not
-//     in the set of compatible codes for the query.
+//     doesn't match any of the requested cities.
+//  3. ByCategory      – post-CNPJ; discards leads whose CNAE codes (primary
+//     and secondary) are all outside the set of compatible codes for the query.
+//  4. ByRadius        – optional; discards leads farther than radius_km from
+//     the search center and annotates the distance.
+//  5. ByOpenAt        – optional; discards leads whose opening hours say
+//     they are closed at the requested time.
+//  6. ByRules         – optional; discards leads failing any of the
+//     user-defined rules of the request (see internal/rules).
+//  7. BySuppression   – tenant-wide; discards leads on the tenant's
+//     suppression lists (see internal/suppression).
+//
+// Every pass returns the leads it removed as domain.DiscardedLead, with the
+// filter name, a reason code and the details behind the decision (the name
+// keyword, the mismatched municipality, the CNAE codes compared, ...).
 package filter
 
 import (
+	"fmt"
+	"math"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/lucasfdcampos/find-leads/pkg/ibge"
+	leadsearch "github.com/lucasfdcampos/find-leads/pkg/leads"
+	"github.com/lucasfdcampos/find-leads/pkg/taxonomy"
 
+	"github.com/lucasfdcampos/lead-api/internal/cnae"
 	"github.com/lucasfdcampos/lead-api/internal/domain"
+	"github.com/lucasfdcampos/lead-api/internal/rules"
+	"github.com/lucasfdcampos/lead-api/internal/suppression"
+)
+
+// Filter names (domain.Discard.Filter).
+const (
+	FilterNameRelevance = "name_relevance"
+	FilterRadius        = "radius"
+	FilterLocation      = "location"
+	FilterCategory      = "category"
+	FilterOpenAt        = "open_at"
+	FilterRules         = "rules"
+	FilterSuppression   = "suppression"
 )
 
-// ─── nameKeywords ─────────────────────────────────────────────────────────────
-
-// nameKeywords maps lowercase keywords that commonly appear in Brazilian
-// business names to the top-level CNAE prefix(es) they indicate.
-// Used in ByNameRelevance to classify a lead before CNPJ lookup.
-var nameKeywords = map[string][]string{
-	// Alimentação
-	"restaurante":  {"5611"},
-	"churrascaria": {"5611"},
-	"lanchonete":   {"5611"},
-	"pizzaria":     {"5611"},
-	"hamburgueria": {"5611"},
-	"padaria":      {"1091", "4721"},
-	"confeitaria":  {"1091"},
-	"sorveteria":   {"1053", "5611"},
-	"açougue":      {"4722"},
-	"abatedouro":   {"1012", "1013"},
-	"frigorífico":  {"1013"},
-	"peixaria":     {"4723"},
-	"hortifruti":   {"4724"},
-	"bar ":         {"5611"}, // trailing space avoids matching "barbearia"
-	"boteco":       {"5611"},
-	"lancheria":    {"5611"},
-	"rotisseria":   {"4721"},
-	"mercearia":    {"4712"},
-	// "empório" omitted – too ambiguous (Empório do Jeans vs Empório do Café)
-	"supermercado": {"4711"},
-	// Saúde
-	"farmácia":    {"4771"},
-	"drogaria":    {"4771"},
-	"clínica":     {"8630"},
-	"hospital":    {"8610"},
-	"laboratório": {"8640"},
-	"dentista":    {"8630"},
-	"odontologia": {"8630"},
-	"veterinári":  {"7500"}, // veterinário / veterinária
-	"pet shop":    {"7500", "4789"},
-	"petshop":     {"7500", "4789"},
-	// Automóvel / Transporte
-	"caminhões":      {"4511", "4512"},
-	"caminhao":       {"4511"},
-	"veículos":       {"4511"},
-	"automóveis":     {"4511"},
-	"concessionár":   {"4511"},
-	"oficina":        {"4520"},
-	"mecânica":       {"4520"},
-	"borracharia":    {"4530"},
-	"transportadora": {"4930"},
-	"motoboy":        {"5320"},
-	"locadora de":    {"7711"},
-	// Beleza
-	"salão":      {"9602"},
-	"barbearia":  {"9602"},
-	"estética":   {"9602"},
-	"cabeleirei": {"9602"},
-	// Construção
-	"construção":              {"4120"},
-	"construtora":             {"4120"},
-	"madeireira":              {"1610"},
-	"serraria":                {"1610"},
-	"marmoraria":              {"2391"},
-	"materiais de construção": {"4744"},
-	// Hospedagem
-	"hotel":   {"5510"},
-	"pousada": {"5510"},
-	"motel":   {"5510"},
-	"hostel":  {"5510"},
-	// Impressão / Gráfica
-	"gráfica":    {"1811", "1812"},
-	"gráficas":   {"1811", "1812"},
-	"tipografia": {"1811"},
-	// Educação
-	"escola":       {"8511", "8512", "8513"},
-	"colégio":      {"8512"},
-	"faculdade":    {"8530"},
-	"universidade": {"8530"},
-	// Agro / Campo
-	"agropecuária": {"4612", "4623"},
-	"fazenda":      {"0111"},
-	"granja":       {"0155"},
-	// Logística
-	"armazém":  {"5211"},
-	"depósito": {"5211"},
+// Reason codes (domain.Discard.Reason).
+const (
+	ReasonNameCategory = "name_category_mismatch" // the name indicates another category
+	ReasonOutOfRadius  = "out_of_radius"          // farther than radius_km from the center
+	ReasonMunicipio    = "municipio_mismatch"     // enriched municipality outside the requested cities
+	ReasonCNAE         = "cnae_mismatch"          // no CNAE code in the compatible set
+	ReasonClosed       = "closed"                 // opening hours say it is closed
+	ReasonRule         = "rule_failed"            // a user-defined rule is false
+	ReasonSuppressed   = "suppressed"             // on one of the tenant's suppression lists
+)
+
+func discard(l domain.Lead, filter, reason string, details map[string]string) domain.DiscardedLead {
+	return domain.DiscardedLead{
+		Lead:    l,
+		Discard: domain.Discard{Filter: filter, Reason: reason, Details: details},
+	}
 }
 
 // ─── ByNameRelevance ──────────────────────────────────────────────────────────
@@ -105,25 +72,35 @@ var nameKeywords = map[string][]string{
 // ByNameRelevance filters leads whose business names strongly indicate a
 // category incompatible with the search query.
 //
-// Only fires when the query has a known CNAE mapping (via cnae.cnaePrefixMap).
+// Only fires when the query has a known CNAE mapping in the shared category
+// taxonomy (find-leads/pkg/taxonomy), which also lists the name keywords.
 // When no mapping exists for the query, all leads are kept.
 //
-// Returns (kept leads, number discarded).
-func ByNameRelevance(leads []domain.Lead, query string) ([]domain.Lead, int) {
+// Discards carry the name keyword, the category it indicates and the CNAE
+// prefixes on both sides. Returns (kept leads, discarded leads).
+func ByNameRelevance(leads []domain.Lead, query string) ([]domain.Lead, []domain.DiscardedLead) {
 	queryPrefixes := expectedPrefixes(query)
 	if len(queryPrefixes) == 0 {
 		// Unknown query type — keep everything.
-		return leads, 0
+		return leads, nil
 	}
 
 	kept := make([]domain.Lead, 0, len(leads))
-	discarded := 0
+	var discarded []domain.DiscardedLead
 
 	for _, l := range leads {
 		detected := detectCNAEFromName(l.Name)
 		if len(detected) > 0 && !prefixesOverlap(queryPrefixes, detected) {
 			// The name strongly indicates an incompatible category.
-			discarded++
+			details := map[string]string{
+				"name_cnae":  strings.Join(detected, ","),
+				"query_cnae": strings.Join(queryPrefixes, ","),
+			}
+			if kws := taxonomy.MatchNameKeywords(l.Name); len(kws) > 0 {
+				details["keyword"] = kws[0].Keyword
+				details["name_category"] = kws[0].Category.Name
+			}
+			discarded = append(discarded, discard(l, FilterNameRelevance, ReasonNameCategory, details))
 			continue
 		}
 		kept = append(kept, l)
@@ -133,20 +110,57 @@ func ByNameRelevance(leads []domain.Lead, query string) ([]domain.Lead, int) {
 
 // ─── ByLocation ───────────────────────────────────────────────────────────────
 
+// Place is a city accepted by ByLocation.
+type Place struct {
+	City     string
+	State    string
+	IBGECode string // optional; looked up from City/State when empty
+}
+
 // ByLocation removes leads whose enriched Municipio is populated and doesn't
-// match the requested city (case-insensitive, accent-insensitive comparison).
+// match any of the requested cities.
+//
+// When both the lead's Municipio and the place resolve to an IBGE municipality
+// the codes are compared, so spelling variants ("Sta Terezinha de Itaipu")
+// match. Otherwise it falls back to a case- and accent-insensitive comparison
+// of the names.
 //
-// Leads without a Municipio (not yet enriched) are always kept.
-// Returns (kept leads, number discarded).
-func ByLocation(leads []domain.Lead, city, state string) ([]domain.Lead, int) {
-	if city == "" {
-		return leads, 0
+// Leads without a Municipio (not yet enriched) are always kept. Discards
+// carry the lead's municipality and the requested cities.
+// Returns (kept leads, discarded leads).
+func ByLocation(leads []domain.Lead, places ...Place) ([]domain.Lead, []domain.DiscardedLead) {
+	ds := ibge.Default()
+	type want struct{ city, uf, code string }
+	var wants []want
+	for _, p := range places {
+		if p.City == "" {
+			continue
+		}
+		w := want{normalize(p.City), strings.ToUpper(strings.TrimSpace(p.State)), p.IBGECode}
+		if w.code == "" {
+			if m, ok := ds.Find(p.City, p.State); ok {
+				w.code = m.Code
+			}
+		}
+		wants = append(wants, w)
+	}
+	if len(wants) == 0 {
+		return leads, nil
+	}
+	var expected []string
+	for _, p := range places {
+		if p.City == "" {
+			continue
+		}
+		name := p.City
+		if p.State != "" {
+			name += " - " + strings.ToUpper(strings.TrimSpace(p.State))
+		}
+		expected = append(expected, name)
 	}
-	wantCity := normalize(city)
-	wantUF := strings.ToUpper(strings.TrimSpace(state))
 
 	kept := make([]domain.Lead, 0, len(leads))
-	discarded := 0
+	var discarded []domain.DiscardedLead
 
 	for _, l := range leads {
 		if l.Municipio == "" {
@@ -156,15 +170,44 @@ func ByLocation(leads []domain.Lead, city, state string) ([]domain.Lead, int) {
 		}
 		gotCity := normalize(l.Municipio)
 		gotUF := strings.ToUpper(strings.TrimSpace(l.UF))
+		gotCode := l.IBGECode
+		if gotCode == "" {
+			if m, ok := ds.Find(l.Municipio, l.UF); ok {
+				gotCode = m.Code
+			}
+		}
 
-		cityMatch := gotCity == wantCity
-		// If we have both UF values, also check state. Otherwise just city.
-		ufMatch := wantUF == "" || gotUF == "" || gotUF == wantUF
-
-		if cityMatch && ufMatch {
+		match := false
+		for _, w := range wants {
+			if gotCode != "" && w.code != "" {
+				if gotCode == w.code {
+					match = true
+					break
+				}
+				continue
+			}
+			cityMatch := gotCity == w.city
+			// If we have both UF values, also check state. Otherwise just city.
+			ufMatch := w.uf == "" || gotUF == "" || gotUF == w.uf
+			if cityMatch && ufMatch {
+				match = true
+				break
+			}
+		}
+		if match {
 			kept = append(kept, l)
 		} else {
-			discarded++
+			details := map[string]string{
+				"municipio": l.Municipio,
+				"expected":  strings.Join(expected, "; "),
+			}
+			if gotUF != "" {
+				details["uf"] = gotUF
+			}
+			if gotCode != "" {
+				details["ibge_code"] = gotCode
+			}
+			discarded = append(discarded, discard(l, FilterLocation, ReasonMunicipio, details))
 		}
 	}
 	return kept, discarded
@@ -172,170 +215,221 @@ func ByLocation(leads []domain.Lead, city, state string) ([]domain.Lead, int) {
 
 // ─── ByCategory ───────────────────────────────────────────────────────────────
 
-// ByCategory removes leads whose enriched CNAE code is populated and not in
-// the compatible set.
+// ByCategory removes leads whose CNAE codes are known and none of them —
+// primary or secondary — falls under the compatible set. Codes are compared
+// with cnae.MatchAt at the configured hierarchy level, so the set may mix
+// subclasses (discovered hints), classes (MongoDB reference) and the 4-digit
+// prefixes of the category taxonomy. Kept leads get CNAEMatch and
+// CNAEMatchedCode, the lead's code that matched (the primary one first).
 //
 // Leads without a CNAE code (not yet enriched, or CNPJ not found) are kept.
-// Returns (kept leads, number discarded).
-func ByCategory(leads []domain.Lead, compatibleCodes []string) ([]domain.Lead, int) {
-	if len(compatibleCodes) == 0 {
-		return leads, 0
-	}
-	codeSet := make(map[string]bool, len(compatibleCodes))
+// Discards carry the lead's codes, the compatible set and the match level.
+// Returns (kept leads, discarded leads).
+func ByCategory(leads []domain.Lead, compatibleCodes []string) ([]domain.Lead, []domain.DiscardedLead) {
+	var codes []string
 	for _, c := range compatibleCodes {
-		codeSet[strings.TrimSpace(c)] = true
+		if c = strings.TrimSpace(c); c != "" {
+			codes = append(codes, c)
+		}
+	}
+	if len(codes) == 0 {
+		return leads, nil
+	}
+	level := cnae.MatchLevel()
+
+	kept := make([]domain.Lead, 0, len(leads))
+	var discarded []domain.DiscardedLead
+
+	for _, l := range leads {
+		leadCodes := append([]string{l.CNAECode}, l.CNAESecondary...)
+		known, matched := false, ""
+		for _, lc := range leadCodes {
+			if strings.TrimSpace(lc) == "" {
+				continue
+			}
+			known = true
+			if _, ok := cnae.MatchAt(lc, codes, level); ok {
+				matched = lc
+				break
+			}
+		}
+		if !known {
+			// CNPJ enrichment didn't run or found no CNAE – keep.
+			kept = append(kept, l)
+			continue
+		}
+		match := matched != ""
+		l.CNAEMatch = &match
+		if !match {
+			details := map[string]string{
+				"cnae_code":   l.CNAECode,
+				"compatible":  strings.Join(codes, ","),
+				"match_level": level.String(),
+			}
+			if len(l.CNAESecondary) > 0 {
+				details["cnae_secondary"] = strings.Join(l.CNAESecondary, ",")
+			}
+			if l.CNAEDesc != "" {
+				details["cnae_desc"] = l.CNAEDesc
+			}
+			discarded = append(discarded, discard(l, FilterCategory, ReasonCNAE, details))
+			continue
+		}
+		l.CNAEMatchedCode = matched
+		kept = append(kept, l)
+	}
+	return kept, discarded
+}
+
+// ─── ByRadius ─────────────────────────────────────────────────────────────────
+
+// ByRadius sets DistanceKm (km from center, Haversine) on every lead with
+// coordinates and removes those farther than radiusKm. radiusKm <= 0 only
+// annotates distances.
+//
+// Leads without coordinates are always kept.
+// Returns (kept leads, discarded leads).
+func ByRadius(leads []domain.Lead, center domain.GeoPoint, radiusKm float64) ([]domain.Lead, []domain.DiscardedLead) {
+	kept := make([]domain.Lead, 0, len(leads))
+	var discarded []domain.DiscardedLead
+
+	for _, l := range leads {
+		if l.Lat == 0 && l.Lon == 0 {
+			kept = append(kept, l)
+			continue
+		}
+		d := leadsearch.DistanceKm(center.Lat, center.Lon, l.Lat, l.Lon)
+		rounded := math.Round(d*100) / 100
+		l.DistanceKm = &rounded
+		if radiusKm > 0 && d > radiusKm {
+			discarded = append(discarded, discard(l, FilterRadius, ReasonOutOfRadius, map[string]string{
+				"distance_km": strconv.FormatFloat(rounded, 'f', -1, 64),
+				"radius_km":   strconv.FormatFloat(radiusKm, 'f', -1, 64),
+			}))
+			continue
+		}
+		kept = append(kept, l)
 	}
+	return kept, discarded
+}
+
+// ─── ByOpenAt ─────────────────────────────────────────────────────────────────
 
+// ByOpenAt removes leads whose opening hours show them closed at t. The time
+// is converted to each lead's local time (by UF) before checking the schedule.
+//
+// Leads without recognizable opening hours are always kept. Discards carry
+// the opening hours and the local time checked.
+// Returns (kept leads, discarded leads).
+func ByOpenAt(leads []domain.Lead, t time.Time) ([]domain.Lead, []domain.DiscardedLead) {
 	kept := make([]domain.Lead, 0, len(leads))
-	discarded := 0
+	var discarded []domain.DiscardedLead
 
 	for _, l := range leads {
-		if l.CNPJ == "" {
-			// CNPJ enrichment didn't run or failed – keep.
+		sched, ok := leadsearch.ParseOpeningHours(l.OpeningHours)
+		if !ok {
 			kept = append(kept, l)
 			continue
 		}
-		// Extract the raw CNAE code from the lead (stored during enrichment as
-		// the CNAECode field). We infer it from CNAEMatch for now — if explicitly
-		// stored we'd use it directly. For now use the cnaePrefixMap-style check:
-		// We keep leads that have CNAEMatch == nil or CNAEMatch == true.
-		if l.CNAEMatch == nil || *l.CNAEMatch {
+		uf := l.UF
+		if uf == "" {
+			uf = l.State
+		}
+		local := leadsearch.BrazilTime(t, uf)
+		if sched.OpenAt(local) {
 			kept = append(kept, l)
 		} else {
-			discarded++
+			discarded = append(discarded, discard(l, FilterOpenAt, ReasonClosed, map[string]string{
+				"opening_hours": l.OpeningHours,
+				"at":            local.Format("2006-01-02T15:04-07:00"),
+			}))
 		}
 	}
 	return kept, discarded
 }
 
-// ─── helpers ──────────────────────────────────────────────────────────────────
+// ParseOpenAt parses the open_at request parameter: RFC 3339, or a local
+// "2006-01-02T15:04" / "2006-01-02 15:04" read as Brasília time.
+func ParseOpenAt(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	brt := leadsearch.BrazilTime(time.Now(), "").Location()
+	for _, layout := range []string{"2006-01-02T15:04", "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, s, brt); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("open_at must be RFC 3339 or YYYY-MM-DDTHH:MM (Brasília time): %q", s)
+}
+
+// ─── ByRules ──────────────────────────────────────────────────────────────────
 
-// expectedPrefixes returns the CNAE prefix list for the search query,
-// reusing the same lookup logic as cnae.IsCompatible without importing it
-// (to avoid a circular dependency).
-var cnaePrefixMap = map[string][]string{
-	// Vestuário / Moda
-	"loja de roupas": {"4781", "1412", "1411", "4642", "4644"},
-	"moda feminina":  {"4781", "1412"},
-	"moda masculina": {"4781", "1411"},
-	"boutique":       {"4781", "1412", "1411"},
-	"confecção":      {"1412", "1411", "1413"},
-	"brechó":         {"4781"},
-	"multimarcas":    {"4781"},
-	// Alimentação
-	"restaurante":  {"5611", "5612"},
-	"lanchonete":   {"5611"},
-	"pizzaria":     {"5611"},
-	"padaria":      {"1091", "4721"},
-	"bar":          {"5611", "5612"},
-	"cafeteria":    {"5612"},
-	"sorveteria":   {"5611", "1053"},
-	"açougue":      {"4722"},
-	"mercearia":    {"4712"},
-	"supermercado": {"4711"},
-	"mercado":      {"4711", "4712"},
-	"hortifruti":   {"4724"},
-	"quitanda":     {"4724"},
-	"delicatessen": {"4721"},
-	"empório":      {"4721"},
-	// Saúde & Beleza
-	"farmácia":        {"4771"},
-	"drogaria":        {"4771"},
-	"clínica":         {"8630", "8621", "8622"},
-	"clínica médica":  {"8630"},
-	"dentista":        {"8630"},
-	"psicólogo":       {"8630"},
-	"academia":        {"9313"},
-	"salão de beleza": {"9602"},
-	"barbearia":       {"9602"},
-	"estética":        {"9602"},
-	"spa":             {"9609"},
-	"óptica":          {"4774"},
-	"veterinário":     {"7500"},
-	"pet shop":        {"4789", "7500"},
-	// Automotivo
-	"oficina":        {"4520"},
-	"mecânica":       {"4520"},
-	"funilaria":      {"4520"},
-	"borracharia":    {"4530"},
-	"auto peças":     {"4541", "4542"},
-	"autopeças":      {"4541", "4542"},
-	"lavagem":        {"4520"},
-	"estacionamento": {"5223"},
-	"concessionária": {"4511"},
-	"locadora":       {"7711"},
-	// Construção & Casa
-	"construção":              {"4120", "4399"},
-	"materiais de construção": {"4744"},
-	"ferragens":               {"4744"},
-	"elétrica":                {"4321", "4742"},
-	"encanamento":             {"4322"},
-	"pintura":                 {"4330"},
-	"marcenaria":              {"1610", "1622"},
-	"móveis":                  {"4754", "3101", "3102", "3103"},
-	"decoração":               {"4759", "7490"},
-	"arquitetura":             {"7111"},
-	"engenharia":              {"7112"},
-	"imobiliária":             {"6811", "6821"},
-	"condomínio":              {"8110"},
-	// Educação
-	"escola":            {"8511", "8512", "8513"},
-	"creche":            {"8511"},
-	"faculdade":         {"8530"},
-	"universidade":      {"8530"},
-	"curso":             {"8599"},
-	"escola de idiomas": {"8599"},
-	"escola de música":  {"8599"},
-	// Tecnologia & Serviços
-	"ti":               {"6201", "6202", "6209"},
-	"software":         {"6201"},
-	"informática":      {"4751", "9521"},
-	"internet":         {"6110", "6120"},
-	"telecomunicações": {"6110"},
-	"consultoria":      {"7020", "6920"},
-	"contabilidade":    {"6920"},
-	"advocacia":        {"6911"},
-	"segurança":        {"8011", "8012"},
-	// Turismo & Lazer
-	"hotel":               {"5510"},
-	"pousada":             {"5510"},
-	"agência de viagens":  {"7911", "7912"},
-	"academia de dança":   {"9313"},
-	"academia de natação": {"9313"},
-	"cinema":              {"5914"},
-	"teatro":              {"9001"},
-	"quadra esportiva":    {"9313"},
-	// Logística & Transporte
-	"transportadora": {"4930", "4921", "4922"},
-	"motoboy":        {"5320"},
-	"courier":        {"5310", "5320"},
-	"armazém":        {"5211"},
-	"logística":      {"5229"},
+// ByRules keeps the leads that satisfy every rule. Discards carry the first
+// rule the lead failed. Returns (kept leads, discarded leads).
+func ByRules(leads []domain.Lead, rs []*rules.Rule) ([]domain.Lead, []domain.DiscardedLead) {
+	if len(rs) == 0 {
+		return leads, nil
+	}
+	kept := make([]domain.Lead, 0, len(leads))
+	var discarded []domain.DiscardedLead
+
+	for _, l := range leads {
+		failed := ""
+		for _, r := range rs {
+			if !r.Match(&l) {
+				failed = r.String()
+				break
+			}
+		}
+		if failed == "" {
+			kept = append(kept, l)
+		} else {
+			discarded = append(discarded, discard(l, FilterRules, ReasonRule, map[string]string{"rule": failed}))
+		}
+	}
+	return kept, discarded
 }
 
-func expectedPrefixes(query string) []string {
-	q := strings.ToLower(strings.TrimSpace(query))
-	if p, ok := cnaePrefixMap[q]; ok {
-		return p
+// ─── BySuppression ────────────────────────────────────────────────────────────
+
+// BySuppression removes leads matching an entry of the tenant's suppression
+// lists. A nil matcher keeps everything. Discards carry the list, the field
+// matched and the entry. Returns (kept leads, discarded leads).
+func BySuppression(leads []domain.Lead, m *suppression.Matcher) ([]domain.Lead, []domain.DiscardedLead) {
+	if m == nil {
+		return leads, nil
 	}
-	for key, prefixes := range cnaePrefixMap {
-		if strings.Contains(q, key) || strings.Contains(key, q) {
-			return prefixes
+	kept := make([]domain.Lead, 0, len(leads))
+	var discarded []domain.DiscardedLead
+
+	for _, l := range leads {
+		hit, ok := m.Match(&l)
+		if !ok {
+			kept = append(kept, l)
+			continue
 		}
+		discarded = append(discarded, discard(l, FilterSuppression, ReasonSuppressed, map[string]string{
+			"list":  hit.List,
+			"field": hit.Field,
+			"value": hit.Value,
+		}))
 	}
-	return nil
+	return kept, discarded
+}
+
+// ─── helpers ──────────────────────────────────────────────────────────────────
+
+// expectedPrefixes returns the CNAE prefix list for the search query.
+func expectedPrefixes(query string) []string {
+	return taxonomy.CNAEPrefixes(query)
 }
 
 // detectCNAEFromName returns CNAE prefixes inferred from keywords in the
 // business name. Returns nil when no keyword matched.
 func detectCNAEFromName(name string) []string {
-	lower := strings.ToLower(name)
-	for kw, prefixes := range nameKeywords {
-		if strings.Contains(lower, kw) {
-			return prefixes
-		}
-	}
-	return nil
+	return taxonomy.NameCNAEPrefixes(name)
 }
 
 // prefixesOverlap returns true when any prefix in `a` is a prefix of any
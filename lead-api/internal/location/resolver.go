@@ -0,0 +1,114 @@
+// Package location expands a search request into the list of cities to
+// search: a single city, a region (state, mesorregião, microrregião or
+// metro area) or every city within expand_radius_km of the location.
+//
+// Regions and radii are resolved against the IBGE municipality table from
+// find-leads/pkg/ibge. Over a partial table the expansion misses the cities
+// that are not in it; Incomplete tells the caller so.
+package location
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lucasfdcampos/find-leads/pkg/ibge"
+
+	"github.com/lucasfdcampos/lead-api/internal/domain"
+)
+
+// MaxCities bounds how many cities a single search may fan out to.
+const MaxCities = 40
+
+// ErrInvalid is wrapped by every error caused by the request itself
+// (unknown region, too many cities, conflicting fields).
+var ErrInvalid = errors.New("invalid location")
+
+// City is one city the pipeline will search.
+type City struct {
+	Name     string
+	UF       string
+	IBGECode string // empty when the city is not in the IBGE table
+	Lat, Lon float64
+}
+
+// Location returns the "City - UF" string understood by find-leads scrapers.
+func (c City) Location() string {
+	if c.UF == "" {
+		return c.Name
+	}
+	return c.Name + " - " + c.UF
+}
+
+// Resolve returns the cities to search for req.
+//
+//   - Region set          → every city in the region
+//   - ExpandRadiusKm > 0  → the location's city plus every city whose
+//     centroid is within the radius (closest first)
+//   - otherwise           → the single city in Location
+func Resolve(req domain.SearchRequest) ([]City, error) {
+	ds := ibge.Default()
+
+	switch {
+	case req.Region != "" && req.ExpandRadiusKm > 0:
+		return nil, fmt.Errorf("%w: use either region or expand_radius_km, not both", ErrInvalid)
+
+	case req.Region != "":
+		ms, _, err := ds.Region(req.Region)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		return limit(fromMunicipalities(ms), req.Region)
+
+	case req.ExpandRadiusKm > 0:
+		base, err := ds.Resolve(req.Location)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v (expand_radius_km needs a city from the IBGE table)", ErrInvalid, err)
+		}
+		ms := ds.Within(base.Lat, base.Lon, req.ExpandRadiusKm)
+		if len(ms) == 0 {
+			ms = []ibge.Municipality{base}
+		}
+		return limit(fromMunicipalities(ms), fmt.Sprintf("%s + %gkm", base, req.ExpandRadiusKm))
+	}
+
+	m, err := ds.Resolve(req.Location)
+	if err == nil {
+		return []City{fromMunicipality(m)}, nil
+	}
+	// A partial table only covers part of the country: a city missing from it
+	// may still exist, so it is searched as typed. Ambiguous names and cities
+	// missing from the complete table are rejected.
+	if errors.Is(err, ibge.ErrAmbiguousCity) || !ds.Partial() {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	city, state := ibge.SplitLocation(req.Location)
+	return []City{{Name: city, UF: state}}, nil
+}
+
+// Incomplete reports whether req expands to several cities (region or
+// expand_radius_km) over a partial IBGE table, which misses some of them.
+func Incomplete(req domain.SearchRequest) bool {
+	return (req.Region != "" || req.ExpandRadiusKm > 0) && ibge.Default().Partial()
+}
+
+func limit(cities []City, what string) ([]City, error) {
+	if len(cities) == 0 {
+		return nil, fmt.Errorf("%w: %s has no cities", ErrInvalid, what)
+	}
+	if len(cities) > MaxCities {
+		return nil, fmt.Errorf("%w: %s expands to %d cities (max %d)", ErrInvalid, what, len(cities), MaxCities)
+	}
+	return cities, nil
+}
+
+func fromMunicipalities(ms []ibge.Municipality) []City {
+	out := make([]City, len(ms))
+	for i, m := range ms {
+		out[i] = fromMunicipality(m)
+	}
+	return out
+}
+
+func fromMunicipality(m ibge.Municipality) City {
+	return City{Name: m.Name, UF: m.UF, IBGECode: m.Code, Lat: m.Lat, Lon: m.Lon}
+}
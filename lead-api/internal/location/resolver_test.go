@@ -0,0 +1,69 @@
+package location
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lucasfdcampos/find-leads/pkg/ibge"
+
+	"github.com/lucasfdcampos/lead-api/internal/domain"
+)
+
+func TestResolvePartialTable(t *testing.T) {
+	if !ibge.Default().Partial() {
+		t.Skip("the embedded IBGE table is complete")
+	}
+
+	// Region and radius expand over the cities in the table, flagged as
+	// incomplete.
+	tests := []struct {
+		req   domain.SearchRequest
+		first string
+	}{
+		{domain.SearchRequest{Region: "RM de Londrina"}, ""},
+		{domain.SearchRequest{Region: "Norte Central Paranaense"}, ""},
+		{domain.SearchRequest{Location: "Londrina - PR", ExpandRadiusKm: 30}, "Londrina"},
+	}
+	for _, tt := range tests {
+		cities, err := Resolve(tt.req)
+		if err != nil || len(cities) < 2 {
+			t.Errorf("Resolve(%+v) = %v, %v", tt.req, cities, err)
+			continue
+		}
+		if tt.first != "" && cities[0].Name != tt.first {
+			t.Errorf("Resolve(%+v)[0] = %v, want %s", tt.req, cities[0], tt.first)
+		}
+		if !Incomplete(tt.req) {
+			t.Errorf("Incomplete(%+v) = false on a partial table", tt.req)
+		}
+	}
+
+	// Single-city searches work, including cities missing from the table.
+	for _, loc := range []string{"Londrina - PR", "Itati - RS"} {
+		req := domain.SearchRequest{Location: loc}
+		cities, err := Resolve(req)
+		if err != nil || len(cities) != 1 {
+			t.Errorf("Resolve(%q) = %v, %v", loc, cities, err)
+		}
+		if Incomplete(req) {
+			t.Errorf("Incomplete(%q) = true for a single city", loc)
+		}
+	}
+}
+
+func TestResolveInvalid(t *testing.T) {
+	tests := []domain.SearchRequest{
+		{Location: "Londrina - PR", Region: "PR", ExpandRadiusKm: 10},
+		{Region: "Região Inexistente"},
+		{Region: "PR"}, // more than MaxCities on a complete table
+		{Location: "Cidade Inventada - PR", ExpandRadiusKm: 10},
+	}
+	for i, req := range tests {
+		if i == 2 && ibge.Default().Partial() && len(ibge.Default().ByUF("PR")) <= MaxCities {
+			continue
+		}
+		if _, err := Resolve(req); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Resolve(%+v) err = %v, want ErrInvalid", req, err)
+		}
+	}
+}
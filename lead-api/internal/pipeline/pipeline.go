@@ -4,54 +4,159 @@
 //
 //	0a. Redis cache (L1)        – return immediately on hit
 //	0b. MongoDB cache (L2)      – return immediately on hit (hydrate leads from results collection)
-//	0c. CNAE hint               – discover / load CNAE codes for the query
-//	1.  Discovery               – run all find-leads scrapers via SearchAll
-//	2.  Base leads              – build domain.Lead slice, name-relevance filter
+//	0c. CNAE hint               – discover CNAE codes for the query (LLM or search
+//	                              engines); cached in MongoDB when configured
+//	1.  Discovery               – run all find-leads scrapers via SearchAll; multi-city
+//	                              searches (region / expand_radius_km) fan out per city
+//	                              (3 at a time) and dedup across cities; the merge log
+//	                              is returned in the response
+//	2.  Base leads              – build domain.Lead slice, suppression lists,
+//	                              name-relevance filter
+//	2c. Radius                  – optional radius_km around center (or the city centroid)
 //	3.  CNPJ enrichment         – concurrent pool (5 workers)
 //	3b. Location + category     – post-CNPJ filters
 //	4.  Instagram enrichment    – concurrent pool (4 workers)
+//	4b. Website enrichment      – discover + crawl official site (4 workers)
+//	4c. Phone normalization     – E.164, line type, DDD vs UF, wa.me link
+//	4d. E-mail validation       – syntax, MX, disposable/free domain, role account
+//	4e. Opening hours           – weekly schedule parsed from opening_hours
+//	4f. Suppression             – the tenant's suppression lists again, now with
+//	                              CNPJ, Instagram and website found
 //	5.  Build response
-//	6.  Persist                 – save metadata → searches, leads → results
+//	6.  Persist                 – save metadata → searches, leads and discarded
+//	                              leads → results
 //	7.  Warm Redis
+//	8.  Open filter             – open_now / open_at, applied on top of the
+//	                              (possibly cached) response, never cached itself
+//	9.  User rules              – rules / rules_preset (internal/rules), applied
+//	                              like the open filter
+//
+// Every filter records the leads it drops with a reason (domain.DiscardedLead);
+// they are cached with the search and returned only with include_discarded.
 package pipeline
 
 import (
 	"context"
 	"encoding/json"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/lucasfdcampos/find-leads/pkg/ibge"
 	leadsearch "github.com/lucasfdcampos/find-leads/pkg/leads"
 
 	"github.com/lucasfdcampos/lead-api/internal/cache"
 	"github.com/lucasfdcampos/lead-api/internal/cnae"
 	"github.com/lucasfdcampos/lead-api/internal/domain"
+	"github.com/lucasfdcampos/lead-api/internal/emailcheck"
 	"github.com/lucasfdcampos/lead-api/internal/enrichment"
 	"github.com/lucasfdcampos/lead-api/internal/filter"
+	"github.com/lucasfdcampos/lead-api/internal/location"
+	"github.com/lucasfdcampos/lead-api/internal/rules"
 	"github.com/lucasfdcampos/lead-api/internal/store"
+	"github.com/lucasfdcampos/lead-api/internal/suppression"
 )
 
 const (
 	cnpjWorkers      = 5
 	instagramWorkers = 4
+	websiteWorkers   = 4
+	cityWorkers      = 3
+	emailWorkers     = 8
 )
 
 // Config holds injectable dependencies.
 type Config struct {
 	Redis *cache.Client
 	Mongo *store.Client
+	// Email validates lead e-mails. Nil uses a validator backed by the
+	// system DNS resolver.
+	Email *emailcheck.Validator
+	// LLM, when set, maps queries to CNAE codes (cnae.Understand) before
+	// falling back to the search-engine discovery.
+	LLM leadsearch.LLMClient
+	// Suppress holds the tenant's suppression lists; nil disables them.
+	// Its fingerprint must be in req.Suppression so cached searches are
+	// kept apart per list version.
+	Suppress *suppression.Matcher
 }
 
 // Run executes the full pipeline for a search request.
 func Run(ctx context.Context, req domain.SearchRequest, cfg Config) (*domain.SearchResponse, error) {
+	resp, err := search(ctx, req, cfg)
+	if err != nil {
+		return nil, err
+	}
+	out := *resp
+	var dropped []domain.DiscardedLead
+	filtered := false
+
+	// ── Phase 8: Open filter ─────────────────────────────────────────────────
+	if req.OpenNow || req.OpenAt != "" {
+		at := time.Now()
+		if req.OpenAt != "" {
+			if at, err = filter.ParseOpenAt(req.OpenAt); err != nil {
+				return nil, err
+			}
+		}
+		var d []domain.DiscardedLead
+		out.Leads, d = filter.ByOpenAt(out.Leads, at)
+		dropped = append(dropped, d...)
+		out.OpenAt = &at
+		filtered = true
+	}
+
+	// ── Phase 9: User rules ──────────────────────────────────────────────────
+	if len(req.Rules) > 0 {
+		rs, err := rules.CompileAll(req.Rules)
+		if err != nil {
+			return nil, err
+		}
+		var d []domain.DiscardedLead
+		out.Leads, d = filter.ByRules(out.Leads, rs)
+		dropped = append(dropped, d...)
+		filtered = true
+	}
+
+	if filtered {
+		out.Total = len(out.Leads)
+		out.Discarded += len(dropped)
+		out.DiscardedBy = countDiscards(resp.DiscardedBy, dropped)
+		out.DiscardedLeads = append(append([]domain.DiscardedLead(nil), resp.DiscardedLeads...), dropped...)
+		out.Cities = finalizeCityCounts(append([]domain.CityCount(nil), resp.Cities...), out.Leads)
+	}
+
+	if !req.IncludeDiscarded {
+		out.DiscardedLeads = nil
+	}
+	return &out, nil
+}
+
+// countDiscards returns a copy of counts with the discards added per filter.
+func countDiscards(counts map[string]int, discards []domain.DiscardedLead) map[string]int {
+	if len(counts) == 0 && len(discards) == 0 {
+		return nil
+	}
+	out := make(map[string]int, len(counts))
+	for k, v := range counts {
+		out[k] = v
+	}
+	for _, d := range discards {
+		out[d.Filter]++
+	}
+	return out
+}
+
+// search runs phases 0–7; its result is what gets cached.
+func search(ctx context.Context, req domain.SearchRequest, cfg Config) (*domain.SearchResponse, error) {
 	start := time.Now()
 
 	// ── Phase 0a: Redis search cache (L1) ────────────────────────────────────
 	var cacheKey string
 	if cfg.Redis != nil {
-		cacheKey = cache.SearchKey(req.Query, req.Location, req.EnrichCNPJ, req.EnrichInstagram)
+		cacheKey = cache.SearchKey(req.Query, req.Location, req.Variant())
 		if raw, err := cfg.Redis.GetSearch(ctx, cacheKey); err == nil && len(raw) > 0 {
 			var resp domain.SearchResponse
 			if err := json.Unmarshal(raw, &resp); err == nil {
@@ -63,21 +168,33 @@ func Run(ctx context.Context, req domain.SearchRequest, cfg Config) (*domain.Sea
 
 	// ── Phase 0b: MongoDB cache (L2) ─────────────────────────────────────────
 	if cfg.Mongo != nil {
-		stored, err := cfg.Mongo.FindSearch(ctx, req.Query, req.Location, req.EnrichCNPJ, req.EnrichInstagram)
+		stored, err := cfg.Mongo.FindSearch(ctx, req.Query, req.Location, req.Variant())
 		if err == nil && stored != nil {
-			// Hydrate leads from results collection
+			// Hydrate leads (and the discarded ones) from results collection;
+			// the suppressed leads were not stored (see withoutSuppressed).
 			leads, _ := cfg.Mongo.FindResultsBySearchID(ctx, stored.ID)
+			discarded, _ := cfg.Mongo.FindDiscardedBySearchID(ctx, stored.ID)
 			resp := &domain.SearchResponse{
 				Query:         req.Query,
 				Location:      req.Location,
 				Total:         stored.Total,
 				Discarded:     stored.Discarded,
+				DiscardedBy:   stored.DiscardedBy,
+				Suppressed:    stored.DiscardedBy[filter.FilterSuppression],
 				Cached:        true,
 				SearchID:      stored.ID,
 				CNAEHintCodes: stored.CNAEHintCodes,
+				Center:        stored.Center,
+				RadiusKm:      stored.RadiusKm,
+				Cities:        stored.Cities,
+				PartialCities: location.Incomplete(req),
+				Merges:        stored.Merges,
+				AIDiscards:    stored.AIDiscards,
 				StartedAt:     stored.CreatedAt,
 				DurationMs:    stored.DurationMs,
 				Leads:         leads,
+
+				DiscardedLeads: discarded,
 			}
 			// Warm Redis L1
 			if cfg.Redis != nil && cacheKey != "" {
@@ -88,29 +205,61 @@ func Run(ctx context.Context, req domain.SearchRequest, cfg Config) (*domain.Sea
 	}
 
 	// ── Phase 0c: CNAE hint discovery ────────────────────────────────────────
+	// Hints are cached per query in MongoDB; without it they are discovered
+	// again on every search.
 	var cnaeHintCodes []string
 	if cfg.Mongo != nil {
-		// Check MongoDB cache for CNAE hint
-		hint, _ := cfg.Mongo.GetCNAEHint(ctx, req.Query)
-		if hint != nil && len(hint.Codes) > 0 {
+		if hint, _ := cfg.Mongo.GetCNAEHint(ctx, req.Query); hint != nil && len(hint.Codes) > 0 {
 			cnaeHintCodes = hint.Codes
-		} else {
+		}
+	}
+	if len(cnaeHintCodes) == 0 {
+		var hint *domain.CNAEHintDoc
+		if u := understandQuery(ctx, req.Query, cfg); u != nil {
+			hint = &domain.CNAEHintDoc{
+				Query:      req.Query,
+				Codes:      u.Codes(),
+				Via:        "llm",
+				Category:   u.Category,
+				Synonyms:   u.Synonyms,
+				Candidates: u.Candidates,
+			}
+		} else if discovered, snippet, _ := cnae.DiscoverFromSearch(ctx, req.Query); len(discovered) > 0 {
 			// Discover from DuckDuckGo + Mojeek
-			discovered, snippet, _ := cnae.DiscoverFromSearch(ctx, req.Query)
-			if len(discovered) > 0 {
-				cnaeHintCodes = discovered
-				_ = cfg.Mongo.SaveCNAEHint(ctx, &domain.CNAEHintDoc{
-					Query:   req.Query,
-					Codes:   discovered,
-					Snippet: snippet,
-				})
+			hint = &domain.CNAEHintDoc{
+				Query:   req.Query,
+				Codes:   discovered,
+				Snippet: snippet,
+				Via:     "search",
+			}
+		}
+		if hint != nil {
+			cnaeHintCodes = hint.Codes
+			if cfg.Mongo != nil {
+				_ = cfg.Mongo.SaveCNAEHint(ctx, hint)
 			}
 		}
 	}
 
 	// ── Phase 1: Discovery ───────────────────────────────────────────────────
 	city, state := leadsearch.ParseLocation(req.Location)
-	rawLeads, _ := leadsearch.SearchAll(ctx, req.Query, req.Location, buildSearchers()...)
+	cities, err := location.Resolve(req)
+	if err != nil {
+		return nil, err
+	}
+	multiCity := len(cities) > 1
+
+	var rawLeads []*leadsearch.Lead
+	var origin map[*leadsearch.Lead]location.City
+	var merges []leadsearch.MergeRecord
+	var aiDiscards []leadsearch.AIDiscard
+	if multiCity {
+		rawLeads, origin, merges, aiDiscards = discoverCities(ctx, req.Query, cities)
+	} else {
+		var results []leadsearch.SearchResult
+		rawLeads, results, merges = leadsearch.SearchAllWithMerges(ctx, req.Query, req.Location, leadsearch.DefaultDedupConfig(), buildSearchers()...)
+		aiDiscards = discardsOf(results)
+	}
 
 	// ── Phase 2: Build base domain leads ─────────────────────────────────────
 	leads := make([]domain.Lead, 0, len(rawLeads))
@@ -119,23 +268,52 @@ func Run(ctx context.Context, req domain.SearchRequest, cfg Config) (*domain.Sea
 			continue
 		}
 		leads = append(leads, domain.Lead{
-			Name:     rl.Name,
-			Phone:    rl.Phone,
-			Phone2:   rl.Phone2,
-			Address:  rl.Address,
-			City:     rl.City,
-			State:    rl.State,
-			Category: rl.Category,
-			Website:  rl.Website,
-			Email:    rl.Email,
-			Source:   rl.Source,
+			Name:         rl.Name,
+			Phone:        rl.Phone,
+			Phone2:       rl.Phone2,
+			Address:      rl.Address,
+			City:         rl.City,
+			State:        rl.State,
+			Category:     rl.Category,
+			Website:      rl.Website,
+			Email:        rl.Email,
+			Source:       rl.Source,
+			OpeningHours: rl.OpeningHours,
+			Rating:       rl.Rating,
+			RatingCount:  atoi(rl.RatingCount),
+			PriceLevel:   rl.PriceLevel,
+			Phones:       rl.Phones,
+			Sources:      rl.Sources,
+			Unverified:   rl.Unverified,
+			Checks:       rl.Checks,
+			Provenance:   rl.Provenance,
+			Lat:          rl.Lat,
+			Lon:          rl.Lon,
 		})
+		if c, ok := origin[rl]; ok {
+			leads[len(leads)-1].SearchCity = c.Location()
+		}
 	}
+	cityCounts := countByCity(cities, leads, multiCity)
+
+	// ── Phase 2a: Suppression lists (post-dedup) ─────────────────────────────
+	var discarded []domain.DiscardedLead
+	leads, discarded = filter.BySuppression(leads, cfg.Suppress)
 
 	// ── Phase 2b: Name-relevance pre-filter (always-on) ──────────────────────
-	var totalDiscarded int
-	leads, disc0 := filter.ByNameRelevance(leads, req.Query)
-	totalDiscarded += disc0
+	leads, d0 := filter.ByNameRelevance(leads, req.Query)
+	discarded = append(discarded, d0...)
+
+	// ── Phase 2c: Radius filter ──────────────────────────────────────────────
+	var center *domain.GeoPoint
+	if req.RadiusKm > 0 || req.Center != nil {
+		center = resolveCenter(ctx, req, cities[0])
+		if center != nil {
+			var dr []domain.DiscardedLead
+			leads, dr = filter.ByRadius(leads, *center, req.RadiusKm)
+			discarded = append(discarded, dr...)
+		}
+	}
 
 	// ── Phase 3: CNPJ enrichment ─────────────────────────────────────────────
 	if req.EnrichCNPJ && len(leads) > 0 {
@@ -158,10 +336,10 @@ func Run(ctx context.Context, req domain.SearchRequest, cfg Config) (*domain.Sea
 			compatibleCodes = cnae.StaticCompatibleCodes(req.Query)
 		}
 
-		var d1, d2 int
-		leads, d1 = filter.ByLocation(leads, city, state)
+		var d1, d2 []domain.DiscardedLead
+		leads, d1 = filter.ByLocation(leads, places(cities)...)
 		leads, d2 = filter.ByCategory(leads, compatibleCodes)
-		totalDiscarded += d1 + d2
+		discarded = append(append(discarded, d1...), d2...)
 	}
 
 	// ── Phase 4: Instagram enrichment ────────────────────────────────────────
@@ -169,17 +347,55 @@ func Run(ctx context.Context, req domain.SearchRequest, cfg Config) (*domain.Sea
 		leads = enrichInstagramConcurrent(ctx, leads, city, cfg)
 	}
 
+	// ── Phase 4b: Website enrichment ─────────────────────────────────────────
+	if req.EnrichWebsite && len(leads) > 0 {
+		leads = enrichWebsiteConcurrent(ctx, leads, city, state, cfg)
+	}
+
+	// ── Phase 4c: Phone normalization ────────────────────────────────────────
+	annotatePhones(leads)
+
+	// ── Phase 4d: E-mail validation ──────────────────────────────────────────
+	if len(leads) > 0 {
+		v := cfg.Email
+		if v == nil {
+			v = emailcheck.New(nil)
+		}
+		leads = validateEmailsConcurrent(ctx, leads, v)
+	}
+
+	// ── Phase 4e: Opening hours ──────────────────────────────────────────────
+	annotateSchedules(leads)
+
+	// ── Phase 4f: Suppression lists (post-enrichment) ────────────────────────
+	if cfg.Suppress != nil {
+		var ds []domain.DiscardedLead
+		leads, ds = filter.BySuppression(leads, cfg.Suppress)
+		discarded = append(discarded, ds...)
+	}
+
 	// ── Phase 5: Build response ───────────────────────────────────────────────
+	discardedBy := countDiscards(nil, discarded)
 	resp := &domain.SearchResponse{
 		Query:         req.Query,
 		Location:      req.Location,
 		Total:         len(leads),
-		Discarded:     totalDiscarded,
+		Discarded:     len(discarded),
+		DiscardedBy:   discardedBy,
+		Suppressed:    discardedBy[filter.FilterSuppression],
 		Cached:        false,
 		CNAEHintCodes: cnaeHintCodes,
+		Center:        center,
+		RadiusKm:      req.RadiusKm,
+		Cities:        finalizeCityCounts(cityCounts, leads),
+		PartialCities: location.Incomplete(req),
+		Merges:        toMerges(merges),
+		AIDiscards:    toAIDiscards(aiDiscards),
 		StartedAt:     start,
 		DurationMs:    time.Since(start).Milliseconds(),
 		Leads:         leads,
+
+		DiscardedLeads: discarded,
 	}
 
 	// ── Phase 6: Persist to MongoDB ───────────────────────────────────────────
@@ -189,26 +405,214 @@ func Run(ctx context.Context, req domain.SearchRequest, cfg Config) (*domain.Sea
 			Location:        req.Location,
 			EnrichCNPJ:      req.EnrichCNPJ,
 			EnrichInstagram: req.EnrichInstagram,
+			EnrichWebsite:   req.EnrichWebsite,
+			RadiusKm:        req.RadiusKm,
+			Center:          center,
+			Region:          req.Region,
+			Cities:          resp.Cities,
+			Variant:         req.Variant(),
 			Total:           resp.Total,
 			Discarded:       resp.Discarded,
+			DiscardedBy:     resp.DiscardedBy,
 			DurationMs:      resp.DurationMs,
 			CNAEHintCodes:   cnaeHintCodes,
+			Merges:          resp.Merges,
+			AIDiscards:      resp.AIDiscards,
 		}
 		if id, err := cfg.Mongo.SaveSearch(ctx, doc); err == nil {
 			resp.SearchID = id
-			// Save individual results linked to search_id
+			// Save individual results (and the discarded leads) linked to search_id
 			_ = cfg.Mongo.SaveResults(ctx, id, leads)
+			_ = cfg.Mongo.SaveDiscarded(ctx, id, withoutSuppressed(discarded))
 		}
 	}
 
 	// ── Phase 7: Cache in Redis ────────────────────────────────────────────────
 	if cfg.Redis != nil && cacheKey != "" {
-		_ = cfg.Redis.SetSearch(ctx, cacheKey, resp)
+		cached := *resp
+		cached.DiscardedLeads = withoutSuppressed(discarded)
+		_ = cfg.Redis.SetSearch(ctx, cacheKey, &cached)
 	}
 
 	return resp, nil
 }
 
+// understandQuery runs the LLM query understanding when an LLM is
+// configured. Returns nil when it is disabled, fails or finds no confident
+// CNAE subclass, so the caller falls back to the search-engine discovery.
+func understandQuery(ctx context.Context, query string, cfg Config) *cnae.Understanding {
+	if cfg.LLM == nil {
+		return nil
+	}
+	var table cnae.Table
+	if cfg.Mongo != nil {
+		table = cfg.Mongo
+	}
+	u, err := cnae.Understand(ctx, cfg.LLM, table, query)
+	if err != nil || len(u.Codes()) == 0 {
+		return nil
+	}
+	return u
+}
+
+// ─── Multi-city discovery ──────────────────────────────────────────────────────
+
+// discoverCities runs SearchAll for each city (cityWorkers at a time) and
+// dedups the union, so a business listed in two neighbouring cities appears
+// once. origin maps each surviving lead to the city whose search found it.
+func discoverCities(ctx context.Context, query string, cities []location.City) ([]*leadsearch.Lead, map[*leadsearch.Lead]location.City, []leadsearch.MergeRecord, []leadsearch.AIDiscard) {
+	cfg := leadsearch.DefaultDedupConfig()
+	perCity := make([][]*leadsearch.Lead, len(cities))
+	perCityMerges := make([][]leadsearch.MergeRecord, len(cities))
+	perCityResults := make([][]leadsearch.SearchResult, len(cities))
+	sem := make(chan struct{}, cityWorkers)
+	var wg sync.WaitGroup
+
+	for i, c := range cities {
+		wg.Add(1)
+		go func(idx int, c location.City) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if ctx.Err() != nil {
+				return
+			}
+			perCity[idx], perCityResults[idx], perCityMerges[idx] = leadsearch.SearchAllWithMerges(ctx, query, c.Location(), cfg, buildSearchers()...)
+		}(i, c)
+	}
+	wg.Wait()
+
+	origin := make(map[*leadsearch.Lead]location.City)
+	var all []*leadsearch.Lead
+	var merges []leadsearch.MergeRecord
+	var discards []leadsearch.AIDiscard
+	for i, ls := range perCity {
+		for _, l := range ls {
+			origin[l] = cities[i]
+		}
+		all = append(all, ls...)
+		merges = append(merges, perCityMerges[i]...)
+		discards = append(discards, discardsOf(perCityResults[i])...)
+	}
+
+	// Deduplicate keeps the first pointer of each group, so origin stays valid
+	merged, crossCity := leadsearch.DeduplicateWithLog(all, cfg)
+	for _, l := range merged {
+		l.AnnotatePhone()
+	}
+	return merged, origin, append(merges, crossCity...), discards
+}
+
+// toMerges converts the find-leads merge log for the response.
+func toMerges(records []leadsearch.MergeRecord) []domain.Merge {
+	if len(records) == 0 {
+		return nil
+	}
+	out := make([]domain.Merge, len(records))
+	for i, r := range records {
+		out[i] = domain.Merge{
+			Kept:    r.Kept,
+			Merged:  r.Merged,
+			Sources: []string{r.KeptSource, r.MergedSource},
+			Score:   r.Score,
+			Reasons: r.Reasons,
+		}
+	}
+	return out
+}
+
+// discardsOf collects the AI leads each source lost to the hallucination
+// guard (see leadsearch.VerifyAILeads).
+func discardsOf(results []leadsearch.SearchResult) []leadsearch.AIDiscard {
+	var out []leadsearch.AIDiscard
+	for _, r := range results {
+		out = append(out, r.Discarded...)
+	}
+	return out
+}
+
+// toAIDiscards converts the discarded AI leads for the response.
+func toAIDiscards(discards []leadsearch.AIDiscard) []domain.AIDiscard {
+	if len(discards) == 0 {
+		return nil
+	}
+	out := make([]domain.AIDiscard, len(discards))
+	for i, d := range discards {
+		out[i] = domain.AIDiscard{Name: d.Name, Source: d.Source, Reason: d.Reason}
+	}
+	return out
+}
+
+// withoutSuppressed drops the leads removed by the suppression lists before
+// the discarded leads are persisted or cached: neither the results
+// collection nor the Redis key is scoped by tenant, and those leads (with
+// the list entry they matched) are the tenant's customers and competitors.
+// DiscardedBy still counts them.
+func withoutSuppressed(discarded []domain.DiscardedLead) []domain.DiscardedLead {
+	out := make([]domain.DiscardedLead, 0, len(discarded))
+	for _, d := range discarded {
+		if d.Filter != filter.FilterSuppression {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// countByCity counts discovered leads per searched city (multi-city only).
+func countByCity(cities []location.City, leads []domain.Lead, multiCity bool) []domain.CityCount {
+	if !multiCity {
+		return nil
+	}
+	counts := make([]domain.CityCount, len(cities))
+	idx := make(map[string]int, len(cities))
+	for i, c := range cities {
+		counts[i] = domain.CityCount{City: c.Name, UF: c.UF}
+		idx[c.Location()] = i
+	}
+	for _, l := range leads {
+		if i, ok := idx[l.SearchCity]; ok {
+			counts[i].Found++
+		}
+	}
+	return counts
+}
+
+// finalizeCityCounts fills how many leads of each city made it to the response.
+func finalizeCityCounts(counts []domain.CityCount, leads []domain.Lead) []domain.CityCount {
+	if len(counts) == 0 {
+		return nil
+	}
+	idx := make(map[string]int, len(counts))
+	for i, c := range counts {
+		counts[i].Leads = 0
+		idx[c.City+" - "+c.UF] = i
+	}
+	for _, l := range leads {
+		if i, ok := idx[l.SearchCity]; ok {
+			counts[i].Leads++
+		}
+	}
+	return counts
+}
+
+// places converts the searched cities into ByLocation targets.
+func places(cities []location.City) []filter.Place {
+	out := make([]filter.Place, len(cities))
+	for i, c := range cities {
+		out[i] = filter.Place{City: c.Name, State: c.UF, IBGECode: c.IBGECode}
+	}
+	return out
+}
+
+// leadCity returns the city/UF to use when enriching l: the city whose search
+// found it in multi-city mode, otherwise the request's location.
+func leadCity(l domain.Lead, city, state string) (string, string) {
+	if l.SearchCity != "" {
+		return leadsearch.ParseLocation(l.SearchCity)
+	}
+	return city, state
+}
+
 // ─── CNPJ concurrent enrichment ───────────────────────────────────────────────
 
 func enrichCNPJConcurrent(
@@ -233,7 +637,8 @@ func enrichCNPJConcurrent(
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			res, err := enrichment.EnrichCNPJ(ctx, enriched[idx].Name, city, state, query, cfg.Redis, cfg.Mongo)
+			c, st := leadCity(enriched[idx], city, state)
+			res, err := enrichment.EnrichCNPJ(ctx, enriched[idx].Name, c, st, query, cfg.Redis, cfg.Mongo)
 			if err != nil {
 				return
 			}
@@ -244,9 +649,15 @@ func enrichCNPJConcurrent(
 			enriched[idx].NomeFantasia = res.NomeFantasia
 			enriched[idx].Situacao = res.Situacao
 			enriched[idx].Partners = res.Partners
+			enriched[idx].CNAECode = res.CNAECode
+			enriched[idx].CNAESecondary = res.CNAESecondary
 			enriched[idx].CNAEDesc = res.CNAEDesc
 			enriched[idx].Municipio = res.Municipio
 			enriched[idx].UF = res.UF
+			if m, ok := ibge.Default().Find(res.Municipio, res.UF); ok {
+				enriched[idx].IBGECode = m.Code
+			}
+			setField(&enriched[idx], "email", &enriched[idx].Email, res.Email, "cnpj")
 			if res.CNAEMatch {
 				enriched[idx].CNAEMatch = &t
 			} else {
@@ -281,7 +692,8 @@ func enrichInstagramConcurrent(
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			res, err := enrichment.EnrichInstagram(ctx, enriched[idx].Name, city, cfg.Redis, cfg.Mongo)
+			c, _ := leadCity(enriched[idx], city, "")
+			res, err := enrichment.EnrichInstagram(ctx, enriched[idx].Name, c, cfg.Redis, cfg.Mongo)
 			if err != nil {
 				return
 			}
@@ -296,8 +708,197 @@ func enrichInstagramConcurrent(
 	return enriched
 }
 
+// ─── Website concurrent enrichment ────────────────────────────────────────────
+
+func enrichWebsiteConcurrent(
+	ctx context.Context,
+	leads []domain.Lead,
+	city, state string,
+	cfg Config,
+) []domain.Lead {
+	sem := make(chan struct{}, websiteWorkers)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	enriched := make([]domain.Lead, len(leads))
+	copy(enriched, leads)
+
+	for i := range enriched {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			mu.Lock()
+			l := enriched[idx]
+			mu.Unlock()
+
+			hints := leadsearch.WebsiteHints{Website: l.Website, Email: l.Email, Instagram: l.Instagram}
+			c, st := leadCity(l, city, state)
+			res, err := enrichment.EnrichWebsite(ctx, l.Name, c, st, hints, cfg.Redis, cfg.Mongo)
+			if err != nil {
+				return
+			}
+
+			// Reuses find-leads' fill-empty-fields policy so provenance is
+			// recorded the same way in the CLI and the API.
+			tmp := &leadsearch.Lead{
+				Website:      l.Website,
+				Email:        l.Email,
+				Address:      l.Address,
+				OpeningHours: l.OpeningHours,
+				Rating:       l.Rating,
+				RatingCount:  itoa(l.RatingCount),
+				PriceLevel:   l.PriceLevel,
+				Lat:          l.Lat,
+				Lon:          l.Lon,
+				Phone:        l.Phone,
+				Phone2:       l.Phone2,
+				Phones:       l.Phones,
+				CNPJ:         l.CNPJ,
+				Instagram:    l.Instagram,
+				WhatsApp:     l.WhatsApp,
+				SocialLinks:  l.SocialLinks,
+				Provenance:   l.Provenance,
+			}
+			leadsearch.ApplyWebsiteInfo(tmp, res)
+
+			mu.Lock()
+			enriched[idx].Website = tmp.Website
+			enriched[idx].Email = tmp.Email
+			enriched[idx].Address = tmp.Address
+			enriched[idx].OpeningHours = tmp.OpeningHours
+			enriched[idx].Rating = tmp.Rating
+			enriched[idx].RatingCount = atoi(tmp.RatingCount)
+			enriched[idx].PriceLevel = tmp.PriceLevel
+			enriched[idx].Lat, enriched[idx].Lon = tmp.Lat, tmp.Lon
+			enriched[idx].Phone = tmp.Phone
+			enriched[idx].Phone2 = tmp.Phone2
+			enriched[idx].Phones = tmp.Phones
+			enriched[idx].CNPJ = tmp.CNPJ
+			enriched[idx].Instagram = tmp.Instagram
+			enriched[idx].WhatsApp = tmp.WhatsApp
+			enriched[idx].SocialLinks = tmp.SocialLinks
+			enriched[idx].Provenance = tmp.Provenance
+			mu.Unlock()
+		}(i)
+	}
+	wg.Wait()
+	return enriched
+}
+
+// ─── Phone normalization ──────────────────────────────────────────────────────
+
+// annotatePhones classifies each lead's main phone. The UF from the CNPJ
+// registry, when present, takes precedence over the scraper's state.
+func annotatePhones(leads []domain.Lead) {
+	for i := range leads {
+		l := &leads[i]
+		tmp := &leadsearch.Lead{
+			Phone:    l.Phone,
+			Phone2:   l.Phone2,
+			State:    l.State,
+			UF:       l.UF,
+			WhatsApp: l.WhatsApp,
+		}
+		tmp.AnnotatePhone()
+		l.PhoneE164 = tmp.PhoneE164
+		l.PhoneType = tmp.PhoneType
+		l.PhoneStateMismatch = tmp.PhoneStateMismatch
+		l.WhatsAppLink = tmp.WhatsAppLink()
+	}
+}
+
+// ─── Opening hours ────────────────────────────────────────────────────────────
+
+// annotateSchedules fills the weekly schedule of leads whose opening hours
+// are recognized.
+func annotateSchedules(leads []domain.Lead) {
+	for i := range leads {
+		if s, ok := leadsearch.ParseOpeningHours(leads[i].OpeningHours); ok {
+			leads[i].OpeningSchedule = s.Weekly()
+		}
+	}
+}
+
+// ─── E-mail validation ────────────────────────────────────────────────────────
+
+func validateEmailsConcurrent(ctx context.Context, leads []domain.Lead, v *emailcheck.Validator) []domain.Lead {
+	sem := make(chan struct{}, emailWorkers)
+	var wg sync.WaitGroup
+
+	for i := range leads {
+		if leads[i].Email == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(l *domain.Lead) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			res := v.Check(ctx, l.Email)
+			l.EmailStatus = string(res.Status)
+			l.EmailFlags = res.Flags
+		}(&leads[i])
+	}
+	wg.Wait()
+	return leads
+}
+
 // ─── Helpers ──────────────────────────────────────────────────────────────────
 
+// resolveCenter returns the request's center, the IBGE centroid of the
+// (first) searched city, or geocodes it. Region searches have no natural
+// center, so they require an explicit one.
+// Returns nil when geocoding fails (the radius filter is then skipped).
+func resolveCenter(ctx context.Context, req domain.SearchRequest, c location.City) *domain.GeoPoint {
+	if req.Center != nil {
+		return req.Center
+	}
+	if req.Region != "" {
+		return nil
+	}
+	if c.Lat != 0 || c.Lon != 0 {
+		return &domain.GeoPoint{Lat: c.Lat, Lon: c.Lon}
+	}
+	tctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	lat, lon, err := leadsearch.GeocodeCity(tctx, c.Name, c.UF)
+	if err != nil {
+		return nil
+	}
+	return &domain.GeoPoint{Lat: lat, Lon: lon}
+}
+
+// setField fills *dst only when empty and records where the value came from.
+func setField(l *domain.Lead, field string, dst *string, value, source string) {
+	value = strings.TrimSpace(value)
+	if *dst != "" || value == "" {
+		return
+	}
+	*dst = value
+	if l.Provenance == nil {
+		l.Provenance = make(map[string]string)
+	}
+	l.Provenance[field] = source
+}
+
+// atoi parses a digits-only count; invalid or empty values become 0.
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// itoa is the inverse of atoi; 0 becomes "".
+func itoa(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
 // mergeUnique appends elements from src to dst, skipping duplicates.
 func mergeUnique(dst, src []string) []string {
 	seen := make(map[string]bool, len(dst))
@@ -330,6 +931,15 @@ func extractKeywords(query string) []string {
 
 // ─── Scraper wiring ───────────────────────────────────────────────────────────
 
+// definitions are directory scraper definitions loaded at startup (see
+// SetDefinitions); they replace built-in scrapers with the same name or are
+// added as new sources.
+var definitions []*leadsearch.Definition
+
+// SetDefinitions installs directory scraper definitions. Call it before
+// serving requests.
+func SetDefinitions(defs []*leadsearch.Definition) { definitions = defs }
+
 func buildSearchers() []leadsearch.Searcher {
 	s := []leadsearch.Searcher{
 		leadsearch.NewOverpassScraper(),
@@ -359,5 +969,8 @@ func buildSearchers() []leadsearch.Searcher {
 	if key := os.Getenv("GEMINI_API_KEY"); key != "" {
 		s = append(s, leadsearch.NewGeminiScraper(key))
 	}
-	return s
+	if llm := leadsearch.NewLocalLLMScraper(); llm != nil {
+		s = append(s, llm)
+	}
+	return leadsearch.ApplyDefinitions(s, definitions)
 }
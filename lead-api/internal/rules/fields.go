@@ -0,0 +1,133 @@
+package rules
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/lucasfdcampos/lead-api/internal/domain"
+)
+
+type field struct {
+	kind kind
+	get  func(*domain.Lead) value
+}
+
+// fields are the lead fields rules can read, by JSON name. Numbers a lead
+// doesn't have (empty followers, rating_count 0, no coordinates) are unset.
+// uf falls back to the scraped state when the lead has no CNPJ data.
+var fields = map[string]field{
+	// strings
+	"name":              str(func(l *domain.Lead) string { return l.Name }),
+	"phone":             str(func(l *domain.Lead) string { return l.Phone }),
+	"phone2":            str(func(l *domain.Lead) string { return l.Phone2 }),
+	"phone_e164":        str(func(l *domain.Lead) string { return l.PhoneE164 }),
+	"phone_type":        str(func(l *domain.Lead) string { return l.PhoneType }),
+	"whatsapp":          str(func(l *domain.Lead) string { return l.WhatsApp }),
+	"whatsapp_link":     str(func(l *domain.Lead) string { return l.WhatsAppLink }),
+	"address":           str(func(l *domain.Lead) string { return l.Address }),
+	"city":              str(func(l *domain.Lead) string { return l.City }),
+	"state":             str(func(l *domain.Lead) string { return l.State }),
+	"search_city":       str(func(l *domain.Lead) string { return l.SearchCity }),
+	"category":          str(func(l *domain.Lead) string { return l.Category }),
+	"website":           str(func(l *domain.Lead) string { return l.Website }),
+	"email":             str(func(l *domain.Lead) string { return l.Email }),
+	"email_status":      str(func(l *domain.Lead) string { return l.EmailStatus }),
+	"source":            str(func(l *domain.Lead) string { return l.Source }),
+	"opening_hours":     str(func(l *domain.Lead) string { return l.OpeningHours }),
+	"price_level":       str(func(l *domain.Lead) string { return l.PriceLevel }),
+	"cnpj":              str(func(l *domain.Lead) string { return l.CNPJ }),
+	"razao_social":      str(func(l *domain.Lead) string { return l.RazaoSocial }),
+	"nome_fantasia":     str(func(l *domain.Lead) string { return l.NomeFantasia }),
+	"situacao":          str(func(l *domain.Lead) string { return l.Situacao }),
+	"cnae_code":         str(func(l *domain.Lead) string { return l.CNAECode }),
+	"cnae_matched_code": str(func(l *domain.Lead) string { return l.CNAEMatchedCode }),
+	"cnae_desc":         str(func(l *domain.Lead) string { return l.CNAEDesc }),
+	"municipio":         str(func(l *domain.Lead) string { return l.Municipio }),
+	"ibge_code":         str(func(l *domain.Lead) string { return l.IBGECode }),
+	"instagram":         str(func(l *domain.Lead) string { return l.Instagram }),
+	"uf": str(func(l *domain.Lead) string {
+		if l.UF != "" {
+			return l.UF
+		}
+		return l.State
+	}),
+
+	// numbers
+	"followers":    num(func(l *domain.Lead) (float64, bool) { return parseCount(l.Followers) }),
+	"rating":       num(func(l *domain.Lead) (float64, bool) { return parseDecimal(l.Rating) }),
+	"rating_count": num(func(l *domain.Lead) (float64, bool) { return float64(l.RatingCount), l.RatingCount > 0 }),
+	"lat":          num(func(l *domain.Lead) (float64, bool) { return l.Lat, l.Lat != 0 || l.Lon != 0 }),
+	"lon":          num(func(l *domain.Lead) (float64, bool) { return l.Lon, l.Lat != 0 || l.Lon != 0 }),
+	"distance_km": num(func(l *domain.Lead) (float64, bool) {
+		if l.DistanceKm == nil {
+			return 0, false
+		}
+		return *l.DistanceKm, true
+	}),
+
+	// booleans
+	"unverified":           boolean(func(l *domain.Lead) bool { return l.Unverified }),
+	"phone_state_mismatch": boolean(func(l *domain.Lead) bool { return l.PhoneStateMismatch }),
+	"cnae_match":           boolean(func(l *domain.Lead) bool { return l.CNAEMatch != nil && *l.CNAEMatch }),
+
+	// lists
+	"phones":         list(func(l *domain.Lead) []string { return l.Phones }),
+	"sources":        list(func(l *domain.Lead) []string { return l.Sources }),
+	"partners":       list(func(l *domain.Lead) []string { return l.Partners }),
+	"cnae_secondary": list(func(l *domain.Lead) []string { return l.CNAESecondary }),
+	"email_flags":    list(func(l *domain.Lead) []string { return l.EmailFlags }),
+	"checks":         list(func(l *domain.Lead) []string { return l.Checks }),
+}
+
+func str(f func(*domain.Lead) string) field {
+	return field{kind: kString, get: func(l *domain.Lead) value { return value{s: f(l)} }}
+}
+
+func num(f func(*domain.Lead) (float64, bool)) field {
+	return field{kind: kNumber, get: func(l *domain.Lead) value {
+		n, ok := f(l)
+		return value{n: n, ok: ok}
+	}}
+}
+
+func boolean(f func(*domain.Lead) bool) field {
+	return field{kind: kBool, get: func(l *domain.Lead) value { return value{b: f(l)} }}
+}
+
+func list(f func(*domain.Lead) []string) field {
+	return field{kind: kList, get: func(l *domain.Lead) value { return value{list: f(l)} }}
+}
+
+// parseCount reads follower counts as the Instagram scrapers write them:
+// "523", "1,234", "1.2K", "15.3M", "1,2 mil".
+func parseCount(s string) (float64, bool) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	mult := 1.0
+	switch {
+	case strings.HasSuffix(s, "MIL"):
+		mult, s = 1e3, s[:len(s)-3]
+	case strings.HasSuffix(s, "K"):
+		mult, s = 1e3, s[:len(s)-1]
+	case strings.HasSuffix(s, "M"):
+		mult, s = 1e6, s[:len(s)-1]
+	case strings.HasSuffix(s, "B"):
+		mult, s = 1e9, s[:len(s)-1]
+	}
+	s = strings.TrimSpace(s)
+	if mult > 1 {
+		s = strings.ReplaceAll(s, ",", ".") // decimal separator
+	} else {
+		s = strings.NewReplacer(",", "", ".", "").Replace(s) // thousands separator
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n * mult, true
+}
+
+// parseDecimal reads ratings such as "4.5" or "4,5".
+func parseDecimal(s string) (float64, bool) {
+	n, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(s), ",", "."), 64)
+	return n, err == nil
+}
@@ -0,0 +1,389 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+)
+
+// ─── Lexer ────────────────────────────────────────────────────────────────────
+
+type tokKind int
+
+const (
+	tEOF tokKind = iota
+	tIdent
+	tString
+	tNumber
+	tOp // operators and punctuation
+)
+
+type token struct {
+	kind tokKind
+	text string // unquoted for strings
+	pos  int    // 1-based column
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tEOF:
+		return "end of rule"
+	case tString:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// twoCharOps must be tried before the one-character ones.
+var twoCharOps = []string{"==", "!=", "<=", ">=", "&&", "||", "!~"}
+
+const oneCharOps = "<>!~()[],"
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case isLetter(c):
+			j := i + 1
+			for j < len(src) && (isLetter(src[j]) || isDigit(src[j])) {
+				j++
+			}
+			toks = append(toks, token{kind: tIdent, text: src[i:j], pos: i + 1})
+			i = j
+		case isDigit(c) || c == '-' && i+1 < len(src) && isDigit(src[i+1]):
+			j := i + 1
+			for j < len(src) && (isDigit(src[j]) || src[j] == '.') {
+				j++
+			}
+			if _, err := strconv.ParseFloat(src[i:j], 64); err != nil {
+				return nil, &Error{Rule: src, Column: i + 1, Msg: fmt.Sprintf("invalid number %q", src[i:j])}
+			}
+			toks = append(toks, token{kind: tNumber, text: src[i:j], pos: i + 1})
+			i = j
+		case c == '"' || c == '\'':
+			s, n, ok := scanString(src[i:])
+			if !ok {
+				return nil, &Error{Rule: src, Column: i + 1, Msg: "unterminated string"}
+			}
+			toks = append(toks, token{kind: tString, text: s, pos: i + 1})
+			i += n
+		default:
+			op := ""
+			for _, o := range twoCharOps {
+				if strings.HasPrefix(src[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" && strings.IndexByte(oneCharOps, c) >= 0 {
+				op = src[i : i+1]
+			}
+			if op == "" {
+				msg := fmt.Sprintf("unexpected character %q", src[i:i+1])
+				switch c {
+				case '=':
+					msg = `unexpected "=" (use "==")`
+				case '&', '|':
+					msg = fmt.Sprintf("unexpected %q (use %q)", src[i:i+1], src[i:i+1]+src[i:i+1])
+				}
+				return nil, &Error{Rule: src, Column: i + 1, Msg: msg}
+			}
+			toks = append(toks, token{kind: tOp, text: op, pos: i + 1})
+			i += len(op)
+		}
+	}
+	return append(toks, token{kind: tEOF, pos: len(src) + 1}), nil
+}
+
+// scanString reads a quoted string at the start of s, returning its value
+// and the bytes consumed. A backslash escapes the next character.
+func scanString(s string) (string, int, bool) {
+	quote := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == quote:
+			return b.String(), i + 1, true
+		case c == '\\' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(s[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, false
+}
+
+func isLetter(c byte) bool { return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+
+// ─── Parser ───────────────────────────────────────────────────────────────────
+
+type parser struct {
+	src  string
+	toks []token
+	i    int
+}
+
+func (p *parser) peek() token { return p.toks[p.i] }
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tEOF {
+		p.i++
+	}
+	return t
+}
+
+// is reports whether the next token is the operator or keyword text.
+func (p *parser) is(text string) bool {
+	t := p.peek()
+	return (t.kind == tOp || t.kind == tIdent) && t.text == text
+}
+
+func (p *parser) expect(text string) (token, error) {
+	if !p.is(text) {
+		t := p.peek()
+		return t, p.errf(t.pos, "expected %q, got %s", text, t)
+	}
+	return p.next(), nil
+}
+
+func (p *parser) errf(col int, format string, args ...any) *Error {
+	return &Error{Rule: p.src, Column: col, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parseOr() (node, error) {
+	return p.parseLogic("||", false, p.parseAnd)
+}
+
+func (p *parser) parseAnd() (node, error) {
+	return p.parseLogic("&&", true, p.parseUnary)
+}
+
+func (p *parser) parseLogic(op string, and bool, operand func() (node, error)) (node, error) {
+	l, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.is(op) {
+		t := p.next()
+		r, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		for _, x := range []node{l, r} {
+			if x.kind() != kBool {
+				return nil, p.errf(x.col(), "%q needs true/false on both sides, got a %s", op, x.kind())
+			}
+		}
+		l = &logicNode{base: base{k: kBool, at: t.pos}, and: and, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.is("!") {
+		t := p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if x.kind() != kBool {
+			return nil, p.errf(x.col(), `"!" needs true/false, got a %s`, x.kind())
+		}
+		return &notNode{base: base{k: kBool, at: t.pos}, x: x}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (node, error) {
+	l, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	switch {
+	case t.kind == tOp && (t.text == "==" || t.text == "!=" || t.text == "<" || t.text == "<=" || t.text == ">" || t.text == ">="):
+		p.next()
+		r, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return p.compare(t, l, r)
+	case t.kind == tOp && (t.text == "~" || t.text == "!~"):
+		p.next()
+		return p.match(t, l)
+	case t.kind == tIdent && (t.text == "in" || t.text == "not"):
+		p.next()
+		if t.text == "not" {
+			if _, err := p.expect("in"); err != nil {
+				return nil, err
+			}
+		}
+		r, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return p.in(t, l, r)
+	}
+	return l, nil
+}
+
+func (p *parser) compare(op token, l, r node) (node, error) {
+	switch {
+	case l.kind() == kList || r.kind() == kList:
+		return nil, p.errf(op.pos, "%s can't compare lists (use in, ~ or len())", op)
+	case l.kind() != r.kind():
+		return nil, p.errf(op.pos, "%s compares a %s with a %s", op, l.kind(), r.kind())
+	case l.kind() != kNumber && op.text != "==" && op.text != "!=":
+		return nil, p.errf(op.pos, "%s needs numbers, got a %s", op, l.kind())
+	}
+	return &cmpNode{base: base{k: kBool, at: op.pos}, op: op.text, l: l, r: r}, nil
+}
+
+func (p *parser) match(op token, l node) (node, error) {
+	if l.kind() != kString && l.kind() != kList {
+		return nil, p.errf(op.pos, "%s needs a string or list on the left, got a %s", op, l.kind())
+	}
+	t := p.next()
+	if t.kind != tString {
+		return nil, p.errf(t.pos, "%s needs a quoted regular expression, got %s", op, t)
+	}
+	re, err := regexp.Compile("(?i)" + t.text)
+	if err != nil {
+		msg := err.Error()
+		var se *syntax.Error
+		if errors.As(err, &se) {
+			msg = se.Code.String()
+		}
+		return nil, p.errf(t.pos, "invalid regular expression %q: %s", t.text, msg)
+	}
+	return &matchNode{base: base{k: kBool, at: op.pos}, x: l, re: re, neg: op.text == "!~"}, nil
+}
+
+func (p *parser) in(op token, l, r node) (node, error) {
+	if l.kind() != kString && l.kind() != kNumber {
+		return nil, p.errf(l.col(), "in needs a string or number on the left, got a %s", l.kind())
+	}
+	if r.kind() != kList {
+		return nil, p.errf(r.col(), "in needs a list on the right, got a %s", r.kind())
+	}
+	elem := kString
+	if lst, ok := r.(*listNode); ok && lst.elem != 0 {
+		elem = lst.elem
+	}
+	if l.kind() != elem {
+		return nil, p.errf(op.pos, "in looks for a %s in a list of %ss", l.kind(), elem)
+	}
+	return &inNode{base: base{k: kBool, at: op.pos}, x: l, set: r, neg: op.text == "not"}, nil
+}
+
+func (p *parser) parseOperand() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tString:
+		return &litNode{base: base{k: kString, at: t.pos}, v: value{s: t.text}}, nil
+	case tNumber:
+		n, _ := strconv.ParseFloat(t.text, 64) // validated by lex
+		return &litNode{base: base{k: kNumber, at: t.pos}, v: value{n: n, ok: true}}, nil
+	case tIdent:
+		switch t.text {
+		case "true", "false":
+			return &litNode{base: base{k: kBool, at: t.pos}, v: value{b: t.text == "true"}}, nil
+		case "has", "len":
+			if p.is("(") {
+				return p.call(t)
+			}
+		}
+		return p.field(t)
+	case tOp:
+		switch t.text {
+		case "(":
+			x, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		case "[":
+			return p.list(t)
+		}
+	}
+	return nil, p.errf(t.pos, "unexpected %s", t)
+}
+
+func (p *parser) field(t token) (*fieldNode, error) {
+	f, ok := fields[t.text]
+	if !ok {
+		return nil, p.errf(t.pos, "unknown field %q", t.text)
+	}
+	return &fieldNode{base: base{k: f.kind, at: t.pos}, name: t.text, get: f.get}, nil
+}
+
+func (p *parser) call(fn token) (node, error) {
+	p.next() // (
+	t := p.next()
+	if t.kind != tIdent {
+		return nil, p.errf(t.pos, "%s() takes a field name, got %s", fn.text, t)
+	}
+	f, err := p.field(t)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if fn.text == "has" {
+		return &hasNode{base: base{k: kBool, at: fn.pos}, f: f}, nil
+	}
+	if f.kind() != kString && f.kind() != kList {
+		return nil, p.errf(t.pos, "len() takes a string or list field, %s is a %s", f.name, f.kind())
+	}
+	return &lenNode{base: base{k: kNumber, at: fn.pos}, f: f}, nil
+}
+
+func (p *parser) list(open token) (node, error) {
+	lst := &listNode{base: base{k: kList, at: open.pos}}
+	for !p.is("]") {
+		if len(lst.items) > 0 {
+			if _, err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		t := p.next()
+		var it value
+		var k kind
+		switch t.kind {
+		case tString:
+			it, k = value{s: t.text}, kString
+		case tNumber:
+			n, _ := strconv.ParseFloat(t.text, 64)
+			it, k = value{n: n, ok: true}, kNumber
+		default:
+			return nil, p.errf(t.pos, "lists hold quoted strings or numbers, got %s", t)
+		}
+		if lst.elem != 0 && k != lst.elem {
+			return nil, p.errf(t.pos, "lists can't mix strings and numbers")
+		}
+		lst.elem = k
+		lst.items = append(lst.items, it)
+	}
+	p.next() // ]
+	return lst, nil
+}
@@ -0,0 +1,333 @@
+// Package rules compiles user-defined filter rules into safe predicates over
+// domain.Lead.
+//
+// A rule is a boolean expression; a lead passes a search's rules when every
+// one of them is true. Examples:
+//
+//	situacao == "ATIVA"
+//	has(phone) || has(instagram)
+//	followers >= 500
+//	uf in ["PR", "SC"]
+//	name !~ "ltda"
+//
+// Grammar, from lowest to highest precedence:
+//
+//	expr    = and { "||" and }
+//	and     = unary { "&&" unary }
+//	unary   = "!" unary | compare
+//	compare = operand [ op operand ]   op: == != < <= > >= ~ !~ in, not in
+//	operand = field | string | number | true | false | list | call | "(" expr ")"
+//	call    = has(field) | len(field)
+//	list    = "[" [ literal { "," literal } ] "]"
+//
+// Fields are the JSON names of domain.Lead (see fields.go). Strings are
+// compared ignoring case; ~ and !~ take a regular expression (RE2, matched
+// ignoring case) and, on list fields, match when any element does. followers
+// and rating are read as numbers ("1.2K" → 1200); a comparison against a
+// number the lead doesn't have is always false, so has(followers) tells
+// them apart.
+//
+// Rules are type-checked when compiled and can only read lead fields, so
+// evaluating a compiled rule never fails and always terminates.
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lucasfdcampos/lead-api/internal/domain"
+)
+
+// Limits on what a single search may ask for.
+const (
+	MaxRules   = 20  // rules per search or preset
+	MaxRuleLen = 500 // bytes per rule
+)
+
+// Rule is a compiled rule.
+type Rule struct {
+	src  string
+	root node
+}
+
+// String returns the rule source.
+func (r *Rule) String() string { return r.src }
+
+// Match reports whether the lead satisfies the rule.
+func (r *Rule) Match(l *domain.Lead) bool { return r.root.eval(l).b }
+
+// Error is a compile error, pointing at the column where it was found.
+type Error struct {
+	Rule   string // rule source
+	Column int    // 1-based byte offset in Rule
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("rule %q: column %d: %s", e.Rule, e.Column, e.Msg)
+}
+
+// Compile parses and type-checks a rule.
+func Compile(src string) (*Rule, error) {
+	src = strings.TrimSpace(src)
+	if src == "" {
+		return nil, &Error{Rule: src, Column: 1, Msg: "empty rule"}
+	}
+	if len(src) > MaxRuleLen {
+		return nil, &Error{Rule: src[:40] + "...", Column: MaxRuleLen + 1, Msg: fmt.Sprintf("rule longer than %d bytes", MaxRuleLen)}
+	}
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{src: src, toks: toks}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tEOF {
+		return nil, p.errf(t.pos, "unexpected %s", t)
+	}
+	if root.kind() != kBool {
+		msg := fmt.Sprintf("a rule must be true or false, got a %s", root.kind())
+		if f, ok := root.(*fieldNode); ok {
+			msg += fmt.Sprintf(" (use has(%s) to test that it is set)", f.name)
+		}
+		return nil, p.errf(root.col(), "%s", msg)
+	}
+	return &Rule{src: src, root: root}, nil
+}
+
+// CompileAll compiles the rules of a search or preset. Errors name the
+// index of the offending rule.
+func CompileAll(srcs []string) ([]*Rule, error) {
+	if len(srcs) > MaxRules {
+		return nil, fmt.Errorf("at most %d rules are allowed, got %d", MaxRules, len(srcs))
+	}
+	out := make([]*Rule, 0, len(srcs))
+	for i, src := range srcs {
+		r, err := Compile(src)
+		if err != nil {
+			return nil, fmt.Errorf("rules[%d]: %w", i, err)
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// ─── Values and types ─────────────────────────────────────────────────────────
+
+type kind int
+
+const (
+	kString kind = iota + 1
+	kNumber
+	kBool
+	kList // list of strings (fields) or of literals (in ...)
+)
+
+func (k kind) String() string {
+	switch k {
+	case kString:
+		return "string"
+	case kNumber:
+		return "number"
+	case kBool:
+		return "boolean"
+	case kList:
+		return "list"
+	}
+	return "value"
+}
+
+type value struct {
+	s    string
+	n    float64
+	ok   bool // n is set (numbers the lead doesn't have are !ok)
+	b    bool
+	list []string
+}
+
+// ─── Expression tree ──────────────────────────────────────────────────────────
+
+type node interface {
+	kind() kind
+	col() int
+	eval(l *domain.Lead) value
+}
+
+type base struct {
+	k  kind
+	at int
+}
+
+func (b base) kind() kind { return b.k }
+func (b base) col() int   { return b.at }
+
+type fieldNode struct {
+	base
+	name string
+	get  func(*domain.Lead) value
+}
+
+func (n *fieldNode) eval(l *domain.Lead) value { return n.get(l) }
+
+type litNode struct {
+	base
+	v value
+}
+
+func (n *litNode) eval(*domain.Lead) value { return n.v }
+
+// listNode is a list literal; it only appears on the right of in.
+type listNode struct {
+	base
+	elem  kind // kind of the items; 0 for an empty list
+	items []value
+}
+
+func (n *listNode) eval(*domain.Lead) value { return value{} }
+
+type notNode struct {
+	base
+	x node
+}
+
+func (n *notNode) eval(l *domain.Lead) value { return value{b: !n.x.eval(l).b} }
+
+type logicNode struct {
+	base
+	and  bool
+	l, r node
+}
+
+func (n *logicNode) eval(l *domain.Lead) value {
+	left := n.l.eval(l).b
+	if left != n.and {
+		return value{b: left} // short-circuit: false && …, true || …
+	}
+	return value{b: n.r.eval(l).b}
+}
+
+type cmpNode struct {
+	base
+	op   string
+	l, r node
+}
+
+func (n *cmpNode) eval(l *domain.Lead) value {
+	a, b := n.l.eval(l), n.r.eval(l)
+	switch n.l.kind() {
+	case kString:
+		return value{b: strings.EqualFold(a.s, b.s) == (n.op == "==")}
+	case kBool:
+		return value{b: (a.b == b.b) == (n.op == "==")}
+	}
+	if !a.ok || !b.ok {
+		return value{b: false}
+	}
+	switch n.op {
+	case "==":
+		return value{b: a.n == b.n}
+	case "!=":
+		return value{b: a.n != b.n}
+	case "<":
+		return value{b: a.n < b.n}
+	case "<=":
+		return value{b: a.n <= b.n}
+	case ">":
+		return value{b: a.n > b.n}
+	default:
+		return value{b: a.n >= b.n}
+	}
+}
+
+type matchNode struct {
+	base
+	x   node
+	re  *regexp.Regexp
+	neg bool
+}
+
+func (n *matchNode) eval(l *domain.Lead) value {
+	v := n.x.eval(l)
+	found := false
+	if n.x.kind() == kList {
+		for _, s := range v.list {
+			if n.re.MatchString(s) {
+				found = true
+				break
+			}
+		}
+	} else {
+		found = n.re.MatchString(v.s)
+	}
+	return value{b: found != n.neg}
+}
+
+type inNode struct {
+	base
+	x   node
+	set node // *listNode or a list field
+	neg bool
+}
+
+func (n *inNode) eval(l *domain.Lead) value {
+	v := n.x.eval(l)
+	if n.x.kind() == kNumber && !v.ok {
+		return value{b: false}
+	}
+	found := false
+	if lst, ok := n.set.(*listNode); ok {
+		for _, it := range lst.items {
+			if n.x.kind() == kNumber && it.n == v.n ||
+				n.x.kind() == kString && strings.EqualFold(it.s, v.s) {
+				found = true
+				break
+			}
+		}
+	} else {
+		for _, s := range n.set.eval(l).list {
+			if strings.EqualFold(s, v.s) {
+				found = true
+				break
+			}
+		}
+	}
+	return value{b: found != n.neg}
+}
+
+// hasNode is has(field): a non-blank string, a number the lead has, true or
+// a non-empty list.
+type hasNode struct {
+	base
+	f *fieldNode
+}
+
+func (n *hasNode) eval(l *domain.Lead) value {
+	v := n.f.eval(l)
+	switch n.f.kind() {
+	case kString:
+		return value{b: strings.TrimSpace(v.s) != ""}
+	case kNumber:
+		return value{b: v.ok}
+	case kBool:
+		return value{b: v.b}
+	}
+	return value{b: len(v.list) > 0}
+}
+
+// lenNode is len(field) for strings (in characters) and lists.
+type lenNode struct {
+	base
+	f *fieldNode
+}
+
+func (n *lenNode) eval(l *domain.Lead) value {
+	v := n.f.eval(l)
+	if n.f.kind() == kList {
+		return value{n: float64(len(v.list)), ok: true}
+	}
+	return value{n: float64(len([]rune(v.s))), ok: true}
+}
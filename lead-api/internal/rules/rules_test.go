@@ -0,0 +1,201 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lucasfdcampos/lead-api/internal/domain"
+)
+
+func TestExampleRules(t *testing.T) {
+	padaria := domain.Lead{
+		Name: "Padaria Trigo de Ouro LTDA", Phone: "(43) 3325-4471", State: "PR",
+		Situacao: "ATIVA", Followers: "1,2 mil", Rating: "4,6",
+		Sources: []string{"Google Maps", "Apontador"},
+	}
+	tests := []struct {
+		rule string
+		lead domain.Lead
+		want bool
+	}{
+		{`situacao == "ATIVA"`, padaria, true},
+		{`situacao == "ativa"`, padaria, true},
+		{`situacao == "ATIVA"`, domain.Lead{Situacao: "BAIXADA"}, false},
+		{`situacao != "ATIVA"`, domain.Lead{}, true},
+
+		{`has(phone) || has(instagram)`, padaria, true},
+		{`has(phone) || has(instagram)`, domain.Lead{Instagram: "@padariatrigo"}, true},
+		{`has(phone) || has(instagram)`, domain.Lead{Phone: "  "}, false},
+
+		{`followers >= 500`, padaria, true},
+		{`followers >= 500`, domain.Lead{Followers: "499"}, false},
+		{`followers >= 500`, domain.Lead{Followers: "15.3M"}, true},
+
+		{`uf in ["PR", "SC"]`, padaria, true}, // falls back to state
+		{`uf in ["PR", "SC"]`, domain.Lead{UF: "sc", State: "SP"}, true},
+		{`uf in ["PR", "SC"]`, domain.Lead{UF: "SP", State: "PR"}, false},
+		{`uf not in ["PR", "SC"]`, domain.Lead{UF: "SP"}, true},
+
+		{`name !~ "ltda"`, padaria, false},
+		{`name !~ "ltda"`, domain.Lead{Name: "Cantina Bella Napoli"}, true},
+		{`name ~ "^padaria\\s"`, padaria, true},
+
+		{`rating > 4.5 && !(len(sources) < 2)`, padaria, true},
+		{`sources ~ "maps"`, padaria, true},
+		{`"Apontador" in sources`, padaria, true},
+		{`len(name) == 26`, padaria, true},
+		{`!unverified && cnae_match == false`, padaria, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			r, err := Compile(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := r.Match(&tt.lead); got != tt.want {
+				t.Errorf("Match(%+v) = %v, want %v", tt.lead, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		rule   string
+		column int
+		msg    string
+	}{
+		// syntax
+		{``, 1, "empty rule"},
+		{`situacao = "ATIVA"`, 10, `unexpected "=" (use "==")`},
+		{`has(phone) | has(email)`, 12, `unexpected "|" (use "||")`},
+		{`name == "ltda`, 9, "unterminated string"},
+		{`rating > 4.5.1`, 10, `invalid number "4.5.1"`},
+		{`followers >= 500 500`, 18, `unexpected "500"`},
+		{`(has(phone)`, 12, `expected ")", got end of rule`},
+		{`has("phone")`, 5, `has() takes a field name, got "phone"`},
+		{`uf in ["PR", SC]`, 14, `lists hold quoted strings or numbers, got "SC"`},
+		{`uf in ["PR" "SC"]`, 13, `expected ",", got "SC"`},
+		{`name ~ "("`, 8, `invalid regular expression "(": missing closing )`},
+		{`name ~ ltda`, 8, `"~" needs a quoted regular expression, got "ltda"`},
+		{`seguidores >= 500`, 1, `unknown field "seguidores"`},
+		{`uf not ["PR"]`, 8, `expected "in", got "["`},
+
+		// types
+		{`name`, 1, "a rule must be true or false, got a string (use has(name) to test that it is set)"},
+		{`len(phones)`, 1, "a rule must be true or false, got a number"},
+		{`followers >= "500"`, 11, `">=" compares a number with a string`},
+		{`situacao == true`, 10, `"==" compares a string with a boolean`},
+		{`name > "m"`, 6, `">" needs numbers, got a string`},
+		{`phones == "123"`, 8, `"==" can't compare lists (use in, ~ or len())`},
+		{`has(phone) && name`, 15, `"&&" needs true/false on both sides, got a string`},
+		{`!followers`, 2, `"!" needs true/false, got a number`},
+		{`unverified ~ "x"`, 12, `"~" needs a string or list on the left, got a boolean`},
+		{`uf in "PR"`, 7, "in needs a list on the right, got a string"},
+		{`unverified in [true]`, 16, `lists hold quoted strings or numbers, got "true"`},
+		{`followers in ["500"]`, 11, "in looks for a number in a list of strings"},
+		{`uf in ["PR", 41]`, 14, "lists can't mix strings and numbers"},
+		{`len(rating) > 1`, 5, "len() takes a string or list field, rating is a number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			_, err := Compile(tt.rule)
+			var e *Error
+			if !errors.As(err, &e) {
+				t.Fatalf("Compile() error = %v, want an *Error", err)
+			}
+			if e.Column != tt.column || e.Msg != tt.msg {
+				t.Errorf("Compile() = column %d %q, want column %d %q", e.Column, e.Msg, tt.column, tt.msg)
+			}
+		})
+	}
+
+	long := "name ~ \"" + strings.Repeat("a", MaxRuleLen) + "\""
+	if _, err := Compile(long); err == nil || !strings.Contains(err.Error(), fmt.Sprintf("longer than %d bytes", MaxRuleLen)) {
+		t.Errorf("Compile(long rule) error = %v", err)
+	}
+}
+
+func TestUnsetNumbers(t *testing.T) {
+	dist := 0.0
+	tests := []struct {
+		rule string
+		lead domain.Lead
+		want bool
+	}{
+		// a number the lead doesn't have fails every comparison, negated or not
+		{`followers >= 0`, domain.Lead{}, false},
+		{`followers < 500`, domain.Lead{}, false},
+		{`followers != 500`, domain.Lead{}, false},
+		{`followers in [0, 500]`, domain.Lead{}, false},
+		{`followers not in [0, 500]`, domain.Lead{}, false},
+		{`followers < 500`, domain.Lead{Followers: "n/d"}, false},
+		{`!(followers >= 500)`, domain.Lead{}, true},
+		{`!has(followers) || followers >= 500`, domain.Lead{}, true},
+
+		{`has(followers)`, domain.Lead{}, false},
+		{`has(followers)`, domain.Lead{Followers: "0"}, true},
+		{`has(rating)`, domain.Lead{Rating: "4,5"}, true},
+		{`has(rating_count)`, domain.Lead{RatingCount: 0}, false},
+		{`rating_count >= 0`, domain.Lead{}, false},
+		{`has(lat)`, domain.Lead{}, false},
+		{`lat < 0`, domain.Lead{Lat: -23.3, Lon: -51.2}, true},
+		{`has(distance_km)`, domain.Lead{}, false},
+		{`distance_km <= 5`, domain.Lead{}, false},
+		{`distance_km <= 5`, domain.Lead{DistanceKm: &dist}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			r, err := Compile(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := r.Match(&tt.lead); got != tt.want {
+				t.Errorf("Match(%+v) = %v, want %v", tt.lead, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+		ok   bool
+	}{
+		{"523", 523, true},
+		{"1,234", 1234, true},
+		{"1.234", 1234, true},
+		{"1.2K", 1200, true},
+		{"15.3M", 15.3e6, true},
+		{"1,2 mil", 1200, true},
+		{" 2b ", 2e9, true},
+		{"", 0, false},
+		{"muitos", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseCount(tt.in)
+		if ok != tt.ok || ok && fmt.Sprintf("%.1f", got) != fmt.Sprintf("%.1f", tt.want) {
+			t.Errorf("parseCount(%q) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCompileAll(t *testing.T) {
+	rs, err := CompileAll([]string{`has(phone)`, `uf in ["PR"]`})
+	if err != nil || len(rs) != 2 || rs[1].String() != `uf in ["PR"]` {
+		t.Fatalf("CompileAll = %v, %v", rs, err)
+	}
+
+	_, err = CompileAll([]string{`has(phone)`, `followers >= "500"`})
+	var e *Error
+	if err == nil || !strings.HasPrefix(err.Error(), `rules[1]: rule "followers >= \"500\"": column 11: `) || !errors.As(err, &e) {
+		t.Errorf("CompileAll error = %v", err)
+	}
+
+	if _, err := CompileAll(make([]string, MaxRules+1)); err == nil || !strings.Contains(err.Error(), fmt.Sprintf("at most %d rules", MaxRules)) {
+		t.Errorf("CompileAll(%d rules) error = %v", MaxRules+1, err)
+	}
+}
@@ -2,10 +2,14 @@
 //
 // Collections (all in database "lead_api"):
 //   - searches     – search metadata, no embedded leads (TTL: 30 days)
-//   - results      – individual lead results linked to a search via search_id (TTL: 30 days)
-//   - enrichments  – per-lead CNPJ/Instagram data (TTL: 30 days)
+//   - results      – individual lead results linked to a search via search_id,
+//     including the leads removed by the filters (with discard set) (TTL: 30 days)
+//   - enrichments  – per-lead CNPJ/Instagram/website data (TTL: 30 days)
 //   - cnae_hints   – CNAE codes discovered dynamically for a query (TTL: 90 days)
-//   - cnaes        – CNAE reference data (static, managed externally)
+//   - cnaes        – CNAE 2.3 hierarchy (seeded by cmd/seed-cnae, _id = code)
+//   - rule_presets – named filter rule sets, unique per (tenant, name)
+//   - suppression_lists – leads to keep out of a tenant's searches
+//     (customers, competitors, do-not-contact), unique per (tenant, name)
 package store
 
 import (
@@ -29,6 +33,8 @@ const (
 	enrichCollection  = "enrichments"
 	cnaeHintsCol      = "cnae_hints"
 	cnaesCol          = "cnaes"
+	rulePresetsCol    = "rule_presets"
+	suppressionCol    = "suppression_lists"
 
 	searchTTLDays   = 30
 	enrichTTLDays   = 30
@@ -74,7 +80,7 @@ func (c *Client) MongoClient() *mongo.Client {
 
 // ensureIndices creates TTL and lookup indices if missing.
 func (c *Client) ensureIndices(ctx context.Context) error {
-	// searches: TTL + lookup on (query, location, flags)
+	// searches: TTL + lookup on (query, location, variant)
 	sc := c.mdb.Collection(searchCollection)
 	if _, err := sc.Indexes().CreateMany(ctx, []mongo.IndexModel{
 		{
@@ -85,8 +91,7 @@ func (c *Client) ensureIndices(ctx context.Context) error {
 			Keys: bson.D{
 				{Key: "query", Value: 1},
 				{Key: "location", Value: 1},
-				{Key: "enrich_cnpj", Value: 1},
-				{Key: "enrich_instagram", Value: 1},
+				{Key: "variant", Value: 1},
 			},
 		},
 	}); err != nil {
@@ -125,6 +130,24 @@ func (c *Client) ensureIndices(ctx context.Context) error {
 		return fmt.Errorf("store: cnae_hints indices: %w", err)
 	}
 
+	// rule_presets: one preset per (tenant, name)
+	pc := c.mdb.Collection(rulePresetsCol)
+	if _, err := pc.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "tenant", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		return fmt.Errorf("store: rule_presets indices: %w", err)
+	}
+
+	// suppression_lists: one list per (tenant, name)
+	xc := c.mdb.Collection(suppressionCol)
+	if _, err := xc.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "tenant", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		return fmt.Errorf("store: suppression_lists indices: %w", err)
+	}
+
 	return nil
 }
 
@@ -145,17 +168,16 @@ func (c *Client) SaveSearch(ctx context.Context, s *domain.StoredSearch) (string
 	return "", nil
 }
 
-// FindSearch looks up a recent search by query/location/flags.
-// Returns nil, nil when not found.
-func (c *Client) FindSearch(ctx context.Context, query, location string, enrichCNPJ, enrichInstagram bool) (*domain.StoredSearch, error) {
+// FindSearch looks up a recent search by query/location/variant
+// (see domain.SearchRequest.Variant). Returns nil, nil when not found.
+func (c *Client) FindSearch(ctx context.Context, query, location, variant string) (*domain.StoredSearch, error) {
 	// Normalize for case-insensitive match
 	q := regexp.QuoteMeta(strings.ToLower(strings.TrimSpace(query)))
 	l := regexp.QuoteMeta(strings.ToLower(strings.TrimSpace(location)))
 	filter := bson.M{
-		"query":            bson.M{"$regex": "(?i)^" + q + "$"},
-		"location":         bson.M{"$regex": "(?i)^" + l + "$"},
-		"enrich_cnpj":      enrichCNPJ,
-		"enrich_instagram": enrichInstagram,
+		"query":    bson.M{"$regex": "(?i)^" + q + "$"},
+		"location": bson.M{"$regex": "(?i)^" + l + "$"},
+		"variant":  variant,
 	}
 	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})
 
@@ -197,10 +219,38 @@ func (c *Client) SaveResults(ctx context.Context, searchID string, leads []domai
 	return nil
 }
 
-// FindResultsBySearchID retrieves all leads for the given searchID, in insertion order.
+// SaveDiscarded inserts the leads removed by the filters, linked to a
+// searchID, with the reason each one was discarded.
+func (c *Client) SaveDiscarded(ctx context.Context, searchID string, discarded []domain.DiscardedLead) error {
+	if len(discarded) == 0 {
+		return nil
+	}
+	now := time.Now().UTC()
+	exp := now.Add(searchTTLDays * 24 * time.Hour)
+
+	docs := make([]any, 0, len(discarded))
+	for _, d := range discarded {
+		reason := d.Discard
+		docs = append(docs, domain.StoredResult{
+			SearchID:  searchID,
+			Lead:      d.Lead,
+			Discard:   &reason,
+			CreatedAt: now,
+			ExpiresAt: exp,
+		})
+	}
+
+	_, err := c.mdb.Collection(resultsCollection).InsertMany(ctx, docs)
+	if err != nil {
+		return fmt.Errorf("store: save discarded: %w", err)
+	}
+	return nil
+}
+
+// FindResultsBySearchID retrieves all kept leads for the given searchID, in insertion order.
 func (c *Client) FindResultsBySearchID(ctx context.Context, searchID string) ([]domain.Lead, error) {
 	cursor, err := c.mdb.Collection(resultsCollection).Find(ctx,
-		bson.M{"search_id": searchID},
+		bson.M{"search_id": searchID, "discard": bson.M{"$exists": false}},
 		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}),
 	)
 	if err != nil {
@@ -218,6 +268,28 @@ func (c *Client) FindResultsBySearchID(ctx context.Context, searchID string) ([]
 	return leads, cursor.Err()
 }
 
+// FindDiscardedBySearchID retrieves the leads the filters removed from the
+// given searchID, with their reasons, in insertion order.
+func (c *Client) FindDiscardedBySearchID(ctx context.Context, searchID string) ([]domain.DiscardedLead, error) {
+	cursor, err := c.mdb.Collection(resultsCollection).Find(ctx,
+		bson.M{"search_id": searchID, "discard": bson.M{"$exists": true}},
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: find discarded: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var out []domain.DiscardedLead
+	for cursor.Next(ctx) {
+		var doc domain.StoredResult
+		if err := cursor.Decode(&doc); err == nil && doc.Discard != nil {
+			out = append(out, domain.DiscardedLead{Lead: doc.Lead, Discard: *doc.Discard})
+		}
+	}
+	return out, cursor.Err()
+}
+
 // ─── CNAE Hints ───────────────────────────────────────────────────────────────
 
 // GetCNAEHint returns a cached CNAE hint for the given query, or nil if not found.
@@ -248,8 +320,13 @@ func (c *Client) SaveCNAEHint(ctx context.Context, hint *domain.CNAEHintDoc) err
 
 // ─── CNAE reference (lead_api.cnaes) ──────────────────────────────────────────
 
-// QueryCNAEs returns CNAE codes from the local lead_api.cnaes collection
-// whose description contains any of the given keywords.
+// cnaeBroadLevels are the hierarchy levels too wide to be used as compatible
+// codes or LLM candidates; rows without a level (loaded before seeding
+// existed) are treated as subclasses.
+var cnaeBroadLevels = bson.A{"secao", "divisao", "grupo"}
+
+// QueryCNAEs returns class and subclass codes from the local lead_api.cnaes
+// collection whose description contains any of the given keywords.
 func (c *Client) QueryCNAEs(ctx context.Context, keywords []string) ([]string, error) {
 	if len(keywords) == 0 {
 		return nil, nil
@@ -266,7 +343,8 @@ func (c *Client) QueryCNAEs(ctx context.Context, keywords []string) ([]string, e
 		return nil, nil
 	}
 
-	cursor, err := c.mdb.Collection(cnaesCol).Find(ctx, bson.M{"$or": ors},
+	cursor, err := c.mdb.Collection(cnaesCol).Find(ctx,
+		bson.M{"$or": ors, "nivel": bson.M{"$nin": cnaeBroadLevels}},
 		options.Find().SetProjection(bson.M{"codigo": 1}))
 	if err != nil {
 		return nil, fmt.Errorf("store: query cnaes: %w", err)
@@ -286,29 +364,238 @@ func (c *Client) QueryCNAEs(ctx context.Context, keywords []string) ([]string, e
 	return codes, cursor.Err()
 }
 
+// SearchCNAEs returns up to limit subclasses of the CNAE reference whose
+// description contains any of the keywords.
+func (c *Client) SearchCNAEs(ctx context.Context, keywords []string, limit int) ([]domain.CNAE, error) {
+	ors := make(bson.A, 0, len(keywords))
+	for _, kw := range keywords {
+		if kw = strings.TrimSpace(kw); kw != "" {
+			ors = append(ors, bson.M{"descricao": bson.M{"$regex": regexp.QuoteMeta(kw), "$options": "i"}})
+		}
+	}
+	if len(ors) == 0 {
+		return nil, nil
+	}
+	filter := bson.M{"$or": ors, "nivel": bson.M{"$nin": bson.A{"secao", "divisao", "grupo", "classe"}}}
+	cursor, err := c.mdb.Collection(cnaesCol).Find(ctx, filter, options.Find().SetLimit(int64(limit)))
+	if err != nil {
+		return nil, fmt.Errorf("store: search cnaes: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []domain.CNAE
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("store: search cnaes: %w", err)
+	}
+	return rows, nil
+}
+
+// CNAEsByCode returns the CNAE reference rows for the given codes. Codes
+// are matched both as digits ("9512600") and in the official notation
+// ("9512-6/00"), since the collection is loaded from external sources.
+func (c *Client) CNAEsByCode(ctx context.Context, codes []string) ([]domain.CNAE, error) {
+	var in bson.A
+	for _, code := range codes {
+		in = append(in, code)
+		if d := digitsOnly(code); len(d) == 7 {
+			in = append(in, d, d[:4]+"-"+d[4:5]+"/"+d[5:])
+		}
+	}
+	if len(in) == 0 {
+		return nil, nil
+	}
+	cursor, err := c.mdb.Collection(cnaesCol).Find(ctx, bson.M{"codigo": bson.M{"$in": in}})
+	if err != nil {
+		return nil, fmt.Errorf("store: cnaes by code: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []domain.CNAE
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("store: cnaes by code: %w", err)
+	}
+	return rows, nil
+}
+
+func digitsOnly(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// SeedCNAEs upserts the CNAE hierarchy into the cnaes collection, keyed by
+// code, and returns how many documents were inserted or changed. Rows
+// already in the collection under other codes are left alone.
+func (c *Client) SeedCNAEs(ctx context.Context, rows []domain.CNAE) (int64, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	models := make([]mongo.WriteModel, 0, len(rows))
+	for _, r := range rows {
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": r.Code}).
+			SetReplacement(r).
+			SetUpsert(true))
+	}
+	res, err := c.mdb.Collection(cnaesCol).BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, fmt.Errorf("store: seed cnaes: %w", err)
+	}
+	if _, err := c.mdb.Collection(cnaesCol).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "codigo", Value: 1}}},
+		{Keys: bson.D{{Key: "pai", Value: 1}}},
+	}); err != nil {
+		return 0, fmt.Errorf("store: cnaes indices: %w", err)
+	}
+	return res.UpsertedCount + res.ModifiedCount, nil
+}
+
 // QueryLeadfinderCNAEs is kept for backward compatibility; delegates to QueryCNAEs.
 func (c *Client) QueryLeadfinderCNAEs(ctx context.Context, keywords []string) ([]string, error) {
 	return c.QueryCNAEs(ctx, keywords)
 }
 
+// ─── Rule presets ─────────────────────────────────────────────────────────────
+
+// SaveRulePreset creates or replaces the tenant's preset with p.Name.
+func (c *Client) SaveRulePreset(ctx context.Context, p *domain.RulePreset) error {
+	p.UpdatedAt = time.Now().UTC()
+	filter := bson.M{"tenant": p.Tenant, "name": p.Name}
+	opts := options.Replace().SetUpsert(true)
+	if _, err := c.mdb.Collection(rulePresetsCol).ReplaceOne(ctx, filter, p, opts); err != nil {
+		return fmt.Errorf("store: save rule preset: %w", err)
+	}
+	return nil
+}
+
+// GetRulePreset returns the tenant's preset by name, or nil if not found.
+func (c *Client) GetRulePreset(ctx context.Context, tenant, name string) (*domain.RulePreset, error) {
+	var p domain.RulePreset
+	err := c.mdb.Collection(rulePresetsCol).FindOne(ctx, bson.M{"tenant": tenant, "name": name}).Decode(&p)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store: get rule preset: %w", err)
+	}
+	return &p, nil
+}
+
+// ListRulePresets returns the tenant's presets sorted by name.
+func (c *Client) ListRulePresets(ctx context.Context, tenant string) ([]domain.RulePreset, error) {
+	cursor, err := c.mdb.Collection(rulePresetsCol).Find(ctx,
+		bson.M{"tenant": tenant},
+		options.Find().SetSort(bson.D{{Key: "name", Value: 1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: list rule presets: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	presets := []domain.RulePreset{}
+	if err := cursor.All(ctx, &presets); err != nil {
+		return nil, fmt.Errorf("store: list rule presets: %w", err)
+	}
+	return presets, nil
+}
+
+// DeleteRulePreset removes the tenant's preset by name and reports whether
+// it existed.
+func (c *Client) DeleteRulePreset(ctx context.Context, tenant, name string) (bool, error) {
+	res, err := c.mdb.Collection(rulePresetsCol).DeleteOne(ctx, bson.M{"tenant": tenant, "name": name})
+	if err != nil {
+		return false, fmt.Errorf("store: delete rule preset: %w", err)
+	}
+	return res.DeletedCount > 0, nil
+}
+
+// ─── Suppression lists ────────────────────────────────────────────────────────
+
+// SaveSuppressionList creates or replaces the tenant's list with l.Name.
+func (c *Client) SaveSuppressionList(ctx context.Context, l *domain.SuppressionList) error {
+	l.UpdatedAt = time.Now().UTC()
+	filter := bson.M{"tenant": l.Tenant, "name": l.Name}
+	opts := options.Replace().SetUpsert(true)
+	if _, err := c.mdb.Collection(suppressionCol).ReplaceOne(ctx, filter, l, opts); err != nil {
+		return fmt.Errorf("store: save suppression list: %w", err)
+	}
+	return nil
+}
+
+// GetSuppressionList returns the tenant's list by name, or nil if not found.
+func (c *Client) GetSuppressionList(ctx context.Context, tenant, name string) (*domain.SuppressionList, error) {
+	var l domain.SuppressionList
+	err := c.mdb.Collection(suppressionCol).FindOne(ctx, bson.M{"tenant": tenant, "name": name}).Decode(&l)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store: get suppression list: %w", err)
+	}
+	return &l, nil
+}
+
+// ListSuppressionLists returns the tenant's lists sorted by name.
+func (c *Client) ListSuppressionLists(ctx context.Context, tenant string) ([]domain.SuppressionList, error) {
+	cursor, err := c.mdb.Collection(suppressionCol).Find(ctx,
+		bson.M{"tenant": tenant},
+		options.Find().SetSort(bson.D{{Key: "name", Value: 1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: list suppression lists: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	lists := []domain.SuppressionList{}
+	if err := cursor.All(ctx, &lists); err != nil {
+		return nil, fmt.Errorf("store: list suppression lists: %w", err)
+	}
+	return lists, nil
+}
+
+// DeleteSuppressionList removes the tenant's list by name and reports
+// whether it existed.
+func (c *Client) DeleteSuppressionList(ctx context.Context, tenant, name string) (bool, error) {
+	res, err := c.mdb.Collection(suppressionCol).DeleteOne(ctx, bson.M{"tenant": tenant, "name": name})
+	if err != nil {
+		return false, fmt.Errorf("store: delete suppression list: %w", err)
+	}
+	return res.DeletedCount > 0, nil
+}
+
 // ─── Enrichment cache ─────────────────────────────────────────────────────────
 
 // CachedEnrichment is the MongoDB document for per-lead enrichment.
 type CachedEnrichment struct {
-	Key          string    `bson:"_id"`
-	CNPJ         string    `bson:"cnpj,omitempty"`
-	RazaoSocial  string    `bson:"razao_social,omitempty"`
-	NomeFantasia string    `bson:"nome_fantasia,omitempty"`
-	Situacao     string    `bson:"situacao,omitempty"`
-	Partners     []string  `bson:"partners,omitempty"`
-	CNAECode     string    `bson:"cnae_code,omitempty"`
-	CNAEDesc     string    `bson:"cnae_desc,omitempty"`
-	Municipio    string    `bson:"municipio,omitempty"`
-	UF           string    `bson:"uf,omitempty"`
-	Instagram    string    `bson:"instagram,omitempty"`
-	Followers    string    `bson:"followers,omitempty"`
-	UpdatedAt    time.Time `bson:"updated_at"`
-	ExpiresAt    time.Time `bson:"expires_at"`
+	Key           string   `bson:"_id"`
+	CNPJ          string   `bson:"cnpj,omitempty"`
+	RazaoSocial   string   `bson:"razao_social,omitempty"`
+	NomeFantasia  string   `bson:"nome_fantasia,omitempty"`
+	Situacao      string   `bson:"situacao,omitempty"`
+	Partners      []string `bson:"partners,omitempty"`
+	CNAECode      string   `bson:"cnae_code,omitempty"`
+	CNAEDesc      string   `bson:"cnae_desc,omitempty"`
+	CNAESecondary []string `bson:"cnae_secondary,omitempty"`
+	Municipio     string   `bson:"municipio,omitempty"`
+	UF            string   `bson:"uf,omitempty"`
+	Email         string   `bson:"email,omitempty"`
+	Instagram     string   `bson:"instagram,omitempty"`
+	Followers     string   `bson:"followers,omitempty"`
+
+	Website     string            `bson:"website,omitempty"`
+	WebsiteVia  string            `bson:"website_via,omitempty"`
+	Emails      []string          `bson:"emails,omitempty"`
+	Phones      []string          `bson:"phones,omitempty"`
+	WhatsApp    []string          `bson:"whatsapp,omitempty"`
+	SiteCNPJs   []string          `bson:"site_cnpjs,omitempty"`
+	SocialLinks map[string]string `bson:"social_links,omitempty"`
+
+	UpdatedAt time.Time `bson:"updated_at"`
+	ExpiresAt time.Time `bson:"expires_at"`
 }
 
 // GetEnrichment returns cached per-lead enrichment data or nil.
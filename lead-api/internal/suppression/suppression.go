@@ -0,0 +1,261 @@
+// Package suppression matches leads against a tenant's suppression lists:
+// known customers, competitors and do-not-contact entries, stored in the
+// suppression_lists collection.
+//
+// A lead is suppressed when any of these matches an entry of any list:
+//   - its CNPJ root (first 8 digits), shared by every branch of a company
+//   - any of its phones or its WhatsApp number, compared in E.164
+//   - its Instagram handle
+//   - its website host, or a subdomain of a listed domain
+//   - its name, nome fantasia or razão social, against a name pattern
+//     (RE2, case-insensitive)
+package suppression
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	phonepkg "github.com/lucasfdcampos/find-cnpj/pkg/phone"
+
+	"github.com/lucasfdcampos/lead-api/internal/domain"
+)
+
+// Match fields (Hit.Field).
+const (
+	FieldCNPJ      = "cnpj_root"
+	FieldPhone     = "phone"
+	FieldInstagram = "instagram"
+	FieldDomain    = "domain"
+	FieldName      = "name"
+)
+
+// MaxEntries bounds the entries of a single list.
+const MaxEntries = 10000
+
+// Hit says which entry suppressed a lead.
+type Hit struct {
+	List  string // list name
+	Field string // FieldCNPJ, FieldPhone, ...
+	Value string // the matching entry
+}
+
+// Normalize validates a list and rewrites its entries in the form they are
+// matched in (CNPJ roots as 8 digits, phones in E.164, handles without "@",
+// bare hosts), dropping duplicates.
+func Normalize(l *domain.SuppressionList) error {
+	n := len(l.CNPJRoots) + len(l.Phones) + len(l.Instagram) + len(l.Domains) + len(l.NamePatterns)
+	if n == 0 {
+		return fmt.Errorf("list %q has no entries", l.Name)
+	}
+	if n > MaxEntries {
+		return fmt.Errorf("list %q has %d entries, at most %d are allowed", l.Name, n, MaxEntries)
+	}
+
+	var err error
+	if l.CNPJRoots, err = normalizeAll("cnpj_roots", l.CNPJRoots, cnpjRoot); err != nil {
+		return err
+	}
+	if l.Phones, err = normalizeAll("phones", l.Phones, phonepkg.E164); err != nil {
+		return err
+	}
+	if l.Instagram, err = normalizeAll("instagram", l.Instagram, handle); err != nil {
+		return err
+	}
+	if l.Domains, err = normalizeAll("domains", l.Domains, host); err != nil {
+		return err
+	}
+	for i, p := range l.NamePatterns {
+		if _, err := regexp.Compile("(?i)" + p); err != nil {
+			return fmt.Errorf("name_patterns[%d]: invalid regular expression %q", i, p)
+		}
+	}
+	return nil
+}
+
+func normalizeAll(field string, in []string, norm func(string) string) ([]string, error) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+	seen := make(map[string]bool, len(in))
+	out := make([]string, 0, len(in))
+	for i, raw := range in {
+		v := norm(raw)
+		if v == "" {
+			return nil, fmt.Errorf("%s[%d]: invalid entry %q", field, i, raw)
+		}
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+// ─── Matcher ──────────────────────────────────────────────────────────────────
+
+// Matcher matches leads against a set of normalized lists.
+type Matcher struct {
+	roots   map[string]string // entry → list name
+	phones  map[string]string
+	handles map[string]string
+	domains map[string]string
+	names   []namePattern
+
+	fingerprint string
+}
+
+type namePattern struct {
+	list, src string
+	re        *regexp.Regexp
+}
+
+// New builds a Matcher from lists already passed through Normalize.
+// Returns nil when the lists have no entries.
+func New(lists []domain.SuppressionList) (*Matcher, error) {
+	m := &Matcher{
+		roots:   map[string]string{},
+		phones:  map[string]string{},
+		handles: map[string]string{},
+		domains: map[string]string{},
+	}
+	h := sha256.New()
+	lists = append([]domain.SuppressionList(nil), lists...)
+	sort.Slice(lists, func(i, j int) bool { return lists[i].Name < lists[j].Name })
+	for _, l := range lists {
+		add(m.roots, l.Name, l.CNPJRoots)
+		add(m.phones, l.Name, l.Phones)
+		add(m.handles, l.Name, l.Instagram)
+		add(m.domains, l.Name, l.Domains)
+		for _, p := range l.NamePatterns {
+			re, err := regexp.Compile("(?i)" + p)
+			if err != nil {
+				return nil, fmt.Errorf("suppression list %q: invalid name pattern %q", l.Name, p)
+			}
+			m.names = append(m.names, namePattern{list: l.Name, src: p, re: re})
+		}
+		fmt.Fprintf(h, "%s\x00%q%q%q%q%q\x00", l.Name, l.CNPJRoots, l.Phones, l.Instagram, l.Domains, l.NamePatterns)
+	}
+	if len(m.roots)+len(m.phones)+len(m.handles)+len(m.domains)+len(m.names) == 0 {
+		return nil, nil
+	}
+	m.fingerprint = hex.EncodeToString(h.Sum(nil))[:16]
+	return m, nil
+}
+
+func add(idx map[string]string, list string, entries []string) {
+	for _, e := range entries {
+		if _, ok := idx[e]; !ok {
+			idx[e] = list
+		}
+	}
+}
+
+// Fingerprint identifies the lists' contents; searches cached under one
+// fingerprint are not reused once the lists change.
+func (m *Matcher) Fingerprint() string {
+	if m == nil {
+		return ""
+	}
+	return m.fingerprint
+}
+
+// Match returns the first entry the lead matches.
+func (m *Matcher) Match(l *domain.Lead) (Hit, bool) {
+	if m == nil {
+		return Hit{}, false
+	}
+	if r := cnpjRoot(l.CNPJ); r != "" {
+		if list, ok := m.roots[r]; ok {
+			return Hit{List: list, Field: FieldCNPJ, Value: r}, true
+		}
+	}
+	for _, raw := range append([]string{l.PhoneE164, l.Phone, l.Phone2, l.WhatsApp}, l.Phones...) {
+		if p := phonepkg.E164(raw); p != "" {
+			if list, ok := m.phones[p]; ok {
+				return Hit{List: list, Field: FieldPhone, Value: p}, true
+			}
+		}
+	}
+	for _, raw := range []string{l.Instagram, l.SocialLinks["instagram"]} {
+		if hd := handle(raw); hd != "" {
+			if list, ok := m.handles[hd]; ok {
+				return Hit{List: list, Field: FieldInstagram, Value: hd}, true
+			}
+		}
+	}
+	// loja.com.br also suppresses www.loja.com.br and filial.loja.com.br
+	for d := host(l.Website); d != ""; {
+		if list, ok := m.domains[d]; ok {
+			return Hit{List: list, Field: FieldDomain, Value: d}, true
+		}
+		i := strings.IndexByte(d, '.')
+		if i < 0 {
+			break
+		}
+		d = d[i+1:]
+	}
+	for _, p := range m.names {
+		for _, name := range []string{l.Name, l.NomeFantasia, l.RazaoSocial} {
+			if name != "" && p.re.MatchString(name) {
+				return Hit{List: p.list, Field: FieldName, Value: p.src}, true
+			}
+		}
+	}
+	return Hit{}, false
+}
+
+// ─── Normalization ────────────────────────────────────────────────────────────
+
+// cnpjRoot returns the first 8 digits of a CNPJ or CNPJ root.
+func cnpjRoot(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	d := b.String()
+	if len(d) != 8 && len(d) != 14 {
+		return ""
+	}
+	return d[:8]
+}
+
+// handle extracts the Instagram handle from "@loja", "loja" or the
+// profile URL.
+func handle(raw string) string {
+	raw = strings.TrimSpace(strings.ToLower(raw))
+	if strings.Contains(raw, "instagram.com") {
+		if !strings.Contains(raw, "://") {
+			raw = "https://" + raw
+		}
+		u, err := url.Parse(raw)
+		if err != nil {
+			return ""
+		}
+		raw = strings.Split(strings.Trim(u.Path, "/"), "/")[0]
+	}
+	return strings.TrimPrefix(raw, "@")
+}
+
+// host returns the host of a URL or bare domain, lowercased and without
+// "www.".
+func host(raw string) string {
+	raw = strings.TrimSpace(strings.ToLower(raw))
+	if raw == "" {
+		return ""
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || !strings.Contains(u.Hostname(), ".") {
+		return ""
+	}
+	return strings.TrimPrefix(u.Hostname(), "www.")
+}
@@ -0,0 +1,187 @@
+package suppression
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lucasfdcampos/lead-api/internal/domain"
+)
+
+func TestNormalize(t *testing.T) {
+	l := domain.SuppressionList{
+		Name:         "clientes",
+		CNPJRoots:    []string{"11.222.333/0001-81", "11222333", "44.555.666"},
+		Phones:       []string{"(43) 3325-4471", "+55 43 3325-4471", "43 99988-7766"},
+		Instagram:    []string{"@PadariaTrigo", "https://www.instagram.com/padariatrigo/", "instagram.com/bellanapoli?hl=pt"},
+		Domains:      []string{"https://www.Loja.com.br/contato", "loja.com.br", "filial.outra.com.br"},
+		NamePatterns: []string{`^padaria trigo`},
+	}
+	if err := Normalize(&l); err != nil {
+		t.Fatal(err)
+	}
+	want := domain.SuppressionList{
+		Name:         "clientes",
+		CNPJRoots:    []string{"11222333", "44555666"},
+		Phones:       []string{"+554333254471", "+5543999887766"},
+		Instagram:    []string{"padariatrigo", "bellanapoli"},
+		Domains:      []string{"loja.com.br", "filial.outra.com.br"},
+		NamePatterns: []string{`^padaria trigo`},
+	}
+	if !reflect.DeepEqual(l, want) {
+		t.Errorf("Normalize:\n got  %+v\n want %+v", l, want)
+	}
+}
+
+func TestNormalizeErrors(t *testing.T) {
+	tooMany := make([]string, MaxEntries+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("%08d", i)
+	}
+	tests := []struct {
+		name string
+		list domain.SuppressionList
+		want string
+	}{
+		{"no entries", domain.SuppressionList{Name: "vazia"}, `list "vazia" has no entries`},
+		{"too many entries", domain.SuppressionList{Name: "grande", CNPJRoots: tooMany}, "10001 entries, at most 10000"},
+		{"CNPJ root", domain.SuppressionList{CNPJRoots: []string{"11222333", "1122233"}}, `cnpj_roots[1]: invalid entry "1122233"`},
+		{"phone", domain.SuppressionList{Phones: []string{"123"}}, `phones[0]: invalid entry "123"`},
+		{"instagram", domain.SuppressionList{Instagram: []string{"@"}}, `instagram[0]: invalid entry "@"`},
+		{"domain", domain.SuppressionList{Domains: []string{"localhost"}}, `domains[0]: invalid entry "localhost"`},
+		{"blank domain", domain.SuppressionList{Domains: []string{"  "}}, `domains[0]: invalid entry "  "`},
+		{"name pattern", domain.SuppressionList{NamePatterns: []string{"ok", "padaria("}}, `name_patterns[1]: invalid regular expression "padaria("`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Normalize(&tt.list)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Normalize() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	if m, err := New(nil); m != nil || err != nil {
+		t.Errorf("New(nil) = %v, %v, want nil", m, err)
+	}
+	if m, err := New([]domain.SuppressionList{{Name: "vazia"}}); m != nil || err != nil {
+		t.Errorf("New(list without entries) = %v, %v, want nil", m, err)
+	}
+	if _, err := New([]domain.SuppressionList{{Name: "x", NamePatterns: []string{"("}}}); err == nil {
+		t.Error("New with an invalid name pattern should fail")
+	}
+
+	// An entry in more than one list is reported for the first list by name.
+	m, err := New([]domain.SuppressionList{
+		{Name: "concorrentes", CNPJRoots: []string{"11222333"}},
+		{Name: "clientes", CNPJRoots: []string{"11222333"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hit, ok := m.Match(&domain.Lead{CNPJ: "11.222.333/0002-62"}); !ok || hit.List != "clientes" {
+		t.Errorf("Match = %+v, %v, want list clientes", hit, ok)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := domain.SuppressionList{Name: "clientes", CNPJRoots: []string{"11222333"}}
+	b := domain.SuppressionList{Name: "concorrentes", Domains: []string{"loja.com.br"}}
+	fingerprint := func(lists ...domain.SuppressionList) string {
+		t.Helper()
+		m, err := New(lists)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return m.Fingerprint()
+	}
+
+	ab := fingerprint(a, b)
+	if len(ab) != 16 {
+		t.Errorf("Fingerprint() = %q, want 16 hex digits", ab)
+	}
+	if ba := fingerprint(b, a); ba != ab {
+		t.Errorf("list order changed the fingerprint: %q != %q", ba, ab)
+	}
+	changed := b
+	changed.Domains = []string{"loja.com.br", "outra.com.br"}
+	if fingerprint(a, changed) == ab {
+		t.Error("a new entry should change the fingerprint")
+	}
+	renamed := b
+	renamed.Name = "rivais"
+	if fingerprint(a, renamed) == ab {
+		t.Error("a renamed list should change the fingerprint")
+	}
+	var nilMatcher *Matcher
+	if nilMatcher.Fingerprint() != "" {
+		t.Error("nil Matcher should have an empty fingerprint")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	lists := []domain.SuppressionList{
+		{
+			Name:      "clientes",
+			CNPJRoots: []string{"11.222.333"},
+			Phones:    []string{"(43) 3325-4471"},
+			Instagram: []string{"@padariatrigo"},
+		},
+		{
+			Name:         "concorrentes",
+			Domains:      []string{"loja.com.br"},
+			NamePatterns: []string{`^supermercado\s+bom\s+preço`},
+		},
+	}
+	for i := range lists {
+		if err := Normalize(&lists[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m, err := New(lists)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		lead domain.Lead
+		want Hit
+		ok   bool
+	}{
+		{"CNPJ of another branch", domain.Lead{CNPJ: "11.222.333/0002-62"}, Hit{"clientes", FieldCNPJ, "11222333"}, true},
+		{"phone", domain.Lead{Phone: "+55 (43) 3325-4471"}, Hit{"clientes", FieldPhone, "+554333254471"}, true},
+		{"second phone", domain.Lead{Phone: "(43) 3000-0000", Phones: []string{"43 3325 4471"}}, Hit{"clientes", FieldPhone, "+554333254471"}, true},
+		{"WhatsApp", domain.Lead{WhatsApp: "5543 3325-4471"}, Hit{"clientes", FieldPhone, "+554333254471"}, true},
+		{"Instagram handle", domain.Lead{Instagram: "@PadariaTrigo"}, Hit{"clientes", FieldInstagram, "padariatrigo"}, true},
+		{"Instagram social link", domain.Lead{SocialLinks: map[string]string{"instagram": "https://instagram.com/padariatrigo"}}, Hit{"clientes", FieldInstagram, "padariatrigo"}, true},
+		{"domain", domain.Lead{Website: "https://loja.com.br"}, Hit{"concorrentes", FieldDomain, "loja.com.br"}, true},
+		{"www", domain.Lead{Website: "http://www.loja.com.br/contato"}, Hit{"concorrentes", FieldDomain, "loja.com.br"}, true},
+		{"subdomain", domain.Lead{Website: "https://filial.centro.loja.com.br/"}, Hit{"concorrentes", FieldDomain, "loja.com.br"}, true},
+		{"name", domain.Lead{Name: "Supermercado Bom Preço - Centro"}, Hit{"concorrentes", FieldName, `^supermercado\s+bom\s+preço`}, true},
+		{"razão social", domain.Lead{Name: "Bom Preço", RazaoSocial: "SUPERMERCADO BOM PREÇO LTDA"}, Hit{"concorrentes", FieldName, `^supermercado\s+bom\s+preço`}, true},
+
+		{"other CNPJ root", domain.Lead{CNPJ: "11.222.334/0001-00"}, Hit{}, false},
+		{"other phone", domain.Lead{Phone: "(43) 3325-4472"}, Hit{}, false},
+		{"domain suffix", domain.Lead{Website: "https://minhaloja.com.br"}, Hit{}, false},
+		{"domain as a subdomain", domain.Lead{Website: "https://loja.com.br.golpe.com"}, Hit{}, false},
+		{"name not at the start", domain.Lead{Name: "Açougue do Supermercado Bom Preço"}, Hit{}, false},
+		{"empty lead", domain.Lead{}, Hit{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := m.Match(&tt.lead)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Match() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+
+	var nilMatcher *Matcher
+	if _, ok := nilMatcher.Match(&domain.Lead{CNPJ: "11222333000181"}); ok {
+		t.Error("nil Matcher should match nothing")
+	}
+}
@@ -9,8 +9,14 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/lucasfdcampos/find-leads/pkg/ibge"
+	leadsearch "github.com/lucasfdcampos/find-leads/pkg/leads"
+	"github.com/lucasfdcampos/serp/pkg/serp"
+
 	"github.com/lucasfdcampos/lead-api/internal/api"
 	"github.com/lucasfdcampos/lead-api/internal/cache"
+	"github.com/lucasfdcampos/lead-api/internal/cnae"
+	"github.com/lucasfdcampos/lead-api/internal/pipeline"
 	"github.com/lucasfdcampos/lead-api/internal/store"
 )
 
@@ -52,9 +58,98 @@ func main() {
 		}()
 	}
 
+	// ─── IBGE municipalities ──────────────────────────────────────────────────
+	if path := os.Getenv("IBGE_MUNICIPIOS_FILE"); path != "" {
+		ds, err := ibge.LoadFile(path)
+		if err != nil {
+			log.Printf("WARN: IBGE table not loaded (%v) — using the embedded table", err)
+		} else {
+			ibge.SetDefault(ds)
+			log.Printf("IBGE table loaded: %s (%d municipalities)", path, len(ds.All()))
+		}
+	}
+	if ds := ibge.Default(); ds.Partial() {
+		log.Printf("WARN: IBGE table is partial (%d of %d municipalities) — region and expand_radius_km searches only cover those", len(ds.All()), ibge.Count)
+	}
+
+	// ─── CNAE hierarchy ───────────────────────────────────────────────────────
+	if path := os.Getenv("CNAE_FILE"); path != "" {
+		ds, err := cnae.LoadFile(path)
+		if err != nil {
+			log.Printf("WARN: CNAE table not loaded (%v) — using the embedded table", err)
+		} else {
+			cnae.SetDefault(ds)
+			log.Printf("CNAE table loaded: %s (%d nodes)", path, len(ds.All()))
+		}
+	}
+	if ds := cnae.Default(); ds.Partial() {
+		log.Printf("WARN: CNAE table is partial (%d of %d subclasses) — CNAE codes missing from it are dropped", ds.SubclassCount(), cnae.Subclasses)
+	}
+	if s := os.Getenv("CNAE_MATCH_LEVEL"); s != "" {
+		level, err := cnae.ParseLevel(s)
+		if err != nil {
+			log.Printf("WARN: %v — matching CNAE codes at the %s level", err, cnae.MatchLevel())
+		} else {
+			cnae.SetMatchLevel(level)
+			log.Printf("CNAE match level: %s", level)
+		}
+	}
+
+	// ─── Directory scraper definitions ────────────────────────────────────────
+	if dir := os.Getenv("LEADS_DEFINITIONS_DIR"); dir != "" {
+		defs, err := leadsearch.LoadDefinitions(dir)
+		if err != nil {
+			log.Printf("WARN: directory definitions not loaded (%v) — using the built-in scrapers", err)
+		} else {
+			pipeline.SetDefinitions(defs)
+			log.Printf("Directory definitions loaded: %s (%d)", dir, len(defs))
+		}
+	}
+
+	// ─── LLM prompts ──────────────────────────────────────────────────────────
+	if dir := os.Getenv("LLM_PROMPTS_DIR"); dir != "" {
+		prompts, err := leadsearch.LoadPrompts(dir)
+		if err != nil {
+			log.Printf("WARN: LLM prompts not loaded (%v) — using the embedded prompts", err)
+		} else {
+			leadsearch.SetPrompts(prompts)
+			log.Printf("LLM prompts loaded: %s", dir)
+		}
+	}
+
+	// ─── Search engines ───────────────────────────────────────────────────────
+	if cfg := serp.Default().SearXNG; len(cfg.URLs) > 0 {
+		routed := "SearXNG searches only"
+		if cfg.RouteAll {
+			routed = "all engines routed through it"
+		}
+		log.Printf("SearXNG: %d self-hosted instance(s), %s", len(cfg.URLs), routed)
+	}
+
+	// ─── Query understanding ──────────────────────────────────────────────────
+	var llm leadsearch.LLMClient
+	if os.Getenv("QUERY_UNDERSTANDING") == "llm" {
+		if llm = leadsearch.LLMClientFromEnv(); llm != nil {
+			log.Printf("Query understanding: %s", llm.Name())
+		} else {
+			log.Printf("WARN: QUERY_UNDERSTANDING=llm but no LLM is configured — using search-engine CNAE discovery")
+		}
+	}
+
+	// ─── Tenants ──────────────────────────────────────────────────────────────
+	tenants, err := api.ParseTenantKeys(os.Getenv("TENANT_API_KEYS"))
+	if err != nil {
+		log.Fatalf("TENANT_API_KEYS: %v", err)
+	}
+	if n := tenants.Len(); n > 0 {
+		log.Printf("Tenants: %d API key(s); requests must send Authorization: Bearer <key>", n)
+	} else {
+		log.Printf("Tenants: TENANT_API_KEYS not set — single tenant %q, X-Tenant-ID is rejected", "default")
+	}
+
 	// ─── HTTP server ──────────────────────────────────────────────────────────
 	addr := getEnv("ADDR", ":8080")
-	handler := api.NewHandler(redisClient, mongoClient)
+	handler := api.NewHandler(redisClient, mongoClient, llm, tenants)
 	srv := api.NewServer(addr, handler)
 
 	// Graceful shutdown
@@ -0,0 +1,10 @@
+module github.com/lucasfdcampos/serp
+
+go 1.24.0
+
+require github.com/PuerkitoBio/goquery v1.11.0
+
+require (
+	github.com/andybalholm/cascadia v1.3.3 // indirect
+	golang.org/x/net v0.47.0 // indirect
+)
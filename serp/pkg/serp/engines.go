@@ -0,0 +1,232 @@
+package serp
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// engineDef descreve um motor: como montar a URL, o intervalo mínimo entre
+// requisições e os seletores de cada resultado. Uma mudança de layout de um
+// motor se corrige aqui, em um lugar só.
+type engineDef struct {
+	interval time.Duration
+	urls     func(q string) []string
+
+	container string // elemento de cada resultado orgânico
+	title     string
+	link      string // <a> com a URL de destino (padrão: o título)
+	snippet   string
+
+	// parse substitui a extração por container (layouts em tabela)
+	parse func(doc *goquery.Document) []Result
+}
+
+// SearXNGInstances são as instâncias públicas do SearXNG, tentadas em ordem.
+var SearXNGInstances = []string{
+	"https://searx.be",
+	"https://search.bus-hit.me",
+	"https://paulgo.io",
+}
+
+var engines = map[Engine]engineDef{
+	DuckDuckGo: {
+		// versão lite: HTML em tabela e menos detecção de bot que /html
+		interval: 1500 * time.Millisecond,
+		urls: func(q string) []string {
+			return []string{"https://lite.duckduckgo.com/lite/?q=" + url.QueryEscape(q) + "&kl=br-pt"}
+		},
+		parse: parseDDGLite,
+	},
+	Bing: {
+		interval: 1 * time.Second,
+		urls: func(q string) []string {
+			return []string{"https://www.bing.com/search?q=" + url.QueryEscape(q) + "&setlang=pt-BR&cc=BR"}
+		},
+		container: "#b_results li.b_algo",
+		title:     "h2",
+		link:      "h2 a",
+		snippet:   ".b_caption p, .b_lineclamp2, .b_lineclamp3, .b_snippet, .b_dList",
+	},
+	Brave: {
+		interval: 2500 * time.Millisecond,
+		urls: func(q string) []string {
+			return []string{"https://search.brave.com/search?q=" + url.QueryEscape(q) + "&source=web"}
+		},
+		container: "#results .snippet[data-type='web'], #results .snippet[data-pos]",
+		title:     ".title, .heading-results, h3",
+		link:      "a",
+		snippet:   ".snippet-description, .snippet-content, .result-description, .description",
+	},
+	Yandex: {
+		interval: 2 * time.Second,
+		urls: func(q string) []string {
+			return []string{fmt.Sprintf("https://yandex.com/search/?text=%s&lr=102", url.QueryEscape(q))} // lr=102 = Brasil
+		},
+		container: "li.serp-item, .Organic",
+		title:     ".OrganicTitle, h2",
+		link:      ".OrganicTitle a, h2 a, a.Link",
+		snippet:   ".Organic-Text, .OrganicText, .ExtendedText, .serp-item__text",
+	},
+	SearXNG: {
+		interval: 800 * time.Millisecond,
+		urls: func(q string) []string {
+			var out []string
+			for _, inst := range SearXNGInstances {
+				out = append(out, fmt.Sprintf("%s/search?q=%s&language=pt-BR&format=html", strings.TrimRight(inst, "/"), url.QueryEscape(q)))
+			}
+			return out
+		},
+		container: "article.result, div.result",
+		title:     "h3",
+		link:      "h3 a, a.url_header, a.url_wrapper",
+		snippet:   ".content, .result-content",
+	},
+	Mojeek: {
+		interval: 1 * time.Second,
+		urls: func(q string) []string {
+			return []string{"https://www.mojeek.com/search?q=" + url.QueryEscape(q) + "&lb=pt"}
+		},
+		container: "ul.results-standard > li, .results li.result",
+		title:     "h2, a.title",
+		link:      "h2 a, a.title, a.ob",
+		snippet:   "p.s, .result-text",
+	},
+	Swisscows: {
+		interval: 2 * time.Second,
+		urls: func(q string) []string {
+			return []string{"https://swisscows.com/web?query=" + url.QueryEscape(q) + "&region=pt-BR"}
+		},
+		container: ".web-results .item, article.item-web, .result-item",
+		title:     "h2, .title",
+		link:      "a.link, h2 a, a",
+		snippet:   ".description, .item-body, p",
+	},
+}
+
+// Parse interpreta uma página de resultados já baixada (ex: HTML salvo de um
+// motor) com os seletores do motor.
+func Parse(engine Engine, r io.Reader) (*SERP, error) {
+	def, ok := engines[engine]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEngine, engine)
+	}
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseDocument(engine, def, doc), nil
+}
+
+func parseDocument(engine Engine, def engineDef, doc *goquery.Document) *SERP {
+	page := &SERP{Engine: engine}
+	if def.parse != nil {
+		page.Results = def.parse(doc)
+	} else {
+		doc.Find(def.container).Each(func(_ int, item *goquery.Selection) {
+			// seletores alternativos podem casar o item e um bloco dentro
+			// dele (Yandex: li.serp-item > .Organic); vale o mais externo
+			if item.ParentsFiltered(def.container).Length() > 0 {
+				return
+			}
+			title := cleanText(item.Find(def.title).First().Text())
+			link := item.Find(def.link).First()
+			if def.link == "" {
+				link = item.Find(def.title).Find("a").First()
+			}
+			href, _ := link.Attr("href")
+			if title == "" && href == "" {
+				return
+			}
+			page.Results = append(page.Results, Result{
+				Title:   title,
+				URL:     resolveURL(href),
+				Snippet: cleanText(item.Find(def.snippet).First().Text()),
+			})
+		})
+	}
+	for i := range page.Results {
+		page.Results[i].Rank = i + 1
+	}
+	if len(page.Results) == 0 {
+		page.Raw = rawText(doc)
+	}
+	return page
+}
+
+// rawText extrai o texto visível em blocos, uma linha por bloco, para
+// páginas cujo layout os seletores não reconhecem.
+func rawText(doc *goquery.Document) string {
+	var sb strings.Builder
+	doc.Find("h2, h3, p, li, td, [class*='snippet'], [class*='result'], [class*='description']").Each(func(_ int, s *goquery.Selection) {
+		if t := cleanText(s.Text()); len(t) > 10 && len(t) < 500 {
+			sb.WriteString(t)
+			sb.WriteString("\n")
+		}
+	})
+	if sb.Len() < 50 {
+		return cleanText(doc.Find("body").Text())
+	}
+	return sb.String()
+}
+
+// parseDDGLite lê a tabela do DuckDuckGo Lite, onde cada resultado ocupa três
+// linhas: link do título, snippet e URL exibida.
+func parseDDGLite(doc *goquery.Document) []Result {
+	var out []Result
+	doc.Find("a.result-link").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		r := Result{Title: cleanText(a.Text()), URL: resolveURL(href)}
+		row := a.Closest("tr")
+		for next := row.Next(); next.Length() > 0; next = next.Next() {
+			if next.Find("a.result-link").Length() > 0 {
+				break
+			}
+			if sn := next.Find("td.result-snippet"); sn.Length() > 0 {
+				r.Snippet = cleanText(sn.Text())
+				break
+			}
+		}
+		// anúncios apontam para o redirect de cliques do DuckDuckGo
+		if strings.Contains(r.URL, "duckduckgo.com/y.js") {
+			return
+		}
+		out = append(out, r)
+	})
+	return out
+}
+
+// resolveURL converte links de redirecionamento dos motores na URL de
+// destino: DuckDuckGo (/l/?uddg=URL) e Bing (/ck/a?u=a1<base64>).
+func resolveURL(href string) string {
+	href = strings.TrimSpace(href)
+	if strings.HasPrefix(href, "//") {
+		href = "https:" + href
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	q := u.Query()
+	if dest := q.Get("uddg"); dest != "" {
+		return dest
+	}
+	if strings.HasSuffix(u.Host, "bing.com") && strings.HasPrefix(u.Path, "/ck/") {
+		if enc := strings.TrimPrefix(q.Get("u"), "a1"); enc != "" {
+			if dec, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(enc, "=")); err == nil {
+				return string(dec)
+			}
+		}
+	}
+	return href
+}
+
+// cleanText colapsa espaços e quebras de linha.
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
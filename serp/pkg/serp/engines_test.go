@@ -0,0 +1,147 @@
+package serp
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Os arquivos em testdata são capturas das páginas de resultados de cada
+// motor, reduzidas aos elementos que os seletores usam. Quando um motor mudar
+// o layout, atualize a captura junto com os seletores em engines.go.
+
+const (
+	trigoTitle   = "Padaria Trigo de Ouro - Londrina"
+	trigoURL     = "https://www.padariatrigo.com.br/"
+	trigoSnippet = "Pães artesanais, confeitaria e café da manhã na Av. Higienópolis, 1200 - Centro, Londrina - PR."
+
+	solutudoTitle   = "Padarias em Londrina - PR | Solutudo"
+	solutudoURL     = "https://www.solutudo.com.br/empresas/pr/londrina/padarias"
+	solutudoSnippet = "Encontre as melhores padarias de Londrina com telefone, endereço e horário."
+)
+
+var standardResults = []Result{
+	{Rank: 1, Title: trigoTitle, URL: trigoURL, Snippet: trigoSnippet},
+	{Rank: 2, Title: solutudoTitle, URL: solutudoURL, Snippet: solutudoSnippet},
+}
+
+func parseFixture(t *testing.T, engine Engine, name string) *SERP {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	page, err := Parse(engine, f)
+	if err != nil {
+		t.Fatalf("Parse(%s, %s): %v", engine, name, err)
+	}
+	return page
+}
+
+func TestParseFixtures(t *testing.T) {
+	tests := []struct {
+		engine Engine
+		file   string
+		want   []Result
+	}{
+		{DuckDuckGo, "duckduckgo.html", []Result{
+			{Rank: 1, Title: trigoTitle, URL: trigoURL, Snippet: trigoSnippet},
+			{
+				Rank:    2,
+				Title:   "Panificadora Central (@panificadoracentral.ldn)",
+				URL:     "https://www.instagram.com/panificadoracentral.ldn/",
+				Snippet: "1.234 seguidores · Panificadora em Londrina. Pedidos (43) 99999-8888.",
+			},
+		}},
+		{Bing, "bing.html", standardResults},
+		{Brave, "brave.html", standardResults},
+		{Yandex, "yandex.html", standardResults},
+		{SearXNG, "searxng.html", standardResults},
+		{Mojeek, "mojeek.html", standardResults},
+		{Swisscows, "swisscows.html", standardResults},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.engine), func(t *testing.T) {
+			page := parseFixture(t, tt.engine, tt.file)
+			if page.Engine != tt.engine {
+				t.Errorf("Engine = %q, want %q", page.Engine, tt.engine)
+			}
+			if !reflect.DeepEqual(page.Results, tt.want) {
+				t.Errorf("Results:\n got  %+v\n want %+v", page.Results, tt.want)
+			}
+			if page.Raw != "" {
+				t.Errorf("Raw preenchido com resultados reconhecidos: %q", page.Raw)
+			}
+		})
+	}
+}
+
+func TestParseUnknownEngine(t *testing.T) {
+	if _, err := Parse(Engine("altavista"), nil); err == nil {
+		t.Error("Parse com motor desconhecido deveria falhar")
+	}
+}
+
+func TestParseBlockedPages(t *testing.T) {
+	tests := []struct {
+		engine  Engine
+		file    string
+		blocked bool
+	}{
+		{Bing, "bing_captcha.html", true},
+		{DuckDuckGo, "duckduckgo_anomaly.html", true},
+		{Yandex, "yandex_empty.html", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			page := parseFixture(t, tt.engine, tt.file)
+			if len(page.Results) != 0 {
+				t.Fatalf("página sem resultados reconheceu %d resultados", len(page.Results))
+			}
+			if page.Raw == "" {
+				t.Fatal("Raw vazio em página sem resultados")
+			}
+			if got := looksBlocked(page.Raw); got != tt.blocked {
+				t.Errorf("looksBlocked = %v, want %v (Raw: %q)", got, tt.blocked, page.Raw)
+			}
+		})
+	}
+}
+
+func TestLooksBlocked(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"Please solve this CAPTCHA to continue", true},
+		{"Our systems have detected unusual traffic from your computer network", true},
+		{"Detectamos tráfego incomum na sua rede", true},
+		{"Are you a robot?", true},
+		{"/anomaly.js?sv=lite", true},
+		{"Padaria Trigo de Ouro - Londrina", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := looksBlocked(tt.text); got != tt.want {
+			t.Errorf("looksBlocked(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestResolveURL(t *testing.T) {
+	tests := []struct {
+		href, want string
+	}{
+		{"//duckduckgo.com/l/?uddg=https%3A%2F%2Fwww.padariatrigo.com.br%2F&rut=abc", trigoURL},
+		{"https://www.bing.com/ck/a?!&&p=8f1e&u=a1aHR0cHM6Ly93d3cucGFkYXJpYXRyaWdvLmNvbS5ici8&ntb=1", trigoURL},
+		{"  https://www.padariatrigo.com.br/  ", trigoURL},
+		{"//www.padariatrigo.com.br/", trigoURL},
+	}
+	for _, tt := range tests {
+		if got := resolveURL(tt.href); got != tt.want {
+			t.Errorf("resolveURL(%q) = %q, want %q", tt.href, got, tt.want)
+		}
+	}
+}
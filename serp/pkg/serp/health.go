@@ -0,0 +1,123 @@
+package serp
+
+import (
+	"sync"
+	"time"
+)
+
+// Pausas aplicadas a um motor bloqueado: dobram a cada bloqueio seguido.
+const (
+	baseCooldown = 1 * time.Minute
+	maxCooldown  = 30 * time.Minute
+	// falhas comuns (timeout, 5xx) seguidas que também colocam o motor em pausa
+	maxFailures = 3
+)
+
+// EngineHealth é o estado de um motor visto pelo Client.
+type EngineHealth struct {
+	Engine        Engine    `json:"engine"`
+	Requests      int       `json:"requests"`
+	Successes     int       `json:"successes"`
+	Failures      int       `json:"failures"`
+	Blocks        int       `json:"blocks"`
+	CoolingDown   bool      `json:"cooling_down"`
+	CooldownUntil time.Time `json:"cooldown_until,omitzero"`
+	LastSuccess   time.Time `json:"last_success,omitzero"`
+}
+
+type engineState struct {
+	EngineHealth
+	streak int // bloqueios/falhas seguidos desde o último sucesso
+}
+
+// health acompanha sucessos e bloqueios por motor para pular motores que
+// estão recusando buscas em vez de insistir neles a cada lead.
+type health struct {
+	mu     sync.Mutex
+	states map[Engine]*engineState
+}
+
+func newHealth() *health {
+	return &health{states: make(map[Engine]*engineState)}
+}
+
+func (h *health) state(e Engine) *engineState {
+	st, ok := h.states[e]
+	if !ok {
+		st = &engineState{EngineHealth: EngineHealth{Engine: e}}
+		h.states[e] = st
+	}
+	return st
+}
+
+func (h *health) available(e Engine) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return time.Now().After(h.state(e).CooldownUntil)
+}
+
+func (h *health) success(e Engine) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	st := h.state(e)
+	st.Requests++
+	st.Successes++
+	st.streak = 0
+	st.LastSuccess = time.Now()
+}
+
+func (h *health) blocked(e Engine) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	st := h.state(e)
+	st.Requests++
+	st.Blocks++
+	st.streak++
+	st.pause()
+}
+
+func (h *health) failure(e Engine) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	st := h.state(e)
+	st.Requests++
+	st.Failures++
+	st.streak++
+	if st.streak >= maxFailures {
+		st.pause()
+	}
+}
+
+func (st *engineState) pause() {
+	d := baseCooldown
+	for i := 1; i < st.streak && d < maxCooldown; i++ {
+		d *= 2
+	}
+	if d > maxCooldown {
+		d = maxCooldown
+	}
+	st.CooldownUntil = time.Now().Add(d)
+}
+
+// Health retorna o estado de cada motor já consultado pelo Client.
+func (c *Client) Health() []EngineHealth {
+	c.health.mu.Lock()
+	defer c.health.mu.Unlock()
+	now := time.Now()
+	out := []EngineHealth{}
+	for _, e := range Engines {
+		st, ok := c.health.states[e]
+		if !ok {
+			continue
+		}
+		eh := st.EngineHealth
+		eh.CoolingDown = now.Before(eh.CooldownUntil)
+		out = append(out, eh)
+	}
+	return out
+}
+
+// Available informa se o motor pode ser consultado agora (não está em pausa).
+func (c *Client) Available(e Engine) bool {
+	return c.health.available(e)
+}
@@ -0,0 +1,44 @@
+package serp
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// limiter garante um intervalo mínimo entre requisições ao mesmo motor,
+// mesmo com várias goroutines buscando ao mesmo tempo: cada chamada reserva
+// o próximo horário livre do motor e dorme até ele.
+type limiter struct {
+	mu   sync.Mutex
+	next map[Engine]time.Time
+}
+
+func newLimiter() *limiter {
+	return &limiter{next: make(map[Engine]time.Time)}
+}
+
+// wait bloqueia até o horário reservado para a requisição ou até ctx acabar.
+func (l *limiter) wait(ctx context.Context, engine Engine, interval time.Duration) error {
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next[engine]
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next[engine] = slot.Add(interval)
+	l.mu.Unlock()
+
+	d := time.Until(slot)
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,212 @@
+package serp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// SearXNGConfig aponta o cliente para instâncias próprias do SearXNG (ex: um
+// container local), consultadas pela API JSON em vez do HTML das instâncias
+// públicas. A instância precisa ter "json" em search.formats no settings.yml.
+type SearXNGConfig struct {
+	URLs    []string // URLs base, tentadas em ordem ("http://localhost:8888")
+	Engines []string // motores do SearXNG usados nas buscas SearXNG (vazio = padrão da instância)
+	Pages   int      // páginas buscadas por consulta (padrão 1)
+	// RouteAll faz as buscas nos outros motores (Bing, DuckDuckGo, ...)
+	// passarem pela instância, com engines=<motor>, em vez de raspar o site
+	// do motor diretamente.
+	RouteAll bool
+}
+
+// maxSearXNGPages limita a paginação por consulta.
+const maxSearXNGPages = 5
+
+// SearXNGConfigFromEnv lê a configuração das variáveis:
+//
+//	SEARXNG_URL      URLs base separadas por vírgula
+//	SEARXNG_ENGINES  motores do SearXNG separados por vírgula
+//	SEARXNG_PAGES    páginas por consulta (1 a 5)
+//	SEARXNG_ROUTE    "all" para rotear todos os motores pela instância
+func SearXNGConfigFromEnv() SearXNGConfig {
+	cfg := SearXNGConfig{
+		URLs:     splitList(os.Getenv("SEARXNG_URL")),
+		Engines:  splitList(os.Getenv("SEARXNG_ENGINES")),
+		Pages:    1,
+		RouteAll: strings.EqualFold(strings.TrimSpace(os.Getenv("SEARXNG_ROUTE")), "all"),
+	}
+	if n, err := strconv.Atoi(os.Getenv("SEARXNG_PAGES")); err == nil && n > 0 {
+		cfg.Pages = n
+	}
+	return cfg
+}
+
+func (cfg SearXNGConfig) enabled() bool { return len(cfg.URLs) > 0 }
+
+// searxngEngineNames mapeia os motores para o nome do engine no SearXNG.
+// Swisscows não existe no SearXNG e continua sendo raspado diretamente.
+var searxngEngineNames = map[Engine]string{
+	DuckDuckGo: "duckduckgo",
+	Bing:       "bing",
+	Brave:      "brave",
+	Yandex:     "yandex",
+	Mojeek:     "mojeek",
+}
+
+// viaSearXNG diz se a busca no motor vai pela API JSON da instância própria
+// e, nesse caso, com quais engines do SearXNG.
+func (c *Client) viaSearXNG(engine Engine) ([]string, bool) {
+	if !c.SearXNG.enabled() {
+		return nil, false
+	}
+	if engine == SearXNG {
+		return c.SearXNG.Engines, true
+	}
+	if name, ok := searxngEngineNames[engine]; ok && c.SearXNG.RouteAll {
+		return []string{name}, true
+	}
+	return nil, false
+}
+
+// searxngResponse é o corpo de /search?format=json.
+type searxngResponse struct {
+	Results []struct {
+		URL     string `json:"url"`
+		Title   string `json:"title"`
+		Content string `json:"content"`
+	} `json:"results"`
+	// pares [engine, motivo], ex: ["bing", "CAPTCHA"]
+	UnresponsiveEngines [][]string `json:"unresponsive_engines"`
+}
+
+// searchSearXNG consulta as instâncias configuradas, em ordem, juntando até
+// SearXNGConfig.Pages páginas da primeira que responder.
+func (c *Client) searchSearXNG(ctx context.Context, engine Engine, query string, searxEngines []string) (*SERP, error) {
+	pages := c.SearXNG.Pages
+	if pages < 1 {
+		pages = 1
+	}
+	if pages > maxSearXNGPages {
+		pages = maxSearXNGPages
+	}
+
+	var lastErr error
+	for _, base := range c.SearXNG.URLs {
+		page := &SERP{Engine: engine, Query: query}
+		seen := make(map[string]bool)
+		var baseErr error
+		for pageno := 1; pageno <= pages; pageno++ {
+			u := searxngURL(base, query, searxEngines, pageno)
+			if pageno == 1 {
+				page.URL = u
+			}
+			resp, err := c.fetchSearXNG(ctx, engine, u)
+			if err != nil {
+				if pageno == 1 {
+					baseErr = err
+				}
+				break
+			}
+			if len(resp.Results) == 0 {
+				if pageno == 1 && len(searxEngines) == 1 && searxngUnresponsive(resp, searxEngines[0]) {
+					baseErr = fmt.Errorf("%s via searxng: %w", engine, ErrBlocked)
+				}
+				break
+			}
+			for _, r := range resp.Results {
+				if r.URL == "" || seen[r.URL] {
+					continue
+				}
+				seen[r.URL] = true
+				page.Results = append(page.Results, Result{
+					Rank:    len(page.Results) + 1,
+					Title:   cleanText(r.Title),
+					URL:     r.URL,
+					Snippet: cleanText(r.Content),
+				})
+			}
+		}
+		if baseErr == nil {
+			// páginas seguintes com erro não descartam as já obtidas; e uma
+			// instância que respondeu sem resultados encerra a busca
+			return page, nil
+		}
+		lastErr = baseErr
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+func (c *Client) fetchSearXNG(ctx context.Context, engine Engine, u string) (*searxngResponse, error) {
+	if err := c.limiter.wait(ctx, engine, engines[engine].interval); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", c.UserAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("searxng: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusTooManyRequests:
+		return nil, fmt.Errorf("searxng: status %d: %w", resp.StatusCode, ErrBlocked)
+	case http.StatusForbidden:
+		return nil, fmt.Errorf("searxng: status 403 (format=json habilitado em search.formats?)")
+	default:
+		return nil, fmt.Errorf("searxng: status %d", resp.StatusCode)
+	}
+
+	var out searxngResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("searxng: json: %w", err)
+	}
+	return &out, nil
+}
+
+func searxngURL(base, query string, searxEngines []string, pageno int) string {
+	v := url.Values{}
+	v.Set("q", query)
+	v.Set("format", "json")
+	v.Set("language", "pt-BR")
+	v.Set("pageno", strconv.Itoa(pageno))
+	if len(searxEngines) > 0 {
+		v.Set("engines", strings.Join(searxEngines, ","))
+	}
+	return strings.TrimRight(base, "/") + "/search?" + v.Encode()
+}
+
+// searxngUnresponsive diz se o engine do SearXNG foi reportado como sem
+// resposta (captcha, suspenso, rate limit).
+func searxngUnresponsive(resp *searxngResponse, name string) bool {
+	for _, ue := range resp.UnresponsiveEngines {
+		if len(ue) > 0 && strings.EqualFold(ue[0], name) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,271 @@
+package serp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeSearXNG é uma instância do SearXNG que responde a /search com a
+// função handle e registra os parâmetros de cada requisição.
+type fakeSearXNG struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []map[string]string
+}
+
+func newFakeSearXNG(t *testing.T, handle func(w http.ResponseWriter, pageno int)) *fakeSearXNG {
+	t.Helper()
+	f := &fakeSearXNG{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search" {
+			http.NotFound(w, r)
+			return
+		}
+		q := r.URL.Query()
+		params := map[string]string{}
+		for k := range q {
+			params[k] = q.Get(k)
+		}
+		f.mu.Lock()
+		f.requests = append(f.requests, params)
+		f.mu.Unlock()
+		pageno, _ := strconv.Atoi(q.Get("pageno"))
+		handle(w, pageno)
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+// jsonPage responde com n resultados da página pageno.
+func jsonPage(w http.ResponseWriter, pageno, n int) {
+	w.Header().Set("Content-Type", "application/json")
+	var items []string
+	for i := 1; i <= n; i++ {
+		items = append(items, fmt.Sprintf(`{"url":"https://p%d.example.com.br/%d","title":"Padaria %d.%d","content":" Pães   e doces "}`, pageno, i, pageno, i))
+	}
+	fmt.Fprintf(w, `{"query":"padaria","results":[%s],"unresponsive_engines":[]}`, strings.Join(items, ","))
+}
+
+func searxClient(cfg SearXNGConfig) *Client {
+	c := NewClient()
+	c.SearXNG = cfg
+	return c
+}
+
+func TestSearXNGJSONParamsAndPagination(t *testing.T) {
+	srv := newFakeSearXNG(t, func(w http.ResponseWriter, pageno int) {
+		switch pageno {
+		case 1, 2:
+			jsonPage(w, pageno, 2)
+		default:
+			jsonPage(w, pageno, 0) // sem mais resultados: encerra a paginação
+		}
+	})
+	c := searxClient(SearXNGConfig{URLs: []string{srv.URL + "/"}, Engines: []string{"bing", "mojeek"}, Pages: 4})
+
+	page, err := c.Search(context.Background(), SearXNG, "padaria londrina")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.requests) != 3 {
+		t.Fatalf("%d requisições, want 3 (páginas 1-2 e a página vazia)", len(srv.requests))
+	}
+	for i, p := range srv.requests {
+		want := map[string]string{
+			"q": "padaria londrina", "format": "json", "language": "pt-BR",
+			"pageno": strconv.Itoa(i + 1), "engines": "bing,mojeek",
+		}
+		if !reflect.DeepEqual(p, want) {
+			t.Errorf("requisição %d: %v, want %v", i+1, p, want)
+		}
+	}
+
+	if page.Engine != SearXNG || page.Query != "padaria londrina" {
+		t.Errorf("page = %+v", page)
+	}
+	if !strings.HasPrefix(page.URL, srv.URL+"/search?") || !strings.Contains(page.URL, "pageno=1") {
+		t.Errorf("URL = %q", page.URL)
+	}
+	if len(page.Results) != 4 {
+		t.Fatalf("%d resultados, want 4", len(page.Results))
+	}
+	for i, r := range page.Results {
+		if r.Rank != i+1 {
+			t.Errorf("resultado %d com Rank %d", i, r.Rank)
+		}
+		if r.Snippet != "Pães e doces" {
+			t.Errorf("Snippet = %q", r.Snippet)
+		}
+	}
+	if got := page.Results[2].URL; got != "https://p2.example.com.br/1" {
+		t.Errorf("primeiro resultado da página 2 = %q", got)
+	}
+}
+
+func TestSearXNGPagesCapAndDedup(t *testing.T) {
+	srv := newFakeSearXNG(t, func(w http.ResponseWriter, pageno int) {
+		// toda página repete o mesmo resultado
+		jsonPage(w, 1, 1)
+	})
+	c := searxClient(SearXNGConfig{URLs: []string{srv.URL}, Pages: 0})
+
+	page, err := c.Search(context.Background(), SearXNG, "padaria")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.requests) != 1 {
+		t.Errorf("Pages=0 fez %d requisições, want 1", len(srv.requests))
+	}
+	if _, ok := srv.requests[0]["engines"]; ok {
+		t.Error("engines enviado sem Engines configurado")
+	}
+	if len(page.Results) != 1 {
+		t.Errorf("%d resultados, want 1", len(page.Results))
+	}
+
+	srv.requests = nil
+	c = searxClient(SearXNGConfig{URLs: []string{srv.URL}, Pages: 2})
+	page, err = c.Search(context.Background(), SearXNG, "padaria")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.requests) != 2 || len(page.Results) != 1 {
+		t.Errorf("%d requisições e %d resultados, want 2 e 1 (URL repetida)", len(srv.requests), len(page.Results))
+	}
+}
+
+func TestSearXNGErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handle  func(w http.ResponseWriter, pageno int)
+		blocked bool
+		msg     string
+	}{
+		{
+			name:    "429",
+			handle:  func(w http.ResponseWriter, _ int) { w.WriteHeader(http.StatusTooManyRequests) },
+			blocked: true,
+		},
+		{
+			name:   "403 sem format=json",
+			handle: func(w http.ResponseWriter, _ int) { w.WriteHeader(http.StatusForbidden) },
+			msg:    "search.formats",
+		},
+		{
+			name:   "500",
+			handle: func(w http.ResponseWriter, _ int) { w.WriteHeader(http.StatusInternalServerError) },
+			msg:    "status 500",
+		},
+		{
+			name:   "JSON inválido",
+			handle: func(w http.ResponseWriter, _ int) { fmt.Fprint(w, `<html>not json</html>`) },
+			msg:    "json",
+		},
+		{
+			name:   "JSON truncado",
+			handle: func(w http.ResponseWriter, _ int) { fmt.Fprint(w, `{"results":[{"url":"https://a.com.br"`) },
+			msg:    "json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newFakeSearXNG(t, tt.handle)
+			c := searxClient(SearXNGConfig{URLs: []string{srv.URL}})
+			page, err := c.Search(context.Background(), SearXNG, "padaria")
+			if err == nil {
+				t.Fatalf("esperava erro, got %+v", page)
+			}
+			if errors.Is(err, ErrBlocked) != tt.blocked {
+				t.Errorf("errors.Is(ErrBlocked) = %v, want %v: %v", !tt.blocked, tt.blocked, err)
+			}
+			if tt.msg != "" && !strings.Contains(err.Error(), tt.msg) {
+				t.Errorf("erro %q não menciona %q", err, tt.msg)
+			}
+		})
+	}
+}
+
+func TestSearXNGFallbackAndLaterPageErrors(t *testing.T) {
+	down := newFakeSearXNG(t, func(w http.ResponseWriter, _ int) { w.WriteHeader(http.StatusBadGateway) })
+	up := newFakeSearXNG(t, func(w http.ResponseWriter, pageno int) {
+		if pageno > 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		jsonPage(w, pageno, 3)
+	})
+	c := searxClient(SearXNGConfig{URLs: []string{down.URL, up.URL}, Pages: 3})
+
+	page, err := c.Search(context.Background(), SearXNG, "padaria")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(down.requests) != 1 || len(up.requests) != 2 {
+		t.Errorf("requisições: down=%d up=%d, want 1 e 2", len(down.requests), len(up.requests))
+	}
+	// erro na página 2 não descarta a página 1
+	if len(page.Results) != 3 || !strings.HasPrefix(page.URL, up.URL) {
+		t.Errorf("page = %d resultados de %q", len(page.Results), page.URL)
+	}
+}
+
+func TestSearXNGRouteAll(t *testing.T) {
+	srv := newFakeSearXNG(t, func(w http.ResponseWriter, _ int) {
+		fmt.Fprint(w, `{"results":[],"unresponsive_engines":[["bing","CAPTCHA"]]}`)
+	})
+	c := searxClient(SearXNGConfig{URLs: []string{srv.URL}, RouteAll: true})
+
+	_, err := c.Search(context.Background(), Bing, "padaria")
+	if !errors.Is(err, ErrBlocked) {
+		t.Errorf("engine sem resposta no SearXNG: err = %v, want ErrBlocked", err)
+	}
+	if got := srv.requests[0]["engines"]; got != "bing" {
+		t.Errorf("engines = %q, want bing", got)
+	}
+	if c.Available(Bing) {
+		t.Error("Bing deveria estar em pausa após o bloqueio")
+	}
+
+	// Swisscows não existe no SearXNG: continua fora da instância.
+	if _, ok := c.viaSearXNG(Swisscows); ok {
+		t.Error("Swisscows não deveria ser roteado pelo SearXNG")
+	}
+	c.SearXNG.RouteAll = false
+	if _, ok := c.viaSearXNG(Bing); ok {
+		t.Error("sem RouteAll, Bing não deveria ser roteado pelo SearXNG")
+	}
+}
+
+func TestSearXNGConfigFromEnv(t *testing.T) {
+	t.Setenv("SEARXNG_URL", " http://localhost:8888 , http://searx.lan/ ,")
+	t.Setenv("SEARXNG_ENGINES", "bing,duckduckgo")
+	t.Setenv("SEARXNG_PAGES", "3")
+	t.Setenv("SEARXNG_ROUTE", "ALL")
+
+	want := SearXNGConfig{
+		URLs:     []string{"http://localhost:8888", "http://searx.lan/"},
+		Engines:  []string{"bing", "duckduckgo"},
+		Pages:    3,
+		RouteAll: true,
+	}
+	if got := SearXNGConfigFromEnv(); !reflect.DeepEqual(got, want) {
+		t.Errorf("SearXNGConfigFromEnv() = %+v, want %+v", got, want)
+	}
+
+	t.Setenv("SEARXNG_URL", "")
+	t.Setenv("SEARXNG_ENGINES", "")
+	t.Setenv("SEARXNG_PAGES", "x")
+	t.Setenv("SEARXNG_ROUTE", "")
+	got := SearXNGConfigFromEnv()
+	if got.enabled() || got.Pages != 1 || got.RouteAll {
+		t.Errorf("sem variáveis: %+v", got)
+	}
+}
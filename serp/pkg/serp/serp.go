@@ -0,0 +1,257 @@
+// Package serp é o cliente de motores de busca compartilhado por find-leads,
+// find-cnpj, find-instagram e lead-api: monta a URL de cada motor, respeita
+// um intervalo mínimo entre requisições ao mesmo motor, acompanha motores
+// bloqueados e converte a página de resultados em uma lista tipada.
+package serp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Engine identifica um motor de busca suportado.
+type Engine string
+
+const (
+	DuckDuckGo Engine = "duckduckgo"
+	Bing       Engine = "bing"
+	Brave      Engine = "brave"
+	Yandex     Engine = "yandex"
+	SearXNG    Engine = "searxng"
+	Mojeek     Engine = "mojeek"
+	Swisscows  Engine = "swisscows"
+)
+
+var engineNames = map[Engine]string{
+	DuckDuckGo: "DuckDuckGo",
+	Bing:       "Bing",
+	Brave:      "Brave",
+	Yandex:     "Yandex",
+	SearXNG:    "SearXNG",
+	Mojeek:     "Mojeek",
+	Swisscows:  "Swisscows",
+}
+
+// Name retorna o nome de exibição do motor ("DuckDuckGo").
+func (e Engine) Name() string {
+	if n, ok := engineNames[e]; ok {
+		return n
+	}
+	return string(e)
+}
+
+// Engines lista os motores na ordem de preferência usada quando o consumidor
+// não escolhe uma.
+var Engines = []Engine{DuckDuckGo, Bing, Brave, Mojeek, SearXNG, Yandex, Swisscows}
+
+// Result é um resultado orgânico da página de busca.
+type Result struct {
+	Rank    int    `json:"rank"` // posição na página, a partir de 1
+	Title   string `json:"title"`
+	URL     string `json:"url"` // URL de destino (redirects do motor já decodificados)
+	Snippet string `json:"snippet"`
+}
+
+// SERP é a página de resultados de uma busca em um motor.
+type SERP struct {
+	Engine  Engine   `json:"engine"`
+	Query   string   `json:"query"`
+	URL     string   `json:"url"` // URL efetivamente consultada
+	Results []Result `json:"results"`
+	// Raw é o texto visível da página. Só é preenchido quando nenhum
+	// resultado foi reconhecido, para que o consumidor ainda possa extrair
+	// dados de layouts que os seletores não conhecem.
+	Raw string `json:"raw,omitempty"`
+}
+
+// Text junta título e snippet de cada resultado, um por linha, ou devolve
+// Raw quando a página não teve resultados reconhecidos.
+func (s *SERP) Text() string {
+	if s == nil {
+		return ""
+	}
+	if len(s.Results) == 0 {
+		return s.Raw
+	}
+	var sb strings.Builder
+	for _, r := range s.Results {
+		sb.WriteString(r.Title)
+		sb.WriteString("\n")
+		if r.Snippet != "" {
+			sb.WriteString(r.Snippet)
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+var (
+	// ErrBlocked indica que o motor recusou a busca (429, 403, captcha).
+	ErrBlocked = errors.New("serp: motor bloqueou a requisição")
+	// ErrCoolingDown indica que o motor foi bloqueado recentemente e está em
+	// pausa; a busca nem chegou a ser feita.
+	ErrCoolingDown = errors.New("serp: motor em pausa após bloqueio")
+	// ErrUnknownEngine indica um Engine sem definição.
+	ErrUnknownEngine = errors.New("serp: motor desconhecido")
+)
+
+const userAgent = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+// Client faz buscas nos motores. O limitador e a saúde dos motores são do
+// Client, então consumidores que compartilham o mesmo Client (Default)
+// também compartilham o intervalo entre requisições e as pausas.
+type Client struct {
+	HTTP      *http.Client
+	UserAgent string
+	SearXNG   SearXNGConfig // instâncias próprias; vazio = instâncias públicas (HTML)
+
+	limiter *limiter
+	health  *health
+}
+
+// NewClient cria um Client com timeout de 15s e o SearXNG configurado pelas
+// variáveis de ambiente (ver SearXNGConfigFromEnv).
+func NewClient() *Client {
+	return &Client{
+		HTTP:      &http.Client{Timeout: 15 * time.Second},
+		UserAgent: userAgent,
+		SearXNG:   SearXNGConfigFromEnv(),
+		limiter:   newLimiter(),
+		health:    newHealth(),
+	}
+}
+
+var (
+	defaultOnce   sync.Once
+	defaultClient *Client
+)
+
+// Default retorna o Client do processo, compartilhado por todos os pacotes.
+func Default() *Client {
+	defaultOnce.Do(func() { defaultClient = NewClient() })
+	return defaultClient
+}
+
+// Search busca query no motor usando o Client padrão.
+func Search(ctx context.Context, engine Engine, query string) (*SERP, error) {
+	return Default().Search(ctx, engine, query)
+}
+
+// Search busca query no motor. Motores com várias instâncias (SearXNG) são
+// tentados em ordem até uma responder com resultados. Com instâncias
+// próprias do SearXNG configuradas, a busca SearXNG (e, com RouteAll, a dos
+// outros motores) usa a API JSON delas.
+func (c *Client) Search(ctx context.Context, engine Engine, query string) (*SERP, error) {
+	def, ok := engines[engine]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEngine, engine)
+	}
+	if !c.health.available(engine) {
+		return nil, fmt.Errorf("%s: %w", engine, ErrCoolingDown)
+	}
+
+	var page *SERP
+	var err error
+	if searxEngines, ok := c.viaSearXNG(engine); ok {
+		page, err = c.searchSearXNG(ctx, engine, query, searxEngines)
+	} else {
+		page, err = c.searchHTML(ctx, engine, def, query)
+	}
+
+	switch {
+	case err == nil:
+		c.health.success(engine)
+		return page, nil
+	case errors.Is(err, ErrBlocked):
+		c.health.blocked(engine)
+	case ctx.Err() == nil:
+		c.health.failure(engine)
+	}
+	return nil, err
+}
+
+// searchHTML raspa a página de resultados do motor.
+func (c *Client) searchHTML(ctx context.Context, engine Engine, def engineDef, query string) (*SERP, error) {
+	var lastErr error
+	var last *SERP
+	for _, u := range def.urls(query) {
+		page, err := c.fetch(ctx, engine, def, u)
+		if err != nil {
+			lastErr = err
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		page.Query = query
+		last = page
+		if len(page.Results) > 0 {
+			break
+		}
+	}
+
+	if last != nil {
+		return last, nil
+	}
+	return nil, lastErr
+}
+
+// fetch faz uma requisição (respeitando o intervalo do motor) e interpreta a
+// página.
+func (c *Client) fetch(ctx context.Context, engine Engine, def engineDef, u string) (*SERP, error) {
+	if err := c.limiter.wait(ctx, engine, def.interval); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", c.UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	req.Header.Set("Accept-Language", "pt-BR,pt;q=0.9,en;q=0.8")
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", engine, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusTooManyRequests, http.StatusForbidden, http.StatusServiceUnavailable, http.StatusAccepted:
+		// DuckDuckGo responde 202 com a página de desafio anti-bot
+		return nil, fmt.Errorf("%s: status %d: %w", engine, resp.StatusCode, ErrBlocked)
+	default:
+		return nil, fmt.Errorf("%s: status %d", engine, resp.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: parse: %w", engine, err)
+	}
+	page := parseDocument(engine, def, doc)
+	page.URL = u
+	if len(page.Results) == 0 && looksBlocked(page.Raw) {
+		return nil, fmt.Errorf("%s: captcha: %w", engine, ErrBlocked)
+	}
+	return page, nil
+}
+
+// looksBlocked reconhece páginas de captcha/desafio servidas com status 200.
+func looksBlocked(text string) bool {
+	lower := strings.ToLower(text)
+	for _, marker := range []string{"captcha", "unusual traffic", "tráfego incomum", "are you a robot", "anomaly", "search was made by a human"} {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}